@@ -0,0 +1,166 @@
+// Package bench generates large synthetic inputs, and measures how long it takes to parse and
+// score them. It is used by the benchmarks and by the hidden bench command, to validate
+// performance changes and to catch regressions.
+package bench
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/parser"
+	"github.com/romnn/kube-score/score"
+	"github.com/romnn/kube-score/score/checks"
+)
+
+// objectsPerApp is the number of objects of each app of the synthetic input
+const objectsPerApp = 5
+
+// Manifests returns a synthetic input of about the number of objects, split into apps that each
+// have a Deployment, and a Service, PodDisruptionBudget, NetworkPolicy and HorizontalPodAutoscaler
+// that reference it. The apps are spread over 10 namespaces.
+func Manifests(objects int) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < max(1, objects/objectsPerApp); i++ {
+		fmt.Fprintf(&buf, appTemplate, fmt.Sprintf("app-%d", i), fmt.Sprintf("team-%d", i%10))
+	}
+	return buf.Bytes()
+}
+
+const appTemplate = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: %[1]s
+  namespace: %[2]s
+spec:
+  replicas: 3
+  selector:
+    matchLabels:
+      app: %[1]s
+  template:
+    metadata:
+      labels:
+        app: %[1]s
+    spec:
+      containers:
+      - name: app
+        image: registry.example.com/%[1]s:1.0.0
+        imagePullPolicy: Always
+        ports:
+        - containerPort: 8080
+        resources:
+          requests:
+            cpu: 100m
+            memory: 128Mi
+          limits:
+            cpu: 100m
+            memory: 128Mi
+        readinessProbe:
+          httpGet:
+            path: /ready
+            port: 8080
+        livenessProbe:
+          httpGet:
+            path: /health
+            port: 8080
+        securityContext:
+          runAsUser: 12345
+          runAsGroup: 12345
+          readOnlyRootFilesystem: true
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: %[1]s
+  namespace: %[2]s
+spec:
+  selector:
+    app: %[1]s
+  ports:
+  - port: 80
+    targetPort: 8080
+---
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: %[1]s
+  namespace: %[2]s
+spec:
+  minAvailable: 1
+  selector:
+    matchLabels:
+      app: %[1]s
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: %[1]s
+  namespace: %[2]s
+spec:
+  podSelector:
+    matchLabels:
+      app: %[1]s
+  policyTypes:
+  - Ingress
+  - Egress
+---
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: %[1]s
+  namespace: %[2]s
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: %[1]s
+  minReplicas: 3
+  maxReplicas: 10
+---
+`
+
+// Result is the duration of a run
+type Result struct {
+	Objects int
+	Parse   time.Duration
+	Score   time.Duration
+}
+
+// Run parses and scores the input with all checks
+func Run(input []byte) (Result, error) {
+	p, err := parser.New(nil)
+	if err != nil {
+		return Result{}, err
+	}
+
+	start := time.Now()
+	parsed, err := p.ParseFiles([]ks.NamedReader{namedReader{Reader: bytes.NewReader(input), name: "bench.yaml"}})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to parse: %w", err)
+	}
+	parsedAt := time.Now()
+
+	runConfig := &config.RunConfiguration{KubernetesVersion: config.Semver{Major: 1, Minor: 29}}
+	allChecks := score.RegisterAllChecks(&checks.Config{}, runConfig)
+	card, err := score.Score(parsed, allChecks, runConfig)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to score: %w", err)
+	}
+
+	return Result{
+		Objects: len(*card),
+		Parse:   parsedAt.Sub(start),
+		Score:   time.Since(parsedAt),
+	}, nil
+}
+
+type namedReader struct {
+	*bytes.Reader
+	name string
+}
+
+func (n namedReader) Name() string {
+	return n.name
+}
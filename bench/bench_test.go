@@ -0,0 +1,127 @@
+package bench
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"runtime/metrics"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/parser"
+	"github.com/romnn/kube-score/score"
+	"github.com/romnn/kube-score/score/checks"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	res, err := Run(Manifests(50))
+	assert.NoError(t, err)
+	assert.Equal(t, 50, res.Objects)
+}
+
+func BenchmarkParse(b *testing.B) {
+	for _, objects := range []int{1000, 10000} {
+		input := Manifests(objects)
+		b.Run(fmt.Sprintf("objects=%d", objects), func(b *testing.B) {
+			p, err := parser.New(nil)
+			assert.NoError(b, err)
+			b.SetBytes(int64(len(input)))
+			b.ReportAllocs()
+			for b.Loop() {
+				_, err := p.ParseFiles([]ks.NamedReader{namedReader{Reader: bytes.NewReader(input), name: "bench.yaml"}})
+				assert.NoError(b, err)
+			}
+		})
+	}
+}
+
+// BenchmarkParseMemory reports the peak heap size while parsing, and the heap size of the parsed
+// objects, relative to the size of the input
+func BenchmarkParseMemory(b *testing.B) {
+	for _, objects := range []int{1000, 10000} {
+		input := Manifests(objects)
+		b.Run(fmt.Sprintf("objects=%d", objects), func(b *testing.B) {
+			p, err := parser.New(nil)
+			assert.NoError(b, err)
+			b.ReportAllocs()
+			var peak, retained uint64
+			for b.Loop() {
+				var parsed ks.AllTypes
+				usage := heapUsage(func() {
+					parsed, err = p.ParseFiles([]ks.NamedReader{namedReader{Reader: bytes.NewReader(input), name: "bench.yaml"}})
+					assert.NoError(b, err)
+				})
+				runtime.KeepAlive(parsed)
+				peak, retained = max(peak, usage.peak), max(retained, usage.retained)
+			}
+			b.ReportMetric(float64(peak)/float64(len(input)), "peak-heap/input-byte")
+			b.ReportMetric(float64(retained)/float64(len(input)), "retained-heap/input-byte")
+		})
+	}
+}
+
+type heap struct {
+	// peak is the largest increase of the heap while fn runs, including the garbage that has not
+	// been collected yet
+	peak uint64
+	// retained is the increase of the heap after fn, once the garbage has been collected
+	retained uint64
+}
+
+// heapUsage samples the size of the heap objects while fn runs
+func heapUsage(fn func()) heap {
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	read := func() uint64 {
+		metrics.Read(sample)
+		return sample[0].Value.Uint64()
+	}
+
+	runtime.GC()
+	before := read()
+	peak := before
+	done := make(chan struct{})
+	sampled := make(chan struct{})
+	go func() {
+		defer close(sampled)
+		ticker := time.NewTicker(time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				peak = max(peak, read())
+			}
+		}
+	}()
+	fn()
+	close(done)
+	<-sampled
+	peak = max(peak, read())
+	runtime.GC()
+	return heap{peak: peak - before, retained: read() - before}
+}
+
+func BenchmarkScore(b *testing.B) {
+	for _, objects := range []int{1000, 10000} {
+		p, err := parser.New(nil)
+		assert.NoError(b, err)
+		parsed, err := p.ParseFiles([]ks.NamedReader{namedReader{Reader: bytes.NewReader(Manifests(objects)), name: "bench.yaml"}})
+		assert.NoError(b, err)
+
+		b.Run(fmt.Sprintf("objects=%d", objects), func(b *testing.B) {
+			runConfig := &config.RunConfiguration{KubernetesVersion: config.Semver{Major: 1, Minor: 29}}
+			b.ReportAllocs()
+			for b.Loop() {
+				allChecks := score.RegisterAllChecks(&checks.Config{}, runConfig)
+				_, err := score.Score(parsed, allChecks, runConfig)
+				assert.NoError(b, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// canonicalCheckIDs replaces the old IDs of renamed checks with their current IDs, see
+// checks.Aliases, and reports every old ID to w
+func canonicalCheckIDs(flagName string, ids []string, aliases map[string]string, w io.Writer) []string {
+	canonical := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if current, ok := aliases[id]; ok {
+			_, _ = fmt.Fprintf(w, "kube-score: the check ID %s of --%s is deprecated, use %s\n", id, flagName, current)
+			id = current
+		}
+		canonical = append(canonical, id)
+	}
+	return canonical
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalCheckIDs(t *testing.T) {
+	var w bytes.Buffer
+	ids := canonicalCheckIDs(
+		"ignore-test",
+		[]string{"service-type", "container-resource-limits"},
+		map[string]string{"container-resource-limits": "container-resources"},
+		&w,
+	)
+	assert.Equal(t, []string{"service-type", "container-resources"}, ids)
+	assert.Equal(t, "kube-score: the check ID container-resource-limits of --ignore-test is deprecated, use container-resources\n", w.String())
+}
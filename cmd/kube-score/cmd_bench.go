@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/romnn/kube-score/bench"
+)
+
+// benchmark parses and scores a synthetic input, to compare the performance of builds
+func benchmark(binName string, args []string) error {
+	fs := flag.NewFlagSet(binName, flag.ExitOnError)
+	printHelp := fs.Bool("help", false, "Print help")
+	objects := fs.Int("objects", 10000, "Number of objects of the synthetic input")
+	runs := fs.Int("runs", 3, "Number of runs, the fastest run is reported")
+	setDefault(fs, binName, "bench", false)
+
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	if *printHelp {
+		fs.Usage()
+		return nil
+	}
+
+	if *objects < 1 || *runs < 1 {
+		return errors.New("--objects and --runs must be at least 1")
+	}
+
+	input := bench.Manifests(*objects)
+	var results []bench.Result
+	for range *runs {
+		res, err := bench.Run(input)
+		if err != nil {
+			return err
+		}
+		results = append(results, res)
+	}
+	writeBenchResult(os.Stdout, len(input), results)
+	return nil
+}
+
+// writeBenchResult writes the fastest parse and score durations of the runs
+func writeBenchResult(w io.Writer, inputSize int, results []bench.Result) {
+	best := results[0]
+	for _, res := range results[1:] {
+		best.Parse = min(best.Parse, res.Parse)
+		best.Score = min(best.Score, res.Score)
+	}
+	total := best.Parse + best.Score
+	_, _ = fmt.Fprintf(
+		w,
+		"objects: %d, input: %.1f MB, runs: %d\nparse: %s\nscore: %s\ntotal: %s (%.0f objects/s)\n",
+		best.Objects,
+		float64(inputSize)/1e6,
+		len(results),
+		best.Parse.Round(time.Millisecond),
+		best.Score.Round(time.Millisecond),
+		total.Round(time.Millisecond),
+		float64(best.Objects)/total.Seconds(),
+	)
+}
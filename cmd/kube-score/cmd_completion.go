@@ -0,0 +1,254 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/romnn/kube-score/score"
+)
+
+var commandNames = []string{"score", "list", "docs", "trend", "images", "parse", "validate-config", "lint-annotations", "completion", "version", "help"}
+
+// checkIDFlags are the flags of the score command that take check IDs as values
+var checkIDFlags = []string{"ignore-test", "enable-optional-test"}
+
+func completion(binName string, args []string) error {
+	fs := flag.NewFlagSet(binName, flag.ExitOnError)
+	printHelp := fs.Bool("help", false, "Print help")
+	setDefault(fs, binName, "completion", false)
+
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	if *printHelp {
+		fs.Usage()
+		return nil
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected exactly one shell: 'bash', 'zsh', 'fish' or 'powershell'")
+	}
+
+	return writeCompletion(os.Stdout, binName, fs.Arg(0))
+}
+
+type completionFlag struct {
+	Name      string
+	Shorthand string
+	Usage     string
+	// CheckIDs is true if the flag takes check IDs as values
+	CheckIDs bool
+}
+
+type completionData struct {
+	BinName  string
+	FuncName string
+	Commands []string
+	Flags    []completionFlag
+	Checks   []string
+}
+
+// FlagNames returns the long and short names of all flags, including the dashes
+func (d completionData) FlagNames() []string {
+	var names []string
+	for _, f := range d.Flags {
+		names = append(names, "--"+f.Name)
+		if f.Shorthand != "" {
+			names = append(names, "-"+f.Shorthand)
+		}
+	}
+	return names
+}
+
+// CheckIDFlagNames returns the names of the flags that take check IDs, including the dashes
+func (d completionData) CheckIDFlagNames() []string {
+	names := make([]string, 0, len(checkIDFlags))
+	for _, name := range checkIDFlags {
+		names = append(names, "--"+name)
+	}
+	return names
+}
+
+func newCompletionData(binName string) completionData {
+	data := completionData{
+		BinName:  binName,
+		FuncName: "_" + regexp.MustCompile(`[^a-zA-Z0-9]`).ReplaceAllString(binName, "_"),
+		Commands: commandNames,
+	}
+
+	fs, _ := newScoreFlagSet(binName)
+	fs.VisitAll(func(f *flag.Flag) {
+		data.Flags = append(data.Flags, completionFlag{
+			Name:      f.Name,
+			Shorthand: f.Shorthand,
+			Usage:     f.Usage,
+			CheckIDs:  slices.Contains(checkIDFlags, f.Name),
+		})
+	})
+
+	for _, c := range score.AllChecks() {
+		data.Checks = append(data.Checks, c.ID)
+	}
+	sort.Strings(data.Checks)
+
+	return data
+}
+
+func writeCompletion(w io.Writer, binName, shell string) error {
+	var tmpl string
+	switch shell {
+	case "bash":
+		tmpl = bashCompletion
+	case "zsh":
+		tmpl = zshCompletion
+	case "fish":
+		tmpl = fishCompletion
+	case "powershell":
+		tmpl = powershellCompletion
+	default:
+		return fmt.Errorf("unsupported shell %q, use one of 'bash', 'zsh', 'fish' or 'powershell'", shell)
+	}
+
+	t, err := template.New(shell).Funcs(template.FuncMap{
+		"join": strings.Join,
+		"fishQuote": func(s string) string {
+			return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+		},
+		"psQuote": func(s string) string {
+			return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+		},
+	}).Parse(tmpl)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, newCompletionData(binName))
+}
+
+const bashCompletion = `# bash completion for {{.BinName}}
+# Load with: source <({{.BinName}} completion bash)
+{{.FuncName}}() {
+    local cur prev
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+
+    if [[ ${COMP_CWORD} -eq 1 ]]; then
+        COMPREPLY=($(compgen -W "{{join .Commands " "}}" -- "${cur}"))
+        return
+    fi
+
+    case "${prev}" in
+        {{join .CheckIDFlagNames "|"}})
+            COMPREPLY=($(compgen -W "{{join .Checks " "}}" -- "${cur}"))
+            return
+            ;;
+        completion)
+            COMPREPLY=($(compgen -W "bash zsh fish powershell" -- "${cur}"))
+            return
+            ;;
+    esac
+
+    if [[ "${cur}" == -* ]]; then
+        COMPREPLY=($(compgen -W "{{join .FlagNames " "}}" -- "${cur}"))
+        return
+    fi
+
+    COMPREPLY=($(compgen -f -- "${cur}"))
+}
+complete -o default -F {{.FuncName}} {{.BinName}}
+`
+
+const zshCompletion = `#compdef {{.BinName}}
+# zsh completion for {{.BinName}}
+# Load with: source <({{.BinName}} completion zsh)
+{{.FuncName}}() {
+    local -a commands flags checks
+    commands=({{join .Commands " "}})
+    flags=({{join .FlagNames " "}})
+    checks=({{join .Checks " "}})
+
+    if (( CURRENT == 2 )); then
+        compadd -a commands
+        return
+    fi
+
+    case "${words[CURRENT-1]}" in
+        {{join .CheckIDFlagNames "|"}})
+            compadd -a checks
+            return
+            ;;
+        completion)
+            compadd bash zsh fish powershell
+            return
+            ;;
+    esac
+
+    if [[ "${words[CURRENT]}" == -* ]]; then
+        compadd -a flags
+        return
+    fi
+
+    _files
+}
+
+if [[ "${funcstack[1]}" == "{{.FuncName}}" ]]; then
+    {{.FuncName}} "$@"
+else
+    compdef {{.FuncName}} {{.BinName}}
+fi
+`
+
+const fishCompletion = `# fish completion for {{.BinName}}
+# Load with: {{.BinName}} completion fish | source
+{{- $bin := .BinName}}
+{{- $checks := join .Checks " "}}
+complete -c {{$bin}} -f -n '__fish_use_subcommand' -a '{{join .Commands " "}}'
+complete -c {{$bin}} -f -n '__fish_seen_subcommand_from completion' -a 'bash zsh fish powershell'
+{{- range .Flags}}
+complete -c {{$bin}} -n '__fish_seen_subcommand_from score' -l {{.Name}}{{if .Shorthand}} -s {{.Shorthand}}{{end}}{{if .CheckIDs}} -x -a '{{$checks}}'{{end}} -d {{fishQuote .Usage}}
+{{- end}}
+`
+
+const powershellCompletion = `# powershell completion for {{.BinName}}
+# Load with: {{.BinName}} completion powershell | Out-String | Invoke-Expression
+Register-ArgumentCompleter -Native -CommandName '{{.BinName}}' -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+    $commands = @({{range $i, $c := .Commands}}{{if $i}}, {{end}}{{psQuote $c}}{{end}})
+    $flags = @({{range $i, $f := .FlagNames}}{{if $i}}, {{end}}{{psQuote $f}}{{end}})
+    $checks = @({{range $i, $c := .Checks}}{{if $i}}, {{end}}{{psQuote $c}}{{end}})
+    $checkIDFlags = @({{range $i, $f := .CheckIDFlagNames}}{{if $i}}, {{end}}{{psQuote $f}}{{end}})
+
+    $elements = @($commandAst.CommandElements | ForEach-Object { $_.ToString() })
+    if ($wordToComplete) {
+        $elements = $elements[0..($elements.Count - 2)]
+    }
+
+    if ($elements.Count -eq 1) {
+        $candidates = $commands
+    } elseif ($checkIDFlags -contains $elements[-1]) {
+        $candidates = $checks
+    } elseif ($elements[-1] -eq 'completion') {
+        $candidates = @('bash', 'zsh', 'fish', 'powershell')
+    } elseif ($wordToComplete -like '-*') {
+        $candidates = $flags
+    } else {
+        return
+    }
+
+    $candidates | Where-Object { $_ -like "$wordToComplete*" } | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+}
+`
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteCompletion(t *testing.T) {
+	t.Parallel()
+
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		var buf bytes.Buffer
+		err := writeCompletion(&buf, "kube-score", shell)
+		assert.NoError(t, err, shell)
+
+		out := buf.String()
+		assert.Contains(t, out, "container-resources", shell)
+		assert.Contains(t, out, "container-seccomp-profile", shell)
+		assert.Contains(t, out, "ignore-test", shell)
+		assert.Contains(t, out, "kubernetes-version", shell)
+	}
+}
+
+func TestWriteCompletionFishEscapesUsage(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	assert.NoError(t, writeCompletion(&buf, "kube-score", "fish"))
+	assert.Contains(t, buf.String(), `in addition to \'kube-score\'`)
+}
+
+func TestWriteCompletionUnknownShell(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	assert.Error(t, writeCompletion(&buf, "kube-score", "tcsh"))
+}
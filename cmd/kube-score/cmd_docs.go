@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	flag "github.com/spf13/pflag"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/score"
+	"github.com/romnn/kube-score/score/checks"
+)
+
+func docs(binName string, args []string) error {
+	fs := flag.NewFlagSet(binName, flag.ExitOnError)
+	printHelp := fs.Bool("help", false, "Print help")
+	format := fs.StringP("output-format", "o", "markdown", "Set to 'markdown' or 'json'")
+	setDefault(fs, binName, "docs", false)
+
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	if *printHelp {
+		fs.Usage()
+		return nil
+	}
+
+	allChecks := score.AllChecks()
+
+	switch *format {
+	case "markdown":
+		return writeDocsMarkdown(os.Stdout, allChecks)
+	case "json":
+		return writeDocsJSON(os.Stdout, allChecks)
+	default:
+		fs.Usage()
+		return fmt.Errorf("--output-format must be set to: 'markdown' or 'json'")
+	}
+}
+
+type checkDoc struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	TargetType string `json:"target_type"`
+	Optional   bool   `json:"optional"`
+	Namespace  string `json:"namespace,omitempty"`
+	// Aliases are the old IDs of a renamed check
+	Aliases     []string `json:"aliases,omitempty"`
+	Category    string   `json:"category"`
+	Description string   `json:"description"`
+	Remediation string   `json:"remediation"`
+	// KubernetesVersions is empty for the checks that apply to all versions of Kubernetes
+	KubernetesVersions string `json:"kubernetes_versions,omitempty"`
+}
+
+func writeDocsJSON(w io.Writer, allChecks []ks.Check) error {
+	checkDocs := make([]checkDoc, 0, len(allChecks))
+	for _, c := range sortedChecks(allChecks) {
+		doc := checkDoc{
+			ID:          c.ID,
+			Name:        c.Name,
+			TargetType:  c.TargetType,
+			Optional:    c.Optional,
+			Namespace:   c.Namespace,
+			Aliases:     c.Aliases,
+			Category:    c.Category,
+			Description: c.Comment,
+			Remediation: c.Remediation,
+		}
+		if versions := checks.KubernetesVersions(c); versions != (checks.VersionRange{}) {
+			doc.KubernetesVersions = versions.String()
+		}
+		checkDocs = append(checkDocs, doc)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(checkDocs)
+}
+
+func writeDocsMarkdown(w io.Writer, allChecks []ks.Check) error {
+	byCategory := make(map[string][]ks.Check)
+	var categories []string
+	for _, c := range sortedChecks(allChecks) {
+		category := c.Category
+		if category == "" {
+			category = "other"
+		}
+		if _, ok := byCategory[category]; !ok {
+			categories = append(categories, category)
+		}
+		byCategory[category] = append(byCategory[category], c)
+	}
+	sort.Strings(categories)
+
+	fmt.Fprintf(w, "# Checks\n\n")
+	fmt.Fprintf(w, "<!-- This file was generated by kube-score docs -->\n")
+	for _, category := range categories {
+		fmt.Fprintf(w, "\n## %s\n\n", category)
+		fmt.Fprintf(w, "| ID | Target | Enabled | Description | Remediation |\n")
+		fmt.Fprintf(w, "|----|--------|---------|-------------|-------------|\n")
+		for _, c := range byCategory[category] {
+			enabled := "default"
+			if c.Optional {
+				enabled = "optional"
+			}
+			_, err := fmt.Fprintf(w, "| %s | %s | %s | %s | %s |\n",
+				c.ID,
+				c.TargetType,
+				enabled,
+				markdownCell(c.Comment),
+				markdownCell(c.Remediation),
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func sortedChecks(allChecks []ks.Check) []ks.Check {
+	sorted := append([]ks.Check{}, allChecks...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	return sorted
+}
+
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.TrimSpace(strings.ReplaceAll(s, "\n", " "))
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ks "github.com/romnn/kube-score/domain"
+)
+
+var testDocsChecks = []ks.Check{
+	{ID: "b-check", TargetType: "Pod", Comment: "Checks | pipes", Category: "security", Remediation: "Fix it"},
+	{ID: "a-check", TargetType: "Service", Comment: "Checks services", Optional: true, Category: "networking"},
+	{ID: "custom", TargetType: "all", Comment: "Custom check"},
+}
+
+func TestWriteDocsMarkdown(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	assert.NoError(t, writeDocsMarkdown(&buf, testDocsChecks))
+
+	out := buf.String()
+	assert.Contains(t, out, "## networking\n")
+	assert.Contains(t, out, "| a-check | Service | optional | Checks services |  |\n")
+	assert.Contains(t, out, "| b-check | Pod | default | Checks \\| pipes | Fix it |\n")
+	assert.Contains(t, out, "## other\n")
+
+	// Categories are sorted
+	assert.Less(t, bytes.Index(buf.Bytes(), []byte("## networking")), bytes.Index(buf.Bytes(), []byte("## other")))
+	assert.Less(t, bytes.Index(buf.Bytes(), []byte("## other")), bytes.Index(buf.Bytes(), []byte("## security")))
+}
+
+func TestWriteDocsJSON(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	assert.NoError(t, writeDocsJSON(&buf, testDocsChecks))
+
+	var checkDocs []checkDoc
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &checkDocs))
+	assert.Len(t, checkDocs, 3)
+	assert.Equal(t, checkDoc{
+		ID:          "b-check",
+		TargetType:  "Pod",
+		Category:    "security",
+		Description: "Checks | pipes",
+		Remediation: "Fix it",
+	}, checkDocs[1])
+}
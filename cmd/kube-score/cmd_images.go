@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	flag "github.com/spf13/pflag"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/parser"
+	"github.com/romnn/kube-score/score/image"
+)
+
+func images(binName string, args []string) error {
+	fs := flag.NewFlagSet(binName, flag.ExitOnError)
+	printHelp := fs.Bool("help", false, "Print help")
+	format := fs.StringP("output-format", "o", "json", "Set to 'json' or 'csv'")
+	setDefault(fs, binName, "images", false)
+
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	if *printHelp {
+		fs.Usage()
+		return nil
+	}
+
+	if *format != "json" && *format != "csv" {
+		fs.Usage()
+		return fmt.Errorf("--output-format must be set to: 'json' or 'csv'")
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("no files given")
+	}
+
+	var files []ks.NamedReader
+	for _, file := range fs.Args() {
+		if file == "-" {
+			files = append(files, namedReader{Reader: os.Stdin, name: "STDIN"})
+			continue
+		}
+		fp, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer fp.Close()
+		filename, _ := filepath.Abs(file)
+		files = append(files, namedReader{Reader: fp, name: filename})
+	}
+
+	p, err := parser.New(nil)
+	if err != nil {
+		return fmt.Errorf("failed to initialize parser: %w", err)
+	}
+	parsedFiles, err := p.ParseFiles(files)
+	if err != nil {
+		return fmt.Errorf("failed to parse files: %w", err)
+	}
+
+	inventory := image.NewInventory(parsedFiles)
+	if *format == "csv" {
+		return writeImagesCSV(os.Stdout, inventory)
+	}
+	return writeImagesJSON(os.Stdout, inventory)
+}
+
+func writeImagesJSON(w io.Writer, inventory image.Inventory) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(inventory)
+}
+
+func writeImagesCSV(w io.Writer, inventory image.Inventory) error {
+	output := csv.NewWriter(w)
+	_ = output.Write([]string{"image", "registry", "repository", "tag", "digest", "workloads"})
+	for _, i := range inventory.Images {
+		_ = output.Write([]string{i.Image, i.Registry, i.Repository, i.Tag, i.Digest, strconv.Itoa(i.Workloads)})
+	}
+	output.Flush()
+	return output.Error()
+}
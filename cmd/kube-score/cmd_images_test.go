@@ -0,0 +1,19 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/romnn/kube-score/score/image"
+)
+
+func TestWriteImagesCSV(t *testing.T) {
+	var w bytes.Buffer
+	err := writeImagesCSV(&w, image.Inventory{Images: []image.InventoryImage{
+		{Image: "nginx", Registry: "docker.io", Repository: "library/nginx", Tag: "latest", Workloads: 2},
+	}})
+	assert.NoError(t, err)
+	assert.Equal(t, "image,registry,repository,tag,digest,workloads\nnginx,docker.io,library/nginx,latest,,2\n", w.String())
+}
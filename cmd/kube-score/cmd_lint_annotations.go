@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	flag "github.com/spf13/pflag"
+)
+
+func lintAnnotations(binName string, args []string) error {
+	fs := flag.NewFlagSet(binName, flag.ExitOnError)
+	printHelp := fs.Bool("help", false, "Print help")
+	annotationPrefixes := fs.StringSlice("annotation-prefix", []string{}, "Additional annotation prefix, can be set multiple times")
+	configFile := fs.String("config", "", "Path to the YAML configuration file, with the annotation prefixes and the groups of checks that the annotations can use")
+	setDefault(fs, binName, "lint-annotations", false)
+
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	if *printHelp {
+		fs.Usage()
+		return nil
+	}
+
+	v := newConfigValidator(*annotationPrefixes)
+	v.hygiene = true
+	v.now = time.Now()
+
+	if *configFile != "" {
+		if err := v.configFile(*configFile); err != nil {
+			return err
+		}
+	}
+	for _, path := range fs.Args() {
+		if err := v.manifestFile(path); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range v.problems {
+		fmt.Println(p)
+	}
+	if len(v.problems) > 0 {
+		return fmt.Errorf("found %d problems", len(v.problems))
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintAnnotationsManifest(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+  annotations:
+    kube-score/ignore: servce-type
+    kube-score/severity: container-resources=warning@2025-12-31,service-type=ok@2026-06-30
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: reasoned
+  annotations:
+    kube-score/ignore: service-type
+    kube-score/container-resources: disabled
+    kube-score/ignore-reason: the port is exposed by the load balancer
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: deploy
+spec:
+  template:
+    metadata:
+      annotations:
+        kube-score/container-resources: disabled
+        kube-score/service-type: enabled
+`), 0o600))
+
+	v := newTestConfigValidator()
+	v.hygiene = true
+	v.now = time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, v.manifestFile(path))
+
+	assert.Equal(t, []string{
+		path + `:6: kube-score/ignore: unknown check "servce-type"`,
+		path + `:6: kube-score/ignore: checks are ignored without a kube-score/ignore-reason annotation`,
+		path + `:7: kube-score/severity: override of container-resources expired on 2025-12-31`,
+		path + `:26: kube-score/container-resources: checks are ignored without a kube-score/ignore-reason annotation`,
+	}, v.problems)
+}
+
+func TestValidateConfigIgnoresHygiene(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+  annotations:
+    kube-score/ignore: service-type
+    kube-score/severity: container-resources=warning@2000-01-01
+`), 0o600))
+
+	v := newTestConfigValidator()
+	assert.NoError(t, v.manifestFile(path))
+	assert.Empty(t, v.problems)
+}
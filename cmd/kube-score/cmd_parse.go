@@ -0,0 +1,164 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	flag "github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/parser"
+)
+
+// parsedObject is an object that the parser found in the input
+type parsedObject struct {
+	APIVersion string `json:"api_version"`
+	Kind       string `json:"kind"`
+	Namespace  string `json:"namespace,omitempty"`
+	Name       string `json:"name"`
+	File       string `json:"file"`
+	Line       int    `json:"line"`
+	// Scored is false for the objects that are only referenced by the checks of other objects
+	Scored bool `json:"scored"`
+	// Skipped is true for the objects with the kube-score/skip annotation, and the objects that
+	// match a skip expression
+	Skipped        bool   `json:"skipped"`
+	SkipExpression string `json:"skip_expression,omitempty"`
+	// Duplicates are the locations of the identical copies of the object, that are not scored
+	Duplicates []string `json:"duplicates,omitempty"`
+}
+
+func parseFiles(binName string, args []string) error {
+	fs := flag.NewFlagSet(binName, flag.ExitOnError)
+	printHelp := fs.Bool("help", false, "Print help")
+	skipExpressions := fs.StringArray("skip", []string{}, "skip resources that match a YAML path and regex, can be set multiple times")
+	namespaceFor := fs.StringArray(
+		"namespace-for",
+		[]string{},
+		"Default namespace of the objects without a namespace in the files that match a pattern, on the format pattern=namespace, like with score",
+	)
+	fileNamespace := fs.StringArray(
+		"file-namespace",
+		[]string{},
+		"Default namespace of the objects without a namespace in a file, on the format path=namespace, like with score",
+	)
+	setDefault(fs, binName, "parse", false)
+
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	if *printHelp {
+		fs.Usage()
+		return nil
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("no files given")
+	}
+
+	var expressions []*config.SkipExpression
+	for _, rawExpr := range *skipExpressions {
+		expr, err := config.ParseSkipExpression(rawExpr)
+		if err != nil {
+			return fmt.Errorf("invalid skip expression: %w", err)
+		}
+		expressions = append(expressions, expr)
+	}
+
+	namespaces, err := fileNamespaces(fs.Args(), *namespaceFor, *fileNamespace)
+	if err != nil {
+		return err
+	}
+
+	var files []ks.NamedReader
+	for _, file := range fs.Args() {
+		if file == "-" {
+			files = append(files, namedReader{Reader: os.Stdin, name: "STDIN"})
+			continue
+		}
+		fp, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer fp.Close()
+		filename, _ := filepath.Abs(file)
+		files = append(files, namedReader{Reader: fp, name: filename})
+	}
+
+	var skipped []parsedObject
+	p, err := parser.New(&parser.Config{
+		SkipExpressions: expressions,
+		FileNamespaces:  namespaces,
+		SkippedByExpression: func(obj metav1.PartialObjectMetadata, location ks.FileLocation, expr *config.SkipExpression) {
+			skipped = append(skipped, skippedObject(obj, location, expr))
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to initialize parser: %w", err)
+	}
+	parsedFiles, err := p.ParseFiles(files)
+	if err != nil {
+		return fmt.Errorf("failed to parse files: %w", err)
+	}
+
+	return writeParsedObjects(os.Stdout, parsedObjects(parsedFiles, skipped))
+}
+
+func newParsedObject(typeMeta metav1.TypeMeta, objectMeta metav1.ObjectMeta, location ks.FileLocation) parsedObject {
+	o := parsedObject{
+		APIVersion: typeMeta.APIVersion,
+		Kind:       typeMeta.Kind,
+		Namespace:  objectMeta.Namespace,
+		Name:       objectMeta.Name,
+		File:       location.Name,
+		Line:       location.Line,
+		Skipped:    location.Skip,
+	}
+	for _, d := range location.Duplicates {
+		o.Duplicates = append(o.Duplicates, fmt.Sprintf("%s:%d", d.Name, d.Line))
+	}
+	return o
+}
+
+// skippedObject is the object that matches a skip expression, see parser.Config.SkippedByExpression
+func skippedObject(obj metav1.PartialObjectMetadata, location ks.FileLocation, expr *config.SkipExpression) parsedObject {
+	o := newParsedObject(obj.TypeMeta, obj.ObjectMeta, location)
+	o.Skipped = true
+	o.SkipExpression = expr.String()
+	return o
+}
+
+// parsedObjects returns all objects of the input, in the order of their location
+func parsedObjects(all ks.AllTypes, skipped []parsedObject) []parsedObject {
+	objects := []parsedObject{}
+	for _, m := range all.Metas() {
+		o := newParsedObject(m.TypeMeta, m.ObjectMeta, m.FileLocation())
+		o.Scored = true
+		objects = append(objects, o)
+	}
+	for _, m := range all.OtherObjects() {
+		objects = append(objects, newParsedObject(m.TypeMeta, m.ObjectMeta, m.FileLocation()))
+	}
+	objects = append(objects, skipped...)
+
+	slices.SortStableFunc(objects, func(a, b parsedObject) int {
+		return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Line, b.Line))
+	})
+	return objects
+}
+
+func writeParsedObjects(w io.Writer, objects []parsedObject) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	return encoder.Encode(objects)
+}
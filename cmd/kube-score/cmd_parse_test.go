@@ -0,0 +1,61 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/parser"
+)
+
+func TestParsedObjects(t *testing.T) {
+	doc := `apiVersion: v1
+kind: Namespace
+metadata:
+  name: app
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: app
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test-web
+  namespace: app
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: app
+  annotations:
+    kube-score/skip: "true"
+`
+	expr, err := config.ParseSkipExpression("metadata.name=^test-")
+	assert.NoError(t, err)
+
+	var skipped []parsedObject
+	p, err := parser.New(&parser.Config{
+		SkipExpressions: []*config.SkipExpression{expr},
+		SkippedByExpression: func(obj metav1.PartialObjectMetadata, location ks.FileLocation, expr *config.SkipExpression) {
+			skipped = append(skipped, skippedObject(obj, location, expr))
+		},
+	})
+	assert.NoError(t, err)
+	parsed, err := p.ParseFiles([]ks.NamedReader{namedReader{Reader: strings.NewReader(doc), name: "app.yaml"}})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []parsedObject{
+		{APIVersion: "v1", Kind: "Namespace", Name: "app", File: "app.yaml", Line: 1},
+		{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "app", Name: "web", File: "app.yaml", Line: 6, Scored: true},
+		{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "app", Name: "test-web", File: "app.yaml", Line: 12, Skipped: true, SkipExpression: "metadata.name=^test-"},
+		{APIVersion: "v1", Kind: "Service", Namespace: "app", Name: "web", File: "app.yaml", Line: 18, Scored: true, Skipped: true},
+	}, parsedObjects(parsed, skipped))
+}
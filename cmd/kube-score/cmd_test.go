@@ -1,9 +1,16 @@
 package main
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/score/checks"
+	"github.com/romnn/kube-score/scorecard"
 )
 
 func TestParseCli(t *testing.T) {
@@ -38,3 +45,42 @@ func TestParseCli(t *testing.T) {
 	assert.Equal(t, 2, offset)
 	assert.Nil(t, err)
 }
+
+func TestLoadSuppressions(t *testing.T) {
+	t.Parallel()
+	file := filepath.Join(t.TempDir(), "suppressions.txt")
+	assert.NoError(t, os.WriteFile(file, []byte(`# accepted risks
+619a78e8d3e4e2bd  the budget is created by the operator
+
+  ae3c10922e6069b0
+`), 0o600))
+
+	fingerprints, err := loadSuppressions(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"619a78e8d3e4e2bd", "ae3c10922e6069b0"}, fingerprints)
+
+	_, err = loadSuppressions(filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
+
+func TestWriteCheckList(t *testing.T) {
+	t.Parallel()
+	all := []ks.Check{
+		{ID: "container-security-context-privileged", TargetType: "Pod", Comment: "Privileged", Category: "security"},
+		{ID: "service-type", TargetType: "Service", Comment: "Type", Category: "networking"},
+		{ID: "pod-dns", TargetType: "Pod", Comment: "DNS", Optional: true, Category: "reliability"},
+	}
+	policy, err := scorecard.NewPolicy(
+		scorecard.GradeWarning,
+		map[string]string{"security": "never"},
+		checks.Categories,
+	)
+	assert.NoError(t, err)
+
+	var w bytes.Buffer
+	assert.NoError(t, writeCheckList(&w, all, policy, func(c ks.Check) bool { return !c.Optional }))
+	assert.Equal(t, `container-security-context-privileged,Pod,Privileged,default,security,critical,false
+service-type,Service,Type,default,networking,warning,true
+pod-dns,Pod,DNS,optional,reliability,critical,false
+`, w.String())
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/romnn/kube-score/history"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func trend(binName string, args []string) error {
+	fs := flag.NewFlagSet(binName, flag.ExitOnError)
+	printHelp := fs.Bool("help", false, "Print help")
+	historyDB := fs.String(
+		"history-db",
+		"",
+		"Path to the SQLite database written by 'score --history-db'",
+	)
+	runs := fs.Int("runs", 10, "Number of most recent runs to report")
+	setDefault(fs, binName, "trend", false)
+
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	if *printHelp {
+		fs.Usage()
+		return nil
+	}
+
+	if *historyDB == "" {
+		fs.Usage()
+		return errors.New("--history-db must be set")
+	}
+	if *runs < 2 {
+		return errors.New("--runs must be at least 2")
+	}
+
+	ctx := context.Background()
+	db, err := history.Open(ctx, *historyDB)
+	if err != nil {
+		return fmt.Errorf("failed to open history database: %w", err)
+	}
+	defer db.Close()
+
+	return writeTrend(ctx, os.Stdout, db, *runs)
+}
+
+func appendHistory(ctx context.Context, path string, scoreCard *scorecard.Scorecard) error {
+	db, err := history.Open(ctx, path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return db.Append(ctx, time.Now(), scoreCard)
+}
+
+func writeTrend(ctx context.Context, w io.Writer, db *history.DB, limit int) error {
+	runs, err := db.Runs(ctx, limit)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		_, err := fmt.Fprintln(w, "No runs recorded")
+		return err
+	}
+
+	fmt.Fprintln(w, "Runs:")
+	for i, run := range runs {
+		line := fmt.Sprintf(
+			"  %s  critical: %d, warning: %d",
+			run.CreatedAt.Format(time.RFC3339), run.Critical, run.Warning,
+		)
+		if i > 0 {
+			line += fmt.Sprintf(
+				" (critical: %+d, warning: %+d)",
+				run.Critical-runs[i-1].Critical, run.Warning-runs[i-1].Warning,
+			)
+		}
+		fmt.Fprintln(w, line)
+	}
+
+	if len(runs) < 2 {
+		return nil
+	}
+
+	first, last := runs[0], runs[len(runs)-1]
+	deltas, err := db.Deltas(ctx, first.ID, last.ID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\nChanges since %s:\n", first.CreatedAt.Format(time.RFC3339))
+	if len(deltas) == 0 {
+		fmt.Fprintln(w, "  No changes")
+	}
+	for _, d := range deltas {
+		var state string
+		switch {
+		case d.From == nil:
+			state = "NEW"
+		case d.To == nil:
+			state = "REMOVED"
+		case d.Improved():
+			state = "IMPROVED"
+		default:
+			state = "REGRESSED"
+		}
+		from, to := "NOT RUN", "NOT RUN"
+		if d.From != nil {
+			from = d.From.String()
+		}
+		if d.To != nil {
+			to = d.To.String()
+		}
+		fmt.Fprintf(w, "  [%s] %s: %s %s -> %s\n", state, d.Object, d.Check, from, to)
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+
+	"github.com/romnn/kube-score/config"
+	"github.com/romnn/kube-score/score"
+	"github.com/romnn/kube-score/score/checks"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func validateConfig(binName string, args []string) error {
+	fs := flag.NewFlagSet(binName, flag.ExitOnError)
+	printHelp := fs.Bool("help", false, "Print help")
+	configFile := fs.String("config", "", "Path to the YAML configuration file to validate")
+	skipExpressions := fs.StringArray("skip", []string{}, "Skip expression to validate, can be set multiple times")
+	ignoreTests := fs.StringSlice("ignore-test", []string{}, "Test ID of --ignore-test to validate, can be set multiple times")
+	optionalTests := fs.StringSlice("enable-optional-test", []string{}, "Test ID of --enable-optional-test to validate, can be set multiple times")
+	annotationPrefixes := fs.StringSlice("annotation-prefix", []string{}, "Additional annotation prefix, can be set multiple times")
+	setDefault(fs, binName, "validate-config", false)
+
+	err := fs.Parse(args)
+	if err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	if *printHelp {
+		fs.Usage()
+		return nil
+	}
+
+	v := newConfigValidator(*annotationPrefixes)
+
+	if *configFile != "" {
+		if err := v.configFile(*configFile); err != nil {
+			return err
+		}
+	}
+	v.flags(*skipExpressions, *ignoreTests, *optionalTests)
+	for _, path := range fs.Args() {
+		if err := v.manifestFile(path); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range v.problems {
+		fmt.Println(p)
+	}
+	if len(v.problems) > 0 {
+		return fmt.Errorf("found %d problems", len(v.problems))
+	}
+	return nil
+}
+
+type configValidator struct {
+	knownChecks map[string]bool
+	// aliases are the current IDs of the renamed checks, keyed by their old IDs
+	aliases            map[string]string
+	annotationPrefixes []string
+	problems           []string
+
+	// hygiene also reports expired severity overrides and ignores without a reason, at now
+	hygiene bool
+	now     time.Time
+}
+
+// newConfigValidator returns a validator of the built-in checks. The old IDs of renamed checks
+// are known, but reported as deprecated.
+func newConfigValidator(annotationPrefixes []string) *configValidator {
+	v := &configValidator{
+		knownChecks:        make(map[string]bool),
+		aliases:            checks.Aliases(),
+		annotationPrefixes: annotationPrefixes,
+	}
+	for _, c := range score.AllChecks() {
+		v.knownChecks[c.ID] = true
+	}
+	for id := range v.aliases {
+		v.knownChecks[id] = true
+	}
+	return v
+}
+
+func (v *configValidator) add(source string, p config.Problem) {
+	if p.Line == 0 {
+		v.problems = append(v.problems, fmt.Sprintf("%s: %s", source, p.Message))
+		return
+	}
+	v.problems = append(v.problems, fmt.Sprintf("%s:%d: %s", source, p.Line, p.Message))
+}
+
+func (v *configValidator) configFile(path string) error {
+	problems, err := config.LintFile(path)
+	if err != nil {
+		return err
+	}
+	for _, p := range problems {
+		v.add(path, p)
+	}
+
+	// The annotation prefixes of the config file are used when validating the manifests
+	if len(problems) == 0 {
+		file, err := config.LoadFile(path)
+		if err != nil {
+			return err
+		}
+		v.annotationPrefixes = append(file.AnnotationPrefixes, v.annotationPrefixes...)
+		for _, name := range slices.Sorted(maps.Keys(file.Groups)) {
+			v.knownChecks[config.GroupPrefix+name] = true
+			for _, id := range file.Groups[name] {
+				if !v.knownChecks[id] {
+					v.add(path, config.Problem{Message: fmt.Sprintf("group %q: unknown check %q", name, id)})
+				}
+			}
+		}
+		if _, err := scorecard.NewPolicy(scorecard.GradeCritical, file.Thresholds, checks.Categories); err != nil {
+			v.add(path, config.Problem{Message: err.Error()})
+		}
+	}
+	return nil
+}
+
+func (v *configValidator) flags(skipExpressions, ignoreTests, optionalTests []string) {
+	for _, e := range skipExpressions {
+		if _, err := config.ParseSkipExpression(e); err != nil {
+			v.add("--skip", config.Problem{Message: err.Error()})
+		}
+	}
+	for _, id := range ignoreTests {
+		v.checkID("--ignore-test", 0, id)
+	}
+	for _, id := range optionalTests {
+		v.checkID("--enable-optional-test", 0, id)
+	}
+}
+
+// checkID reports an unknown check ID, or the old ID of a renamed check
+func (v *configValidator) checkID(source string, line int, id string) {
+	if current, ok := v.aliases[id]; ok {
+		v.add(source, config.Problem{Line: line, Message: fmt.Sprintf("check %q is deprecated, use %q", id, current)})
+		return
+	}
+	if !v.knownChecks[id] {
+		v.add(source, config.Problem{Line: line, Message: fmt.Sprintf("unknown check %q", id)})
+	}
+}
+
+// manifestFile validates the kube-score annotations of all objects in the file
+func (v *configValidator) manifestFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc yaml.Node
+		err := decoder.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			v.add(path, config.ProblemFromYAMLError(err))
+			return nil
+		}
+		v.annotations(path, &doc)
+	}
+}
+
+// annotations walks the node, and validates all mappings under an "annotations" key
+func (v *configValidator) annotations(path string, node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "annotations" && value.Kind == yaml.MappingNode {
+				v.annotationMapping(path, value)
+				continue
+			}
+			v.annotations(path, value)
+		}
+		return
+	}
+	for _, child := range node.Content {
+		v.annotations(path, child)
+	}
+}
+
+func (v *configValidator) annotationMapping(path string, node *yaml.Node) {
+	annotations := make(map[string]string, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		annotations[node.Content[i].Value] = node.Content[i+1].Value
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		problems := scorecard.LintAnnotation(v.annotationPrefixes, key.Value, value.Value, v.knownChecks)
+		sort.Strings(problems)
+		if v.hygiene {
+			problems = append(problems, scorecard.LintAnnotationHygiene(
+				v.annotationPrefixes, key.Value, value.Value, annotations, v.knownChecks, v.now,
+			)...)
+		}
+		for _, p := range problems {
+			v.add(path, config.Problem{Line: key.Line, Message: p})
+		}
+		for _, id := range scorecard.AnnotationCheckIDs(v.annotationPrefixes, key.Value, value.Value) {
+			if current, ok := v.aliases[id]; ok {
+				v.add(path, config.Problem{
+					Line:    key.Line,
+					Message: fmt.Sprintf("%s: check %q is deprecated, use %q", key.Value, id, current),
+				})
+			}
+		}
+	}
+}
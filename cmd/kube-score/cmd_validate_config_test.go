@@ -0,0 +1,144 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestConfigValidator() configValidator {
+	return configValidator{
+		knownChecks: map[string]bool{
+			"container-resources": true,
+			"service-type":        true,
+		},
+	}
+}
+
+func TestValidateConfigFlags(t *testing.T) {
+	t.Parallel()
+
+	v := newTestConfigValidator()
+	v.flags(
+		[]string{"metadata.name=^test-", "metadata.name"},
+		[]string{"service-type", "servce-type"},
+		[]string{"container-resources"},
+	)
+
+	assert.Len(t, v.problems, 2)
+	assert.Contains(t, v.problems[0], "--skip: ")
+	assert.Equal(t, `--ignore-test: unknown check "servce-type"`, v.problems[1])
+}
+
+func TestValidateConfigManifest(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(`apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+  annotations:
+    kube-score/ignore: service-type,servce-type
+    kube-score/severity: container-resources=maybe
+    kube-score/service-type: off
+    kube-score/min-replicas: "3"
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: deploy
+spec:
+  template:
+    metadata:
+      annotations:
+        score.example.com/enable: container-resource
+`), 0o600))
+
+	v := newTestConfigValidator()
+	v.annotationPrefixes = []string{"score.example.com"}
+	assert.NoError(t, v.manifestFile(path))
+
+	assert.Equal(t, []string{
+		path + `:6: kube-score/ignore: unknown check "servce-type"`,
+		path + `:7: kube-score/severity: invalid grade "maybe" of container-resources, expected warning, info or ok`,
+		path + `:8: kube-score/service-type: invalid value "off", expected enabled or disabled`,
+		path + `:19: score.example.com/enable: unknown check "container-resource"`,
+	}, v.problems)
+}
+
+func TestValidateConfigFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "kube-score.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("checks:\n  service-type:\n    foo: bar\n"), 0o600))
+
+	v := newTestConfigValidator()
+	assert.NoError(t, v.configFile(path))
+	assert.Equal(t, []string{path + `:2: check "service-type" has no parameters`}, v.problems)
+}
+
+func TestValidateConfigFileThresholds(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "kube-score.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("thresholds:\n  security: warning\n  performance: never\n"), 0o600))
+
+	v := newTestConfigValidator()
+	assert.NoError(t, v.configFile(path))
+	assert.Equal(t, []string{
+		path + `: unknown category "performance", expected one of security, reliability, resources, networking, best-practice`,
+	}, v.problems)
+}
+
+func TestValidateConfigDeprecatedIDs(t *testing.T) {
+	t.Parallel()
+
+	v := newTestConfigValidator()
+	v.aliases = map[string]string{"container-resource-limits": "container-resources"}
+	v.knownChecks["container-resource-limits"] = true
+	v.flags(nil, []string{"container-resource-limits"}, nil)
+
+	path := filepath.Join(t.TempDir(), "manifest.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: pod
+  annotations:
+    kube-score/ignore: container-resource-limits
+    kube-score/severity: container-resource-limits=warning
+`), 0o600))
+	assert.NoError(t, v.manifestFile(path))
+
+	assert.Equal(t, []string{
+		`--ignore-test: check "container-resource-limits" is deprecated, use "container-resources"`,
+		path + `:6: kube-score/ignore: check "container-resource-limits" is deprecated, use "container-resources"`,
+		path + `:7: kube-score/severity: check "container-resource-limits" is deprecated, use "container-resources"`,
+	}, v.problems)
+}
+
+func TestValidateConfigGroups(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "kube-score.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("groups:\n  resources: [container-resources, container-resource]\n"), 0o600))
+	manifest := filepath.Join(dir, "manifest.yaml")
+	assert.NoError(t, os.WriteFile(manifest, []byte(`apiVersion: v1
+kind: Pod
+metadata:
+  name: pod
+  annotations:
+    kube-score/ignore: group:resources,group:other
+`), 0o600))
+
+	v := newTestConfigValidator()
+	assert.NoError(t, v.configFile(path))
+	assert.NoError(t, v.manifestFile(manifest))
+	assert.Equal(t, []string{
+		path + `: group "resources": unknown check "container-resource"`,
+		manifest + `:6: kube-score/ignore: unknown group "other"`,
+	}, v.problems)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+)
+
+// skipExplainer records which documents were skipped by which skip expression, so that a skip
+// expression that matches nothing, or too much, is noticed
+type skipExplainer struct {
+	expressions []*config.SkipExpression
+	matches     map[*config.SkipExpression][]string
+}
+
+func newSkipExplainer(expressions []*config.SkipExpression) *skipExplainer {
+	return &skipExplainer{
+		expressions: expressions,
+		matches:     make(map[*config.SkipExpression][]string),
+	}
+}
+
+// skipped is the parser.Config.SkippedByExpression callback
+func (e *skipExplainer) skipped(obj metav1.PartialObjectMetadata, location ks.FileLocation, expr *config.SkipExpression) {
+	ref := obj.Kind + "/" + obj.Name
+	if obj.Namespace != "" {
+		ref = obj.Kind + "/" + obj.Namespace + "/" + obj.Name
+	}
+	e.matches[expr] = append(e.matches[expr], fmt.Sprintf("%s in %s:%d", ref, location.Name, location.Line))
+}
+
+// write reports the documents that every skip expression matched, in the order of the
+// expressions. A document is only reported for the first expression that matches it.
+func (e *skipExplainer) write(w io.Writer) {
+	for _, expr := range e.expressions {
+		matches := e.matches[expr]
+		if len(matches) == 0 {
+			_, _ = fmt.Fprintf(w, "kube-score: skip expression %q matched no documents\n", expr.String())
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "kube-score: skip expression %q matched %d documents:\n", expr.String(), len(matches))
+		for _, m := range matches {
+			_, _ = fmt.Fprintf(w, "  %s\n", m)
+		}
+	}
+}
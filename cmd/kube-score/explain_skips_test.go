@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/parser"
+)
+
+func TestSkipExplainer(t *testing.T) {
+	doc := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: test-web
+  namespace: app
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: test-web
+`
+	var expressions []*config.SkipExpression
+	for _, raw := range []string{"metadata.name=^test-", "kind=Service", "metadata.name=typo"} {
+		expr, err := config.ParseSkipExpression(raw)
+		assert.NoError(t, err)
+		expressions = append(expressions, expr)
+	}
+
+	e := newSkipExplainer(expressions)
+	p, err := parser.New(&parser.Config{SkipExpressions: expressions, SkippedByExpression: e.skipped})
+	assert.NoError(t, err)
+	_, err = p.ParseFiles([]ks.NamedReader{namedReader{Reader: strings.NewReader(doc), name: "app.yaml"}})
+	assert.NoError(t, err)
+
+	var w bytes.Buffer
+	e.write(&w)
+	assert.Equal(t, `kube-score: skip expression "metadata.name=^test-" matched 2 documents:
+  Deployment/app/test-web in app.yaml:1
+  Service/test-web in app.yaml:7
+kube-score: skip expression "kind=Service" matched no documents
+kube-score: skip expression "metadata.name=typo" matched no documents
+`, w.String())
+}
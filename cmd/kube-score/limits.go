@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/romnn/kube-score/parser"
+	"github.com/romnn/kube-score/plugin"
+)
+
+// parserLimits returns the limits of the input, the sizes are quantities like '1Mi'
+func parserLimits(maxDocuments int, maxDocumentSize, maxInputSize string, timeout time.Duration) (parser.Limits, error) {
+	limits := parser.Limits{MaxDocuments: maxDocuments, Timeout: timeout}
+	if maxDocuments < 0 {
+		return limits, fmt.Errorf("invalid --max-documents %d: must not be negative", maxDocuments)
+	}
+	if timeout < 0 {
+		return limits, fmt.Errorf("invalid --parse-timeout %s: must not be negative", timeout)
+	}
+
+	documentSize, err := parseSize(maxDocumentSize)
+	if err != nil {
+		return limits, fmt.Errorf("invalid --max-document-size: %w", err)
+	}
+	limits.MaxDocumentSize = int(documentSize)
+
+	limits.MaxInputSize, err = parseSize(maxInputSize)
+	if err != nil {
+		return limits, fmt.Errorf("invalid --max-input-size: %w", err)
+	}
+	return limits, nil
+}
+
+// pluginLimits returns the resource limits of the plugins, the sizes are quantities like '1Mi'
+func pluginLimits(memory string, cpuTime time.Duration, fileSize string, openFiles int) (plugin.Limits, error) {
+	limits := plugin.Limits{CPUTime: cpuTime, OpenFiles: openFiles}
+	if cpuTime < 0 {
+		return limits, fmt.Errorf("invalid --plugin-cpu-limit %s: must not be negative", cpuTime)
+	}
+	if openFiles < 0 {
+		return limits, fmt.Errorf("invalid --plugin-max-open-files %d: must not be negative", openFiles)
+	}
+
+	var err error
+	limits.Memory, err = parseSize(memory)
+	if err != nil {
+		return limits, fmt.Errorf("invalid --plugin-memory-limit: %w", err)
+	}
+	limits.FileSize, err = parseSize(fileSize)
+	if err != nil {
+		return limits, fmt.Errorf("invalid --plugin-max-file-size: %w", err)
+	}
+	return limits, nil
+}
+
+func parseSize(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	q, err := resource.ParseQuantity(s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a size like '1Mi': %w", s, err)
+	}
+	if q.Sign() < 0 {
+		return 0, fmt.Errorf("%q must not be negative", s)
+	}
+	return q.Value(), nil
+}
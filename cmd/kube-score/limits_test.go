@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/romnn/kube-score/parser"
+	"github.com/romnn/kube-score/plugin"
+)
+
+func TestParserLimits(t *testing.T) {
+	limits, err := parserLimits(0, "", "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, parser.Limits{}, limits)
+
+	limits, err = parserLimits(1000, "1Mi", "100M", 30*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, parser.Limits{
+		MaxDocuments:    1000,
+		MaxDocumentSize: 1024 * 1024,
+		MaxInputSize:    100 * 1000 * 1000,
+		Timeout:         30 * time.Second,
+	}, limits)
+
+	_, err = parserLimits(-1, "", "", 0)
+	assert.EqualError(t, err, "invalid --max-documents -1: must not be negative")
+	_, err = parserLimits(0, "1 MB", "", 0)
+	assert.ErrorContains(t, err, "invalid --max-document-size: \"1 MB\" is not a size like '1Mi'")
+	_, err = parserLimits(0, "", "-1Ki", 0)
+	assert.EqualError(t, err, "invalid --max-input-size: \"-1Ki\" must not be negative")
+	_, err = parserLimits(0, "", "", -time.Second)
+	assert.EqualError(t, err, "invalid --parse-timeout -1s: must not be negative")
+}
+
+func TestPluginLimits(t *testing.T) {
+	limits, err := pluginLimits("0", 0, "", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, plugin.Limits{}, limits)
+
+	limits, err = pluginLimits("1Gi", 5*time.Second, "64Mi", 256)
+	assert.NoError(t, err)
+	assert.Equal(t, plugin.Limits{
+		Memory:    1 << 30,
+		CPUTime:   5 * time.Second,
+		FileSize:  64 << 20,
+		OpenFiles: 256,
+	}, limits)
+
+	_, err = pluginLimits("1 GB", 0, "", 0)
+	assert.ErrorContains(t, err, "invalid --plugin-memory-limit: \"1 GB\" is not a size like '1Mi'")
+	_, err = pluginLimits("", -time.Second, "", 0)
+	assert.EqualError(t, err, "invalid --plugin-cpu-limit -1s: must not be negative")
+	_, err = pluginLimits("", 0, "-1", 0)
+	assert.EqualError(t, err, "invalid --plugin-max-file-size: \"-1\" must not be negative")
+	_, err = pluginLimits("", 0, "", -1)
+	assert.EqualError(t, err, "invalid --plugin-max-open-files -1: must not be negative")
+}
@@ -2,24 +2,42 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"path"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/mattn/go-isatty"
 	"github.com/romnn/kube-score/config"
 	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/i18n"
+	"github.com/romnn/kube-score/notify"
 	"github.com/romnn/kube-score/parser"
+	"github.com/romnn/kube-score/plugin"
 	"github.com/romnn/kube-score/renderer/ci"
+	csvrenderer "github.com/romnn/kube-score/renderer/csv"
+	"github.com/romnn/kube-score/renderer/dot"
+	"github.com/romnn/kube-score/renderer/events"
+	"github.com/romnn/kube-score/renderer/github"
 	"github.com/romnn/kube-score/renderer/human"
 	"github.com/romnn/kube-score/renderer/json_v2"
+	"github.com/romnn/kube-score/renderer/json_v3"
+	"github.com/romnn/kube-score/renderer/mermaid"
+	"github.com/romnn/kube-score/renderer/metadata"
 	"github.com/romnn/kube-score/renderer/sarif"
 	"github.com/romnn/kube-score/score"
 	"github.com/romnn/kube-score/score/checks"
+	"github.com/romnn/kube-score/score/graph"
+	"github.com/romnn/kube-score/score/image"
 	"github.com/romnn/kube-score/scorecard"
 	flag "github.com/spf13/pflag"
 	"golang.org/x/term"
@@ -46,6 +64,63 @@ func main() {
 			}
 		},
 
+		"trend": func(helpName string, args []string) {
+			if err := trend(helpName, args); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "Failed to report trend: %v\n", err)
+				os.Exit(1)
+			}
+		},
+
+		// bench is not listed in the usage, it is only used for the development of kube-score
+		"bench": func(helpName string, args []string) {
+			if err := benchmark(helpName, args); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "Failed to run benchmark: %v\n", err)
+				os.Exit(1)
+			}
+		},
+
+		"docs": func(helpName string, args []string) {
+			if err := docs(helpName, args); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "Failed to generate docs: %v\n", err)
+				os.Exit(1)
+			}
+		},
+
+		"images": func(helpName string, args []string) {
+			if err := images(helpName, args); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "Failed to list images: %v\n", err)
+				os.Exit(1)
+			}
+		},
+
+		"parse": func(helpName string, args []string) {
+			if err := parseFiles(helpName, args); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "Failed to parse files: %v\n", err)
+				os.Exit(1)
+			}
+		},
+
+		"validate-config": func(helpName string, args []string) {
+			if err := validateConfig(helpName, args); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "Failed to validate config: %v\n", err)
+				os.Exit(1)
+			}
+		},
+
+		"lint-annotations": func(helpName string, args []string) {
+			if err := lintAnnotations(helpName, args); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "Failed to lint annotations: %v\n", err)
+				os.Exit(1)
+			}
+		},
+
+		"completion": func(helpName string, args []string) {
+			if err := completion(helpName, args); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "Failed to generate completion: %v\n", err)
+				os.Exit(1)
+			}
+		},
+
 		"version": func(helpName string, args []string) {
 			cmdVersion()
 		},
@@ -78,6 +153,13 @@ func setDefault(fs *flag.FlagSet, binName, actionName string, displayForMoreInfo
 Actions:
 	score	Checks all files in the input, and gives them a score and recommendations
 	list	Prints a CSV list of all available score checks
+	docs	Prints a Markdown or JSON catalog of all checks, with their category and remediation
+	trend	Reports how the grades changed over the runs stored with --history-db
+	images	Prints a JSON or CSV inventory of the container images of all files in the input
+	parse	Prints the objects that are found in the input as JSON, and if they are scored or skipped
+	validate-config	Validates the --config file, skip expressions, test IDs and the kube-score annotations of manifests
+	lint-annotations	Reports unknown test IDs, malformed values, expired severity overrides and ignores without a reason in the kube-score annotations of manifests
+	completion	Prints a shell completion script for bash, zsh, fish or powershell
 	version	Print the version of kube-score
 	help	Print this message`+"\n\n", binName, binName)
 
@@ -100,7 +182,9 @@ Actions:
 	}
 }
 
-func scoreFiles(binName string, args []string) error {
+// newScoreFlagSet defines the flags of the score command. filesToRead and setFlags of the
+// returned options are set after parsing.
+func newScoreFlagSet(binName string) (*flag.FlagSet, Options) {
 	fs := flag.NewFlagSet(binName, flag.ExitOnError)
 	exitOneOnWarning := fs.Bool(
 		"exit-one-on-warning",
@@ -143,12 +227,12 @@ func scoreFiles(binName string, args []string) error {
 		"output-format",
 		"o",
 		"human",
-		"Set to 'human', 'json', 'ci' or 'sarif'. If set to ci, kube-score will output the program in a format that is easier to parse by other programs. Sarif output allows for easier integration with CI platforms.",
+		"Set to 'human', 'json', 'ci', 'csv', 'sarif', 'dot', 'mermaid' or 'events'. If set to ci, kube-score will output the program in a format that is easier to parse by other programs. The 'csv' format has one row per finding, with the file, line, kind, namespace, name, check, grade, path and summary as columns. Sarif output allows for easier integration with CI platforms. The 'dot' and 'mermaid' formats render the references between the objects, such as the Services selecting a Deployment, as a Graphviz or Mermaid graph. The 'events' format renders a 'kube-score/grade' annotation of every object and a Warning Event of the objects that fail critical checks, to be applied with 'kubectl apply --server-side'.",
 	)
 	outputVersion := fs.String(
 		"output-version",
 		"",
-		"Changes the version of the --output-format. The 'json' format has version 'v2' (default) and 'v1' (deprecated, will be removed in v1.7.0). The 'human' and 'ci' formats has only version 'v1' (default). If not explicitly set, the default version for that particular output format will be used.",
+		"Changes the version of the --output-format. The 'json' format has version 'v2' (default), 'v3' (v2 wrapped in an envelope with the metadata of the run) and 'v1' (deprecated, will be removed in v1.7.0). The 'human' and 'ci' formats has only version 'v1' (default). If not explicitly set, the default version for that particular output format will be used.",
 	)
 	color := fs.String(
 		"color",
@@ -170,6 +254,11 @@ func scoreFiles(binName string, args []string) error {
 		[]string{},
 		"skip resources that match a YAML path and regex",
 	)
+	explainSkips := fs.Bool(
+		"explain-skips",
+		false,
+		"Report which documents every --skip expression matched, and the expressions that matched no documents, to stderr",
+	)
 	disableIgnoreChecksAnnotation := fs.Bool(
 		"disable-ignore-checks-annotations",
 		false,
@@ -185,28 +274,297 @@ func scoreFiles(binName string, args []string) error {
 		false,
 		"Set to true to enable all tests",
 	)
-	kubernetesVersion := fs.String(
+	kubernetesVersion := fs.StringArray(
 		"kubernetes-version",
-		"v1.18",
-		"Setting the kubernetes-version will affect the checks ran against the manifests. Set this to the version of Kubernetes that you're using in production for the best results.",
+		[]string{"v1.18"},
+		"Setting the kubernetes-version will affect the checks ran against the manifests. Set this to the version of Kubernetes that you're using in production for the best results. Can be set multiple times to plan an upgrade, the output is scored with the first version, and the checks with different grades for the other versions are reported to stderr.",
+	)
+	serviceMesh := fs.String(
+		"service-mesh",
+		"",
+		"Set to 'istio' or 'linkerd' if the workloads are part of a service mesh. Adjusts the checks to the expectations of the injected sidecars, and enables the service-port-name check.",
+	)
+	helmHooks := fs.String(
+		"helm-hooks",
+		string(config.HelmHooksScore),
+		"How the objects with a 'helm.sh/hook' annotation are scored. Set to 'score' to score them like any other object, 'relaxed' to not expect them to be targeted by NetworkPolicies, PodDisruptionBudgets and Services, or 'skip-tests' to also not score test hooks",
+	)
+	registryAuthFile := fs.String(
+		"registry-auth-file",
+		"",
+		"Path to a file in the format of the docker config.json, with the credentials used by the container-image-exists check to access private registries.",
+	)
+	vulnerabilityScanner := fs.String(
+		"vulnerability-scanner",
+		"",
+		"Set to 'trivy' or 'grype' to scan all images with the container-image-vulnerabilities check. The scanner has to be installed and in the PATH.",
+	)
+	vulnerabilityReports := fs.StringSlice(
+		"vulnerability-report",
+		[]string{},
+		"Path to a JSON report of trivy or grype, used by the container-image-vulnerabilities check instead of invoking the scanner. Can be set multiple times",
+	)
+	plugins := fs.StringSlice(
+		"plugin",
+		[]string{},
+		"Path to an executable that implements checks with the kube-score plugin protocol. Can be set multiple times",
+	)
+	pluginDiscovery := fs.Bool(
+		"plugin-discovery",
+		false,
+		"Use all executables named '"+plugin.Prefix+"*' in the PATH as plugins.",
+	)
+	pluginTimeout := fs.Duration(
+		"plugin-timeout",
+		plugin.DefaultTimeout,
+		"The timeout of every invocation of a plugin, that scores all objects of a check.",
+	)
+	pluginMemoryLimit := fs.String(
+		"plugin-memory-limit",
+		"1Gi",
+		"The maximum virtual memory of every process of a plugin, and the maximum memory of a WebAssembly module, e.g. '512Mi'. Set to 0 for no limit",
+	)
+	pluginCPULimit := fs.Duration(
+		"plugin-cpu-limit",
+		0,
+		"The maximum CPU time of every process of a plugin, e.g. '5s'. Set to 0 for no limit",
+	)
+	pluginMaxFileSize := fs.String(
+		"plugin-max-file-size",
+		"64Mi",
+		"The maximum size of a file that a plugin writes, e.g. '1Mi'. Set to 0 for no limit",
+	)
+	pluginMaxOpenFiles := fs.Int(
+		"plugin-max-open-files",
+		256,
+		"The maximum number of files that every process of a plugin has open. Set to 0 for no limit",
+	)
+	pluginUnconfined := fs.Bool(
+		"plugin-unconfined",
+		false,
+		"Run the plugins that are executables without the sandbox and without the limits, with the access of the user. The sandbox is only supported on Linux, WebAssembly modules are always confined.",
+	)
+	pluginEnv := fs.StringSlice(
+		"plugin-env",
+		[]string{},
+		"Name of an environment variable that is passed to the plugins, which run with an otherwise empty environment. Can be set multiple times",
+	)
+	notifyWebhook := fs.String(
+		"notify-webhook",
+		"",
+		"URL of a webhook that a summary of the run and the worst offenders is posted to after scoring.",
+	)
+	notifyWebhookFormat := fs.String(
+		"notify-webhook-format",
+		"auto",
+		"Set to 'slack', 'teams' or 'json'. If set to 'auto', the format is detected from the --notify-webhook URL, and falls back to 'json'.",
+	)
+	historyDB := fs.String(
+		"history-db",
+		"",
+		"Path to a SQLite database that the results of the run are appended to. Use 'trend' to report how the grades change over time.",
+	)
+	githubStepSummary := fs.Bool(
+		"github-step-summary",
+		false,
+		"Set to true to write a Markdown summary to $GITHUB_STEP_SUMMARY, and set the critical_count, warning_count and info_count step outputs, when running in GitHub Actions.",
+	)
+	configFile := fs.String(
+		"config",
+		"",
+		"Path to a YAML configuration file. The 'checks' section sets the parameters of the checks, keyed by the check ID, e.g. the minimum user ID of container-security-context-user-group-id.",
+	)
+	environment := fs.String(
+		"environment",
+		"",
+		"Name of an environment in the 'environments' section of the --config file, whose check parameters override the top level ones, e.g. to require more replicas in production.",
+	)
+	annotationPrefixes := fs.StringSlice(
+		"annotation-prefix",
+		[]string{},
+		"Accept the ignore, enable and per-check annotations with this prefix in addition to 'kube-score', e.g. 'score.example.com' for 'score.example.com/ignore'. Can be set multiple times",
+	)
+	exitOn := fs.StringArray(
+		"exit-on",
+		[]string{},
+		"Exit with code 1 if any check matches the expression, e.g. 'namespace=prod&&grade<=warning', instead of exiting on critical grades. Conditions on namespace, kind, name, check and grade are joined with '&&' and '||'. Can be set multiple times",
+	)
+	exemptNamespaces := fs.StringSlice(
+		"exempt-namespace",
+		[]string{},
+		"Do not score the objects in this namespace, e.g. 'kube-system'. Supports the wildcards of path.Match, e.g. 'cattle-*'. Can be set multiple times",
+	)
+	includeKinds := fs.StringSlice(
+		"include-kind",
+		[]string{},
+		"Only score the objects of this kind, e.g. 'Deployment,StatefulSet'. Objects of other kinds are still used by the checks of the scored objects. Can be set multiple times",
+	)
+	excludeKinds := fs.StringSlice(
+		"exclude-kind",
+		[]string{},
+		"Do not score the objects of this kind, e.g. 'Job'. Can be set multiple times",
+	)
+	selector := fs.StringP(
+		"selector",
+		"l",
+		"",
+		"Only score the objects with matching labels, e.g. 'app.kubernetes.io/part-of=payments'. Supports '=', '==', '!=', 'in', 'notin' and existence like kubectl. Objects that are not selected are still used by the checks of the scored objects",
+	)
+	aggregateComments := fs.Bool(
+		"aggregate-comments",
+		true,
+		"Merge the identical comments of a check on multiple containers of an object into a single comment that lists all containers. Set to false to print a comment per container",
+	)
+	lang := fs.String(
+		"lang",
+		i18n.DefaultLanguage,
+		"Language of the summaries and descriptions of the checks, one of "+strings.Join(i18n.Languages(), ", ")+". Messages without a translation are printed in English",
+	)
+	summary := fs.Bool(
+		"summary",
+		true,
+		"Print a summary of the run, with the number of scored objects, the worst grade and the duration, to stderr when the output format is not 'human'. The machine-readable formats only write data to stdout",
+	)
+	progress := fs.String(
+		"progress",
+		"none",
+		"Set to 'json' to write the progress of the run to stderr as newline delimited JSON events, when a file has been parsed ('file-parsed'), when the percentage of scored objects changes ('progress'), for every scored object ('object-scored') and at the end of the scoring ('done'), which replaces the run summary. Set to 'none' to disable",
+	)
+	namespaceFor := fs.StringArray(
+		"namespace-for",
+		[]string{},
+		"Default namespace of the objects without a namespace in the files that match a pattern, on the format pattern=namespace, e.g. 'overlays/prod/**=prod-apps'. '**' matches any number of directories. Can be set multiple times, the last matching pattern is used. Overrides the namespace of a kustomization in the directory of the file",
+	)
+	fileNamespace := fs.StringArray(
+		"file-namespace",
+		[]string{},
+		"Default namespace of the objects without a namespace in a file, on the format path=namespace. Can be set multiple times. Overrides --namespace-for and the namespace of a kustomization in the directory of the file",
+	)
+	suppress := fs.StringArray(
+		"suppress",
+		[]string{},
+		"Do not report the finding with the fingerprint, as printed with each finding. Can be set multiple times",
+	)
+	suppressFile := fs.String(
+		"suppress-file",
+		"",
+		"Path to a file with the fingerprints of the findings that are not reported, one per line. Lines starting with '#' are ignored",
+	)
+	maxDocuments := fs.Int(
+		"max-documents",
+		0,
+		"Fail if the input has more documents than this, the items of a List are counted as documents. Set to 0 for no limit",
+	)
+	maxDocumentSize := fs.String(
+		"max-document-size",
+		"",
+		"Fail if a document of the input is larger than this, e.g. '1Mi'. Not limited if empty",
+	)
+	maxInputSize := fs.String(
+		"max-input-size",
+		"",
+		"Fail if all files of the input are larger than this together, e.g. '100Mi'. Not limited if empty",
+	)
+	parseTimeout := fs.Duration(
+		"parse-timeout",
+		0,
+		"Fail if parsing the input takes longer than this, e.g. '30s'. Set to 0 for no limit",
 	)
 	setDefault(fs, binName, "score", false)
 
+	return fs, Options{
+		nil,
+		exitOneOnWarning,
+		skipInitContainers,
+		skipJobs,
+		namespace,
+		ignoreContainerCpuLimit,
+		ignoreContainerMemoryLimit,
+		verboseOutput,
+		printHelp,
+		outputFormat,
+		outputVersion,
+		color,
+		optionalTests,
+		ignoreTests,
+		skipExpressions,
+		explainSkips,
+		disableIgnoreChecksAnnotation,
+		disableOptionalChecksAnnotation,
+		allDefaultOptional,
+		kubernetesVersion,
+		serviceMesh,
+		helmHooks,
+		registryAuthFile,
+		vulnerabilityScanner,
+		vulnerabilityReports,
+		plugins,
+		pluginDiscovery,
+		pluginTimeout,
+		pluginEnv,
+		pluginMemoryLimit,
+		pluginCPULimit,
+		pluginMaxFileSize,
+		pluginMaxOpenFiles,
+		pluginUnconfined,
+		notifyWebhook,
+		notifyWebhookFormat,
+		historyDB,
+		githubStepSummary,
+		configFile,
+		environment,
+		annotationPrefixes,
+		exitOn,
+		exemptNamespaces,
+		includeKinds,
+		excludeKinds,
+		selector,
+		aggregateComments,
+		lang,
+		summary,
+		progress,
+		namespaceFor,
+		fileNamespace,
+		suppress,
+		suppressFile,
+		maxDocuments,
+		maxDocumentSize,
+		maxInputSize,
+		parseTimeout,
+		nil,
+	}
+}
+
+func scoreFiles(binName string, args []string) error {
+	fs, opts := newScoreFlagSet(binName)
+
 	err := fs.Parse(args)
 	if err != nil {
 		return fmt.Errorf("failed to parse files: %w", err)
 	}
 
-	if *printHelp {
+	if *opts.printHelp {
 		fs.Usage()
 		return nil
 	}
 
-	if *outputFormat != "human" && *outputFormat != "ci" && *outputFormat != "json" &&
-		*outputFormat != "sarif" {
+	// The flags that have been set explicitly, recorded in the metadata of the output
+	setFlags := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		if redactedFlags[f.Name] {
+			setFlags[f.Name] = "REDACTED"
+			return
+		}
+		setFlags[f.Name] = f.Value.String()
+	})
+
+	if *opts.outputFormat != "human" && *opts.outputFormat != "ci" && *opts.outputFormat != "csv" &&
+		*opts.outputFormat != "json" &&
+		*opts.outputFormat != "sarif" && *opts.outputFormat != "dot" && *opts.outputFormat != "mermaid" &&
+		*opts.outputFormat != "events" {
 		fs.Usage()
 		return fmt.Errorf(
-			"--output-format must be set to: 'human', 'json', 'sarif', or 'ci'",
+			"--output-format must be set to: 'human', 'json', 'sarif', 'ci', 'csv', 'dot', 'mermaid' or 'events'",
 		)
 	}
 
@@ -215,11 +573,16 @@ func scoreFiles(binName string, args []string) error {
 		"always": true,
 		"never":  true,
 	}
-	if !acceptedColors[*color] {
+	if !acceptedColors[*opts.color] {
 		fs.Usage()
 		return fmt.Errorf("--color must be set to: 'auto', 'always' or 'never'")
 	}
 
+	if *opts.progress != "none" && *opts.progress != "json" {
+		fs.Usage()
+		return fmt.Errorf("--progress must be set to: 'none' or 'json'")
+	}
+
 	filesToRead := fs.Args()
 	if len(filesToRead) == 0 {
 		fmt.Fprintf(os.Stderr, `no files given as arguments.
@@ -230,27 +593,9 @@ Use "-" as filename to read from STDIN.`, execName(binName))
 		return fmt.Errorf("no files given")
 	}
 
-	return run(Options{
-		filesToRead,
-		exitOneOnWarning,
-		skipInitContainers,
-		skipJobs,
-		namespace,
-		ignoreContainerCpuLimit,
-		ignoreContainerMemoryLimit,
-		verboseOutput,
-		printHelp,
-		outputFormat,
-		outputVersion,
-		color,
-		optionalTests,
-		ignoreTests,
-		skipExpressions,
-		disableIgnoreChecksAnnotation,
-		disableOptionalChecksAnnotation,
-		allDefaultOptional,
-		kubernetesVersion,
-	})
+	opts.filesToRead = filesToRead
+	opts.setFlags = setFlags
+	return run(opts)
 }
 
 type Options struct {
@@ -269,14 +614,73 @@ type Options struct {
 	optionalTests                   *[]string
 	ignoreTests                     *[]string
 	skipExpressions                 *[]string
+	explainSkips                    *bool
 	disableIgnoreChecksAnnotation   *bool
 	disableOptionalChecksAnnotation *bool
 	allDefaultOptional              *bool
-	kubernetesVersion               *string
+	kubernetesVersion               *[]string
+	serviceMesh                     *string
+	helmHooks                       *string
+	registryAuthFile                *string
+	vulnerabilityScanner            *string
+	vulnerabilityReports            *[]string
+	plugins                         *[]string
+	pluginDiscovery                 *bool
+	pluginTimeout                   *time.Duration
+	pluginEnv                       *[]string
+	pluginMemoryLimit               *string
+	pluginCPULimit                  *time.Duration
+	pluginMaxFileSize               *string
+	pluginMaxOpenFiles              *int
+	pluginUnconfined                *bool
+	notifyWebhook                   *string
+	notifyWebhookFormat             *string
+	historyDB                       *string
+	githubStepSummary               *bool
+	configFile                      *string
+	environment                     *string
+	annotationPrefixes              *[]string
+	exitOn                          *[]string
+	exemptNamespaces                *[]string
+	includeKinds                    *[]string
+	excludeKinds                    *[]string
+	selector                        *string
+	aggregateComments               *bool
+	lang                            *string
+	summary                         *bool
+	progress                        *string
+	namespaceFor                    *[]string
+	fileNamespace                   *[]string
+	suppress                        *[]string
+	suppressFile                    *string
+	maxDocuments                    *int
+	maxDocumentSize                 *string
+	maxInputSize                    *string
+	parseTimeout                    *time.Duration
+	setFlags                        map[string]string
+}
+
+// Flags with values that may contain secrets, and are not included in the output
+var redactedFlags = map[string]bool{
+	"notify-webhook": true,
 }
 
 func run(opts Options) error {
+	ctx := context.Background()
+
+	shutdownTelemetry, err := setupTelemetry(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to setup telemetry: %w", err)
+	}
+	defer func() { _ = shutdownTelemetry(ctx) }()
+
+	ctx, runSpan := tracer.Start(ctx, "run")
+	defer runSpan.End()
+
+	startTime := time.Now()
+
 	var allFilePointers []ks.NamedReader
+	var inputs []*metadata.Input
 
 	for _, file := range opts.filesToRead {
 		var fp io.Reader
@@ -293,6 +697,8 @@ func run(opts Options) error {
 			}
 			filename, _ = filepath.Abs(file)
 		}
+		input, fp := metadata.NewInput(filename, fp)
+		inputs = append(inputs, input)
 		allFilePointers = append(
 			allFilePointers,
 			namedReader{Reader: fp, name: filename},
@@ -304,14 +710,97 @@ func run(opts Options) error {
 	// return errors.New("Invalid argument combination. --all-default-optional and --ignore-tests cannot be used together")
 	// }
 
+	var kubeVers []config.Semver
+	for _, rawVer := range *opts.kubernetesVersion {
+		kubeVer, err := config.ParseSemver(rawVer)
+		if err != nil {
+			return errors.New("invalid --kubernetes-version. Use on format \"vN.NN\"")
+		}
+		kubeVers = append(kubeVers, kubeVer)
+	}
+	if len(kubeVers) == 0 {
+		return errors.New("invalid --kubernetes-version. Use on format \"vN.NN\"")
+	}
+	kubeVer := kubeVers[0]
+
+	serviceMesh, err := config.ParseServiceMesh(*opts.serviceMesh)
+	if err != nil {
+		return errors.New("invalid --service-mesh. Use one of 'istio' or 'linkerd'")
+	}
+
+	helmHooks, err := config.ParseHelmHooks(*opts.helmHooks)
+	if err != nil {
+		return errors.New("invalid --helm-hooks. Use one of 'score', 'relaxed' or 'skip-tests'")
+	}
+
+	var registryCredentials map[string]config.RegistryCredential
+	if *opts.registryAuthFile != "" {
+		registryCredentials, err = config.LoadRegistryCredentials(*opts.registryAuthFile)
+		if err != nil {
+			return fmt.Errorf("invalid --registry-auth-file: %w", err)
+		}
+	}
+
+	configFile := &config.File{}
+	if *opts.configFile != "" {
+		configFile, err = config.LoadFile(*opts.configFile)
+		if err != nil {
+			return fmt.Errorf("invalid --config: %w", err)
+		}
+	}
+	checkParameters, err := configFile.CheckParametersFor(*opts.environment)
+	if err != nil {
+		return fmt.Errorf("invalid --environment: %w", err)
+	}
+
+	// The groups of the config file are expanded before the old IDs of renamed checks are replaced
+	*opts.ignoreTests, err = configFile.Groups.Expand(*opts.ignoreTests)
+	if err != nil {
+		return fmt.Errorf("invalid --ignore-test: %w", err)
+	}
+	*opts.optionalTests, err = configFile.Groups.Expand(*opts.optionalTests)
+	if err != nil {
+		return fmt.Errorf("invalid --enable-optional-test: %w", err)
+	}
+	aliases := checks.Aliases()
+	*opts.ignoreTests = canonicalCheckIDs("ignore-test", *opts.ignoreTests, aliases, os.Stderr)
+	*opts.optionalTests = canonicalCheckIDs("enable-optional-test", *opts.optionalTests, aliases, os.Stderr)
 	ignoredTests := listToStructMap(opts.ignoreTests)
 	enabledOptionalTests := listToStructMap(opts.optionalTests)
+	if serviceMesh != config.ServiceMeshNone {
+		// The service mesh relies on the protocol of the port names
+		enabledOptionalTests["service-port-name"] = struct{}{}
+	}
 
 	checkConfig := checks.Config{IgnoredTests: ignoredTests}
 
-	kubeVer, err := config.ParseSemver(*opts.kubernetesVersion)
+	switch *opts.vulnerabilityScanner {
+	case "", image.ScannerTrivy, image.ScannerGrype:
+	default:
+		return errors.New("invalid --vulnerability-scanner. Use one of 'trivy' or 'grype'")
+	}
+
+	pluginPaths := *opts.plugins
+	if *opts.pluginDiscovery {
+		pluginPaths = append(pluginPaths, plugin.Discover(os.Getenv("PATH"))...)
+	}
+	pluginLimits, err := pluginLimits(*opts.pluginMemoryLimit, *opts.pluginCPULimit, *opts.pluginMaxFileSize, *opts.pluginMaxOpenFiles)
 	if err != nil {
-		return errors.New("invalid --kubernetes-version. Use on format \"vN.NN\"")
+		return err
+	}
+	plugins, err := plugin.Load(pluginPaths, plugin.Options{
+		Timeout:    *opts.pluginTimeout,
+		Env:        *opts.pluginEnv,
+		Limits:     pluginLimits,
+		Unconfined: *opts.pluginUnconfined,
+	})
+	if err != nil {
+		return fmt.Errorf("invalid --plugin: %w", err)
+	}
+
+	webhookFormat, err := notify.ParseFormat(*opts.notifyWebhookFormat)
+	if err != nil {
+		return errors.New("invalid --notify-webhook-format. Use one of 'auto', 'slack', 'teams' or 'json'")
 	}
 
 	var skipExpressions []*config.SkipExpression
@@ -323,6 +812,46 @@ func run(opts Options) error {
 		skipExpressions = append(skipExpressions, skipExpr)
 	}
 
+	threshold := scorecard.GradeCritical
+	if *opts.exitOneOnWarning {
+		threshold = scorecard.GradeWarning
+	}
+	policy, err := scorecard.NewPolicy(threshold, configFile.Thresholds, checks.Categories)
+	if err != nil {
+		return fmt.Errorf("invalid thresholds in --config: %w", err)
+	}
+
+	var exitOn []scorecard.Query
+	for _, rawQuery := range *opts.exitOn {
+		q, err := scorecard.ParseQuery(rawQuery)
+		if err != nil {
+			return fmt.Errorf("invalid --exit-on: %w", err)
+		}
+		exitOn = append(exitOn, q)
+	}
+
+	catalog, err := i18n.Load(*opts.lang)
+	if err != nil {
+		return fmt.Errorf("invalid --lang: %w", err)
+	}
+
+	suppressions := listToStructMap(opts.suppress)
+	if *opts.suppressFile != "" {
+		fingerprints, err := loadSuppressions(*opts.suppressFile)
+		if err != nil {
+			return fmt.Errorf("invalid --suppress-file: %w", err)
+		}
+		for _, f := range fingerprints {
+			suppressions[f] = struct{}{}
+		}
+	}
+
+	for _, pattern := range *opts.exemptNamespaces {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid --exempt-namespace %q: %w", pattern, err)
+		}
+	}
+
 	runConfig := &config.RunConfiguration{
 		Namespace:                             *opts.namespace,
 		SkipInitContainers:                    *opts.skipInitContainers,
@@ -333,47 +862,160 @@ func run(opts Options) error {
 		UseIgnoreChecksAnnotation:             !*opts.disableIgnoreChecksAnnotation,
 		UseOptionalChecksAnnotation:           !*opts.disableOptionalChecksAnnotation,
 		KubernetesVersion:                     kubeVer,
+		ServiceMesh:                           serviceMesh,
+		HelmHooks:                             helmHooks,
+		RegistryCredentials:                   registryCredentials,
+		VulnerabilityScanner:                  *opts.vulnerabilityScanner,
+		VulnerabilityReports:                  *opts.vulnerabilityReports,
+		CheckParameters:                       checkParameters,
+		AnnotationPrefixes:                    append(configFile.AnnotationPrefixes, *opts.annotationPrefixes...),
+		Messages:                              configFile.Messages,
+		ExemptNamespaces:                      *opts.exemptNamespaces,
+		IncludeKinds:                          *opts.includeKinds,
+		ExcludeKinds:                          *opts.excludeKinds,
+		Selector:                              *opts.selector,
+		AggregateComments:                     *opts.aggregateComments,
+		Suppressions:                          suppressions,
+		CheckGroups:                           configFile.Groups,
 	}
 
-	if *opts.allDefaultOptional {
-		for _, c := range score.RegisterAllChecks(parser.Empty(), &checkConfig, runConfig).All() {
-			if c.Optional {
-				if _, ok := ignoredTests[c.ID]; !ok {
-					enabledOptionalTests[c.ID] = struct{}{}
-				}
-			}
-		}
+	namespaces, err := fileNamespaces(opts.filesToRead, *opts.namespaceFor, *opts.fileNamespace)
+	if err != nil {
+		return err
+	}
+
+	limits, err := parserLimits(*opts.maxDocuments, *opts.maxDocumentSize, *opts.maxInputSize, *opts.parseTimeout)
+	if err != nil {
+		return err
 	}
-	p, err := parser.New(&parser.Config{
+
+	var progress *progressReporter
+	if *opts.progress == "json" {
+		progress = newProgressReporter(os.Stderr, startTime)
+	}
+
+	parserConfig := &parser.Config{
 		VerboseOutput:   *opts.verboseOutput,
 		SkipExpressions: skipExpressions,
-	})
+		FileNamespaces:  namespaces,
+		KeepDocuments:   len(plugins) > 0,
+		Limits:          limits,
+	}
+	if progress != nil {
+		parserConfig.FileParsed = progress.fileParsed
+	}
+	var skips *skipExplainer
+	if *opts.explainSkips {
+		skips = newSkipExplainer(skipExpressions)
+		parserConfig.SkippedByExpression = skips.skipped
+	}
+	p, err := parser.New(parserConfig)
 	if err != nil {
 		return fmt.Errorf("failed to initializer parser: %w", err)
 	}
 
+	_, parseSpan := tracer.Start(ctx, "parse")
 	parsedFiles, err := p.ParseFiles(allFilePointers)
+	parseSpan.End()
 	if err != nil {
 		return fmt.Errorf("failed to parse files: %w", err)
 	}
+	for _, input := range inputs {
+		input.Finish()
+	}
+	if skips != nil {
+		skips.write(os.Stderr)
+	}
 
-	checks := score.RegisterAllChecks(parsedFiles, &checkConfig, runConfig)
+	scoreVersion := func(runConfig *config.RunConfiguration, hooks *score.Hooks) (*scorecard.Scorecard, error) {
+		checks := score.RegisterAllChecks(&checkConfig, runConfig)
+		if err := plugin.Register(checks, plugins); err != nil {
+			return nil, err
+		}
+		if err := checks.Err(); err != nil {
+			return nil, fmt.Errorf("invalid checks: %w", err)
+		}
+		if *opts.allDefaultOptional {
+			// The optional checks are enabled when scoring, so the checks are only registered once
+			for _, c := range checks.All() {
+				if _, ok := ignoredTests[c.ID]; c.Optional && !ok {
+					enabledOptionalTests[c.ID] = struct{}{}
+				}
+			}
+		}
+		return score.ScoreWithHooks(parsedFiles, checks, runConfig, hooks)
+	}
 
-	scoreCard, err := score.Score(parsedFiles, checks, runConfig)
+	_, scoreSpan := tracer.Start(ctx, "score")
+	var hooks *score.Hooks
+	if progress != nil {
+		hooks = progress.hooks()
+	}
+	scoreCard, err := scoreVersion(runConfig, hooks)
+	scoreSpan.End()
 	if err != nil {
 		return err
 	}
+	if len(kubeVers) > 1 {
+		// The other versions are only compared to the first one, and are not in the output
+		matrix := &versionMatrix{}
+		matrix.add(kubeVer, scoreCard)
+		for _, otherVer := range kubeVers[1:] {
+			otherConfig := *runConfig
+			otherConfig.KubernetesVersion = otherVer
+			otherCard, err := scoreVersion(&otherConfig, nil)
+			if err != nil {
+				return err
+			}
+			matrix.add(otherVer, otherCard)
+		}
+		matrix.write(os.Stderr)
+	}
+	if progress != nil {
+		progress.done(scoreCard, time.Now())
+	}
+	i18n.Translate(scoreCard, catalog)
+
+	if err := recordScorecardMetrics(ctx, scoreCard); err != nil {
+		return fmt.Errorf("failed to record metrics: %w", err)
+	}
+
+	if *opts.historyDB != "" {
+		if err := appendHistory(ctx, *opts.historyDB, scoreCard); err != nil {
+			return fmt.Errorf("failed to append to history database: %w", err)
+		}
+	}
+
+	if *opts.githubStepSummary && os.Getenv("GITHUB_ACTIONS") == "true" {
+		if err := writeGitHubSummary(scoreCard); err != nil {
+			return fmt.Errorf("failed to write GitHub step summary: %w", err)
+		}
+	}
+
+	if *opts.notifyWebhook != "" {
+		client := &http.Client{Timeout: 30 * time.Second}
+		err := notify.Send(ctx, client, *opts.notifyWebhook, webhookFormat, scoreCard)
+		if err != nil {
+			return fmt.Errorf("failed to notify webhook: %w", err)
+		}
+	}
 
 	var exitCode int
 	switch {
-	case scoreCard.AnyBelowOrEqualToGrade(scorecard.GradeCritical):
-		exitCode = 1
-	case *opts.exitOneOnWarning && scoreCard.AnyBelowOrEqualToGrade(scorecard.GradeWarning):
+	case len(exitOn) > 0:
+		for _, q := range exitOn {
+			if scoreCard.AnyMatches(q) {
+				exitCode = 1
+			}
+		}
+	case policy.Evaluate(*scoreCard):
 		exitCode = 1
 	default:
 		exitCode = 0
 	}
 
+	_, renderSpan := tracer.Start(ctx, "render")
+
 	var r io.Reader
 
 	version := getOutputVersion(*opts.outputVersion, *opts.outputFormat)
@@ -386,6 +1028,8 @@ func run(opts Options) error {
 		r = w
 	case *opts.outputFormat == "json" && version == "v2":
 		r = json_v2.Output(scoreCard)
+	case *opts.outputFormat == "json" && version == "v3":
+		r = json_v3.Output(scoreCard, runMetadata(opts, kubeVer, inputs, startTime))
 	case *opts.outputFormat == "human" && version == "v1":
 		termWidth, _, err := term.GetSize(int(os.Stdin.Fd()))
 		// Assume a width of 80 if it can't be detected
@@ -399,22 +1043,64 @@ func run(opts Options) error {
 			useColor(*opts.color),
 		)
 		if err != nil {
+			renderSpan.End()
 			return err
 		}
 	case *opts.outputFormat == "ci" && version == "v1":
 		r = ci.CI(scoreCard)
+	case *opts.outputFormat == "csv":
+		r = csvrenderer.CSV(scoreCard)
 	case *opts.outputFormat == "sarif":
-		r = sarif.Output(scoreCard)
+		r = sarif.OutputWithMetadata(scoreCard, runMetadata(opts, kubeVer, inputs, startTime), configFile.Sarif)
+	case *opts.outputFormat == "dot":
+		r = dot.Dot(graph.Build(parsedFiles, *opts.namespace))
+	case *opts.outputFormat == "mermaid":
+		r = mermaid.Mermaid(graph.Build(parsedFiles, *opts.namespace))
+	case *opts.outputFormat == "events":
+		r = events.Events(scoreCard, time.Now())
 	default:
+		renderSpan.End()
 		return fmt.Errorf("error: Unknown --output-format or --output-version")
 	}
 
 	output, _ := io.ReadAll(r)
+	renderSpan.End()
 	fmt.Print(string(output))
+
+	// The done event of --progress json reports the summary, so that stderr only has JSON events
+	if *opts.summary && *opts.outputFormat != "human" && progress == nil {
+		writeRunSummary(os.Stderr, scoreCard, time.Since(startTime))
+	}
+
+	// Deferred functions are not run by os.Exit
+	runSpan.End()
+	_ = shutdownTelemetry(ctx)
+
 	os.Exit(exitCode)
 	return nil
 }
 
+func runMetadata(
+	opts Options,
+	kubeVer config.Semver,
+	inputs []*metadata.Input,
+	startTime time.Time,
+) *metadata.Metadata {
+	return &metadata.Metadata{
+		Tool: metadata.Tool{
+			Name:    "kube-score",
+			Version: version,
+			Commit:  commit,
+			Date:    date,
+		},
+		Flags:             opts.setFlags,
+		KubernetesVersion: fmt.Sprintf("v%d.%d", kubeVer.Major, kubeVer.Minor),
+		Inputs:            inputs,
+		StartTime:         startTime.UTC(),
+		EndTime:           time.Now().UTC(),
+	}
+}
+
 func getOutputVersion(flagValue, format string) string {
 	if len(flagValue) > 0 {
 		return flagValue
@@ -431,6 +1117,19 @@ func getOutputVersion(flagValue, format string) string {
 func listChecks(binName string, args []string) error {
 	fs := flag.NewFlagSet(binName, flag.ExitOnError)
 	printHelp := fs.Bool("help", false, "Print help")
+	configFile := fs.String(
+		"config",
+		"",
+		"Path to a YAML configuration file, its thresholds are used to list if the checks change the exit code",
+	)
+	exitOneOnWarning := fs.Bool(
+		"exit-one-on-warning",
+		false,
+		"List the checks that change the exit code when exiting with code 1 in case of warnings",
+	)
+	optionalTests := fs.StringSlice("enable-optional-test", []string{}, "List an optional test as enabled, can be set multiple times")
+	ignoreTests := fs.StringSlice("ignore-test", []string{}, "List a test as disabled, can be set multiple times")
+	allDefaultOptional := fs.Bool("all-default-optional", false, "List all tests as enabled")
 	setDefault(fs, binName, "list", false)
 	err := fs.Parse(args)
 	if err != nil {
@@ -442,22 +1141,62 @@ func listChecks(binName string, args []string) error {
 		return nil
 	}
 
-	allChecks := score.RegisterAllChecks(parser.Empty(), nil, nil)
+	file := &config.File{}
+	if *configFile != "" {
+		file, err = config.LoadFile(*configFile)
+		if err != nil {
+			return fmt.Errorf("invalid --config: %w", err)
+		}
+	}
+	threshold := scorecard.GradeCritical
+	if *exitOneOnWarning {
+		threshold = scorecard.GradeWarning
+	}
+	policy, err := scorecard.NewPolicy(threshold, file.Thresholds, checks.Categories)
+	if err != nil {
+		return fmt.Errorf("invalid thresholds in --config: %w", err)
+	}
+
+	ignored := listToStructMap(ignoreTests)
+	enabledOptional := listToStructMap(optionalTests)
+	enabled := func(c ks.Check) bool {
+		if _, ok := ignored[c.ID]; ok {
+			return false
+		}
+		_, ok := enabledOptional[c.ID]
+		return !c.Optional || ok || *allDefaultOptional
+	}
 
-	output := csv.NewWriter(os.Stdout)
-	for _, c := range allChecks.All() {
+	return writeCheckList(os.Stdout, score.AllChecks(), policy, enabled)
+}
+
+// writeCheckList writes the checks as CSV records of the ID, target type, comment, if the check is
+// optional, the category, the grade of the check when it fails, and if a failure of the check
+// changes the exit code. Disabled checks never change the exit code.
+func writeCheckList(w io.Writer, all []ks.Check, policy scorecard.Policy, enabled func(ks.Check) bool) error {
+	output := csv.NewWriter(w)
+	for _, c := range all {
 		optionalString := "default"
 		if c.Optional {
 			optionalString = "optional"
 		}
-		err := output.Write([]string{c.ID, c.TargetType, c.Comment, optionalString})
+		grade := checks.DefaultGrade(c.ID)
+		exits := enabled(c) && policy.Fails(scorecard.TestScore{Check: c, Grade: grade})
+		err := output.Write([]string{
+			c.ID,
+			c.TargetType,
+			c.Comment,
+			optionalString,
+			c.Category,
+			strings.ToLower(grade.String()),
+			strconv.FormatBool(exits),
+		})
 		if err != nil {
-			return nil
+			return err
 		}
 	}
 	output.Flush()
-
-	return nil
+	return output.Error()
 }
 
 func listToStructMap(items *[]string) map[string]struct{} {
@@ -468,6 +1207,24 @@ func listToStructMap(items *[]string) map[string]struct{} {
 	return structMap
 }
 
+// loadSuppressions reads the fingerprints of a file, one per line. Empty lines and lines starting
+// with '#' are ignored, and anything after the fingerprint on a line is a comment.
+func loadSuppressions(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fingerprints []string
+	for line := range strings.Lines(string(data)) {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		fingerprints = append(fingerprints, fields[0])
+	}
+	return fingerprints, nil
+}
+
 type namedReader struct {
 	io.Reader
 	name string
@@ -507,3 +1264,51 @@ func useColor(colorArg string) bool {
 	// Use colors
 	return true
 }
+
+func writeGitHubSummary(scoreCard *scorecard.Scorecard) error {
+	env := github.Environment{
+		ServerURL:  os.Getenv("GITHUB_SERVER_URL"),
+		Repository: os.Getenv("GITHUB_REPOSITORY"),
+		SHA:        os.Getenv("GITHUB_SHA"),
+		Workspace:  os.Getenv("GITHUB_WORKSPACE"),
+	}
+	if path := os.Getenv("GITHUB_STEP_SUMMARY"); path != "" {
+		if err := appendToFile(path, github.Summary(scoreCard, env)); err != nil {
+			return err
+		}
+	}
+	if path := os.Getenv("GITHUB_OUTPUT"); path != "" {
+		if err := appendToFile(path, github.Outputs(scoreCard)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeRunSummary writes a single line summary of the run, so that it is visible when the
+// machine-readable output is redirected to a file
+func writeRunSummary(w io.Writer, scoreCard *scorecard.Scorecard, duration time.Duration) {
+	worst := "none"
+	if grade, ok := scoreCard.WorstGrade(); ok {
+		worst = grade.String()
+	}
+	_, _ = fmt.Fprintf(
+		w,
+		"kube-score: scored %d objects in %s, worst grade: %s\n",
+		len(*scoreCard),
+		duration.Round(time.Millisecond),
+		worst,
+	)
+}
+
+func appendToFile(path string, r io.Reader) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// kustomizationFileNames are the names of the kustomization files that kustomize reads
+var kustomizationFileNames = []string{"kustomization.yaml", "kustomization.yml", "Kustomization"}
+
+// fileNamespaces returns the default namespace of the objects of each file by the absolute name
+// of the file. The namespace of a kustomization in the directory of a file is used, unless the
+// file matches a mapping, given as "pattern=namespace", or has a namespace in overrides, given as
+// "path=namespace". The last matching mapping is used.
+func fileNamespaces(files []string, mappings []string, overrides []string) (map[string]string, error) {
+	type mapping struct{ pattern, namespace string }
+	var patterns []mapping
+	for _, m := range mappings {
+		pattern, namespace, ok := strings.Cut(m, "=")
+		if !ok || pattern == "" || namespace == "" {
+			return nil, fmt.Errorf("invalid --namespace-for %q, must be on the format pattern=namespace", m)
+		}
+		for _, element := range strings.Split(pattern, "/") {
+			if _, err := path.Match(element, ""); err != nil {
+				return nil, fmt.Errorf("invalid --namespace-for %q: %w", m, err)
+			}
+		}
+		patterns = append(patterns, mapping{pattern: pattern, namespace: namespace})
+	}
+
+	namespaces := make(map[string]string)
+	for _, file := range files {
+		if file == "-" {
+			continue
+		}
+		filename, err := filepath.Abs(file)
+		if err != nil {
+			return nil, err
+		}
+		namespace, err := kustomizationNamespace(filepath.Dir(filename))
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range patterns {
+			if matchPath(m.pattern, file) || matchPath(m.pattern, filename) {
+				namespace = m.namespace
+			}
+		}
+		if namespace != "" {
+			namespaces[filename] = namespace
+		}
+	}
+
+	for _, override := range overrides {
+		file, namespace, ok := strings.Cut(override, "=")
+		if !ok || file == "" || namespace == "" {
+			return nil, fmt.Errorf("invalid --file-namespace %q, must be on the format path=namespace", override)
+		}
+		filename, err := filepath.Abs(file)
+		if err != nil {
+			return nil, err
+		}
+		namespaces[filename] = namespace
+	}
+	return namespaces, nil
+}
+
+// matchPath reports whether the path matches the pattern. The pattern has the syntax of
+// path.Match for each element of the path, and a "**" element matches any number of elements.
+func matchPath(pattern, name string) bool {
+	return matchElements(
+		strings.Split(path.Clean(pattern), "/"),
+		strings.Split(path.Clean(filepath.ToSlash(name)), "/"),
+	)
+}
+
+func matchElements(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := len(name); i >= 0; i-- {
+				if matchElements(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if matched, _ := path.Match(pattern[0], name[0]); !matched {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// kustomizationNamespace returns the namespace of the kustomization in the directory, or an empty
+// string if there is no kustomization or it does not set a namespace
+func kustomizationNamespace(dir string) (string, error) {
+	for _, name := range kustomizationFileNames {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		var kustomization struct {
+			Namespace string `yaml:"namespace"`
+		}
+		if err := yaml.Unmarshal(data, &kustomization); err != nil {
+			return "", fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, name), err)
+		}
+		return kustomization.Namespace, nil
+	}
+	return "", nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileNamespaces(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	overlay := filepath.Join(dir, "overlay")
+	assert.NoError(t, os.Mkdir(overlay, 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(overlay, "kustomization.yaml"), []byte("namespace: app\nresources:\n- ../base\n"), 0o600))
+
+	deployment := filepath.Join(overlay, "deployment.yaml")
+	service := filepath.Join(overlay, "service.yaml")
+	other := filepath.Join(dir, "other.yaml")
+
+	namespaces, err := fileNamespaces([]string{deployment, service, other, "-"}, nil, []string{service + "=web"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{deployment: "app", service: "web"}, namespaces)
+
+	_, err = fileNamespaces(nil, nil, []string{"service.yaml"})
+	assert.EqualError(t, err, `invalid --file-namespace "service.yaml", must be on the format path=namespace`)
+}
+
+func TestFileNamespacesMapping(t *testing.T) {
+	t.Parallel()
+	files := []string{
+		"overlays/prod/deployment.yaml",
+		"overlays/prod/eu/service.yaml",
+		"overlays/staging/deployment.yaml",
+		"base/deployment.yaml",
+	}
+	namespaces, err := fileNamespaces(files, []string{
+		"overlays/**=apps",
+		"overlays/prod/**=prod-apps",
+		"**/service.yaml=web",
+	}, nil)
+	assert.NoError(t, err)
+
+	abs := func(file string) string {
+		filename, err := filepath.Abs(file)
+		assert.NoError(t, err)
+		return filename
+	}
+	assert.Equal(t, map[string]string{
+		abs("overlays/prod/deployment.yaml"):    "prod-apps",
+		abs("overlays/prod/eu/service.yaml"):    "web",
+		abs("overlays/staging/deployment.yaml"): "apps",
+	}, namespaces)
+
+	_, err = fileNamespaces(nil, []string{"overlays/[prod/**=prod"}, nil)
+	assert.EqualError(t, err, `invalid --namespace-for "overlays/[prod/**=prod": syntax error in pattern`)
+	_, err = fileNamespaces(nil, []string{"overlays/**"}, nil)
+	assert.EqualError(t, err, `invalid --namespace-for "overlays/**", must be on the format pattern=namespace`)
+}
+
+func TestMatchPath(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		pattern, name string
+		expected      bool
+	}{
+		{"overlays/prod/**", "overlays/prod/deployment.yaml", true},
+		{"overlays/prod/**", "overlays/prod/eu/deployment.yaml", true},
+		{"overlays/prod/**", "overlays/staging/deployment.yaml", false},
+		{"overlays/*/deployment.yaml", "overlays/prod/deployment.yaml", true},
+		{"overlays/*/deployment.yaml", "overlays/prod/eu/deployment.yaml", false},
+		{"**/deployment.yaml", "deployment.yaml", true},
+		{"./overlays/**", "overlays/prod/deployment.yaml", true},
+		{"overlays/**/eu/*.yaml", "overlays/prod/eu/service.yaml", true},
+		{"overlays/**/eu/*.yaml", "overlays/prod/us/service.yaml", false},
+	} {
+		assert.Equal(t, tc.expected, matchPath(tc.pattern, tc.name), "%s %s", tc.pattern, tc.name)
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/romnn/kube-score/score"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// progressReporter writes the progress of a run as newline delimited JSON events, so that wrappers
+// can display the progress of runs with many objects
+type progressReporter struct {
+	enc     *json.Encoder
+	start   time.Time
+	percent int
+}
+
+type fileParsedEvent struct {
+	Event     string `json:"event"`
+	File      string `json:"file"`
+	Documents int    `json:"documents"`
+}
+
+type progressEvent struct {
+	Event   string `json:"event"`
+	Done    int    `json:"done"`
+	Total   int    `json:"total"`
+	Percent int    `json:"percent"`
+}
+
+type objectScoredEvent struct {
+	Event     string `json:"event"`
+	ObjectID  string `json:"object_id"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Grade     string `json:"grade,omitempty"`
+}
+
+type doneEvent struct {
+	Event      string `json:"event"`
+	Objects    int    `json:"objects"`
+	DurationMs int64  `json:"duration_ms"`
+	WorstGrade string `json:"worst_grade,omitempty"`
+}
+
+func newProgressReporter(w io.Writer, start time.Time) *progressReporter {
+	return &progressReporter{enc: json.NewEncoder(w), start: start, percent: -1}
+}
+
+func (p *progressReporter) write(event any) {
+	_ = p.enc.Encode(event)
+}
+
+// fileParsed is the parser.Config.FileParsed callback
+func (p *progressReporter) fileParsed(name string, documents int) {
+	p.write(fileParsedEvent{Event: "file-parsed", File: name, Documents: documents})
+}
+
+// hooks reports the progress of the scoring, the progress is only reported when the percentage
+// changes, so that runs with thousands of objects write at most 101 progress events
+func (p *progressReporter) hooks() *score.Hooks {
+	return &score.Hooks{
+		Progress: func(done, total int) {
+			percent := 100
+			if total > 0 {
+				percent = done * 100 / total
+			}
+			if percent == p.percent {
+				return
+			}
+			p.percent = percent
+			p.write(progressEvent{Event: "progress", Done: done, Total: total, Percent: percent})
+		},
+		AfterObject: func(o *scorecard.ScoredObject) error {
+			e := objectScoredEvent{
+				Event:     "object-scored",
+				ObjectID:  o.ID(),
+				Kind:      o.TypeMeta.Kind,
+				Namespace: o.ObjectMeta.Namespace,
+				Name:      o.ObjectMeta.Name,
+			}
+			if grade, ok := o.WorstGrade(); ok {
+				e.Grade = strings.ToLower(grade.String())
+			}
+			p.write(e)
+			return nil
+		},
+	}
+}
+
+// done reports the end of the scoring, it replaces the run summary of --summary
+func (p *progressReporter) done(scoreCard *scorecard.Scorecard, now time.Time) {
+	e := doneEvent{Event: "done", Objects: len(*scoreCard), DurationMs: now.Sub(p.start).Milliseconds()}
+	if grade, ok := scoreCard.WorstGrade(); ok {
+		e.WorstGrade = strings.ToLower(grade.String())
+	}
+	p.write(e)
+}
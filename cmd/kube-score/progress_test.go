@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func TestProgressReporter(t *testing.T) {
+	var w bytes.Buffer
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	p := newProgressReporter(&w, start)
+
+	p.fileParsed("app.yaml", 2)
+	hooks := p.hooks()
+	for done := 1; done <= 300; done++ {
+		hooks.Progress(done, 300)
+	}
+
+	s := scorecard.New()
+	o := s.NewObject(metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}, metav1.ObjectMeta{Name: "app", Namespace: "prod"}, &config.RunConfiguration{})
+	o.Add(scorecard.TestScore{Grade: scorecard.GradeWarning}, ks.Check{ID: "deployment-replicas"}, summaryLocation{})
+	assert.NoError(t, hooks.AfterObject(o))
+	p.done(&s, start.Add(1500*time.Millisecond))
+
+	lines := bytes.Split(bytes.TrimSpace(w.Bytes()), []byte("\n"))
+	// One progress event for every percent, from 0 to 100
+	if !assert.Len(t, lines, 1+101+2) {
+		return
+	}
+	assert.Equal(t, `{"event":"file-parsed","file":"app.yaml","documents":2}`, string(lines[0]))
+	assert.Equal(t, `{"event":"progress","done":1,"total":300,"percent":0}`, string(lines[1]))
+	assert.Equal(t, `{"event":"progress","done":3,"total":300,"percent":1}`, string(lines[2]))
+	assert.Equal(t, `{"event":"progress","done":300,"total":300,"percent":100}`, string(lines[101]))
+	assert.Equal(t, `{"event":"object-scored","object_id":"apps/v1/Deployment/prod/app@app.yaml","kind":"Deployment","namespace":"prod","name":"app","grade":"warning"}`, string(lines[102]))
+	assert.Equal(t, `{"event":"done","objects":1,"duration_ms":1500,"worst_grade":"warning"}`, string(lines[103]))
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/bench"
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+type summaryLocation struct{}
+
+func (summaryLocation) FileLocation() ks.FileLocation {
+	return ks.FileLocation{Name: "app.yaml", Line: 1}
+}
+
+func TestWriteRunSummary(t *testing.T) {
+	s := scorecard.New()
+	var w bytes.Buffer
+	writeRunSummary(&w, &s, 1234567*time.Microsecond)
+	assert.Equal(t, "kube-score: scored 0 objects in 1.235s, worst grade: none\n", w.String())
+
+	o := s.NewObject(metav1.TypeMeta{Kind: "Deployment"}, metav1.ObjectMeta{Name: "app"}, &config.RunConfiguration{})
+	o.Add(scorecard.TestScore{Grade: scorecard.GradeWarning}, ks.Check{ID: "deployment-replicas"}, summaryLocation{})
+
+	w.Reset()
+	writeRunSummary(&w, &s, 20*time.Millisecond)
+	assert.Equal(t, "kube-score: scored 1 objects in 20ms, worst grade: WARNING\n", w.String())
+}
+
+func TestWriteBenchResult(t *testing.T) {
+	var w bytes.Buffer
+	writeBenchResult(&w, 2500000, []bench.Result{
+		{Objects: 1000, Parse: 300 * time.Millisecond, Score: 250 * time.Millisecond},
+		{Objects: 1000, Parse: 350 * time.Millisecond, Score: 200 * time.Millisecond},
+	})
+	assert.Equal(t, "objects: 1000, input: 2.5 MB, runs: 2\nparse: 300ms\nscore: 200ms\ntotal: 500ms (2000 objects/s)\n", w.String())
+}
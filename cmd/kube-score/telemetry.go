@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+
+	"github.com/romnn/kube-score/scorecard"
+)
+
+const instrumentationName = "github.com/romnn/kube-score"
+
+var tracer = otel.Tracer(instrumentationName)
+
+// telemetryEnabled returns true if an OTLP endpoint is configured via the standard
+// OpenTelemetry environment variables
+func telemetryEnabled() bool {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return false
+	}
+	for _, env := range []string{
+		"OTEL_EXPORTER_OTLP_ENDPOINT",
+		"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT",
+		"OTEL_EXPORTER_OTLP_METRICS_ENDPOINT",
+	} {
+		if os.Getenv(env) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// setupTelemetry installs the global OTLP trace and metric providers if telemetry is enabled.
+// The returned function flushes and stops the providers, and has to be called before exiting.
+func setupTelemetry(ctx context.Context) (func(context.Context) error, error) {
+	if !telemetryEnabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName("kube-score"),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	traceExporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tracerProvider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(traceExporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tracerProvider)
+
+	metricExporter, err := otlpmetrichttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	meterProvider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter)),
+		sdkmetric.WithResource(res),
+	)
+	otel.SetMeterProvider(meterProvider)
+
+	return func(ctx context.Context) error {
+		return errors.Join(tracerProvider.Shutdown(ctx), meterProvider.Shutdown(ctx))
+	}, nil
+}
+
+// recordScorecardMetrics records the number of scored objects, and the number of checks per grade
+func recordScorecardMetrics(ctx context.Context, scoreCard *scorecard.Scorecard) error {
+	meter := otel.Meter(instrumentationName)
+
+	objects, err := meter.Int64Counter(
+		"kube_score.objects",
+		metric.WithDescription("Number of scored objects"),
+	)
+	if err != nil {
+		return err
+	}
+	checks, err := meter.Int64Counter(
+		"kube_score.checks",
+		metric.WithDescription("Number of checks, by grade"),
+	)
+	if err != nil {
+		return err
+	}
+
+	grades := make(map[string]int64)
+	for _, so := range *scoreCard {
+		for _, check := range so.Checks {
+			grade := "SKIPPED"
+			if !check.Skipped {
+				grade = check.Grade.String()
+			}
+			grades[grade]++
+		}
+	}
+
+	objects.Add(ctx, int64(len(*scoreCard)))
+	for grade, count := range grades {
+		checks.Add(ctx, count, metric.WithAttributes(attribute.String("grade", grade)))
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/romnn/kube-score/config"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// versionMatrix holds the scorecards of the same input for every --kubernetes-version, so that
+// the checks with a different grade for another version are noticed before upgrading
+type versionMatrix struct {
+	versions   []config.Semver
+	scorecards []*scorecard.Scorecard
+}
+
+// versionDifference is a check of an object with a different grade for some of the versions
+type versionDifference struct {
+	object string
+	check  string
+	// grades are in the order of the versions of the matrix
+	grades []string
+}
+
+func (m *versionMatrix) add(version config.Semver, card *scorecard.Scorecard) {
+	m.versions = append(m.versions, version)
+	m.scorecards = append(m.scorecards, card)
+}
+
+// differences returns the checks that have a different grade or are only skipped for some of
+// the versions, sorted by the object and the check
+func (m *versionMatrix) differences() []versionDifference {
+	if len(m.scorecards) < 2 {
+		return nil
+	}
+
+	var keys []string
+	for key := range *m.scorecards[0] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var diffs []versionDifference
+	for _, key := range keys {
+		first := (*m.scorecards[0])[key]
+		for _, check := range first.Checks {
+			grades := []string{versionGrade(check)}
+			differs := false
+			for _, card := range m.scorecards[1:] {
+				grade := "-"
+				if other, ok := (*card)[key]; ok {
+					for _, c := range other.Checks {
+						if c.Check.ID == check.Check.ID {
+							grade = versionGrade(c)
+						}
+					}
+				}
+				differs = differs || grade != grades[0]
+				grades = append(grades, grade)
+			}
+			if differs {
+				diffs = append(diffs, versionDifference{
+					object: objectRef(first),
+					check:  check.Check.ID,
+					grades: grades,
+				})
+			}
+		}
+	}
+	sort.SliceStable(diffs, func(i, j int) bool {
+		if diffs[i].object != diffs[j].object {
+			return diffs[i].object < diffs[j].object
+		}
+		return diffs[i].check < diffs[j].check
+	})
+	return diffs
+}
+
+// write reports the checks that differ between the versions, or that there are no differences
+func (m *versionMatrix) write(w io.Writer) {
+	if len(m.versions) < 2 {
+		return
+	}
+
+	versions := make([]string, 0, len(m.versions))
+	for _, v := range m.versions {
+		versions = append(versions, v.String())
+	}
+
+	diffs := m.differences()
+	if len(diffs) == 0 {
+		_, _ = fmt.Fprintf(w, "kube-score: all checks have the same grades for Kubernetes %s\n", strings.Join(versions, ", "))
+		return
+	}
+	_, _ = fmt.Fprintf(w, "kube-score: %d checks have different grades for Kubernetes %s:\n",
+		len(diffs), strings.Join(versions, ", "))
+	for _, d := range diffs {
+		grades := make([]string, 0, len(d.grades))
+		for i, grade := range d.grades {
+			grades = append(grades, versions[i]+" "+grade)
+		}
+		_, _ = fmt.Fprintf(w, "  %s %s: %s\n", d.object, d.check, strings.Join(grades, ", "))
+	}
+}
+
+func versionGrade(ts scorecard.TestScore) string {
+	if ts.Skipped {
+		return "SKIPPED"
+	}
+	return ts.Grade.String()
+}
+
+func objectRef(o *scorecard.ScoredObject) string {
+	if o.ObjectMeta.Namespace == "" {
+		return o.TypeMeta.Kind + "/" + o.ObjectMeta.Name
+	}
+	return o.TypeMeta.Kind + "/" + o.ObjectMeta.Namespace + "/" + o.ObjectMeta.Name
+}
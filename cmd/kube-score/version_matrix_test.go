@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/parser"
+	"github.com/romnn/kube-score/score"
+	"github.com/romnn/kube-score/score/checks"
+)
+
+func TestVersionMatrix(t *testing.T) {
+	doc := `apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: backup
+  namespace: app
+spec:
+  schedule: "0 * * * *"
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          restartPolicy: Never
+          containers:
+          - name: backup
+            image: backup:1.0
+`
+	p, err := parser.New(nil)
+	assert.NoError(t, err)
+	parsed, err := p.ParseFiles([]ks.NamedReader{namedReader{Reader: strings.NewReader(doc), name: "backup.yaml"}})
+	assert.NoError(t, err)
+
+	matrix := &versionMatrix{}
+	for _, minor := range []int{26, 27, 30} {
+		version := config.Semver{Major: 1, Minor: minor}
+		runConfig := &config.RunConfiguration{KubernetesVersion: version}
+		card, err := score.Score(parsed, score.RegisterAllChecks(&checks.Config{}, runConfig), runConfig)
+		assert.NoError(t, err)
+		matrix.add(version, card)
+	}
+
+	var w bytes.Buffer
+	matrix.write(&w)
+	assert.Equal(t, `kube-score: 1 checks have different grades for Kubernetes v1.26, v1.27, v1.30:
+  CronJob/app/backup cronjob-has-timezone: v1.26 SKIPPED, v1.27 WARNING, v1.30 WARNING
+`, w.String())
+
+	// A single version is not compared
+	single := &versionMatrix{}
+	single.add(matrix.versions[0], matrix.scorecards[0])
+	w.Reset()
+	single.write(&w)
+	assert.Empty(t, w.String())
+
+	same := &versionMatrix{}
+	same.add(matrix.versions[1], matrix.scorecards[1])
+	same.add(matrix.versions[2], matrix.scorecards[2])
+	w.Reset()
+	same.write(&w)
+	assert.Equal(t, "kube-score: all checks have the same grades for Kubernetes v1.27, v1.30\n", w.String())
+}
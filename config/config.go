@@ -17,6 +17,34 @@ type RunConfiguration struct {
 	UseIgnoreChecksAnnotation             bool
 	UseOptionalChecksAnnotation           bool
 	KubernetesVersion                     Semver
+	ServiceMesh                           ServiceMesh
+	RegistryCredentials                   map[string]RegistryCredential
+	VulnerabilityScanner                  string
+	VulnerabilityReports                  []string
+	CheckParameters                       CheckParameters
+	AnnotationPrefixes                    []string
+	// ExemptNamespaces are patterns of the namespaces whose objects are not scored
+	ExemptNamespaces []string
+	// IncludeKinds are the kinds of the objects that are scored, all kinds are scored if it is empty
+	IncludeKinds []string
+	// ExcludeKinds are the kinds of the objects that are not scored
+	ExcludeKinds []string
+	// Selector is a label selector like "app=foo,tier!=db", only the objects with matching labels are scored
+	Selector string
+	// AggregateComments merges the identical comments of a check on multiple containers into a single comment
+	AggregateComments bool
+	// Messages override the comments of the checks, keyed by the check ID
+	Messages MessageTemplates
+	// Suppressions are the fingerprints of the findings that are not reported
+	Suppressions map[string]struct{}
+	// CheckGroups are the groups of checks that the ignore and enable annotations can reference
+	CheckGroups CheckGroups
+	// HelmHooks is how the hooks of Helm charts are scored, hooks are scored like any other object
+	// if it is empty
+	HelmHooks HelmHooks
+	// Exceptions ignore checks of the objects that they match. The ScoreException objects of the
+	// input are added to them when scoring.
+	Exceptions []ScoreException
 }
 
 type Semver struct {
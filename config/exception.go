@@ -0,0 +1,19 @@
+package config
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// ScoreException ignores checks of the objects in its namespace that match its selector, until it
+// expires. ScoreExceptions are read from the ScoreException objects of the input.
+type ScoreException struct {
+	Namespace string
+	Name      string
+	Checks    []string
+	Selector  labels.Selector
+	// Expires is the time that the exception stops to be applied at, it never expires if it is zero
+	Expires time.Time
+	Reason  string
+}
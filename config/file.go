@@ -0,0 +1,383 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File is the configuration file passed with --config
+//
+//	checks:
+//	  container-security-context-user-group-id:
+//	    minUserId: 20000
+//	  deployment-replicas:
+//	    minReplicas: 3
+//	environments:
+//	  dev:
+//	    checks:
+//	      deployment-replicas:
+//	        minReplicas: 1
+//	annotationPrefixes:
+//	  - score.example.com
+//	messages:
+//	  container-resources:
+//	    description: "See https://runbooks.example.com/resources#{{ .Kind }}"
+//	thresholds:
+//	  security: warning
+//	  reliability: never
+//	sarif:
+//	  container-security-context-privileged:
+//	    level: error
+//	    securitySeverity: 9.0
+//	groups:
+//	  resources:
+//	    - container-resources
+//	    - container-ephemeral-storage-request-and-limit
+type File struct {
+	// Checks are the parameters of the checks, keyed by the check ID
+	Checks CheckParameters `yaml:"checks"`
+
+	// AnnotationPrefixes are accepted in addition to "kube-score" for the ignore, enable and per-check annotations
+	AnnotationPrefixes []string `yaml:"annotationPrefixes"`
+
+	// Environments override the parameters of the checks, selected with --environment
+	Environments map[string]Environment `yaml:"environments"`
+
+	// Messages override the comments of the checks, keyed by the check ID
+	Messages MessageTemplates `yaml:"messages"`
+
+	// Thresholds are the highest grades of the checks of a category that make kube-score exit with
+	// code 1, keyed by the category. The value is a grade, or "never".
+	Thresholds map[string]string `yaml:"thresholds"`
+
+	// Sarif overrides the levels and security severities of the checks in the SARIF output, keyed
+	// by the check ID
+	Sarif SarifSeverities `yaml:"sarif"`
+
+	// Groups are named groups of checks, that are referenced as "group:<name>" in place of a check
+	// ID in the ignore and enable annotations and flags
+	Groups CheckGroups `yaml:"groups"`
+}
+
+// Environment are the settings of the configuration file that are specific to an environment
+type Environment struct {
+	Checks CheckParameters `yaml:"checks"`
+}
+
+// LoadFile reads and validates the configuration file at path
+func LoadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file File
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	if err := file.Checks.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	if err := file.Messages.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	if err := file.Sarif.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	if err := file.Groups.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	for name, env := range file.Environments {
+		if err := env.Checks.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid environment %q in %s: %w", name, path, err)
+		}
+	}
+	return &file, nil
+}
+
+// CheckParametersFor returns the parameters of the checks, with the overrides of the environment applied.
+// If environment is empty, only the top level parameters are returned.
+func (f *File) CheckParametersFor(environment string) (CheckParameters, error) {
+	if environment == "" {
+		return f.Checks, nil
+	}
+	env, ok := f.Environments[environment]
+	if !ok {
+		return nil, fmt.Errorf("unknown environment %q", environment)
+	}
+
+	params := make(CheckParameters, len(f.Checks))
+	for _, p := range []CheckParameters{f.Checks, env.Checks} {
+		for check, values := range p {
+			if params[check] == nil {
+				params[check] = make(map[string]any, len(values))
+			}
+			for name, value := range values {
+				params[check][name] = value
+			}
+		}
+	}
+	return params, nil
+}
+
+// CheckParameters are the values of the check parameters, keyed by the check ID and the parameter name
+type CheckParameters map[string]map[string]any
+
+// DefaultCheckParameters are all parameters that can be configured, and their default values.
+// The type of the default value is the type that is accepted in the configuration file.
+var DefaultCheckParameters = CheckParameters{
+	"container-security-context-user-group-id": {
+		"minUserId":  10000,
+		"minGroupId": 10000,
+	},
+	"container-ports-check": {
+		// Port names have to be IANA_SVC_NAMEs, which are limited to 15 characters
+		"maxPortNameLength": 15,
+	},
+	"deployment-replicas": {
+		"minReplicas": 2,
+	},
+	"horizontalpodautoscaler-replicas": {
+		"minReplicas": 2,
+	},
+	"horizontalpodautoscaler-target-scalable": {
+		"scalableKinds": []string{},
+	},
+	"deployment-has-host-podantiaffinity": {
+		"topologyKeys": defaultTopologyKeys,
+	},
+	"statefulset-has-host-podantiaffinity": {
+		"topologyKeys": defaultTopologyKeys,
+	},
+	"pod-service-account-token": {
+		"maxExpirationSeconds": 86400,
+	},
+	"pod-sysctls": {
+		"safeSysctls": defaultSafeSysctls,
+	},
+	"pod-security-admission": {
+		// The level that is enforced in the namespaces without a pod-security.kubernetes.io/enforce label
+		"defaultEnforce": "privileged",
+	},
+	"pod-runtimeclass": {
+		"allowedRuntimeClasses": []string{},
+		"requiredNamespaces":    []string{},
+	},
+	"container-image-pull-secrets": {
+		"publicRegistries": defaultPublicRegistries,
+	},
+	"service-dual-stack": {
+		// dual-stack flags SingleStack services, single-stack flags services that require dual-stack
+		"profile": "dual-stack",
+	},
+	"priorityclass-preemption-policy": {
+		"batchClassNames": []string{"*batch*"},
+	},
+	"storageclass-reclaim-policy": {
+		// default flags StorageClasses without a reclaimPolicy, data-critical also flags Delete
+		"profile": "default",
+	},
+	"storageclass-volume-binding-mode": {
+		"topologyProvisioners": defaultTopologyProvisioners,
+	},
+	"webhook-timeout": {
+		"maxTimeoutSeconds": 10,
+	},
+	"service-traffic-distribution": {
+		// Topology aware routing works best with at least 3 endpoints in each of 3 zones
+		"minReplicas": 9,
+	},
+	"container-image-tag": {
+		// latest only flags the latest tag, floating also flags tags that are not a full version
+		"tagPolicy":    "latest",
+		"floatingTags": defaultFloatingTags,
+	},
+	"container-volume-mounts": {
+		// allow only flags mounts at the same path, warn also flags mounts inside other mounts
+		"nestedMounts": "allow",
+	},
+}
+
+// allowedParameterValues are the values of the string parameters that only accept some values
+var allowedParameterValues = map[string]map[string][]string{
+	"container-image-tag": {
+		"tagPolicy": {"latest", "floating"},
+	},
+	"service-dual-stack": {
+		"profile": {"dual-stack", "single-stack"},
+	},
+	"pod-security-admission": {
+		"defaultEnforce": {"privileged", "baseline", "restricted"},
+	},
+	"storageclass-reclaim-policy": {
+		"profile": {"default", "data-critical"},
+	},
+	"container-volume-mounts": {
+		"nestedMounts": {"allow", "warn"},
+	},
+}
+
+// defaultTopologyProvisioners are provisioners of volumes that are bound to a zone or a node
+var defaultTopologyProvisioners = []string{
+	"ebs.csi.aws.com",
+	"pd.csi.storage.gke.io",
+	"disk.csi.azure.com",
+	"cinder.csi.openstack.org",
+	"kubernetes.io/aws-ebs",
+	"kubernetes.io/gce-pd",
+	"kubernetes.io/azure-disk",
+	"kubernetes.io/cinder",
+	"kubernetes.io/no-provisioner",
+}
+
+// defaultPublicRegistries are registries of public images, that do not need imagePullSecrets
+var defaultPublicRegistries = []string{
+	"docker.io",
+	"registry.k8s.io",
+	"k8s.gcr.io",
+	"quay.io",
+	"ghcr.io",
+	"gcr.io",
+	"mcr.microsoft.com",
+	"public.ecr.aws",
+}
+
+// defaultFloatingTags are the tags of release channels, that are moved to new versions
+var defaultFloatingTags = []string{
+	"stable",
+	"edge",
+	"lts",
+	"main",
+	"master",
+	"dev",
+	"nightly",
+}
+
+var defaultTopologyKeys = []string{
+	"kubernetes.io/hostname",
+	"topology.kubernetes.io/region",
+	"topology.kubernetes.io/zone",
+
+	// Deprecated in Kubernetes v1.17
+	"failure-domain.beta.kubernetes.io/region",
+	"failure-domain.beta.kubernetes.io/zone",
+}
+
+// defaultSafeSysctls are the sysctls that are namespaced, and isolated between pods on the same node
+var defaultSafeSysctls = []string{
+	"kernel.shm_rmid_forced",
+	"net.ipv4.ip_local_port_range",
+	"net.ipv4.ip_unprivileged_port_start",
+	"net.ipv4.ping_group_range",
+	"net.ipv4.tcp_syncookies",
+	"net.ipv4.ip_local_reserved_ports",
+	"net.ipv4.tcp_keepalive_time",
+	"net.ipv4.tcp_fin_timeout",
+	"net.ipv4.tcp_keepalive_intvl",
+	"net.ipv4.tcp_keepalive_probes",
+}
+
+// Validate makes sure that all parameters exist, and have the type of their default value.
+// Lists are normalized to []string.
+func (p CheckParameters) Validate() error {
+	for check, params := range p {
+		for name, value := range params {
+			value, err := validateParameter(check, name, value)
+			if err != nil {
+				return err
+			}
+			params[name] = value
+		}
+	}
+	return nil
+}
+
+// validateParameter returns the normalized value of the parameter, or an error if the parameter
+// does not exist or the value has the wrong type
+func validateParameter(check, name string, value any) (any, error) {
+	defaults, ok := DefaultCheckParameters[check]
+	if !ok {
+		return nil, fmt.Errorf("check %q has no parameters", check)
+	}
+	def, ok := defaults[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown parameter %q of check %q", name, check)
+	}
+	switch def.(type) {
+	case int:
+		if _, ok := value.(int); !ok {
+			return nil, fmt.Errorf("parameter %q of check %q must be an integer", name, check)
+		}
+	case string:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("parameter %q of check %q must be a string", name, check)
+		}
+		if allowed, ok := allowedParameterValues[check][name]; ok && !slices.Contains(allowed, s) {
+			return nil, fmt.Errorf(
+				"parameter %q of check %q must be one of %s",
+				name, check, strings.Join(allowed, ", "),
+			)
+		}
+	case []string:
+		values, ok := toStrings(value)
+		if !ok {
+			return nil, fmt.Errorf("parameter %q of check %q must be a list of strings", name, check)
+		}
+		return values, nil
+	}
+	return value, nil
+}
+
+func toStrings(value any) ([]string, bool) {
+	switch v := value.(type) {
+	case []string:
+		return v, true
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, s)
+		}
+		return values, true
+	default:
+		return nil, false
+	}
+}
+
+// Int returns the integer parameter of the check, or its default value if it is not configured
+func (p CheckParameters) Int(check, name string) int {
+	if v, ok := p[check][name].(int); ok {
+		return v
+	}
+	return DefaultCheckParameters[check][name].(int)
+}
+
+// String returns the string parameter of the check, or its default value if it is not configured
+func (p CheckParameters) String(check, name string) string {
+	if v, ok := p[check][name].(string); ok {
+		return v
+	}
+	return DefaultCheckParameters[check][name].(string)
+}
+
+// Strings returns the list parameter of the check, or its default value if it is not configured
+func (p CheckParameters) Strings(check, name string) []string {
+	if v, ok := p[check][name].([]string); ok {
+		return v
+	}
+	return DefaultCheckParameters[check][name].([]string)
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "kube-score.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadFile(t *testing.T) {
+	path := writeFile(t, `
+checks:
+  container-security-context-user-group-id:
+    minUserId: 1000
+  deployment-has-host-podantiaffinity:
+    topologyKeys:
+      - kubernetes.io/hostname
+annotationPrefixes:
+  - score.example.com
+`)
+
+	file, err := LoadFile(path)
+	assert.NoError(t, err)
+
+	params := file.Checks
+	assert.Equal(t, 1000, params.Int("container-security-context-user-group-id", "minUserId"))
+	assert.Equal(t, 10000, params.Int("container-security-context-user-group-id", "minGroupId"))
+	assert.Equal(t, 2, params.Int("deployment-replicas", "minReplicas"))
+	assert.Equal(t, []string{"kubernetes.io/hostname"}, params.Strings("deployment-has-host-podantiaffinity", "topologyKeys"))
+	assert.Len(t, params.Strings("statefulset-has-host-podantiaffinity", "topologyKeys"), 5)
+	assert.Equal(t, "latest", params.String("container-image-tag", "tagPolicy"))
+	assert.Equal(t, []string{"score.example.com"}, file.AnnotationPrefixes)
+}
+
+func TestLoadFileMessages(t *testing.T) {
+	file, err := LoadFile(writeFile(t, `
+messages:
+  container-resources:
+    description: "See {{ .DocumentationURL }}"
+    documentationURL: https://wiki.example.com/{{ .Check }}
+`))
+	assert.NoError(t, err)
+
+	summary, description, documentationURL, err := file.Messages["container-resources"].Execute(MessageData{
+		Check:            "container-resources",
+		Summary:          "CPU limit is not set",
+		DocumentationURL: "https://kube-score.com",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "CPU limit is not set", summary)
+	assert.Equal(t, "See https://kube-score.com", description)
+	assert.Equal(t, "https://wiki.example.com/container-resources", documentationURL)
+}
+
+func TestLoadFileSarif(t *testing.T) {
+	file, err := LoadFile(writeFile(t, `
+sarif:
+  container-security-context-privileged:
+    level: error
+    securitySeverity: 9
+  container-image-tag:
+    level: none
+`))
+	assert.NoError(t, err)
+
+	severity := 9.0
+	assert.Equal(t, SarifSeverities{
+		"container-security-context-privileged": {Level: "error", SecuritySeverity: &severity},
+		"container-image-tag":                   {Level: "none"},
+	}, file.Sarif)
+}
+
+func TestLoadFileGroups(t *testing.T) {
+	file, err := LoadFile(writeFile(t, `
+groups:
+  resources:
+    - container-resources
+    - container-ephemeral-storage-request-and-limit
+`))
+	assert.NoError(t, err)
+
+	ids, err := file.Groups.Expand([]string{"service-type", "group:resources"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"service-type", "container-resources", "container-ephemeral-storage-request-and-limit"}, ids)
+
+	_, err = file.Groups.Expand([]string{"group:unknown"})
+	assert.EqualError(t, err, `unknown group "unknown"`)
+}
+
+func TestLoadFileEmpty(t *testing.T) {
+	file, err := LoadFile(writeFile(t, ""))
+	assert.NoError(t, err)
+	assert.Equal(t, 15, file.Checks.Int("container-ports-check", "maxPortNameLength"))
+}
+
+func TestLoadFileInvalid(t *testing.T) {
+	for _, content := range []string{
+		"unknown: true",
+		"checks:\n  unknown-check:\n    minReplicas: 2",
+		"checks:\n  deployment-replicas:\n    unknown: 2",
+		"checks:\n  deployment-replicas:\n    minReplicas: two",
+		"checks:\n  deployment-has-host-podantiaffinity:\n    topologyKeys: [1, 2]",
+		"checks:\n  container-image-tag:\n    tagPolicy: [floating]",
+		"checks:\n  container-image-tag:\n    tagPolicy: semver",
+		"messages:\n  container-resources:\n    summary: '{{ .Unknown }}'",
+		"messages:\n  container-resources:\n    summary: '{{ .Name'",
+		"sarif:\n  container-resources:\n    level: critical",
+		"sarif:\n  container-resources:\n    securitySeverity: 11",
+		"sarif:\n  container-resources:\n    securitySeverity: high",
+		"groups:\n  resources: []",
+		"groups:\n  resources: [group:other]",
+		"groups:\n  res,ources: [container-resources]",
+	} {
+		_, err := LoadFile(writeFile(t, content))
+		assert.Error(t, err, content)
+	}
+}
+
+func TestLoadFileEnvironments(t *testing.T) {
+	path := writeFile(t, `
+checks:
+  deployment-replicas:
+    minReplicas: 2
+  horizontalpodautoscaler-replicas:
+    minReplicas: 2
+environments:
+  prod:
+    checks:
+      deployment-replicas:
+        minReplicas: 3
+  dev:
+    checks:
+      deployment-replicas:
+        minReplicas: 1
+      horizontalpodautoscaler-replicas:
+        minReplicas: 1
+`)
+
+	file, err := LoadFile(path)
+	assert.NoError(t, err)
+
+	params, err := file.CheckParametersFor("")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, params.Int("deployment-replicas", "minReplicas"))
+
+	params, err = file.CheckParametersFor("prod")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, params.Int("deployment-replicas", "minReplicas"))
+	assert.Equal(t, 2, params.Int("horizontalpodautoscaler-replicas", "minReplicas"))
+
+	params, err = file.CheckParametersFor("dev")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, params.Int("deployment-replicas", "minReplicas"))
+	assert.Equal(t, 1, params.Int("horizontalpodautoscaler-replicas", "minReplicas"))
+
+	// The environment does not change the top level parameters
+	assert.Equal(t, 2, file.Checks.Int("deployment-replicas", "minReplicas"))
+
+	_, err = file.CheckParametersFor("staging")
+	assert.Error(t, err)
+}
+
+func TestLoadFileInvalidEnvironment(t *testing.T) {
+	_, err := LoadFile(writeFile(t, "environments:\n  dev:\n    checks:\n      deployment-replicas:\n        minReplicas: one"))
+	assert.Error(t, err)
+}
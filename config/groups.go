@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GroupPrefix is the prefix of the references to a group of checks, e.g. "group:resources"
+const GroupPrefix = "group:"
+
+// CheckGroups are named groups of check IDs, keyed by the name of the group. A group is referenced
+// as "group:<name>" in the ignore and enable annotations, --ignore-test and --enable-optional-test,
+// and stands for all checks of the group.
+type CheckGroups map[string][]string
+
+// Validate makes sure that the names of the groups can be referenced, and that the groups only
+// contain check IDs
+func (g CheckGroups) Validate() error {
+	for name, ids := range g {
+		if name == "" || strings.ContainsAny(name, ", \t") {
+			return fmt.Errorf("invalid group name %q, expected a name without commas and spaces", name)
+		}
+		if len(ids) == 0 {
+			return fmt.Errorf("group %q has no checks", name)
+		}
+		for _, id := range ids {
+			if strings.TrimSpace(id) == "" || strings.Contains(id, ",") {
+				return fmt.Errorf("invalid check ID %q in group %q", id, name)
+			}
+			if strings.HasPrefix(id, GroupPrefix) {
+				return fmt.Errorf("group %q contains the group %s, groups can not be nested", name, id)
+			}
+		}
+	}
+	return nil
+}
+
+// Group returns the checks of the group that ref references, and false if ref does not reference
+// a group. It returns an error if the group does not exist.
+func (g CheckGroups) Group(ref string) ([]string, bool, error) {
+	name, ok := strings.CutPrefix(ref, GroupPrefix)
+	if !ok {
+		return nil, false, nil
+	}
+	ids, ok := g[name]
+	if !ok {
+		return nil, true, fmt.Errorf("unknown group %q", name)
+	}
+	return ids, true, nil
+}
+
+// Expand replaces the references to groups in ids with the checks of the groups
+func (g CheckGroups) Expand(ids []string) ([]string, error) {
+	expanded := make([]string, 0, len(ids))
+	for _, id := range ids {
+		group, ok, err := g.Group(id)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			expanded = append(expanded, group...)
+			continue
+		}
+		expanded = append(expanded, id)
+	}
+	return expanded, nil
+}
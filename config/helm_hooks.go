@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// HelmHookAnnotation marks an object of a Helm chart as a hook, that is run at a point of the
+// lifecycle of the release instead of being part of it
+const HelmHookAnnotation = "helm.sh/hook"
+
+// HelmHooks is how the hooks of Helm charts are scored
+type HelmHooks string
+
+const (
+	// HelmHooksScore scores hooks like any other object
+	HelmHooksScore HelmHooks = "score"
+	// HelmHooksRelaxed does not expect one-shot hooks to be targeted by NetworkPolicies,
+	// PodDisruptionBudgets and Services
+	HelmHooksRelaxed HelmHooks = "relaxed"
+	// HelmHooksSkipTests is HelmHooksRelaxed, and does not score test hooks
+	HelmHooksSkipTests HelmHooks = "skip-tests"
+)
+
+func ParseHelmHooks(s string) (HelmHooks, error) {
+	switch hooks := HelmHooks(strings.ToLower(strings.TrimSpace(s))); hooks {
+	case "":
+		return HelmHooksScore, nil
+	case HelmHooksScore, HelmHooksRelaxed, HelmHooksSkipTests:
+		return hooks, nil
+	default:
+		return HelmHooksScore, fmt.Errorf("unknown helm hooks policy %q", s)
+	}
+}
+
+// Relaxed returns true if hooks are not expected to be targeted by other objects
+func (h HelmHooks) Relaxed() bool {
+	return h == HelmHooksRelaxed || h == HelmHooksSkipTests
+}
+
+// IsHelmHook returns true if the object with the annotations is a Helm hook
+func IsHelmHook(annotations map[string]string) bool {
+	return strings.TrimSpace(annotations[HelmHookAnnotation]) != ""
+}
+
+// IsHelmTestHook returns true if the object with the annotations is a Helm test, "test-success"
+// and "test-failure" are the test hooks of Helm 2
+func IsHelmTestHook(annotations map[string]string) bool {
+	hooks := strings.Split(annotations[HelmHookAnnotation], ",")
+	for i := range hooks {
+		hooks[i] = strings.TrimSpace(hooks[i])
+	}
+	return slices.Contains(hooks, "test") || slices.Contains(hooks, "test-success") ||
+		slices.Contains(hooks, "test-failure")
+}
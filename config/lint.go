@@ -0,0 +1,181 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Problem is an error in a file, at a line
+type Problem struct {
+	Line    int
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Line == 0 {
+		return p.Message
+	}
+	return fmt.Sprintf("line %d: %s", p.Line, p.Message)
+}
+
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// ProblemFromYAMLError converts an error of the YAML parser to a Problem with the line of the error
+func ProblemFromYAMLError(err error) Problem {
+	if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return Problem{Line: line, Message: m[2]}
+	}
+	return Problem{Message: err.Error()}
+}
+
+// LintFile reports all problems of the configuration file at path. Unlike LoadFile,
+// it does not stop at the first problem, and reports the lines of the problems.
+func LintFile(path string) ([]Problem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return []Problem{ProblemFromYAMLError(err)}, nil
+	}
+	if len(root.Content) == 0 {
+		return nil, nil
+	}
+
+	var problems []Problem
+	lintMapping(root.Content[0], &problems, func(key, value *yaml.Node) {
+		switch key.Value {
+		case "checks":
+			lintCheckParameters(value, &problems)
+		case "environments":
+			lintMapping(value, &problems, func(_, env *yaml.Node) {
+				lintMapping(env, &problems, func(key, value *yaml.Node) {
+					if key.Value != "checks" {
+						problems = append(problems, Problem{key.Line, fmt.Sprintf("unknown field %q", key.Value)})
+						return
+					}
+					lintCheckParameters(value, &problems)
+				})
+			})
+		case "messages":
+			lintMessages(value, &problems)
+		case "sarif":
+			lintSarif(value, &problems)
+		case "groups":
+			lintGroups(value, &problems)
+		case "annotationPrefixes":
+			var prefixes []string
+			if err := value.Decode(&prefixes); err != nil {
+				problems = append(problems, Problem{value.Line, "annotationPrefixes must be a list of strings"})
+			}
+		case "thresholds":
+			var thresholds map[string]string
+			if err := value.Decode(&thresholds); err != nil {
+				problems = append(problems, Problem{value.Line, "thresholds must be a mapping of categories to grades"})
+			}
+		default:
+			problems = append(problems, Problem{key.Line, fmt.Sprintf("unknown field %q", key.Value)})
+		}
+	})
+	return problems, nil
+}
+
+// lintMapping calls fn for all keys and values of the mapping node
+func lintMapping(node *yaml.Node, problems *[]Problem, fn func(key, value *yaml.Node)) {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!null" {
+		return
+	}
+	if node.Kind != yaml.MappingNode {
+		*problems = append(*problems, Problem{node.Line, "expected a mapping"})
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		fn(node.Content[i], node.Content[i+1])
+	}
+}
+
+func lintCheckParameters(node *yaml.Node, problems *[]Problem) {
+	lintMapping(node, problems, func(check, params *yaml.Node) {
+		if _, ok := DefaultCheckParameters[check.Value]; !ok {
+			*problems = append(*problems, Problem{check.Line, fmt.Sprintf("check %q has no parameters", check.Value)})
+			return
+		}
+		lintMapping(params, problems, func(name, value *yaml.Node) {
+			var v any
+			if err := value.Decode(&v); err != nil {
+				*problems = append(*problems, ProblemFromYAMLError(err))
+				return
+			}
+			if _, err := validateParameter(check.Value, name.Value, v); err != nil {
+				*problems = append(*problems, Problem{name.Line, err.Error()})
+			}
+		})
+	})
+}
+
+func lintMessages(node *yaml.Node, problems *[]Problem) {
+	lintMapping(node, problems, func(check, fields *yaml.Node) {
+		lintMapping(fields, problems, func(name, value *yaml.Node) {
+			var t MessageTemplate
+			switch name.Value {
+			case "summary":
+				t.Summary = value.Value
+			case "description":
+				t.Description = value.Value
+			case "documentationURL":
+				t.DocumentationURL = value.Value
+			default:
+				*problems = append(*problems, Problem{name.Line, fmt.Sprintf("unknown field %q", name.Value)})
+				return
+			}
+			if _, _, _, err := t.Execute(MessageData{}); err != nil {
+				*problems = append(*problems, Problem{value.Line, fmt.Sprintf("invalid template of %s: %s", check.Value, err)})
+			}
+		})
+	})
+}
+
+func lintGroups(node *yaml.Node, problems *[]Problem) {
+	lintMapping(node, problems, func(name, ids *yaml.Node) {
+		var group []string
+		if err := ids.Decode(&group); err != nil {
+			*problems = append(*problems, Problem{ids.Line, fmt.Sprintf("group %q must be a list of check IDs", name.Value)})
+			return
+		}
+		if err := (CheckGroups{name.Value: group}).Validate(); err != nil {
+			*problems = append(*problems, Problem{name.Line, err.Error()})
+		}
+	})
+}
+
+func lintSarif(node *yaml.Node, problems *[]Problem) {
+	lintMapping(node, problems, func(check, fields *yaml.Node) {
+		lintMapping(fields, problems, func(name, value *yaml.Node) {
+			var severity SarifSeverity
+			switch name.Value {
+			case "level":
+				severity.Level = value.Value
+			case "securitySeverity":
+				var v float64
+				if err := value.Decode(&v); err != nil {
+					*problems = append(*problems, Problem{value.Line, fmt.Sprintf("securitySeverity of %s must be a number", check.Value)})
+					return
+				}
+				severity.SecuritySeverity = &v
+			default:
+				*problems = append(*problems, Problem{name.Line, fmt.Sprintf("unknown field %q", name.Value)})
+				return
+			}
+			if err := (SarifSeverities{check.Value: severity}).Validate(); err != nil {
+				*problems = append(*problems, Problem{value.Line, err.Error()})
+			}
+		})
+	})
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintFile(t *testing.T) {
+	path := writeFile(t, `
+checks:
+  container-security-context-user-group-id:
+    minUserId: low
+  deployment-replicas:
+    minReplica: 3
+  service-type:
+    foo: bar
+environments:
+  prod:
+    checks:
+      deployment-replicas:
+        minReplicas: 3
+    exitOn: critical
+annotationPrefixes: score.example.com
+unknown: true
+messages:
+  deployment-replicas:
+    summary: "{{ .Unknown }}"
+    link: https://wiki.example.com
+thresholds:
+  - security
+sarif:
+  container-image-tag:
+    level: critical
+    securitySeverity: high
+    severity: 5
+groups:
+  resources: container-resources
+  "my group":
+    - container-resources
+`)
+
+	problems, err := LintFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []Problem{
+		{4, `parameter "minUserId" of check "container-security-context-user-group-id" must be an integer`},
+		{6, `unknown parameter "minReplica" of check "deployment-replicas"`},
+		{7, `check "service-type" has no parameters`},
+		{14, `unknown field "exitOn"`},
+		{15, "annotationPrefixes must be a list of strings"},
+		{16, `unknown field "unknown"`},
+		{19, `invalid template of deployment-replicas: template: summary:1:3: executing "summary" at <.Unknown>: can't evaluate field Unknown in type config.MessageData`},
+		{20, `unknown field "link"`},
+		{22, "thresholds must be a mapping of categories to grades"},
+		{25, `invalid SARIF level "critical" of container-image-tag, expected one of [error warning note none]`},
+		{26, "securitySeverity of container-image-tag must be a number"},
+		{27, `unknown field "severity"`},
+		{29, `group "resources" must be a list of check IDs`},
+		{30, `invalid group name "my group", expected a name without commas and spaces`},
+	}, problems)
+}
+
+func TestLintFileValid(t *testing.T) {
+	path := writeFile(t, `
+checks:
+  deployment-replicas:
+    minReplicas: 3
+`)
+
+	problems, err := LintFile(path)
+	assert.NoError(t, err)
+	assert.Empty(t, problems)
+}
+
+func TestLintFileSyntaxError(t *testing.T) {
+	path := writeFile(t, `
+checks:
+  deployment-replicas:
+    minReplicas: [3
+`)
+
+	problems, err := LintFile(path)
+	assert.NoError(t, err)
+	assert.Len(t, problems, 1)
+	assert.NotZero(t, problems[0].Line)
+	assert.Contains(t, problems[0].String(), "line ")
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// MessageTemplate overrides the comments of a check, e.g. to link to an internal runbook instead of
+// the generic advice. The fields are Go templates, that can use the fields of MessageData. Empty fields
+// keep the original text.
+type MessageTemplate struct {
+	Summary          string `yaml:"summary"`
+	Description      string `yaml:"description"`
+	DocumentationURL string `yaml:"documentationURL"`
+}
+
+// MessageData are the fields that can be used in a MessageTemplate
+type MessageData struct {
+	// Check is the ID of the check
+	Check     string
+	Kind      string
+	Namespace string
+	Name      string
+	// Container is the path of the comment, usually the name of the container
+	Container string
+	// Summary, Description and DocumentationURL are the original texts of the comment
+	Summary          string
+	Description      string
+	DocumentationURL string
+}
+
+// MessageTemplates are the message templates, keyed by the check ID
+type MessageTemplates map[string]MessageTemplate
+
+// Validate makes sure that all templates can be parsed and executed
+func (m MessageTemplates) Validate() error {
+	for check, t := range m {
+		if _, _, _, err := t.Execute(MessageData{}); err != nil {
+			return fmt.Errorf("invalid message template of %s: %w", check, err)
+		}
+	}
+	return nil
+}
+
+// Execute returns the summary, description and documentation URL of the comment
+func (t MessageTemplate) Execute(data MessageData) (summary, description, documentationURL string, err error) {
+	if summary, err = execute("summary", t.Summary, data.Summary, data); err != nil {
+		return
+	}
+	if description, err = execute("description", t.Description, data.Description, data); err != nil {
+		return
+	}
+	documentationURL, err = execute("documentationURL", t.DocumentationURL, data.DocumentationURL, data)
+	return
+}
+
+func execute(name, text, original string, data MessageData) (string, error) {
+	if text == "" {
+		return original, nil
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// RegistryCredential is a username and password used to authenticate against a container registry
+type RegistryCredential struct {
+	Username string
+	Password string
+}
+
+type dockerConfig struct {
+	Auths map[string]struct {
+		Auth     string `json:"auth"`
+		Username string `json:"username"`
+		Password string `json:"password"`
+	} `json:"auths"`
+}
+
+// LoadRegistryCredentials reads the registry credentials from a file in the format of the
+// docker config.json. The returned credentials are keyed by the normalized registry host.
+func LoadRegistryCredentials(path string) (map[string]RegistryCredential, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cnf dockerConfig
+	if err := json.Unmarshal(data, &cnf); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	credentials := make(map[string]RegistryCredential)
+	for registry, auth := range cnf.Auths {
+		cred := RegistryCredential{Username: auth.Username, Password: auth.Password}
+		if auth.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+			if err != nil {
+				return nil, fmt.Errorf("invalid auth for registry %s: %w", registry, err)
+			}
+			username, password, ok := strings.Cut(string(decoded), ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid auth for registry %s: expected username:password", registry)
+			}
+			cred = RegistryCredential{Username: username, Password: password}
+		}
+		credentials[NormalizeRegistryHost(registry)] = cred
+	}
+	return credentials, nil
+}
+
+// NormalizeRegistryHost strips the scheme and path from a registry address, and maps the
+// legacy Docker Hub addresses to docker.io
+func NormalizeRegistryHost(registry string) string {
+	registry = strings.TrimPrefix(registry, "https://")
+	registry = strings.TrimPrefix(registry, "http://")
+	registry, _, _ = strings.Cut(registry, "/")
+	switch registry {
+	case "index.docker.io", "registry-1.docker.io":
+		return "docker.io"
+	}
+	return registry
+}
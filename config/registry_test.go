@@ -0,0 +1,27 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadRegistryCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	err := os.WriteFile(path, []byte(`{
+		"auths": {
+			"https://index.docker.io/v1/": {"auth": "dXNlcjpzZWNyZXQ="},
+			"ghcr.io": {"username": "octocat", "password": "token"}
+		}
+	}`), 0o600)
+	assert.NoError(t, err)
+
+	credentials, err := LoadRegistryCredentials(path)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]RegistryCredential{
+		"docker.io": {Username: "user", Password: "secret"},
+		"ghcr.io":   {Username: "octocat", Password: "token"},
+	}, credentials)
+}
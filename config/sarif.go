@@ -0,0 +1,35 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+)
+
+// SarifLevels are the levels of the results of the SARIF output
+var SarifLevels = []string{"error", "warning", "note", "none"}
+
+// SarifSeverity overrides how the findings of a check are reported in the SARIF output
+type SarifSeverity struct {
+	// Level is the level of the results of the check, one of SarifLevels. The level of the grade of
+	// the check is used if it is empty, "none" omits the results of the check.
+	Level string `yaml:"level"`
+	// SecuritySeverity is the security severity of the check between 0 and 10, that GitHub code
+	// scanning ranks the findings by
+	SecuritySeverity *float64 `yaml:"securitySeverity"`
+}
+
+// SarifSeverities are the severities of the checks in the SARIF output, keyed by the check ID
+type SarifSeverities map[string]SarifSeverity
+
+// Validate makes sure that all levels and security severities are valid
+func (s SarifSeverities) Validate() error {
+	for check, severity := range s {
+		if severity.Level != "" && !slices.Contains(SarifLevels, severity.Level) {
+			return fmt.Errorf("invalid SARIF level %q of %s, expected one of %v", severity.Level, check, SarifLevels)
+		}
+		if v := severity.SecuritySeverity; v != nil && (*v < 0 || *v > 10) {
+			return fmt.Errorf("invalid SARIF security severity %v of %s, expected a number between 0 and 10", *v, check)
+		}
+	}
+	return nil
+}
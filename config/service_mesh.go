@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ServiceMesh is the service mesh that the scored workloads are expected to run in
+type ServiceMesh string
+
+const (
+	ServiceMeshNone    ServiceMesh = ""
+	ServiceMeshIstio   ServiceMesh = "istio"
+	ServiceMeshLinkerd ServiceMesh = "linkerd"
+)
+
+func ParseServiceMesh(s string) (ServiceMesh, error) {
+	switch mesh := ServiceMesh(strings.ToLower(strings.TrimSpace(s))); mesh {
+	case ServiceMeshNone, ServiceMeshIstio, ServiceMeshLinkerd:
+		return mesh, nil
+	default:
+		return ServiceMeshNone, fmt.Errorf("unknown service mesh %q", s)
+	}
+}
+
+// SidecarPorts returns the ports of the injected sidecar proxy that have to
+// be reachable from outside of the pod, e.g. for telemetry scraping.
+func (m ServiceMesh) SidecarPorts() []int32 {
+	switch m {
+	case ServiceMeshIstio:
+		// 15008: HBONE mTLS tunnel, 15020: merged prometheus telemetry,
+		// 15090: envoy prometheus telemetry
+		return []int32{15008, 15020, 15090}
+	case ServiceMeshLinkerd:
+		// 4143: inbound proxy, 4191: admin and metrics
+		return []int32{4143, 4191}
+	default:
+		return nil
+	}
+}
+
+// ProtocolPrefixes returns the port name prefixes used by the service mesh
+// to detect the application protocol of a Service port.
+func (m ServiceMesh) ProtocolPrefixes() []string {
+	switch m {
+	case ServiceMeshIstio:
+		return []string{
+			"http", "http2", "https", "grpc", "grpc-web", "mongo",
+			"mysql", "redis", "tcp", "tls", "udp",
+		}
+	case ServiceMeshLinkerd:
+		return []string{"http", "http2", "https", "grpc", "tcp", "tls"}
+	default:
+		return nil
+	}
+}
@@ -27,7 +27,14 @@ type SkipExpression struct {
 	ValueRegex *regexp.Regexp
 }
 
-func ParseSkipExpression(rawExpression string) (*SkipExpression, error) {
+func ParseSkipExpression(rawExpression string) (expr *SkipExpression, err error) {
+	// The expressions can be untrusted, they must never crash kube-score
+	defer func() {
+		if r := recover(); r != nil {
+			expr, err = nil, fmt.Errorf("invalid expression %q: %v", rawExpression, r)
+		}
+	}()
+
 	rawPath, value, err := splitRawExpression(rawExpression)
 	if err != nil {
 		return nil, err
@@ -49,23 +56,29 @@ func ParseSkipExpression(rawExpression string) (*SkipExpression, error) {
 
 	valueRegex, err := regexp.Compile(value)
 	if err != nil {
-		return nil, fmt.Errorf("invalid value pattern %q: %w", valueRegex.String(), err)
+		return nil, fmt.Errorf("invalid value pattern %q: %w", value, err)
 	}
 
-	expr := &SkipExpression{
+	return &SkipExpression{
 		RawPath:    rawPath,
 		Path:       path,
 		RawValue:   value,
 		ValueRegex: valueRegex,
-	}
-	return expr, nil
+	}, nil
 }
 
 func (e *SkipExpression) String() string {
 	return fmt.Sprintf("%s=%s", e.RawPath, e.RawValue)
 }
 
-func (e *SkipExpression) Evaluate(doc yaml.Node) bool {
+func (e *SkipExpression) Evaluate(doc yaml.Node) (matched bool) {
+	// A path that can not be evaluated on the document does not match
+	defer func() {
+		if recover() != nil {
+			matched = false
+		}
+	}()
+
 	// func (e *SkipExpression) Evaluate(value any) bool {
 	// to yaml
 	// yaml.Marshal(in interface{})
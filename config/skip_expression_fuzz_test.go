@@ -0,0 +1,36 @@
+package config
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func FuzzParseSkipExpression(f *testing.F) {
+	for _, seed := range []string{
+		"$.metadata.name=foo",
+		`$.metadata.labels["app.kubernetes.io/name"]="web.*"`,
+		"$..image='nginx:1.*'",
+		"$.spec.containers[*].name=app",
+		"$.metadata.annotations.a=b=c",
+		"=",
+		"$[",
+		"'$.a'='",
+	} {
+		f.Add(seed)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte("metadata:\n  name: foo\n  labels:\n    app: web\nspec:\n  containers:\n  - name: app\n    image: nginx:1.25\n"), &doc); err != nil {
+		f.Fatal(err)
+	}
+
+	f.Fuzz(func(t *testing.T, raw string) {
+		expr, err := ParseSkipExpression(raw)
+		if err != nil {
+			return
+		}
+		_ = expr.String()
+		_ = expr.Evaluate(doc)
+	})
+}
@@ -2,13 +2,21 @@ package domain
 
 import (
 	"io"
+	"slices"
+
+	"github.com/romnn/kube-score/config"
 
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
 
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	nodev1 "k8s.io/api/node/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -18,6 +26,23 @@ type Check struct {
 	TargetType string
 	Comment    string
 	Optional   bool
+	// Aliases are the old IDs of a renamed check, that are still accepted in place of the ID
+	Aliases []string
+	// Namespace is the namespace of the ID of a custom check, e.g. custom for custom/<name>. It is
+	// empty for the built-in checks.
+	Namespace string
+	// Category and Remediation are only documentation, and are empty for checks that are not built-in
+	Category    string
+	Remediation string
+	// MinKubernetesVersion and MaxKubernetesVersion are the inclusive range of the versions of
+	// Kubernetes that the check applies to, a zero version is unbounded
+	MinKubernetesVersion config.Semver
+	MaxKubernetesVersion config.Semver
+}
+
+// HasID returns true if id is the ID or one of the aliases of the check
+func (c Check) HasID(id string) bool {
+	return id == c.ID || slices.Contains(c.Aliases, id)
 }
 
 type NamedReader interface {
@@ -29,6 +54,11 @@ type FileLocation struct {
 	Name string
 	Skip bool
 	Line int
+	// Duplicates are the locations of identical copies of the object in the input, that are only scored once
+	Duplicates []FileLocation
+	// Exported is true if the object was exported from a cluster, its status and server populated
+	// fields are removed before it is scored
+	Exported bool
 }
 
 type BothMeta struct {
@@ -67,6 +97,8 @@ type Ingress interface {
 	GetTypeMeta() metav1.TypeMeta
 	GetObjectMeta() metav1.ObjectMeta
 	Rules() []networkingv1.IngressRule
+	TLS() []networkingv1.IngressTLS
+	IngressClassName() string
 	FileLocationer
 	// Annotations
 }
@@ -99,6 +131,39 @@ type Services interface {
 	Services() []Service
 }
 
+type ConfigMap interface {
+	ConfigMap() corev1.ConfigMap
+	FileLocationer
+}
+
+type ConfigMaps interface {
+	ConfigMaps() []ConfigMap
+}
+
+type Secret interface {
+	Secret() corev1.Secret
+	FileLocationer
+}
+
+type Secrets interface {
+	Secrets() []Secret
+}
+
+// OtherObjects are the objects of kinds that are not scored, such as custom resources. Only
+// their metadata is parsed.
+type OtherObjects interface {
+	OtherObjects() []BothMeta
+}
+
+type ServiceAccount interface {
+	ServiceAccount() corev1.ServiceAccount
+	FileLocationer
+}
+
+type ServiceAccounts interface {
+	ServiceAccounts() []ServiceAccount
+}
+
 type StatefulSet interface {
 	StatefulSet() appsv1.StatefulSet
 	FileLocationer
@@ -129,6 +194,218 @@ type NetworkPolicies interface {
 	NetworkPolicies() []NetworkPolicy
 }
 
+type PriorityClass interface {
+	PriorityClass() schedulingv1.PriorityClass
+	FileLocationer
+}
+
+type PriorityClasses interface {
+	PriorityClasses() []PriorityClass
+}
+
+type StorageClass interface {
+	StorageClass() storagev1.StorageClass
+	FileLocationer
+}
+
+type StorageClasses interface {
+	StorageClasses() []StorageClass
+}
+
+// WebhookConfiguration is a MutatingWebhookConfiguration or a ValidatingWebhookConfiguration
+type WebhookConfiguration interface {
+	GetTypeMeta() metav1.TypeMeta
+	GetObjectMeta() metav1.ObjectMeta
+	Webhooks() []Webhook
+	FileLocationer
+}
+
+// Webhook are the fields that mutating and validating webhooks have in common
+type Webhook struct {
+	Name              string
+	FailurePolicy     *admissionregistrationv1.FailurePolicyType
+	TimeoutSeconds    *int32
+	NamespaceSelector *metav1.LabelSelector
+	SideEffects       *admissionregistrationv1.SideEffectClass
+}
+
+type WebhookConfigurations interface {
+	WebhookConfigurations() []WebhookConfiguration
+}
+
+// CustomResourceDefinition is an apiextensions.k8s.io/v1 CustomResourceDefinition
+type CustomResourceDefinition interface {
+	GetTypeMeta() metav1.TypeMeta
+	GetObjectMeta() metav1.ObjectMeta
+	Spec() CustomResourceDefinitionSpec
+	FileLocationer
+}
+
+// CustomResourceDefinitionSpec are the fields of the spec of a CustomResourceDefinition that are
+// used by the checks. The apiextensions types are not a dependency of kube-score, the fields have the
+// same names and JSON representation.
+type CustomResourceDefinitionSpec struct {
+	Versions   []CustomResourceDefinitionVersion `json:"versions"`
+	Conversion *CustomResourceConversion         `json:"conversion,omitempty"`
+}
+
+type CustomResourceDefinitionVersion struct {
+	Name               string                    `json:"name"`
+	Served             bool                      `json:"served"`
+	Storage            bool                      `json:"storage"`
+	Deprecated         bool                      `json:"deprecated,omitempty"`
+	DeprecationWarning *string                   `json:"deprecationWarning,omitempty"`
+	Schema             *CustomResourceValidation `json:"schema,omitempty"`
+}
+
+type CustomResourceValidation struct {
+	OpenAPIV3Schema *JSONSchemaProps `json:"openAPIV3Schema,omitempty"`
+}
+
+// JSONSchemaProps are the fields of an OpenAPI v3 schema that decide if the schema is structural
+type JSONSchemaProps struct {
+	Type                   string                     `json:"type,omitempty"`
+	Properties             map[string]JSONSchemaProps `json:"properties,omitempty"`
+	Items                  *JSONSchemaProps           `json:"items,omitempty"`
+	XPreserveUnknownFields *bool                      `json:"x-kubernetes-preserve-unknown-fields,omitempty"`
+	XIntOrString           bool                       `json:"x-kubernetes-int-or-string,omitempty"`
+}
+
+type CustomResourceConversion struct {
+	Strategy string             `json:"strategy"`
+	Webhook  *WebhookConversion `json:"webhook,omitempty"`
+}
+
+type WebhookConversion struct {
+	ClientConfig             *admissionregistrationv1.WebhookClientConfig `json:"clientConfig,omitempty"`
+	ConversionReviewVersions []string                                     `json:"conversionReviewVersions"`
+}
+
+type CustomResourceDefinitions interface {
+	CustomResourceDefinitions() []CustomResourceDefinition
+}
+
+// ServiceMonitor is a monitoring.coreos.com/v1 ServiceMonitor of the Prometheus Operator
+type ServiceMonitor interface {
+	GetTypeMeta() metav1.TypeMeta
+	GetObjectMeta() metav1.ObjectMeta
+	Spec() ServiceMonitorSpec
+	FileLocationer
+}
+
+// ServiceMonitorSpec are the fields of the spec of a ServiceMonitor that are used by the checks
+type ServiceMonitorSpec struct {
+	Selector          metav1.LabelSelector     `json:"selector"`
+	NamespaceSelector MonitorNamespaceSelector `json:"namespaceSelector,omitempty"`
+	Endpoints         []MonitorEndpoint        `json:"endpoints,omitempty"`
+}
+
+// PodMonitor is a monitoring.coreos.com/v1 PodMonitor of the Prometheus Operator
+type PodMonitor interface {
+	GetTypeMeta() metav1.TypeMeta
+	GetObjectMeta() metav1.ObjectMeta
+	Spec() PodMonitorSpec
+	FileLocationer
+}
+
+// PodMonitorSpec are the fields of the spec of a PodMonitor that are used by the checks
+type PodMonitorSpec struct {
+	Selector            metav1.LabelSelector     `json:"selector"`
+	NamespaceSelector   MonitorNamespaceSelector `json:"namespaceSelector,omitempty"`
+	PodMetricsEndpoints []MonitorEndpoint        `json:"podMetricsEndpoints,omitempty"`
+}
+
+// MonitorNamespaceSelector selects the namespaces of the targets of a monitor, the namespace of the
+// monitor is selected if it is empty
+type MonitorNamespaceSelector struct {
+	Any        bool     `json:"any,omitempty"`
+	MatchNames []string `json:"matchNames,omitempty"`
+}
+
+// MonitorEndpoint is an endpoint of a monitor, Port is the name of the port of the Service or the
+// container
+type MonitorEndpoint struct {
+	Port string `json:"port,omitempty"`
+}
+
+// Certificate is a cert-manager.io/v1 Certificate
+type Certificate interface {
+	GetTypeMeta() metav1.TypeMeta
+	GetObjectMeta() metav1.ObjectMeta
+	// SecretName is the name of the Secret that the certificate is stored in
+	SecretName() string
+	FileLocationer
+}
+
+type Certificates interface {
+	Certificates() []Certificate
+}
+
+// RuntimeClass is a node.k8s.io/v1 RuntimeClass
+type RuntimeClass interface {
+	RuntimeClass() nodev1.RuntimeClass
+	FileLocationer
+}
+
+type RuntimeClasses interface {
+	RuntimeClasses() []RuntimeClass
+}
+
+type ResourceQuota interface {
+	ResourceQuota() corev1.ResourceQuota
+	FileLocationer
+}
+
+type ResourceQuotas interface {
+	ResourceQuotas() []ResourceQuota
+}
+
+type LimitRange interface {
+	LimitRange() corev1.LimitRange
+	FileLocationer
+}
+
+type LimitRanges interface {
+	LimitRanges() []LimitRange
+}
+
+// ScoreException is a kube-score.com/v1alpha1 ScoreException, that ignores checks of the objects
+// in its namespace
+type ScoreException interface {
+	GetTypeMeta() metav1.TypeMeta
+	GetObjectMeta() metav1.ObjectMeta
+	Spec() ScoreExceptionSpec
+	FileLocationer
+}
+
+// ScoreExceptionSpec is the spec of a ScoreException. The checks are ignored on the objects that
+// match the selector, or on all objects of the namespace if the selector is nil, until the end of
+// the Expires date in the format YYYY-MM-DD.
+type ScoreExceptionSpec struct {
+	Checks   []string              `json:"checks"`
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	Expires  string                `json:"expires,omitempty"`
+	Reason   string                `json:"reason"`
+}
+
+type ScoreExceptions interface {
+	ScoreExceptions() []ScoreException
+}
+
+// Documents are the objects of the input as JSON documents. The documents are only kept if the
+// parser is configured to keep them, see parser.Config.KeepDocuments.
+type Documents interface {
+	Document(typeMeta metav1.TypeMeta, objectMeta metav1.ObjectMeta) ([]byte, bool)
+}
+
+type ServiceMonitors interface {
+	ServiceMonitors() []ServiceMonitor
+}
+
+type PodMonitors interface {
+	PodMonitors() []PodMonitor
+}
+
 type Ingresses interface {
 	Ingresses() []Ingress
 }
@@ -136,6 +413,9 @@ type Ingresses interface {
 type Job interface {
 	GetTypeMeta() metav1.TypeMeta
 	GetObjectMeta() metav1.ObjectMeta
+	Completions() *int32
+	Parallelism() *int32
+	CompletionMode() *batchv1.CompletionMode
 	GetPodTemplateSpec() corev1.PodTemplateSpec
 	FileLocationer
 	// Annotations
@@ -149,6 +429,8 @@ type CronJob interface {
 	GetTypeMeta() metav1.TypeMeta
 	GetObjectMeta() metav1.ObjectMeta
 	StartingDeadlineSeconds() *int64
+	Schedule() string
+	TimeZone() *string
 	GetPodTemplateSpec() corev1.PodTemplateSpec
 	FileLocationer
 	// Annotations
@@ -182,6 +464,9 @@ type AllTypes interface {
 	Jobs
 	PodSpeccers
 	Services
+	ConfigMaps
+	Secrets
+	ServiceAccounts
 	StatefulSets
 	Deployments
 	NetworkPolicies
@@ -189,4 +474,17 @@ type AllTypes interface {
 	CronJobs
 	PodDisruptionBudgets
 	HorizontalPodAutoscalers
+	PriorityClasses
+	StorageClasses
+	WebhookConfigurations
+	CustomResourceDefinitions
+	ServiceMonitors
+	PodMonitors
+	Certificates
+	RuntimeClasses
+	ResourceQuotas
+	LimitRanges
+	ScoreExceptions
+	OtherObjects
+	Documents
 }
@@ -0,0 +1,13 @@
+package domain
+
+// DefaultNamespace is the namespace of the objects that do not set a namespace, e.g. the
+// namespace that kubectl applies them to
+type DefaultNamespace string
+
+// Of returns the namespace, or the default namespace if the namespace is empty
+func (d DefaultNamespace) Of(namespace string) string {
+	if namespace == "" {
+		return string(d)
+	}
+	return namespace
+}
@@ -51,10 +51,13 @@ func ExampleCheckObject(raw []byte) (*scorecard.Scorecard, error) {
 	// Register check functions to run
 	checks := checks.New(nil)
 	checks.RegisterDeploymentCheck(
-		"custom-deployment-check",
+		"custom/deployment-check",
 		"A custom kube-score check function",
 		customDeploymentCheck,
 	)
+	if err := checks.Err(); err != nil {
+		return nil, err
+	}
 
 	return score.Score(allObjects, checks, &config.RunConfiguration{})
 }
@@ -30,7 +30,7 @@ spec:
 
 	for _, v := range *card {
 		assert.Len(t, v.Checks, 1)
-		assert.Equal(t, "custom-deployment-check", v.Checks[0].Check.ID)
+		assert.Equal(t, "custom/deployment-check", v.Checks[0].Check.ID)
 		assert.Equal(t, scorecard.GradeAllOK, v.Checks[0].Grade)
 	}
 }
@@ -58,7 +58,7 @@ spec:
 
 	for _, v := range *card {
 		assert.Len(t, v.Checks, 1)
-		assert.Equal(t, "custom-deployment-check", v.Checks[0].Check.ID)
+		assert.Equal(t, "custom/deployment-check", v.Checks[0].Check.ID)
 		assert.Equal(t, scorecard.GradeCritical, v.Checks[0].Grade)
 	}
 }
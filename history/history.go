@@ -0,0 +1,217 @@
+// Package history stores the scorecards of kube-score runs in a SQLite database,
+// and reports how the grades change over time
+package history
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	// Registers the "sqlite" database/sql driver
+	_ "modernc.org/sqlite"
+
+	"github.com/romnn/kube-score/scorecard"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	created_at TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS results (
+	run_id   INTEGER NOT NULL REFERENCES runs(id),
+	object   TEXT NOT NULL,
+	check_id TEXT NOT NULL,
+	grade    INTEGER NOT NULL,
+	skipped  INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS results_run_id ON results(run_id);
+`
+
+type DB struct {
+	db *sql.DB
+}
+
+// Open opens the history database at path, and creates it if it does not exist
+func Open(ctx context.Context, path string) (*DB, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create schema: %w", err)
+	}
+	return &DB{db: db}, nil
+}
+
+func (h *DB) Close() error {
+	return h.db.Close()
+}
+
+// Append stores the results of all checks of the scorecard as a new run
+func (h *DB) Append(ctx context.Context, createdAt time.Time, scoreCard *scorecard.Scorecard) error {
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO runs (created_at) VALUES (?)`,
+		createdAt.UTC().Format(time.RFC3339),
+	)
+	if err != nil {
+		return err
+	}
+	runID, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(
+		ctx,
+		`INSERT INTO results (run_id, object, check_id, grade, skipped) VALUES (?, ?, ?, ?, ?)`,
+	)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, so := range *scoreCard {
+		for _, check := range so.Checks {
+			_, err := stmt.ExecContext(
+				ctx, runID, so.HumanFriendlyRef(), check.Check.ID, int(check.Grade), check.Skipped,
+			)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Run is the summary of a single stored run
+type Run struct {
+	ID        int64
+	CreatedAt time.Time
+	Critical  int
+	Warning   int
+}
+
+// Delta is a check of an object whose grade changed between two runs
+type Delta struct {
+	Object string
+	Check  string
+	// From is nil if the check was not run in the previous run
+	From *scorecard.Grade
+	// To is nil if the check was not run in the latest run
+	To *scorecard.Grade
+}
+
+// Improved returns true if the check was run in both runs, and the grade got better
+func (d Delta) Improved() bool {
+	return d.From != nil && d.To != nil && *d.To > *d.From
+}
+
+// Runs returns the summaries of the last limit runs, oldest first
+func (h *DB) Runs(ctx context.Context, limit int) ([]Run, error) {
+	rows, err := h.db.QueryContext(ctx, `
+		SELECT r.id, r.created_at,
+			COALESCE(SUM(res.skipped = 0 AND res.grade <= ?), 0),
+			COALESCE(SUM(res.skipped = 0 AND res.grade > ? AND res.grade <= ?), 0)
+		FROM runs r LEFT JOIN results res ON res.run_id = r.id
+		GROUP BY r.id
+		ORDER BY r.id DESC
+		LIMIT ?`,
+		scorecard.GradeCritical, scorecard.GradeCritical, scorecard.GradeWarning, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var runs []Run
+	for rows.Next() {
+		var run Run
+		var createdAt string
+		if err := rows.Scan(&run.ID, &createdAt, &run.Critical, &run.Warning); err != nil {
+			return nil, err
+		}
+		run.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+		if err != nil {
+			return nil, err
+		}
+		runs = append([]Run{run}, runs...)
+	}
+	return runs, rows.Err()
+}
+
+// Deltas returns the checks whose grade changed between the two runs.
+// Skipped checks are treated as not run.
+func (h *DB) Deltas(ctx context.Context, fromRunID, toRunID int64) ([]Delta, error) {
+	from, err := h.grades(ctx, fromRunID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := h.grades(ctx, toRunID)
+	if err != nil {
+		return nil, err
+	}
+
+	var deltas []Delta
+	for key, toGrade := range to {
+		fromGrade, ok := from[key]
+		switch {
+		case !ok:
+			deltas = append(deltas, Delta{Object: key.object, Check: key.check, To: &toGrade})
+		case fromGrade.String() != toGrade.String():
+			deltas = append(deltas, Delta{Object: key.object, Check: key.check, From: &fromGrade, To: &toGrade})
+		}
+	}
+	for key, fromGrade := range from {
+		if _, ok := to[key]; !ok {
+			deltas = append(deltas, Delta{Object: key.object, Check: key.check, From: &fromGrade})
+		}
+	}
+
+	sort.Slice(deltas, func(i, j int) bool {
+		if deltas[i].Object != deltas[j].Object {
+			return deltas[i].Object < deltas[j].Object
+		}
+		return deltas[i].Check < deltas[j].Check
+	})
+	return deltas, nil
+}
+
+type resultKey struct {
+	object string
+	check  string
+}
+
+func (h *DB) grades(ctx context.Context, runID int64) (map[resultKey]scorecard.Grade, error) {
+	rows, err := h.db.QueryContext(
+		ctx,
+		`SELECT object, check_id, grade FROM results WHERE run_id = ? AND skipped = 0`,
+		runID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	grades := make(map[resultKey]scorecard.Grade)
+	for rows.Next() {
+		var key resultKey
+		var grade int
+		if err := rows.Scan(&key.object, &key.check, &grade); err != nil {
+			return nil, err
+		}
+		grades[key] = scorecard.Grade(grade)
+	}
+	return grades, rows.Err()
+}
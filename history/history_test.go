@@ -0,0 +1,73 @@
+package history
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+type location struct{}
+
+func (location) FileLocation() ks.FileLocation {
+	return ks.FileLocation{}
+}
+
+func testScorecard(grades map[string]scorecard.Grade) *scorecard.Scorecard {
+	sc := scorecard.New()
+	o := sc.NewObject(
+		metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+		metav1.ObjectMeta{Name: "app"},
+		nil,
+	)
+	for id, grade := range grades {
+		o.Add(scorecard.TestScore{Grade: grade}, ks.Check{ID: id}, location{})
+	}
+	return &sc
+}
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	db, err := Open(ctx, filepath.Join(t.TempDir(), "history.db"))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, db.Append(ctx, start, testScorecard(map[string]scorecard.Grade{
+		"a": scorecard.GradeCritical,
+		"b": scorecard.GradeAllOK,
+		"c": scorecard.GradeWarning,
+	})))
+	assert.NoError(t, db.Append(ctx, start.Add(time.Hour), testScorecard(map[string]scorecard.Grade{
+		"a": scorecard.GradeAllOK,
+		"b": scorecard.GradeWarning,
+		"d": scorecard.GradeCritical,
+	})))
+
+	runs, err := db.Runs(ctx, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []Run{
+		{ID: 1, CreatedAt: start, Critical: 1, Warning: 1},
+		{ID: 2, CreatedAt: start.Add(time.Hour), Critical: 1, Warning: 1},
+	}, runs)
+
+	deltas, err := db.Deltas(ctx, 1, 2)
+	assert.NoError(t, err)
+	grade := func(g scorecard.Grade) *scorecard.Grade { return &g }
+	assert.Equal(t, []Delta{
+		{Object: "app apps/v1/Deployment", Check: "a", From: grade(scorecard.GradeCritical), To: grade(scorecard.GradeAllOK)},
+		{Object: "app apps/v1/Deployment", Check: "b", From: grade(scorecard.GradeAllOK), To: grade(scorecard.GradeWarning)},
+		{Object: "app apps/v1/Deployment", Check: "c", From: grade(scorecard.GradeWarning)},
+		{Object: "app apps/v1/Deployment", Check: "d", To: grade(scorecard.GradeCritical)},
+	}, deltas)
+	assert.True(t, deltas[0].Improved())
+	assert.False(t, deltas[1].Improved())
+}
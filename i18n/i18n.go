@@ -0,0 +1,73 @@
+// Package i18n translates the comments of the checks, with the message catalogs that are embedded in the binary
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// DefaultLanguage is the language that the messages are written in
+const DefaultLanguage = "en"
+
+//go:embed catalogs/*.yaml
+var catalogs embed.FS
+
+// Catalog maps the English messages to their translation
+type Catalog map[string]string
+
+// Languages returns the languages that have a catalog
+func Languages() []string {
+	entries, _ := catalogs.ReadDir("catalogs")
+	var languages []string
+	for _, e := range entries {
+		languages = append(languages, strings.TrimSuffix(e.Name(), path.Ext(e.Name())))
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Load returns the catalog of the language
+func Load(language string) (Catalog, error) {
+	data, err := catalogs.ReadFile("catalogs/" + language + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("unsupported language %q, expected one of %s", language, strings.Join(Languages(), ", "))
+	}
+	var c Catalog
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("invalid catalog of %q: %w", language, err)
+	}
+	return c, nil
+}
+
+// T returns the translation of the message, or the message if it is not translated
+func (c Catalog) T(message string) string {
+	if translated, ok := c[message]; ok {
+		return translated
+	}
+	return message
+}
+
+// Translate translates the summaries and descriptions of all comments of the scorecard
+func Translate(scoreCard *scorecard.Scorecard, c Catalog) {
+	if len(c) == 0 {
+		return
+	}
+	for _, so := range *scoreCard {
+		for i := range so.Checks {
+			comments := make([]scorecard.TestScoreComment, len(so.Checks[i].Comments))
+			for j, comment := range so.Checks[i].Comments {
+				comment.Summary = c.T(comment.Summary)
+				comment.Description = c.T(comment.Description)
+				comments[j] = comment
+			}
+			so.Checks[i].Comments = comments
+		}
+	}
+}
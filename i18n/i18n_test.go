@@ -0,0 +1,63 @@
+package i18n
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func TestLanguages(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, []string{"de", "en", "ja"}, Languages())
+
+	_, err := Load("xx")
+	assert.ErrorContains(t, err, `unsupported language "xx", expected one of de, en, ja`)
+}
+
+func TestTranslate(t *testing.T) {
+	t.Parallel()
+	c, err := Load("de")
+	assert.NoError(t, err)
+
+	card := scorecard.Scorecard{"a": &scorecard.ScoredObject{
+		Checks: []scorecard.TestScore{{Comments: []scorecard.TestScoreComment{
+			{Path: "app", Summary: "CPU limit is not set", Description: "Not translated"},
+		}}},
+	}}
+	Translate(&card, c)
+	assert.Equal(t, scorecard.TestScoreComment{
+		Path:        "app",
+		Summary:     "Das CPU-Limit ist nicht gesetzt",
+		Description: "Not translated",
+	}, card["a"].Checks[0].Comments[0])
+}
+
+// TestCatalogsMatchSource makes sure that all translated messages are still used by a check
+func TestCatalogsMatchSource(t *testing.T) {
+	t.Parallel()
+	var source strings.Builder
+	err := filepath.WalkDir("../score", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		source.Write(data)
+		return err
+	})
+	assert.NoError(t, err)
+
+	for _, language := range Languages() {
+		c, err := Load(language)
+		assert.NoError(t, err)
+		for message := range c {
+			assert.Contains(t, source.String(), strconv.Quote(message), "%s: unknown message", language)
+		}
+	}
+}
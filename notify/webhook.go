@@ -0,0 +1,203 @@
+// Package notify posts a summary of a kube-score run to chat webhooks
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/romnn/kube-score/scorecard"
+)
+
+type Format string
+
+const (
+	FormatAuto  Format = "auto"
+	FormatSlack Format = "slack"
+	FormatTeams Format = "teams"
+	FormatJSON  Format = "json"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case "", FormatAuto:
+		return FormatAuto, nil
+	case FormatSlack, FormatTeams, FormatJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unknown webhook format %q", s)
+	}
+}
+
+// formatFromURL detects the webhook format from the well-known webhook hosts
+func formatFromURL(webhookURL string) Format {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return FormatJSON
+	}
+	switch host := u.Hostname(); {
+	case host == "hooks.slack.com":
+		return FormatSlack
+	case strings.HasSuffix(host, ".webhook.office.com"),
+		strings.HasSuffix(host, ".logic.azure.com"):
+		return FormatTeams
+	default:
+		return FormatJSON
+	}
+}
+
+// Offender is an object with failed checks
+type Offender struct {
+	Object   string `json:"object"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Critical int    `json:"critical"`
+	Warning  int    `json:"warning"`
+}
+
+// Summary is the summary of a kube-score run
+type Summary struct {
+	Objects        int        `json:"objects"`
+	Critical       int        `json:"critical"`
+	Warning        int        `json:"warning"`
+	WorstOffenders []Offender `json:"worstOffenders"`
+}
+
+// Summarize counts the failed checks of the scorecard, and returns the maxOffenders objects with
+// the most critical, and then warning, checks
+func Summarize(scoreCard *scorecard.Scorecard, maxOffenders int) Summary {
+	var summary Summary
+	var offenders []Offender
+
+	for _, so := range *scoreCard {
+		summary.Objects++
+		offender := Offender{
+			Object: so.HumanFriendlyRef(),
+			File:   so.FileLocation.Name,
+			Line:   so.FileLocation.Line,
+		}
+		for _, check := range so.Checks {
+			if check.Skipped {
+				continue
+			}
+			switch {
+			case check.Grade <= scorecard.GradeCritical:
+				offender.Critical++
+			case check.Grade <= scorecard.GradeWarning:
+				offender.Warning++
+			}
+		}
+		summary.Critical += offender.Critical
+		summary.Warning += offender.Warning
+		if offender.Critical > 0 || offender.Warning > 0 {
+			offenders = append(offenders, offender)
+		}
+	}
+
+	sort.Slice(offenders, func(i, j int) bool {
+		if offenders[i].Critical != offenders[j].Critical {
+			return offenders[i].Critical > offenders[j].Critical
+		}
+		if offenders[i].Warning != offenders[j].Warning {
+			return offenders[i].Warning > offenders[j].Warning
+		}
+		return offenders[i].Object < offenders[j].Object
+	})
+	if len(offenders) > maxOffenders {
+		offenders = offenders[:maxOffenders]
+	}
+	summary.WorstOffenders = offenders
+
+	return summary
+}
+
+func (s Summary) title() string {
+	return fmt.Sprintf(
+		"kube-score: %d critical and %d warning checks in %d objects",
+		s.Critical, s.Warning, s.Objects,
+	)
+}
+
+// lines returns one line per worst offender, with the given bullet prefix
+func (s Summary) lines(bullet string) []string {
+	var lines []string
+	for _, o := range s.WorstOffenders {
+		line := fmt.Sprintf("%s%s: %d critical, %d warning", bullet, o.Object, o.Critical, o.Warning)
+		if o.File != "" {
+			line += fmt.Sprintf(" (%s:%d)", o.File, o.Line)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Payload renders the summary as the body of a webhook request
+func Payload(format Format, summary Summary) ([]byte, error) {
+	switch format {
+	case FormatSlack:
+		text := "*" + summary.title() + "*"
+		if lines := summary.lines("• "); len(lines) > 0 {
+			text += "\nWorst offenders:\n" + strings.Join(lines, "\n")
+		}
+		return json.Marshal(map[string]any{"text": text})
+
+	case FormatTeams:
+		text := "Worst offenders:\n\n" + strings.Join(summary.lines("- "), "\n")
+		if len(summary.WorstOffenders) == 0 {
+			text = "All checks passed"
+		}
+		return json.Marshal(map[string]any{
+			"@type":    "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"summary":  summary.title(),
+			"title":    summary.title(),
+			"text":     text,
+		})
+
+	case FormatJSON:
+		return json.Marshal(summary)
+
+	default:
+		return nil, fmt.Errorf("unknown webhook format %q", format)
+	}
+}
+
+// Send posts the summary of the scorecard to the webhook
+func Send(
+	ctx context.Context,
+	client *http.Client,
+	webhookURL string,
+	format Format,
+	scoreCard *scorecard.Scorecard,
+) error {
+	if format == FormatAuto {
+		format = formatFromURL(webhookURL)
+	}
+
+	body, err := Payload(format, Summarize(scoreCard, 5))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func testScorecard() *scorecard.Scorecard {
+	sc := scorecard.New()
+	add := func(name string, grades ...scorecard.Grade) {
+		o := sc.NewObject(
+			metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+			metav1.ObjectMeta{Name: name},
+			nil,
+		)
+		for _, g := range grades {
+			o.Add(scorecard.TestScore{Grade: g}, ks.Check{ID: "test"}, location{name: name + ".yaml"})
+		}
+	}
+	add("a", scorecard.GradeAllOK, scorecard.GradeWarning)
+	add("b", scorecard.GradeCritical, scorecard.GradeWarning, scorecard.GradeWarning)
+	add("c", scorecard.GradeAllOK)
+	add("d", scorecard.GradeCritical)
+	return &sc
+}
+
+type location struct {
+	name string
+}
+
+func (l location) FileLocation() ks.FileLocation {
+	return ks.FileLocation{Name: l.name, Line: 1}
+}
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+
+	summary := Summarize(testScorecard(), 2)
+	assert.Equal(t, Summary{
+		Objects:  4,
+		Critical: 2,
+		Warning:  3,
+		WorstOffenders: []Offender{
+			{Object: "b apps/v1/Deployment", File: "b.yaml", Line: 1, Critical: 1, Warning: 2},
+			{Object: "d apps/v1/Deployment", File: "d.yaml", Line: 1, Critical: 1},
+		},
+	}, summary)
+}
+
+func TestFormatFromURL(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, FormatSlack, formatFromURL("https://hooks.slack.com/services/T000/B000/XXX"))
+	assert.Equal(t, FormatTeams, formatFromURL("https://example.webhook.office.com/webhookb2/xxx"))
+	assert.Equal(t, FormatJSON, formatFromURL("https://example.com/hook"))
+}
+
+func TestSend(t *testing.T) {
+	t.Parallel()
+
+	var payload map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &payload)
+	}))
+	defer srv.Close()
+
+	err := Send(context.Background(), srv.Client(), srv.URL, FormatSlack, testScorecard())
+	assert.NoError(t, err)
+	assert.Equal(t,
+		"*kube-score: 2 critical and 3 warning checks in 4 objects*\n"+
+			"Worst offenders:\n"+
+			"• b apps/v1/Deployment: 1 critical, 2 warning (b.yaml:1)\n"+
+			"• d apps/v1/Deployment: 1 critical, 0 warning (d.yaml:1)\n"+
+			"• a apps/v1/Deployment: 0 critical, 1 warning (a.yaml:1)",
+		payload["text"],
+	)
+}
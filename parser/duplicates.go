@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	ks "github.com/romnn/kube-score/domain"
+)
+
+type objectIdentity struct {
+	Metadata struct {
+		Name      string `json:"name"`
+		Namespace string `json:"namespace"`
+	} `json:"metadata"`
+}
+
+// decodedDocuments are the unique documents of the input, in the order of their first
+// occurrence. Documents are identical if they have the same kind, namespace and name, and the same
+// contents regardless of formatting and comments, such as the same object of a kustomize base
+// rendered in multiple overlays. The locations of the identical documents are added to the
+// duplicates of the first document.
+type decodedDocuments struct {
+	documents []decodedDocument
+	// first is the index of the first document by documentKey
+	first map[[sha256.Size]byte]int
+}
+
+type decodedDocument struct {
+	location ks.FileLocation
+	// objects are the positions of the objects of the document in the lists of parsedObjects
+	objects []objectPosition
+}
+
+type objectPosition struct {
+	list, index int32
+}
+
+func newDecodedDocuments() *decodedDocuments {
+	return &decodedDocuments{first: make(map[[sha256.Size]byte]int)}
+}
+
+// add decodes the objects of the document into s. The document is not kept, if it is identical to
+// a document that was added before, its location is added to the duplicates of the objects of
+// that document instead.
+func (d *decodedDocuments) add(p *Parser, s *parsedObjects, doc document) error {
+	key, ok := documentKey(doc)
+	if i, found := d.first[key]; ok && found {
+		first := &d.documents[i]
+		first.location.Duplicates = append(
+			first.location.Duplicates,
+			detectFileLocation(doc.fileName, doc.offset, doc.raw),
+		)
+		// The location is part of the objects, they are decoded again from the identical document
+		// and replace the objects of the first document
+		decoded := &parsedObjects{}
+		if err := p.decodeItem(decoded, doc, first.location); err != nil {
+			return err
+		}
+		lists, from := s.lists(), decoded.lists()
+		for _, o := range first.objects {
+			lists[o.list].copyFrom(int(o.index), from[o.list])
+		}
+		return nil
+	}
+
+	location := detectFileLocation(doc.fileName, doc.offset, doc.raw)
+	location.Exported = doc.stripped
+	if ok {
+		d.first[key] = len(d.documents)
+	}
+
+	lists := s.lists()
+	before := make([]int, len(lists))
+	for i, l := range lists {
+		before[i] = l.len()
+	}
+	err := p.decodeItem(s, doc, location)
+
+	decoded := decodedDocument{location: location}
+	for i, l := range lists {
+		if l.len() > before[i] {
+			decoded.objects = append(decoded.objects, objectPosition{list: int32(i), index: int32(before[i])})
+		}
+	}
+	d.documents = append(d.documents, decoded)
+	return err
+}
+
+// exported returns the number of unique objects that were exported from a cluster
+func (d *decodedDocuments) exported() (n int) {
+	for _, doc := range d.documents {
+		if doc.location.Exported {
+			n++
+		}
+	}
+	return n
+}
+
+// documentKey returns the identity and the hash of the contents of the document. ok is false if the
+// document can not be compared, documents without a name are never deduplicated.
+func documentKey(d document) (key [sha256.Size]byte, ok bool) {
+	var identity objectIdentity
+	if err := json.Unmarshal(d.contents, &identity); err != nil || identity.Metadata.Name == "" {
+		return key, false
+	}
+
+	// The keys of the JSON are sorted, so that the hash does not depend on their order
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s/%s/%s/", d.kind, identity.Metadata.Namespace, identity.Metadata.Name)
+	_, _ = h.Write(d.contents)
+	return [sha256.Size]byte(h.Sum(nil)), true
+}
@@ -0,0 +1,36 @@
+package internal
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+)
+
+var _ ks.Certificate = (*CertificateV1)(nil)
+
+// CertificateV1 is a cert-manager.io/v1 Certificate, only the fields that are used by the checks
+// are decoded
+type CertificateV1 struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		SecretName string `json:"secretName"`
+	} `json:"spec"`
+	Location ks.FileLocation `json:"-"`
+}
+
+func (c CertificateV1) FileLocation() ks.FileLocation {
+	return c.Location
+}
+
+func (c CertificateV1) GetObjectMeta() metav1.ObjectMeta {
+	return c.ObjectMeta
+}
+
+func (c CertificateV1) GetTypeMeta() metav1.TypeMeta {
+	return c.TypeMeta
+}
+
+func (c CertificateV1) SecretName() string {
+	return c.Spec.SecretName
+}
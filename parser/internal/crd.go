@@ -0,0 +1,32 @@
+package internal
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+)
+
+var _ ks.CustomResourceDefinition = (*CustomResourceDefinitionV1)(nil)
+
+type CustomResourceDefinitionV1 struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	CRDSpec           ks.CustomResourceDefinitionSpec `json:"spec"`
+	Location          ks.FileLocation                 `json:"-"`
+}
+
+func (c CustomResourceDefinitionV1) FileLocation() ks.FileLocation {
+	return c.Location
+}
+
+func (c CustomResourceDefinitionV1) GetObjectMeta() metav1.ObjectMeta {
+	return c.ObjectMeta
+}
+
+func (c CustomResourceDefinitionV1) GetTypeMeta() metav1.TypeMeta {
+	return c.TypeMeta
+}
+
+func (c CustomResourceDefinitionV1) Spec() ks.CustomResourceDefinitionSpec {
+	return c.CRDSpec
+}
@@ -13,6 +13,16 @@ var _ ks.Ingress = (*IngressV1)(nil)
 var _ ks.Ingress = (*IngressV1beta1)(nil)
 var _ ks.Ingress = (*ExtensionsIngressV1beta1)(nil)
 
+// ingressClassAnnotation is the deprecated predecessor of spec.ingressClassName
+const ingressClassAnnotation = "kubernetes.io/ingress.class"
+
+func ingressClassName(className *string, annotations map[string]string) string {
+	if className != nil {
+		return *className
+	}
+	return annotations[ingressClassAnnotation]
+}
+
 type IngressV1 struct {
 	networkingv1.Ingress
 	Location ks.FileLocation
@@ -34,6 +44,14 @@ func (i IngressV1) Rules() []networkingv1.IngressRule {
 	return i.Spec.Rules
 }
 
+func (i IngressV1) TLS() []networkingv1.IngressTLS {
+	return i.Spec.TLS
+}
+
+func (i IngressV1) IngressClassName() string {
+	return ingressClassName(i.Spec.IngressClassName, i.Annotations)
+}
+
 type IngressV1beta1 struct {
 	networkingv1beta1.Ingress
 	Location ks.FileLocation
@@ -51,13 +69,34 @@ func (i IngressV1beta1) GetTypeMeta() v1.TypeMeta {
 	return i.TypeMeta
 }
 
+func (i IngressV1beta1) TLS() []networkingv1.IngressTLS {
+	var res []networkingv1.IngressTLS
+	for _, tls := range i.Spec.TLS {
+		res = append(res, networkingv1.IngressTLS{
+			Hosts:      tls.Hosts,
+			SecretName: tls.SecretName,
+		})
+	}
+	return res
+}
+
+func (i IngressV1beta1) IngressClassName() string {
+	return ingressClassName(i.Spec.IngressClassName, i.Annotations)
+}
+
 func (i IngressV1beta1) Rules() []networkingv1.IngressRule {
 	var res []networkingv1.IngressRule
 
 	paths := func(in []networkingv1beta1.HTTPIngressPath) (out []networkingv1.HTTPIngressPath) {
 		for _, path := range in {
+			var pathType *networkingv1.PathType
+			if path.PathType != nil {
+				pt := networkingv1.PathType(*path.PathType)
+				pathType = &pt
+			}
 			out = append(out, networkingv1.HTTPIngressPath{
-				Path: path.Path,
+				Path:     path.Path,
+				PathType: pathType,
 				Backend: networkingv1.IngressBackend{
 					Service: &networkingv1.IngressServiceBackend{
 						Name: path.Backend.ServiceName,
@@ -104,13 +143,34 @@ func (i ExtensionsIngressV1beta1) GetTypeMeta() v1.TypeMeta {
 	return i.TypeMeta
 }
 
+func (i ExtensionsIngressV1beta1) TLS() []networkingv1.IngressTLS {
+	var res []networkingv1.IngressTLS
+	for _, tls := range i.Spec.TLS {
+		res = append(res, networkingv1.IngressTLS{
+			Hosts:      tls.Hosts,
+			SecretName: tls.SecretName,
+		})
+	}
+	return res
+}
+
+func (i ExtensionsIngressV1beta1) IngressClassName() string {
+	return ingressClassName(i.Spec.IngressClassName, i.Annotations)
+}
+
 func (i ExtensionsIngressV1beta1) Rules() []networkingv1.IngressRule {
 	var res []networkingv1.IngressRule
 
 	paths := func(in []extensionsv1beta1.HTTPIngressPath) (out []networkingv1.HTTPIngressPath) {
 		for _, path := range in {
+			var pathType *networkingv1.PathType
+			if path.PathType != nil {
+				pt := networkingv1.PathType(*path.PathType)
+				pathType = &pt
+			}
 			out = append(out, networkingv1.HTTPIngressPath{
-				Path: path.Path,
+				Path:     path.Path,
+				PathType: pathType,
 				Backend: networkingv1.IngressBackend{
 					Service: &networkingv1.IngressServiceBackend{
 						Name: path.Backend.ServiceName,
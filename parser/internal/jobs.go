@@ -25,6 +25,18 @@ func (d Batchv1Job) GetObjectMeta() metav1.ObjectMeta {
 	return d.ObjectMeta
 }
 
+func (d Batchv1Job) Completions() *int32 {
+	return d.Spec.Completions
+}
+
+func (d Batchv1Job) Parallelism() *int32 {
+	return d.Spec.Parallelism
+}
+
+func (d Batchv1Job) CompletionMode() *batchv1.CompletionMode {
+	return d.Spec.CompletionMode
+}
+
 func (d Batchv1Job) GetPodTemplateSpec() corev1.PodTemplateSpec {
 	d.Spec.Template.Namespace = d.Namespace
 	return d.Spec.Template
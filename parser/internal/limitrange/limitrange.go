@@ -0,0 +1,20 @@
+package limitrange
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+)
+
+type LimitRange struct {
+	Obj      corev1.LimitRange
+	Location ks.FileLocation
+}
+
+func (l LimitRange) LimitRange() corev1.LimitRange {
+	return l.Obj
+}
+
+func (l LimitRange) FileLocation() ks.FileLocation {
+	return l.Location
+}
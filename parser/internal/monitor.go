@@ -0,0 +1,56 @@
+package internal
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+)
+
+var _ ks.ServiceMonitor = (*ServiceMonitorV1)(nil)
+var _ ks.PodMonitor = (*PodMonitorV1)(nil)
+
+type ServiceMonitorV1 struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	MonitorSpec       ks.ServiceMonitorSpec `json:"spec"`
+	Location          ks.FileLocation       `json:"-"`
+}
+
+func (m ServiceMonitorV1) FileLocation() ks.FileLocation {
+	return m.Location
+}
+
+func (m ServiceMonitorV1) GetObjectMeta() metav1.ObjectMeta {
+	return m.ObjectMeta
+}
+
+func (m ServiceMonitorV1) GetTypeMeta() metav1.TypeMeta {
+	return m.TypeMeta
+}
+
+func (m ServiceMonitorV1) Spec() ks.ServiceMonitorSpec {
+	return m.MonitorSpec
+}
+
+type PodMonitorV1 struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	MonitorSpec       ks.PodMonitorSpec `json:"spec"`
+	Location          ks.FileLocation   `json:"-"`
+}
+
+func (m PodMonitorV1) FileLocation() ks.FileLocation {
+	return m.Location
+}
+
+func (m PodMonitorV1) GetObjectMeta() metav1.ObjectMeta {
+	return m.ObjectMeta
+}
+
+func (m PodMonitorV1) GetTypeMeta() metav1.TypeMeta {
+	return m.TypeMeta
+}
+
+func (m PodMonitorV1) Spec() ks.PodMonitorSpec {
+	return m.MonitorSpec
+}
@@ -0,0 +1,18 @@
+package internal
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+)
+
+// Other is an object of a kind that kube-score does not score, e.g. a custom resource.
+// Only its metadata is parsed.
+type Other struct {
+	metav1.PartialObjectMetadata
+	Location ks.FileLocation
+}
+
+func (o Other) FileLocation() ks.FileLocation {
+	return o.Location
+}
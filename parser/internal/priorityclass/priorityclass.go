@@ -0,0 +1,20 @@
+package priorityclass
+
+import (
+	schedulingv1 "k8s.io/api/scheduling/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+)
+
+type PriorityClass struct {
+	Obj      schedulingv1.PriorityClass
+	Location ks.FileLocation
+}
+
+func (p PriorityClass) PriorityClass() schedulingv1.PriorityClass {
+	return p.Obj
+}
+
+func (p PriorityClass) FileLocation() ks.FileLocation {
+	return p.Location
+}
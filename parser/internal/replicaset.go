@@ -0,0 +1,58 @@
+package internal
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+)
+
+type Appsv1ReplicaSet struct {
+	appsv1.ReplicaSet
+	Location ks.FileLocation
+}
+
+func (r Appsv1ReplicaSet) FileLocation() ks.FileLocation {
+	return r.Location
+}
+
+func (r Appsv1ReplicaSet) GetTypeMeta() metav1.TypeMeta {
+	return r.TypeMeta
+}
+
+func (r Appsv1ReplicaSet) GetObjectMeta() metav1.ObjectMeta {
+	return r.ObjectMeta
+}
+
+func (r Appsv1ReplicaSet) GetPodTemplateSpec() corev1.PodTemplateSpec {
+	r.Spec.Template.Namespace = r.Namespace
+	return r.Spec.Template
+}
+
+type Corev1ReplicationController struct {
+	corev1.ReplicationController
+	Location ks.FileLocation
+}
+
+func (r Corev1ReplicationController) FileLocation() ks.FileLocation {
+	return r.Location
+}
+
+func (r Corev1ReplicationController) GetTypeMeta() metav1.TypeMeta {
+	return r.TypeMeta
+}
+
+func (r Corev1ReplicationController) GetObjectMeta() metav1.ObjectMeta {
+	return r.ObjectMeta
+}
+
+// GetPodTemplateSpec returns the template of the pods, which is optional for ReplicationControllers
+func (r Corev1ReplicationController) GetPodTemplateSpec() corev1.PodTemplateSpec {
+	var template corev1.PodTemplateSpec
+	if r.Spec.Template != nil {
+		template = *r.Spec.Template
+	}
+	template.Namespace = r.Namespace
+	return template
+}
@@ -0,0 +1,20 @@
+package resourcequota
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+)
+
+type ResourceQuota struct {
+	Obj      corev1.ResourceQuota
+	Location ks.FileLocation
+}
+
+func (r ResourceQuota) ResourceQuota() corev1.ResourceQuota {
+	return r.Obj
+}
+
+func (r ResourceQuota) FileLocation() ks.FileLocation {
+	return r.Location
+}
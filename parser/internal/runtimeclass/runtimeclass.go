@@ -0,0 +1,20 @@
+package runtimeclass
+
+import (
+	nodev1 "k8s.io/api/node/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+)
+
+type RuntimeClass struct {
+	Obj      nodev1.RuntimeClass
+	Location ks.FileLocation
+}
+
+func (r RuntimeClass) RuntimeClass() nodev1.RuntimeClass {
+	return r.Obj
+}
+
+func (r RuntimeClass) FileLocation() ks.FileLocation {
+	return r.Location
+}
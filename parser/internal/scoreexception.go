@@ -0,0 +1,33 @@
+package internal
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+)
+
+var _ ks.ScoreException = (*ScoreExceptionV1alpha1)(nil)
+
+// ScoreExceptionV1alpha1 is a kube-score.com/v1alpha1 ScoreException
+type ScoreExceptionV1alpha1 struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	ExceptionSpec     ks.ScoreExceptionSpec `json:"spec"`
+	Location          ks.FileLocation       `json:"-"`
+}
+
+func (e ScoreExceptionV1alpha1) FileLocation() ks.FileLocation {
+	return e.Location
+}
+
+func (e ScoreExceptionV1alpha1) GetObjectMeta() metav1.ObjectMeta {
+	return e.ObjectMeta
+}
+
+func (e ScoreExceptionV1alpha1) GetTypeMeta() metav1.TypeMeta {
+	return e.TypeMeta
+}
+
+func (e ScoreExceptionV1alpha1) Spec() ks.ScoreExceptionSpec {
+	return e.ExceptionSpec
+}
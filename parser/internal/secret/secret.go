@@ -0,0 +1,20 @@
+package secret
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+)
+
+type Secret struct {
+	Obj      corev1.Secret
+	Location ks.FileLocation
+}
+
+func (s Secret) Secret() corev1.Secret {
+	return s.Obj
+}
+
+func (s Secret) FileLocation() ks.FileLocation {
+	return s.Location
+}
@@ -0,0 +1,20 @@
+package storageclass
+
+import (
+	storagev1 "k8s.io/api/storage/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+)
+
+type StorageClass struct {
+	Obj      storagev1.StorageClass
+	Location ks.FileLocation
+}
+
+func (s StorageClass) StorageClass() storagev1.StorageClass {
+	return s.Obj
+}
+
+func (s StorageClass) FileLocation() ks.FileLocation {
+	return s.Location
+}
@@ -0,0 +1,73 @@
+package internal
+
+import (
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+)
+
+var _ ks.WebhookConfiguration = (*MutatingWebhookConfigurationV1)(nil)
+var _ ks.WebhookConfiguration = (*ValidatingWebhookConfigurationV1)(nil)
+
+type MutatingWebhookConfigurationV1 struct {
+	admissionregistrationv1.MutatingWebhookConfiguration
+	Location ks.FileLocation
+}
+
+func (w MutatingWebhookConfigurationV1) FileLocation() ks.FileLocation {
+	return w.Location
+}
+
+func (w MutatingWebhookConfigurationV1) GetObjectMeta() v1.ObjectMeta {
+	return w.ObjectMeta
+}
+
+func (w MutatingWebhookConfigurationV1) GetTypeMeta() v1.TypeMeta {
+	return w.TypeMeta
+}
+
+func (w MutatingWebhookConfigurationV1) Webhooks() []ks.Webhook {
+	webhooks := make([]ks.Webhook, 0, len(w.MutatingWebhookConfiguration.Webhooks))
+	for _, webhook := range w.MutatingWebhookConfiguration.Webhooks {
+		webhooks = append(webhooks, ks.Webhook{
+			Name:              webhook.Name,
+			FailurePolicy:     webhook.FailurePolicy,
+			TimeoutSeconds:    webhook.TimeoutSeconds,
+			NamespaceSelector: webhook.NamespaceSelector,
+			SideEffects:       webhook.SideEffects,
+		})
+	}
+	return webhooks
+}
+
+type ValidatingWebhookConfigurationV1 struct {
+	admissionregistrationv1.ValidatingWebhookConfiguration
+	Location ks.FileLocation
+}
+
+func (w ValidatingWebhookConfigurationV1) FileLocation() ks.FileLocation {
+	return w.Location
+}
+
+func (w ValidatingWebhookConfigurationV1) GetObjectMeta() v1.ObjectMeta {
+	return w.ObjectMeta
+}
+
+func (w ValidatingWebhookConfigurationV1) GetTypeMeta() v1.TypeMeta {
+	return w.TypeMeta
+}
+
+func (w ValidatingWebhookConfigurationV1) Webhooks() []ks.Webhook {
+	webhooks := make([]ks.Webhook, 0, len(w.ValidatingWebhookConfiguration.Webhooks))
+	for _, webhook := range w.ValidatingWebhookConfiguration.Webhooks {
+		webhooks = append(webhooks, ks.Webhook{
+			Name:              webhook.Name,
+			FailurePolicy:     webhook.FailurePolicy,
+			TimeoutSeconds:    webhook.TimeoutSeconds,
+			NamespaceSelector: webhook.NamespaceSelector,
+			SideEffects:       webhook.SideEffects,
+		})
+	}
+	return webhooks
+}
@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrLimitExceeded is wrapped by the errors of the input that exceeds the Limits
+var ErrLimitExceeded = errors.New("the input exceeds the limits")
+
+// Limits bound the input that is parsed, so that untrusted input can not use up the memory and the
+// time of kube-score. The zero value of every limit is unlimited.
+type Limits struct {
+	// MaxDocuments is the maximum number of documents of all files. The items of a List are
+	// counted as documents.
+	MaxDocuments int
+	// MaxDocumentSize is the maximum size of a single document in bytes
+	MaxDocumentSize int
+	// MaxInputSize is the maximum size of all files in bytes
+	MaxInputSize int64
+	// Timeout is the maximum duration of the parsing. The parsing is canceled when it has passed:
+	// ParseFiles returns without waiting for a slow read or the decoding of a large document, and
+	// the parsing stops in the background at the next read or document.
+	Timeout time.Duration
+}
+
+// inputLimiter fails the reads of the files when more than max bytes have been read from all of
+// them together, or when the parsing is canceled
+type inputLimiter struct {
+	ctx  context.Context
+	read int64
+	max  int64
+}
+
+func (l *inputLimiter) reader(r io.Reader) io.Reader {
+	if l.max <= 0 && l.ctx.Done() == nil {
+		return r
+	}
+	return &limitedReader{r: r, limiter: l}
+}
+
+type limitedReader struct {
+	r       io.Reader
+	limiter *inputLimiter
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	// A large document is not read to its end after the parsing is canceled
+	if err := r.limiter.ctx.Err(); err != nil {
+		return 0, context.Cause(r.limiter.ctx)
+	}
+	n, err := r.r.Read(p)
+	r.limiter.read += int64(n)
+	if r.limiter.max > 0 && r.limiter.read > r.limiter.max {
+		return n, fmt.Errorf("%w: the files are larger than %d bytes", ErrLimitExceeded, r.limiter.max)
+	}
+	return n, err
+}
+
+// checkLimits returns an error if there are more documents than allowed, or if the parsing is
+// canceled
+func (p *Parser) checkLimits(ctx context.Context, documents int) error {
+	limits := p.config.Limits
+	if limits.MaxDocuments > 0 && documents > limits.MaxDocuments {
+		return fmt.Errorf("%w: there are more than %d documents", ErrLimitExceeded, limits.MaxDocuments)
+	}
+	if ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+	return nil
+}
+
+// parseState is shared by the parsing and ParseFilesContext, that returns when the parsing is
+// canceled without waiting for it
+type parseState struct {
+	mu sync.Mutex
+	// file is the name of the file that is parsed
+	file string
+	// abandoned is set when ParseFilesContext has returned, the callbacks of the Config are not
+	// called anymore
+	abandoned bool
+}
+
+func (s *parseState) setFile(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.file = name
+}
+
+// callback runs fn, unless ParseFilesContext has returned
+func (s *parseState) callback(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.abandoned {
+		fn()
+	}
+}
+
+// abandon returns the name of the file that is parsed, the parsing stops in the background
+func (s *parseState) abandon() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.abandoned = true
+	return s.file
+}
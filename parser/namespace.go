@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"bytes"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// clusterScopedKinds are the built-in kinds without a namespace, the default namespace of a
+// file is never set on them
+var clusterScopedKinds = map[schema.GroupKind]bool{
+	{Group: "", Kind: "Namespace"}:                                                    true,
+	{Group: "", Kind: "Node"}:                                                         true,
+	{Group: "", Kind: "PersistentVolume"}:                                             true,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"}:                         true,
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"}:                  true,
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}:                 true,
+	{Group: "apiregistration.k8s.io", Kind: "APIService"}:                             true,
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"}:     true,
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"}:   true,
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicy"}:        true,
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicyBinding"}: true,
+	{Group: "storage.k8s.io", Kind: "StorageClass"}:                                   true,
+	{Group: "storage.k8s.io", Kind: "CSIDriver"}:                                      true,
+	{Group: "storage.k8s.io", Kind: "VolumeAttachment"}:                               true,
+	{Group: "scheduling.k8s.io", Kind: "PriorityClass"}:                               true,
+	{Group: "networking.k8s.io", Kind: "IngressClass"}:                                true,
+	{Group: "node.k8s.io", Kind: "RuntimeClass"}:                                      true,
+	{Group: "policy", Kind: "PodSecurityPolicy"}:                                      true,
+	{Group: "flowcontrol.apiserver.k8s.io", Kind: "FlowSchema"}:                       true,
+	{Group: "flowcontrol.apiserver.k8s.io", Kind: "PriorityLevelConfiguration"}:       true,
+	{Group: "certificates.k8s.io", Kind: "CertificateSigningRequest"}:                 true,
+}
+
+// withFileNamespace sets the namespace on an object without a namespace. The object is returned
+// as is if the namespace is empty, the kind is cluster scoped, or the object can not be changed.
+func withFileNamespace(kind schema.GroupVersionKind, raw []byte, namespace string) []byte {
+	if namespace == "" || clusterScopedKinds[kind.GroupKind()] {
+		return raw
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil || len(doc.Content) == 0 {
+		return raw
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return raw
+	}
+
+	metadata := mappingValue(root, "metadata")
+	if metadata == nil {
+		metadata = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "metadata"}, metadata)
+	}
+	if metadata.Kind != yaml.MappingNode {
+		return raw
+	}
+
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: namespace}
+	switch existing := mappingValue(metadata, "namespace"); {
+	case existing == nil:
+		metadata.Content = append(metadata.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "namespace"}, value)
+	case existing.Kind == yaml.ScalarNode && (existing.Value == "" || existing.Tag == "!!null"):
+		*existing = *value
+	default:
+		return raw
+	}
+
+	// Items of lists are JSON, that must not be encoded as YAML with flow style
+	blockStyle(root)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return raw
+	}
+	return buf.Bytes()
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func blockStyle(node *yaml.Node) {
+	node.Style &^= yaml.FlowStyle
+	for _, n := range node.Content {
+		blockStyle(n)
+	}
+}
@@ -2,12 +2,15 @@ package parser
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"log"
-	"strings"
+	"os"
 
 	"gopkg.in/yaml.v3"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	appsv1beta1 "k8s.io/api/apps/v1beta1"
 	appsv1beta2 "k8s.io/api/apps/v1beta2"
@@ -21,36 +24,92 @@ import (
 	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
 	networkingv1 "k8s.io/api/networking/v1"
 	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	nodev1 "k8s.io/api/node/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
+	sigsyaml "sigs.k8s.io/yaml"
 
 	"github.com/romnn/kube-score/config"
 	ks "github.com/romnn/kube-score/domain"
 	"github.com/romnn/kube-score/parser/internal"
+	internalconfigmap "github.com/romnn/kube-score/parser/internal/configmap"
 	internalcronjob "github.com/romnn/kube-score/parser/internal/cronjob"
+	internallimitrange "github.com/romnn/kube-score/parser/internal/limitrange"
 	internalnetpol "github.com/romnn/kube-score/parser/internal/networkpolicy"
 	internalpdb "github.com/romnn/kube-score/parser/internal/pdb"
 	internalpod "github.com/romnn/kube-score/parser/internal/pod"
+	internalpriorityclass "github.com/romnn/kube-score/parser/internal/priorityclass"
+	internalresourcequota "github.com/romnn/kube-score/parser/internal/resourcequota"
+	internalruntimeclass "github.com/romnn/kube-score/parser/internal/runtimeclass"
+	internalsecret "github.com/romnn/kube-score/parser/internal/secret"
 	internalservice "github.com/romnn/kube-score/parser/internal/service"
+	internalserviceaccount "github.com/romnn/kube-score/parser/internal/serviceaccount"
+	internalstorageclass "github.com/romnn/kube-score/parser/internal/storageclass"
 )
 
 type Parser struct {
 	scheme *runtime.Scheme
 	codecs serializer.CodecFactory
-	config *Config
+	// deserializer is shared by all documents, instead of creating one per document
+	deserializer runtime.Decoder
+	config       *Config
 }
 
 type Config struct {
 	VerboseOutput   int
 	SkipExpressions []*config.SkipExpression
+	// FileNamespaces is the default namespace of the objects of a file by the name of the file,
+	// e.g. the namespace of a kustomization. It is set on the objects without a namespace.
+	FileNamespaces map[string]string
+	// KeepDocuments keeps every object as a JSON document, that is passed to the check plugins
+	KeepDocuments bool
+	// FileParsed is called after a file has been read, with the number of its documents
+	FileParsed func(name string, documents int)
+	// SkippedByExpression is called for every object that matches one of the SkipExpressions, and
+	// is not parsed
+	SkippedByExpression func(obj metav1.PartialObjectMetadata, location ks.FileLocation, expr *config.SkipExpression)
+	// Limits bound the size of the input, and the duration of the parsing
+	Limits Limits
 }
 
 type schemaAdderFunc func(scheme *runtime.Scheme) error
 
+var customResourceDefinitionV1 = schema.GroupVersionKind{
+	Group:   "apiextensions.k8s.io",
+	Version: "v1",
+	Kind:    "CustomResourceDefinition",
+}
+
+var serviceMonitorV1 = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "ServiceMonitor",
+}
+
+var podMonitorV1 = schema.GroupVersionKind{
+	Group:   "monitoring.coreos.com",
+	Version: "v1",
+	Kind:    "PodMonitor",
+}
+
+var certificateV1 = schema.GroupVersionKind{
+	Group:   "cert-manager.io",
+	Version: "v1",
+	Kind:    "Certificate",
+}
+
+var scoreExceptionV1alpha1 = schema.GroupVersionKind{
+	Group:   "kube-score.com",
+	Version: "v1alpha1",
+	Kind:    "ScoreException",
+}
+
 func New(config *Config) (*Parser, error) {
 	if config == nil {
 		config = &Config{}
@@ -65,6 +124,7 @@ func New(config *Config) (*Parser, error) {
 	if err := p.addToScheme(); err != nil {
 		return nil, fmt.Errorf("failed to init: %w", err)
 	}
+	p.deserializer = p.codecs.UniversalDeserializer()
 	return p, nil
 }
 
@@ -80,6 +140,10 @@ func (p *Parser) addToScheme() error {
 		batchv1beta1.AddToScheme,
 		policyv1beta1.AddToScheme,
 		policyv1.AddToScheme,
+		schedulingv1.AddToScheme,
+		storagev1.AddToScheme,
+		nodev1.AddToScheme,
+		admissionregistrationv1.AddToScheme,
 	}
 
 	for _, adder := range adders {
@@ -92,8 +156,8 @@ func (p *Parser) addToScheme() error {
 }
 
 type detectKind struct {
-	ApiVersion string `yaml:"apiVersion"`
-	Kind       string `yaml:"kind"`
+	ApiVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
 }
 
 type parsedObjects struct {
@@ -102,6 +166,9 @@ type parsedObjects struct {
 	podspecers           []ks.PodSpecer
 	networkPolicies      []ks.NetworkPolicy
 	services             []ks.Service
+	configMaps           []ks.ConfigMap
+	secrets              []ks.Secret
+	serviceAccounts      []ks.ServiceAccount
 	podDisruptionBudgets []ks.PodDisruptionBudget
 	deployments          []ks.Deployment
 	statefulsets         []ks.StatefulSet
@@ -109,12 +176,113 @@ type parsedObjects struct {
 	cronjobs             []ks.CronJob
 	jobs                 []ks.Job
 	hpaTargeters         []ks.HpaTargeter // all versions of HPAs
+	priorityClasses      []ks.PriorityClass
+	storageClasses       []ks.StorageClass
+	webhooks             []ks.WebhookConfiguration
+	crds                 []ks.CustomResourceDefinition
+	serviceMonitors      []ks.ServiceMonitor
+	podMonitors          []ks.PodMonitor
+	certificates         []ks.Certificate
+	runtimeClasses       []ks.RuntimeClass
+	resourceQuotas       []ks.ResourceQuota
+	limitRanges          []ks.LimitRange
+	scoreExceptions      []ks.ScoreException
+	otherObjects         []ks.BothMeta
+	documents            map[objectKey][]byte
+	// skipped are the objects that match a skip expression, they are reported once all documents
+	// have been read, with the locations of their duplicates
+	skipped []skippedObject
+}
+
+// skippedObject is an object that matches a skip expression
+type skippedObject struct {
+	obj      metav1.PartialObjectMetadata
+	location ks.FileLocation
+	expr     *config.SkipExpression
+}
+
+// objectList is a list of objects of parsedObjects
+type objectList interface {
+	len() int
+	// copyFrom replaces the objects from index i with the objects of other, a list of the same type
+	copyFrom(i int, other objectList)
+}
+
+type list[T any] struct{ items *[]T }
+
+func (l list[T]) len() int { return len(*l.items) }
+
+func (l list[T]) copyFrom(i int, other objectList) {
+	copy((*l.items)[i:], *other.(list[T]).items)
+}
+
+// lists returns all lists of objects, in the same order for all parsedObjects
+func (p *parsedObjects) lists() []objectList {
+	return []objectList{
+		list[ks.BothMeta]{&p.bothMetas},
+		list[ks.Pod]{&p.pods},
+		list[ks.PodSpecer]{&p.podspecers},
+		list[ks.NetworkPolicy]{&p.networkPolicies},
+		list[ks.Service]{&p.services},
+		list[ks.ConfigMap]{&p.configMaps},
+		list[ks.Secret]{&p.secrets},
+		list[ks.ServiceAccount]{&p.serviceAccounts},
+		list[ks.PodDisruptionBudget]{&p.podDisruptionBudgets},
+		list[ks.Deployment]{&p.deployments},
+		list[ks.StatefulSet]{&p.statefulsets},
+		list[ks.Ingress]{&p.ingresses},
+		list[ks.CronJob]{&p.cronjobs},
+		list[ks.Job]{&p.jobs},
+		list[ks.HpaTargeter]{&p.hpaTargeters},
+		list[ks.PriorityClass]{&p.priorityClasses},
+		list[ks.StorageClass]{&p.storageClasses},
+		list[ks.WebhookConfiguration]{&p.webhooks},
+		list[ks.CustomResourceDefinition]{&p.crds},
+		list[ks.ServiceMonitor]{&p.serviceMonitors},
+		list[ks.PodMonitor]{&p.podMonitors},
+		list[ks.Certificate]{&p.certificates},
+		list[ks.RuntimeClass]{&p.runtimeClasses},
+		list[ks.ResourceQuota]{&p.resourceQuotas},
+		list[ks.LimitRange]{&p.limitRanges},
+		list[ks.ScoreException]{&p.scoreExceptions},
+		list[ks.BothMeta]{&p.otherObjects},
+		list[skippedObject]{&p.skipped},
+	}
+}
+
+type objectKey struct {
+	apiVersion, kind, namespace, name string
+}
+
+func newObjectKey(typeMeta metav1.TypeMeta, objectMeta metav1.ObjectMeta) objectKey {
+	return objectKey{
+		apiVersion: typeMeta.APIVersion,
+		kind:       typeMeta.Kind,
+		namespace:  objectMeta.Namespace,
+		name:       objectMeta.Name,
+	}
 }
 
 func (p *parsedObjects) Services() []ks.Service {
 	return p.services
 }
 
+func (p *parsedObjects) ConfigMaps() []ks.ConfigMap {
+	return p.configMaps
+}
+
+func (p *parsedObjects) Secrets() []ks.Secret {
+	return p.secrets
+}
+
+func (p *parsedObjects) ServiceAccounts() []ks.ServiceAccount {
+	return p.serviceAccounts
+}
+
+func (p *parsedObjects) OtherObjects() []ks.BothMeta {
+	return p.otherObjects
+}
+
 func (p *parsedObjects) Pods() []ks.Pod {
 	return p.pods
 }
@@ -159,92 +327,259 @@ func (p *parsedObjects) HorizontalPodAutoscalers() []ks.HpaTargeter {
 	return p.hpaTargeters
 }
 
+func (p *parsedObjects) PriorityClasses() []ks.PriorityClass {
+	return p.priorityClasses
+}
+
+func (p *parsedObjects) StorageClasses() []ks.StorageClass {
+	return p.storageClasses
+}
+
+func (p *parsedObjects) WebhookConfigurations() []ks.WebhookConfiguration {
+	return p.webhooks
+}
+
+func (p *parsedObjects) CustomResourceDefinitions() []ks.CustomResourceDefinition {
+	return p.crds
+}
+
+func (p *parsedObjects) ServiceMonitors() []ks.ServiceMonitor {
+	return p.serviceMonitors
+}
+
+func (p *parsedObjects) PodMonitors() []ks.PodMonitor {
+	return p.podMonitors
+}
+
+func (p *parsedObjects) ScoreExceptions() []ks.ScoreException {
+	return p.scoreExceptions
+}
+
+func (p *parsedObjects) Document(typeMeta metav1.TypeMeta, objectMeta metav1.ObjectMeta) ([]byte, bool) {
+	doc, ok := p.documents[newObjectKey(typeMeta, objectMeta)]
+	return doc, ok
+}
+
+func (p *parsedObjects) Certificates() []ks.Certificate {
+	return p.certificates
+}
+
+func (p *parsedObjects) RuntimeClasses() []ks.RuntimeClass {
+	return p.runtimeClasses
+}
+
+func (p *parsedObjects) ResourceQuotas() []ks.ResourceQuota {
+	return p.resourceQuotas
+}
+
+func (p *parsedObjects) LimitRanges() []ks.LimitRange {
+	return p.limitRanges
+}
+
 func Empty() ks.AllTypes {
 	return &parsedObjects{}
 }
 
+// ParseFiles parses the files, see ParseFilesContext
 func (p *Parser) ParseFiles(files []ks.NamedReader) (ks.AllTypes, error) {
-	s := &parsedObjects{}
+	return p.ParseFilesContext(context.Background(), files)
+}
+
+// ParseFilesContext parses the files until ctx is done or the Limits.Timeout has passed. It then
+// returns without waiting for the current read or document, the files are read until the parsing
+// stops in the background at the next read or document.
+func (p *Parser) ParseFilesContext(ctx context.Context, files []ks.NamedReader) (ks.AllTypes, error) {
+	if timeout := p.config.Limits.Timeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, timeout,
+			fmt.Errorf("%w: parsing took longer than %s", ErrLimitExceeded, timeout))
+		defer cancel()
+	}
+
+	state := &parseState{}
+	if len(files) > 0 {
+		state.file = files[0].Name()
+	}
+	if ctx.Done() == nil {
+		return p.parseFiles(ctx, files, state)
+	}
+
+	type result struct {
+		parsed ks.AllTypes
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		parsed, err := p.parseFiles(ctx, files, state)
+		done <- result{parsed, err}
+	}()
+	select {
+	case res := <-done:
+		return res.parsed, res.err
+	case <-ctx.Done():
+		file := state.abandon()
+		err := context.Cause(ctx)
+		if errors.Is(err, ErrLimitExceeded) {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		return nil, err
+	}
+}
 
+func (p *Parser) parseFiles(ctx context.Context, files []ks.NamedReader, state *parseState) (ks.AllTypes, error) {
+	s := &parsedObjects{}
+	limiter := &inputLimiter{ctx: ctx, max: p.config.Limits.MaxInputSize}
+
+	// Identical objects in multiple files are only decoded and scored once. The documents are
+	// decoded as soon as they are read, so that only the decoded objects are kept.
+	d := newDecodedDocuments()
+	documents := 0
+	splitter := newDocumentSplitter()
+	splitter.maxDocumentSize = p.config.Limits.MaxDocumentSize
 	for _, namedReader := range files {
-		fullFile, err := io.ReadAll(namedReader)
+		state.setFile(namedReader.Name())
+		before := documents
+		err := splitter.split(limiter.reader(namedReader), func(offset int, raw []byte) error {
+			return recoverPanic(namedReader.Name(), offset, func() error {
+				// raw is reused for the next document, it is not kept after it has been decoded
+				return p.detect(namedReader.Name(), offset, raw, func(doc document) error {
+					documents++
+					if err := p.checkLimits(ctx, documents); err != nil {
+						return err
+					}
+					return d.add(p, s, doc)
+				})
+			})
+		})
+		if errors.Is(err, ErrLimitExceeded) {
+			return nil, fmt.Errorf("failed to parse %s: %w", namedReader.Name(), err)
+		}
 		if err != nil {
 			return nil, err
 		}
-
-		// Convert to unix style newlines
-		fullFile = bytes.ReplaceAll(fullFile, []byte("\r\n"), []byte("\n"))
-
-		offset := 1 // Line numbers are 1 indexed
-
-		// Remove initial "---\n" if present
-		if bytes.HasPrefix(fullFile, []byte("---\n")) {
-			fullFile = fullFile[4:]
-			offset = 2
+		if p.config.FileParsed != nil {
+			state.callback(func() { p.config.FileParsed(namedReader.Name(), documents-before) })
 		}
+	}
 
-		// for _, fileContents := range bytes.Split(fullFile, []byte("\n---\n")) {
-		for fileContents := range bytes.SplitSeq(fullFile, []byte("\n---\n")) {
-			if len(bytes.TrimSpace(fileContents)) > 0 {
-				if err := p.detectAndDecode(s, namedReader.Name(), offset, fileContents); err != nil {
-					return nil, err
-				}
+	state.callback(func() {
+		for _, o := range s.skipped {
+			if p.config.SkippedByExpression != nil {
+				p.config.SkippedByExpression(o.obj, o.location, o.expr)
+			} else {
+				fmt.Fprintf(os.Stderr, "skipping %s\n", o.obj.GroupVersionKind().String())
 			}
-
-			offset += 2 + bytes.Count(fileContents, []byte("\n"))
 		}
-	}
 
+		if stripped := d.exported(); stripped > 0 && p.config.VerboseOutput > 0 {
+			log.Printf("Removed the status and server populated fields of %d objects exported from a cluster", stripped)
+		}
+	})
+	s.skipped = nil
 	return s, nil
 }
 
-func (p *Parser) detectAndDecode(
-	s *parsedObjects,
+// recoverPanic returns an error instead of panicking if fn panics on malformed input, as the input
+// can be untrusted
+func recoverPanic(fileName string, fileOffset int, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to parse %s:%d: %v", fileName, fileOffset, r)
+		}
+	}()
+	return fn()
+}
+
+// document is a single object of the input, lists are split into their items
+type document struct {
+	kind     schema.GroupVersionKind
+	fileName string
+	offset   int
+	raw      []byte
+	// contents is the object as JSON, with sorted keys and without the comments of raw
+	contents []byte
+	// stripped is true if the status and server populated fields were removed from the object
+	stripped bool
+}
+
+// detect calls fn for the document, or for every item if the document is a list. The document is
+// converted to JSON once, and the JSON is decoded instead of parsing the YAML again for every step.
+func (p *Parser) detect(
 	fileName string,
 	fileOffset int,
 	raw []byte,
+	fn func(document) error,
 ) error {
-	var detect detectKind
-	err := yaml.Unmarshal(raw, &detect)
+	contents, err := sigsyaml.YAMLToJSON(raw)
 	if err != nil {
 		return err
 	}
+	var detect detectKind
+	if err := json.Unmarshal(contents, &detect); err != nil {
+		return err
+	}
 
 	detectedVersion := schema.FromAPIVersionAndKind(detect.ApiVersion, detect.Kind)
 
 	// Parse lists and their items recursively
 	if detectedVersion == corev1.SchemeGroupVersion.WithKind("List") {
 		var list corev1.List
-		err := p.decode(raw, &list)
+		err := p.decode(contents, &list)
 		if err != nil {
 			return err
 		}
 		for _, listItem := range list.Items {
-			err := p.detectAndDecode(s, fileName, fileOffset, listItem.Raw)
-			if err != nil {
+			if err := p.detect(fileName, fileOffset, listItem.Raw, fn); err != nil {
 				return err
 			}
 		}
 		return nil
 	}
 
-	err = p.decodeItem(s, detectedVersion, fileName, fileOffset, raw)
-	if err != nil {
-		return err
+	raw, stripped := withoutServerFields(raw)
+	namespace := p.config.FileNamespaces[fileName]
+	raw = withFileNamespace(detectedVersion, raw, namespace)
+	if stripped || namespace != "" {
+		if contents, err = sigsyaml.YAMLToJSON(raw); err != nil {
+			return err
+		}
 	}
-
-	return nil
+	return fn(document{
+		kind:     detectedVersion,
+		fileName: fileName,
+		offset:   fileOffset,
+		raw:      raw,
+		contents: contents,
+		stripped: stripped,
+	})
 }
 
 func (p *Parser) decode(data []byte, object runtime.Object) error {
-	deserializer := p.codecs.UniversalDeserializer()
-	if _, _, err := deserializer.Decode(data, nil, object); err != nil {
+	if _, _, err := p.deserializer.Decode(data, nil, object); err != nil {
 		gvk := object.GetObjectKind().GroupVersionKind()
 		return fmt.Errorf("failed to parse %s: err=%w", gvk, err)
 	}
 	return nil
 }
 
+// keepDocument keeps the JSON of the document, by the type and the name of the object. Documents
+// without valid metadata are not kept, the errors of the object are reported when it is decoded.
+func keepDocument(s *parsedObjects, fileContents []byte) {
+	var meta metav1.PartialObjectMetadata
+	if err := decodeMetadata(fileContents, &meta); err != nil {
+		return
+	}
+	if s.documents == nil {
+		s.documents = make(map[objectKey][]byte)
+	}
+	s.documents[newObjectKey(meta.TypeMeta, meta.ObjectMeta)] = fileContents
+}
+
+// decodeMetadata decodes the metadata of an object of a kind that is not in the scheme from its JSON
+func decodeMetadata(data []byte, obj *metav1.PartialObjectMetadata) error {
+	return json.Unmarshal(data, obj)
+}
+
 func detectFileLocation(
 	fileName string,
 	fileOffset int,
@@ -252,11 +587,11 @@ func detectFileLocation(
 ) ks.FileLocation {
 	// If the object YAML begins with a Helm style "# Source: " comment
 	// Use the information in there as the file name
-	firstRow := string(bytes.Split(fileContents, []byte("\n"))[0])
+	firstRow, _, _ := bytes.Cut(fileContents, []byte("\n"))
 	helmTemplatePrefix := "# Source: "
-	if strings.HasPrefix(firstRow, helmTemplatePrefix) {
+	if bytes.HasPrefix(firstRow, []byte(helmTemplatePrefix)) {
 		return ks.FileLocation{
-			Name: firstRow[len(helmTemplatePrefix):],
+			Name: string(firstRow[len(helmTemplatePrefix):]),
 			Line: 1, // Set line to 1 as the line definition gets lost in Helm
 		}
 	}
@@ -297,11 +632,11 @@ func (p *Parser) isSkipped(res metav1.ObjectMetaAccessor, errs parseErrors) bool
 
 func (p *Parser) decodeItem(
 	s *parsedObjects,
-	detectedVersion schema.GroupVersionKind,
-	fileName string,
-	fileOffset int,
-	fileContents []byte,
+	item document,
+	fileLocation ks.FileLocation,
 ) error {
+	detectedVersion, fileContents := item.kind, item.contents
+
 	addPodSpeccer := func(ps ks.PodSpecer) {
 		s.podspecers = append(s.podspecers, ps)
 		s.bothMetas = append(s.bothMetas, ks.BothMeta{
@@ -311,22 +646,29 @@ func (p *Parser) decodeItem(
 		})
 	}
 
-	fileLocation := detectFileLocation(fileName, fileOffset, fileContents)
-
-	// check if skipped
+	// check if skipped, the expressions are evaluated on the YAML of the document
 	var doc yaml.Node
-	if err := yaml.Unmarshal(fileContents, &doc); err != nil {
-		return err
+	if len(p.config.SkipExpressions) > 0 {
+		if err := yaml.Unmarshal(item.raw, &doc); err != nil {
+			return err
+		}
 	}
 
 	for _, expr := range p.config.SkipExpressions {
 		fileLocation.Skip = expr.Evaluate(doc)
 		if fileLocation.Skip {
-			fmt.Printf("skipping %s\n", detectedVersion.String())
+			var obj metav1.PartialObjectMetadata
+			_ = decodeMetadata(fileContents, &obj)
+			obj.TypeMeta = metav1.TypeMeta{APIVersion: detectedVersion.GroupVersion().String(), Kind: detectedVersion.Kind}
+			s.skipped = append(s.skipped, skippedObject{obj: obj, location: fileLocation, expr: expr})
 			return nil
 		}
 	}
 
+	if p.config.KeepDocuments {
+		keepDocument(s, fileContents)
+	}
+
 	var errs parseErrors
 
 	switch detectedVersion {
@@ -334,7 +676,7 @@ func (p *Parser) decodeItem(
 		var pod corev1.Pod
 		errs.AddIfErr(p.decode(fileContents, &pod))
 		fileLocation.Skip = p.isSkipped(&pod, errs)
-		p := internalpod.Pod{Obj: pod, Location: fileLocation}
+		p := &internalpod.Pod{Obj: pod, Location: fileLocation}
 		s.pods = append(s.pods, p)
 		s.bothMetas = append(
 			s.bothMetas,
@@ -356,7 +698,7 @@ func (p *Parser) decodeItem(
 		}
 		job.Spec.Template.Labels["job-name"] = job.Name
 
-		j := internal.Batchv1Job{Job: job, Location: fileLocation}
+		j := &internal.Batchv1Job{Job: job, Location: fileLocation}
 		// job.Labels["job-name"] = ;
 		addPodSpeccer(j)
 		// fmt.Printf("=> parsed job: %q\n", j.GetObjectMeta().Name)
@@ -366,7 +708,7 @@ func (p *Parser) decodeItem(
 		var cronjob batchv1beta1.CronJob
 		errs.AddIfErr(p.decode(fileContents, &cronjob))
 		fileLocation.Skip = p.isSkipped(&cronjob, errs)
-		cjob := internalcronjob.CronJobV1beta1{Obj: cronjob, Location: fileLocation}
+		cjob := &internalcronjob.CronJobV1beta1{Obj: cronjob, Location: fileLocation}
 		addPodSpeccer(cjob)
 		s.cronjobs = append(s.cronjobs, cjob)
 
@@ -374,7 +716,7 @@ func (p *Parser) decodeItem(
 		var cronjob batchv1.CronJob
 		errs.AddIfErr(p.decode(fileContents, &cronjob))
 		fileLocation.Skip = p.isSkipped(&cronjob, errs)
-		cjob := internalcronjob.CronJobV1{Obj: cronjob, Location: fileLocation}
+		cjob := &internalcronjob.CronJobV1{Obj: cronjob, Location: fileLocation}
 		addPodSpeccer(cjob)
 		s.cronjobs = append(s.cronjobs, cjob)
 
@@ -382,7 +724,7 @@ func (p *Parser) decodeItem(
 		var deployment appsv1.Deployment
 		errs.AddIfErr(p.decode(fileContents, &deployment))
 		fileLocation.Skip = p.isSkipped(&deployment, errs)
-		deploy := internal.Appsv1Deployment{Obj: deployment, Location: fileLocation}
+		deploy := &internal.Appsv1Deployment{Obj: deployment, Location: fileLocation}
 		addPodSpeccer(deploy)
 
 		// TODO: Support older versions of Deployment as well?
@@ -423,7 +765,7 @@ func (p *Parser) decodeItem(
 		errs.AddIfErr(p.decode(fileContents, &statefulSet))
 		fileLocation.Skip = p.isSkipped(&statefulSet, errs)
 
-		sset := internal.Appsv1StatefulSet{Obj: statefulSet, Location: fileLocation}
+		sset := &internal.Appsv1StatefulSet{Obj: statefulSet, Location: fileLocation}
 		addPodSpeccer(sset)
 
 		// TODO: Support older versions of StatefulSet as well?
@@ -476,11 +818,31 @@ func (p *Parser) decodeItem(
 			},
 		)
 
+	// ReplicaSets and ReplicationControllers are usually created by other controllers, and are
+	// common in dumps of clusters
+	case appsv1.SchemeGroupVersion.WithKind("ReplicaSet"):
+		var replicaSet appsv1.ReplicaSet
+		errs.AddIfErr(p.decode(fileContents, &replicaSet))
+		fileLocation.Skip = p.isSkipped(&replicaSet, errs)
+		addPodSpeccer(
+			internal.Appsv1ReplicaSet{ReplicaSet: replicaSet, Location: fileLocation},
+		)
+	case corev1.SchemeGroupVersion.WithKind("ReplicationController"):
+		var replicationController corev1.ReplicationController
+		errs.AddIfErr(p.decode(fileContents, &replicationController))
+		fileLocation.Skip = p.isSkipped(&replicationController, errs)
+		addPodSpeccer(
+			internal.Corev1ReplicationController{
+				ReplicationController: replicationController,
+				Location:              fileLocation,
+			},
+		)
+
 	case networkingv1.SchemeGroupVersion.WithKind("NetworkPolicy"):
 		var netpol networkingv1.NetworkPolicy
 		errs.AddIfErr(p.decode(fileContents, &netpol))
 		fileLocation.Skip = p.isSkipped(&netpol, errs)
-		np := internalnetpol.NetworkPolicy{Obj: netpol, Location: fileLocation}
+		np := &internalnetpol.NetworkPolicy{Obj: netpol, Location: fileLocation}
 		s.networkPolicies = append(s.networkPolicies, np)
 		s.bothMetas = append(
 			s.bothMetas,
@@ -495,7 +857,7 @@ func (p *Parser) decodeItem(
 		var service corev1.Service
 		errs.AddIfErr(p.decode(fileContents, &service))
 		fileLocation.Skip = p.isSkipped(&service, errs)
-		serv := internalservice.Service{Obj: service, Location: fileLocation}
+		serv := &internalservice.Service{Obj: service, Location: fileLocation}
 		s.services = append(s.services, serv)
 		s.bothMetas = append(
 			s.bothMetas,
@@ -506,11 +868,33 @@ func (p *Parser) decodeItem(
 			},
 		)
 
+	// ConfigMaps, Secrets and ServiceAccounts are not scored, they are only used by the checks of other objects
+	case corev1.SchemeGroupVersion.WithKind("ConfigMap"):
+		var configMap corev1.ConfigMap
+		errs.AddIfErr(p.decode(fileContents, &configMap))
+		fileLocation.Skip = p.isSkipped(&configMap, errs)
+		s.configMaps = append(s.configMaps, internalconfigmap.ConfigMap{Obj: configMap, Location: fileLocation})
+
+	case corev1.SchemeGroupVersion.WithKind("Secret"):
+		var secret corev1.Secret
+		errs.AddIfErr(p.decode(fileContents, &secret))
+		fileLocation.Skip = p.isSkipped(&secret, errs)
+		s.secrets = append(s.secrets, internalsecret.Secret{Obj: secret, Location: fileLocation})
+
+	case corev1.SchemeGroupVersion.WithKind("ServiceAccount"):
+		var serviceAccount corev1.ServiceAccount
+		errs.AddIfErr(p.decode(fileContents, &serviceAccount))
+		fileLocation.Skip = p.isSkipped(&serviceAccount, errs)
+		s.serviceAccounts = append(
+			s.serviceAccounts,
+			internalserviceaccount.ServiceAccount{Obj: serviceAccount, Location: fileLocation},
+		)
+
 	case policyv1beta1.SchemeGroupVersion.WithKind("PodDisruptionBudget"):
 		var disruptBudget policyv1beta1.PodDisruptionBudget
 		errs.AddIfErr(p.decode(fileContents, &disruptBudget))
 		fileLocation.Skip = p.isSkipped(&disruptBudget, errs)
-		dbug := internalpdb.PodDisruptionBudgetV1beta1{
+		dbug := &internalpdb.PodDisruptionBudgetV1beta1{
 			Obj:      disruptBudget,
 			Location: fileLocation,
 		}
@@ -527,7 +911,7 @@ func (p *Parser) decodeItem(
 		var disruptBudget policyv1.PodDisruptionBudget
 		errs.AddIfErr(p.decode(fileContents, &disruptBudget))
 		fileLocation.Skip = p.isSkipped(&disruptBudget, errs)
-		dbug := internalpdb.PodDisruptionBudgetV1{
+		dbug := &internalpdb.PodDisruptionBudgetV1{
 			Obj:      disruptBudget,
 			Location: fileLocation,
 		}
@@ -538,11 +922,76 @@ func (p *Parser) decodeItem(
 			FileLocationer: dbug,
 		})
 
+	case schedulingv1.SchemeGroupVersion.WithKind("PriorityClass"):
+		var priorityClass schedulingv1.PriorityClass
+		errs.AddIfErr(p.decode(fileContents, &priorityClass))
+		fileLocation.Skip = p.isSkipped(&priorityClass, errs)
+		pc := &internalpriorityclass.PriorityClass{Obj: priorityClass, Location: fileLocation}
+		s.priorityClasses = append(s.priorityClasses, pc)
+		s.bothMetas = append(
+			s.bothMetas,
+			ks.BothMeta{
+				TypeMeta:       priorityClass.TypeMeta,
+				ObjectMeta:     priorityClass.ObjectMeta,
+				FileLocationer: pc,
+			},
+		)
+
+	case storagev1.SchemeGroupVersion.WithKind("StorageClass"):
+		var storageClass storagev1.StorageClass
+		errs.AddIfErr(p.decode(fileContents, &storageClass))
+		fileLocation.Skip = p.isSkipped(&storageClass, errs)
+		sc := &internalstorageclass.StorageClass{Obj: storageClass, Location: fileLocation}
+		s.storageClasses = append(s.storageClasses, sc)
+		s.bothMetas = append(
+			s.bothMetas,
+			ks.BothMeta{
+				TypeMeta:       storageClass.TypeMeta,
+				ObjectMeta:     storageClass.ObjectMeta,
+				FileLocationer: sc,
+			},
+		)
+
+	case admissionregistrationv1.SchemeGroupVersion.WithKind("MutatingWebhookConfiguration"):
+		var webhookConfiguration admissionregistrationv1.MutatingWebhookConfiguration
+		errs.AddIfErr(p.decode(fileContents, &webhookConfiguration))
+		fileLocation.Skip = p.isSkipped(&webhookConfiguration, errs)
+		wh := &internal.MutatingWebhookConfigurationV1{
+			MutatingWebhookConfiguration: webhookConfiguration,
+			Location:                     fileLocation,
+		}
+		s.webhooks = append(s.webhooks, wh)
+		s.bothMetas = append(
+			s.bothMetas,
+			ks.BothMeta{
+				TypeMeta:       webhookConfiguration.TypeMeta,
+				ObjectMeta:     webhookConfiguration.ObjectMeta,
+				FileLocationer: wh,
+			},
+		)
+	case admissionregistrationv1.SchemeGroupVersion.WithKind("ValidatingWebhookConfiguration"):
+		var webhookConfiguration admissionregistrationv1.ValidatingWebhookConfiguration
+		errs.AddIfErr(p.decode(fileContents, &webhookConfiguration))
+		fileLocation.Skip = p.isSkipped(&webhookConfiguration, errs)
+		wh := &internal.ValidatingWebhookConfigurationV1{
+			ValidatingWebhookConfiguration: webhookConfiguration,
+			Location:                       fileLocation,
+		}
+		s.webhooks = append(s.webhooks, wh)
+		s.bothMetas = append(
+			s.bothMetas,
+			ks.BothMeta{
+				TypeMeta:       webhookConfiguration.TypeMeta,
+				ObjectMeta:     webhookConfiguration.ObjectMeta,
+				FileLocationer: wh,
+			},
+		)
+
 	case extensionsv1beta1.SchemeGroupVersion.WithKind("Ingress"):
 		var ingress extensionsv1beta1.Ingress
 		errs.AddIfErr(p.decode(fileContents, &ingress))
 		fileLocation.Skip = p.isSkipped(&ingress, errs)
-		ing := internal.ExtensionsIngressV1beta1{
+		ing := &internal.ExtensionsIngressV1beta1{
 			Ingress:  ingress,
 			Location: fileLocation,
 		}
@@ -560,7 +1009,7 @@ func (p *Parser) decodeItem(
 		var ingress networkingv1beta1.Ingress
 		errs.AddIfErr(p.decode(fileContents, &ingress))
 		fileLocation.Skip = p.isSkipped(&ingress, errs)
-		ing := internal.IngressV1beta1{Ingress: ingress, Location: fileLocation}
+		ing := &internal.IngressV1beta1{Ingress: ingress, Location: fileLocation}
 		s.ingresses = append(s.ingresses, ing)
 		s.bothMetas = append(
 			s.bothMetas,
@@ -575,7 +1024,7 @@ func (p *Parser) decodeItem(
 		var ingress networkingv1.Ingress
 		errs.AddIfErr(p.decode(fileContents, &ingress))
 		fileLocation.Skip = p.isSkipped(&ingress, errs)
-		ing := internal.IngressV1{Ingress: ingress, Location: fileLocation}
+		ing := &internal.IngressV1{Ingress: ingress, Location: fileLocation}
 		s.ingresses = append(s.ingresses, ing)
 		s.bothMetas = append(
 			s.bothMetas,
@@ -590,7 +1039,7 @@ func (p *Parser) decodeItem(
 		var hpa autoscalingv1.HorizontalPodAutoscaler
 		errs.AddIfErr(p.decode(fileContents, &hpa))
 		fileLocation.Skip = p.isSkipped(&hpa, errs)
-		h := internal.HPAv1{HorizontalPodAutoscaler: hpa, Location: fileLocation}
+		h := &internal.HPAv1{HorizontalPodAutoscaler: hpa, Location: fileLocation}
 		s.hpaTargeters = append(s.hpaTargeters, h)
 		s.bothMetas = append(
 			s.bothMetas,
@@ -605,7 +1054,7 @@ func (p *Parser) decodeItem(
 		var hpa autoscalingv2beta1.HorizontalPodAutoscaler
 		errs.AddIfErr(p.decode(fileContents, &hpa))
 		fileLocation.Skip = p.isSkipped(&hpa, errs)
-		h := internal.HPAv2beta1{HorizontalPodAutoscaler: hpa, Location: fileLocation}
+		h := &internal.HPAv2beta1{HorizontalPodAutoscaler: hpa, Location: fileLocation}
 		s.hpaTargeters = append(s.hpaTargeters, h)
 		s.bothMetas = append(
 			s.bothMetas,
@@ -620,7 +1069,7 @@ func (p *Parser) decodeItem(
 		var hpa autoscalingv2beta2.HorizontalPodAutoscaler
 		errs.AddIfErr(p.decode(fileContents, &hpa))
 		fileLocation.Skip = p.isSkipped(&hpa, errs)
-		h := internal.HPAv2beta2{HorizontalPodAutoscaler: hpa, Location: fileLocation}
+		h := &internal.HPAv2beta2{HorizontalPodAutoscaler: hpa, Location: fileLocation}
 		s.hpaTargeters = append(s.hpaTargeters, h)
 		s.bothMetas = append(s.bothMetas, ks.BothMeta{
 			TypeMeta:       hpa.TypeMeta,
@@ -632,7 +1081,7 @@ func (p *Parser) decodeItem(
 		var hpa autoscalingv2.HorizontalPodAutoscaler
 		errs.AddIfErr(p.decode(fileContents, &hpa))
 		fileLocation.Skip = p.isSkipped(&hpa, errs)
-		h := internal.HPAv2{HorizontalPodAutoscaler: hpa, Location: fileLocation}
+		h := &internal.HPAv2{HorizontalPodAutoscaler: hpa, Location: fileLocation}
 		s.hpaTargeters = append(s.hpaTargeters, h)
 		s.bothMetas = append(
 			s.bothMetas,
@@ -643,10 +1092,144 @@ func (p *Parser) decodeItem(
 			},
 		)
 
+	// The apiextensions types are not in the scheme, only the fields that are used by the checks are
+	// decoded
+	case customResourceDefinitionV1:
+		var crd internal.CustomResourceDefinitionV1
+		if err := json.Unmarshal(fileContents, &crd); err != nil {
+			errs.AddIfErr(fmt.Errorf("failed to parse %s: err=%w", detectedVersion, err))
+		}
+		fileLocation.Skip = p.isSkipped(&crd.ObjectMeta, errs)
+		crd.Location = fileLocation
+		s.crds = append(s.crds, crd)
+		s.bothMetas = append(
+			s.bothMetas,
+			ks.BothMeta{
+				TypeMeta:       crd.TypeMeta,
+				ObjectMeta:     crd.ObjectMeta,
+				FileLocationer: crd,
+			},
+		)
+
+	// The monitors of the Prometheus Operator are custom resources, they are decoded like CRDs
+	case serviceMonitorV1:
+		var monitor internal.ServiceMonitorV1
+		if err := json.Unmarshal(fileContents, &monitor); err != nil {
+			errs.AddIfErr(fmt.Errorf("failed to parse %s: err=%w", detectedVersion, err))
+		}
+		fileLocation.Skip = p.isSkipped(&monitor.ObjectMeta, errs)
+		monitor.Location = fileLocation
+		s.serviceMonitors = append(s.serviceMonitors, monitor)
+		s.bothMetas = append(
+			s.bothMetas,
+			ks.BothMeta{
+				TypeMeta:       monitor.TypeMeta,
+				ObjectMeta:     monitor.ObjectMeta,
+				FileLocationer: monitor,
+			},
+		)
+	case podMonitorV1:
+		var monitor internal.PodMonitorV1
+		if err := json.Unmarshal(fileContents, &monitor); err != nil {
+			errs.AddIfErr(fmt.Errorf("failed to parse %s: err=%w", detectedVersion, err))
+		}
+		fileLocation.Skip = p.isSkipped(&monitor.ObjectMeta, errs)
+		monitor.Location = fileLocation
+		s.podMonitors = append(s.podMonitors, monitor)
+		s.bothMetas = append(
+			s.bothMetas,
+			ks.BothMeta{
+				TypeMeta:       monitor.TypeMeta,
+				ObjectMeta:     monitor.ObjectMeta,
+				FileLocationer: monitor,
+			},
+		)
+
+	// Certificates are not scored, they are only used by the checks of Ingresses. They are kept with
+	// the other objects, so that they can still be referenced.
+	case certificateV1:
+		var certificate internal.CertificateV1
+		if err := json.Unmarshal(fileContents, &certificate); err != nil {
+			errs.AddIfErr(fmt.Errorf("failed to parse %s: err=%w", detectedVersion, err))
+		}
+		fileLocation.Skip = p.isSkipped(&certificate.ObjectMeta, errs)
+		certificate.Location = fileLocation
+		s.certificates = append(s.certificates, certificate)
+		s.otherObjects = append(s.otherObjects, ks.BothMeta{
+			TypeMeta:       certificate.TypeMeta,
+			ObjectMeta:     certificate.ObjectMeta,
+			FileLocationer: certificate,
+		})
+
+	// RuntimeClasses, ResourceQuotas and LimitRanges are not scored, they are only used by the checks
+	// of pods
+	case nodev1.SchemeGroupVersion.WithKind("RuntimeClass"):
+		var runtimeClass nodev1.RuntimeClass
+		errs.AddIfErr(p.decode(fileContents, &runtimeClass))
+		fileLocation.Skip = p.isSkipped(&runtimeClass, errs)
+		rc := &internalruntimeclass.RuntimeClass{Obj: runtimeClass, Location: fileLocation}
+		s.runtimeClasses = append(s.runtimeClasses, rc)
+		s.otherObjects = append(s.otherObjects, ks.BothMeta{
+			TypeMeta:       runtimeClass.TypeMeta,
+			ObjectMeta:     runtimeClass.ObjectMeta,
+			FileLocationer: rc,
+		})
+
+	case corev1.SchemeGroupVersion.WithKind("ResourceQuota"):
+		var quota corev1.ResourceQuota
+		errs.AddIfErr(p.decode(fileContents, &quota))
+		fileLocation.Skip = p.isSkipped(&quota, errs)
+		rq := &internalresourcequota.ResourceQuota{Obj: quota, Location: fileLocation}
+		s.resourceQuotas = append(s.resourceQuotas, rq)
+		s.otherObjects = append(s.otherObjects, ks.BothMeta{
+			TypeMeta:       quota.TypeMeta,
+			ObjectMeta:     quota.ObjectMeta,
+			FileLocationer: rq,
+		})
+
+	case corev1.SchemeGroupVersion.WithKind("LimitRange"):
+		var limitRange corev1.LimitRange
+		errs.AddIfErr(p.decode(fileContents, &limitRange))
+		fileLocation.Skip = p.isSkipped(&limitRange, errs)
+		lr := &internallimitrange.LimitRange{Obj: limitRange, Location: fileLocation}
+		s.limitRanges = append(s.limitRanges, lr)
+		s.otherObjects = append(s.otherObjects, ks.BothMeta{
+			TypeMeta:       limitRange.TypeMeta,
+			ObjectMeta:     limitRange.ObjectMeta,
+			FileLocationer: lr,
+		})
+
+	// ScoreExceptions are not scored, they ignore the checks of other objects
+	case scoreExceptionV1alpha1:
+		var exception internal.ScoreExceptionV1alpha1
+		if err := json.Unmarshal(fileContents, &exception); err != nil {
+			errs.AddIfErr(fmt.Errorf("failed to parse %s: err=%w", detectedVersion, err))
+		}
+		fileLocation.Skip = p.isSkipped(&exception.ObjectMeta, errs)
+		exception.Location = fileLocation
+		s.scoreExceptions = append(s.scoreExceptions, exception)
+		s.otherObjects = append(s.otherObjects, ks.BothMeta{
+			TypeMeta:       exception.TypeMeta,
+			ObjectMeta:     exception.ObjectMeta,
+			FileLocationer: exception,
+		})
+
 	default:
 		if p.config.VerboseOutput > 1 {
 			log.Printf("Unknown datatype: %s", detectedVersion.String())
 		}
+		// The metadata of other objects is kept, so that they can be referenced, e.g. by the
+		// scaleTargetRef of a HorizontalPodAutoscaler
+		var obj metav1.PartialObjectMetadata
+		if err := decodeMetadata(fileContents, &obj); err == nil {
+			fileLocation.Skip = p.isSkipped(&obj, errs)
+			other := &internal.Other{PartialObjectMetadata: obj, Location: fileLocation}
+			s.otherObjects = append(s.otherObjects, ks.BothMeta{
+				TypeMeta:       obj.TypeMeta,
+				ObjectMeta:     obj.ObjectMeta,
+				FileLocationer: other,
+			})
+		}
 	}
 
 	if errs.Any() {
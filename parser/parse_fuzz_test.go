@@ -0,0 +1,34 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ks "github.com/romnn/kube-score/domain"
+)
+
+func FuzzParseFiles(f *testing.F) {
+	seeds, err := filepath.Glob(filepath.Join("..", "score", "testdata", "*.yaml"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, seed := range append(seeds, filepath.Join("testdata", "valid-yaml.yaml")) {
+		data, err := os.ReadFile(seed)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(string(data))
+	}
+
+	p, err := New(&Config{FileNamespaces: map[string]string{"fuzz.yaml": "fuzz"}})
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	f.Fuzz(func(t *testing.T, doc string) {
+		// Errors are expected, but the parser must never panic
+		_, _ = p.ParseFiles([]ks.NamedReader{namedReader{Reader: strings.NewReader(doc), name: "fuzz.yaml"}})
+	})
+}
@@ -1,15 +1,20 @@
 package parser
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	ks "github.com/romnn/kube-score/domain"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/config"
 )
 
 func TestParse(t *testing.T) {
@@ -191,3 +196,438 @@ spec:
 	assert.Equal(t, "skip-false.yaml", location.Name)
 	assert.Equal(t, false, location.Skip)
 }
+
+func TestConfigMapsAndSecrets(t *testing.T) {
+	t.Parallel()
+	doc := `kind: ConfigMap
+apiVersion: v1
+metadata:
+  name: foo
+data:
+  key: value
+---
+kind: Secret
+apiVersion: v1
+metadata:
+  name: bar
+stringData:
+  password: hunter2`
+
+	parsed := parse(t, doc, "config.yaml")
+	assert.Len(t, parsed.ConfigMaps(), 1)
+	assert.Equal(t, "value", parsed.ConfigMaps()[0].ConfigMap().Data["key"])
+	assert.Len(t, parsed.Secrets(), 1)
+	assert.Equal(t, "hunter2", parsed.Secrets()[0].Secret().StringData["password"])
+	assert.Equal(t, 8, parsed.Secrets()[0].FileLocation().Line)
+
+	// ConfigMaps and Secrets are not scored
+	assert.Empty(t, parsed.Metas())
+}
+
+func TestServiceAccounts(t *testing.T) {
+	t.Parallel()
+	doc := `kind: ServiceAccount
+apiVersion: v1
+metadata:
+  name: foo
+imagePullSecrets:
+  - name: registry`
+
+	parsed := parse(t, doc, "serviceaccount.yaml")
+	assert.Len(t, parsed.ServiceAccounts(), 1)
+	assert.Equal(t, "registry", parsed.ServiceAccounts()[0].ServiceAccount().ImagePullSecrets[0].Name)
+
+	// ServiceAccounts are not scored
+	assert.Empty(t, parsed.Metas())
+}
+
+func TestDeduplicateIdenticalObjects(t *testing.T) {
+	t.Parallel()
+	base := `apiVersion: v1
+kind: Service
+metadata:
+  name: foo
+spec:
+  selector:
+    app: foo
+  ports:
+  - port: 80
+`
+	// The same object with a comment and different order of keys
+	overlay := `# rendered from the base
+kind: Service
+apiVersion: v1
+spec:
+  ports:
+  - port: 80
+  selector:
+    app: foo
+metadata:
+  name: foo
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: foo
+spec:
+  selector:
+    app: bar
+  ports:
+  - port: 80
+`
+
+	p, err := New(nil)
+	assert.NoError(t, err)
+	parsed, err := p.ParseFiles([]ks.NamedReader{
+		namedReader{Reader: strings.NewReader(base), name: "base.yaml"},
+		namedReader{Reader: strings.NewReader(overlay), name: "overlay.yaml"},
+	})
+	assert.NoError(t, err)
+
+	services := parsed.Services()
+	assert.Len(t, services, 2)
+	assert.Equal(t, "base.yaml", services[0].FileLocation().Name)
+	assert.Equal(t, []ks.FileLocation{{Name: "overlay.yaml", Line: 1}}, services[0].FileLocation().Duplicates)
+	// Objects with the same name but different contents are not deduplicated
+	assert.Equal(t, "overlay.yaml", services[1].FileLocation().Name)
+	assert.Equal(t, 12, services[1].FileLocation().Line)
+	assert.Empty(t, services[1].FileLocation().Duplicates)
+	assert.Len(t, parsed.Metas(), 2)
+	assert.Equal(t, services[0].FileLocation(), parsed.Metas()[0].FileLocation())
+}
+
+func TestFileNamespaces(t *testing.T) {
+	t.Parallel()
+	doc := `# Source: app/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: foo
+spec:
+  selector:
+    app: foo
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: bar
+  namespace: other
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: baz
+  namespace:
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: app
+`
+
+	p, err := New(&Config{FileNamespaces: map[string]string{"app.yaml": "app"}})
+	assert.NoError(t, err)
+	parsed, err := p.ParseFiles([]ks.NamedReader{
+		namedReader{Reader: strings.NewReader(doc), name: "app.yaml"},
+		namedReader{Reader: strings.NewReader(doc), name: "other.yaml"},
+	})
+	assert.NoError(t, err)
+
+	var namespaces []string
+	for _, s := range parsed.Services() {
+		namespaces = append(namespaces, s.Service().Namespace)
+	}
+	// Objects without a namespace are only identical in files with the same default namespace
+	assert.Equal(t, []string{"app", "other", "app", "", ""}, namespaces)
+	// The location of the Helm template is kept
+	assert.Equal(t, "app/templates/service.yaml", parsed.Services()[0].FileLocation().Name)
+	assert.Equal(t, "foo", parsed.Services()[0].Service().Spec.Selector["app"])
+	assert.Equal(t, []ks.FileLocation{{Name: "other.yaml", Line: 10}}, parsed.Services()[1].FileLocation().Duplicates)
+
+	// Cluster scoped objects never get a namespace
+	assert.Len(t, parsed.OtherObjects(), 1)
+	for _, o := range parsed.OtherObjects() {
+		assert.Equal(t, "Namespace", o.TypeMeta.Kind)
+		assert.Empty(t, o.ObjectMeta.Namespace)
+	}
+}
+
+func TestFileNamespacesList(t *testing.T) {
+	t.Parallel()
+	doc := `apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: foo
+    labels: {app: foo}
+`
+	p, err := New(&Config{FileNamespaces: map[string]string{"list.yaml": "app"}})
+	assert.NoError(t, err)
+	parsed, err := p.ParseFiles([]ks.NamedReader{namedReader{Reader: strings.NewReader(doc), name: "list.yaml"}})
+	assert.NoError(t, err)
+	assert.Len(t, parsed.Services(), 1)
+	assert.Equal(t, "app", parsed.Services()[0].Service().Namespace)
+	assert.Equal(t, "foo", parsed.Services()[0].Service().Labels["app"])
+}
+
+func TestFileParsed(t *testing.T) {
+	t.Parallel()
+	doc := `apiVersion: v1
+kind: Service
+metadata:
+  name: foo
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: bar
+`
+	parsed := map[string]int{}
+	p, err := New(&Config{FileParsed: func(name string, documents int) {
+		parsed[name] = documents
+	}})
+	assert.NoError(t, err)
+	_, err = p.ParseFiles([]ks.NamedReader{
+		namedReader{Reader: strings.NewReader(doc), name: "services.yaml"},
+		namedReader{Reader: strings.NewReader(""), name: "empty.yaml"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"services.yaml": 2, "empty.yaml": 0}, parsed)
+}
+
+func TestSkippedByExpression(t *testing.T) {
+	t.Parallel()
+	doc := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: skipped
+  namespace: test
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: parsed
+`
+	expr, err := config.ParseSkipExpression("metadata.namespace=test")
+	assert.NoError(t, err)
+
+	var skipped []string
+	p, err := New(&Config{
+		SkipExpressions: []*config.SkipExpression{expr},
+		SkippedByExpression: func(obj metav1.PartialObjectMetadata, location ks.FileLocation, e *config.SkipExpression) {
+			skipped = append(skipped, fmt.Sprintf("%s/%s %s:%d %s", obj.Kind, obj.Name, location.Name, location.Line, e))
+		},
+	})
+	assert.NoError(t, err)
+	parsed, err := p.ParseFiles([]ks.NamedReader{namedReader{Reader: strings.NewReader(doc), name: "app.yaml"}})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"Deployment/skipped app.yaml:1 metadata.namespace=test"}, skipped)
+	if assert.Len(t, parsed.Deployments(), 1) {
+		assert.Equal(t, "parsed", parsed.Deployments()[0].Deployment().Name)
+	}
+}
+
+func TestSplitDocuments(t *testing.T) {
+	t.Parallel()
+	longLine := "# " + strings.Repeat("x", 200*1024) + "\n"
+	input := "---\r\na: 1\r\nb: 2\r\n---\r\n\n---\n" + longLine + "c: 3\n---\n--- \nd: 4\n---"
+
+	type doc struct {
+		offset int
+		raw    string
+	}
+	var docs []doc
+	err := newDocumentSplitter().split(strings.NewReader(input), func(offset int, raw []byte) error {
+		docs = append(docs, doc{offset, string(raw)})
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []doc{
+		{2, "a: 1\nb: 2\n"},
+		{7, longLine + "c: 3\n"},
+		{10, "--- \nd: 4\n"},
+	}, docs)
+}
+
+func TestServerFieldsAreRemoved(t *testing.T) {
+	t.Parallel()
+	manifest := `apiVersion: v1
+kind: Service
+metadata:
+  name: foo
+  annotations:
+    team: platform
+spec:
+  type: LoadBalancer
+  selector:
+    app: foo
+`
+	// The same object exported from a cluster with kubectl get -o yaml
+	export := `apiVersion: v1
+kind: Service
+metadata:
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: |
+      {"apiVersion":"v1","kind":"Service","metadata":{"name":"foo"}}
+    team: platform
+  creationTimestamp: "2024-05-01T10:00:00Z"
+  managedFields:
+  - apiVersion: v1
+    fieldsType: FieldsV1
+    manager: kubectl-client-side-apply
+    operation: Update
+  name: foo
+  resourceVersion: "1234"
+  uid: 0b7d2d1c-5a43-4d8e-9f3c-1b2f3e4d5c6b
+spec:
+  type: LoadBalancer
+  selector:
+    app: foo
+status:
+  loadBalancer:
+    ingress:
+    - ip: 203.0.113.10
+`
+
+	p, err := New(nil)
+	assert.NoError(t, err)
+	parsed, err := p.ParseFiles([]ks.NamedReader{
+		namedReader{Reader: strings.NewReader(manifest), name: "git.yaml"},
+		namedReader{Reader: strings.NewReader(export), name: "cluster.yaml"},
+	})
+	assert.NoError(t, err)
+
+	services := parsed.Services()
+	assert.Len(t, services, 1)
+	assert.Equal(t, []ks.FileLocation{{Name: "cluster.yaml", Line: 1}}, services[0].FileLocation().Duplicates)
+	assert.False(t, services[0].FileLocation().Exported)
+
+	// The fields are also removed from objects that are not deduplicated
+	parsed, err = p.ParseFiles([]ks.NamedReader{namedReader{Reader: strings.NewReader(export), name: "cluster.yaml"}})
+	assert.NoError(t, err)
+	assert.True(t, parsed.Services()[0].FileLocation().Exported)
+	service := parsed.Services()[0].Service()
+	assert.Equal(t, map[string]string{"team": "platform"}, service.Annotations)
+	assert.Empty(t, service.ManagedFields)
+	assert.Empty(t, service.ResourceVersion)
+	assert.Empty(t, service.UID)
+	assert.True(t, service.CreationTimestamp.IsZero())
+	assert.Empty(t, service.Status.LoadBalancer.Ingress)
+	assert.Equal(t, "foo", service.Spec.Selector["app"])
+}
+
+func TestLimits(t *testing.T) {
+	t.Parallel()
+	doc := `apiVersion: v1
+kind: Service
+metadata:
+  name: foo
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: bar
+`
+	parse := func(limits Limits) error {
+		p, err := New(&Config{Limits: limits})
+		assert.NoError(t, err)
+		_, err = p.ParseFiles([]ks.NamedReader{
+			namedReader{Reader: strings.NewReader(doc), name: "services.yaml"},
+			namedReader{Reader: strings.NewReader(doc), name: "copy.yaml"},
+		})
+		return err
+	}
+
+	assert.NoError(t, parse(Limits{MaxDocuments: 4, MaxDocumentSize: 60, MaxInputSize: int64(2 * len(doc)), Timeout: time.Minute}))
+
+	tests := []struct {
+		limits Limits
+		err    string
+	}{
+		{Limits{MaxDocuments: 3}, "failed to parse copy.yaml: the input exceeds the limits: there are more than 3 documents"},
+		{Limits{MaxDocumentSize: 40}, "failed to parse services.yaml: the input exceeds the limits: the document on line 1 is larger than 40 bytes"},
+		{Limits{MaxInputSize: int64(len(doc)) + 10}, "failed to parse copy.yaml: the input exceeds the limits: the files are larger than 116 bytes"},
+		{Limits{Timeout: time.Nanosecond}, "failed to parse services.yaml: the input exceeds the limits: parsing took longer than 1ns"},
+	}
+	for _, tc := range tests {
+		err := parse(tc.limits)
+		assert.ErrorIs(t, err, ErrLimitExceeded)
+		assert.EqualError(t, err, tc.err)
+	}
+}
+
+// blockingReader returns a document, and then blocks until unblock is closed
+type blockingReader struct {
+	doc     *strings.Reader
+	unblock chan struct{}
+}
+
+func (r *blockingReader) Read(p []byte) (int, error) {
+	if r.doc.Len() > 0 {
+		return r.doc.Read(p)
+	}
+	<-r.unblock
+	return 0, io.EOF
+}
+
+// endlessReader is a single document that never ends
+type endlessReader struct{}
+
+func (endlessReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = '#'
+		if i%64 == 63 {
+			p[i] = '\n'
+		}
+	}
+	return len(p), nil
+}
+
+func TestLimitsTimeoutInterruptsReads(t *testing.T) {
+	t.Parallel()
+	p, err := New(&Config{Limits: Limits{Timeout: 50 * time.Millisecond}})
+	assert.NoError(t, err)
+
+	// A read that blocks is not waited for
+	unblock := make(chan struct{})
+	defer close(unblock)
+	start := time.Now()
+	_, err = p.ParseFiles([]ks.NamedReader{namedReader{
+		Reader: &blockingReader{doc: strings.NewReader("apiVersion: v1\nkind: Service\nmetadata:\n  name: foo\n---\n"), unblock: unblock},
+		name:   "slow.yaml",
+	}})
+	assert.EqualError(t, err, "failed to parse slow.yaml: the input exceeds the limits: parsing took longer than 50ms")
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// A document that never ends is not read to its end
+	start = time.Now()
+	_, err = p.ParseFiles([]ks.NamedReader{namedReader{Reader: endlessReader{}, name: "endless.yaml"}})
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestParseFilesContextCanceled(t *testing.T) {
+	t.Parallel()
+	p, err := New(nil)
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = p.ParseFilesContext(ctx, []ks.NamedReader{namedReader{Reader: endlessReader{}, name: "endless.yaml"}})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSplitDocumentsMaxSize(t *testing.T) {
+	t.Parallel()
+	// A single line without a newline can not grow the document beyond the limit
+	splitter := newDocumentSplitter()
+	splitter.maxDocumentSize = 1024
+	err := splitter.split(strings.NewReader("a: "+strings.Repeat("x", 200*1024)), func(int, []byte) error {
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrLimitExceeded)
+	assert.Less(t, cap(splitter.buf), 200*1024)
+}
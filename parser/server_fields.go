@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"bytes"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// lastAppliedAnnotation is the previous configuration of an object that was applied with
+// kubectl apply, it is a copy of the whole object
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// serverMetadataFields are the fields of the metadata that are set by the API server, they are
+// in objects exported from a cluster, but not in the manifests that the objects were applied from
+var serverMetadataFields = []string{
+	"managedFields",
+	"resourceVersion",
+	"uid",
+	"creationTimestamp",
+	"generation",
+	"selfLink",
+}
+
+// withoutServerFields removes the status, the server populated metadata and the last applied
+// configuration from an object exported from a cluster, so that it is scored and deduplicated
+// like the manifest in git. stripped is false if the object is returned as is.
+func withoutServerFields(raw []byte) (_ []byte, stripped bool) {
+	if !mayHaveServerFields(raw) {
+		return raw, false
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil || len(doc.Content) == 0 {
+		return raw, false
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return raw, false
+	}
+
+	stripped = removeMappingKeys(root, "status")
+	if metadata := mappingValue(root, "metadata"); metadata != nil && metadata.Kind == yaml.MappingNode {
+		if removeMappingKeys(metadata, serverMetadataFields...) {
+			stripped = true
+		}
+		if annotations := mappingValue(metadata, "annotations"); annotations != nil && annotations.Kind == yaml.MappingNode {
+			if removeMappingKeys(annotations, lastAppliedAnnotation) {
+				stripped = true
+			}
+		}
+	}
+	if !stripped {
+		return raw, false
+	}
+
+	// Items of lists are JSON, that must not be encoded as YAML with flow style
+	blockStyle(root)
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return raw, false
+	}
+	return buf.Bytes(), true
+}
+
+// mayHaveServerFields is a fast check that avoids parsing the manifests that are not exported
+// from a cluster
+func mayHaveServerFields(raw []byte) bool {
+	if bytes.Contains(raw, []byte("status")) || bytes.Contains(raw, []byte(lastAppliedAnnotation)) {
+		return true
+	}
+	for _, field := range serverMetadataFields {
+		if bytes.Contains(raw, []byte(field)) {
+			return true
+		}
+	}
+	return false
+}
+
+// removeMappingKeys removes the keys and their values from the mapping node, and returns true if
+// any key was removed
+func removeMappingKeys(node *yaml.Node, keys ...string) bool {
+	removed := false
+	content := node.Content[:0]
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if key.Kind == yaml.ScalarNode && slices.Contains(keys, key.Value) {
+			removed = true
+			continue
+		}
+		content = append(content, node.Content[i], node.Content[i+1])
+	}
+	node.Content = content
+	return removed
+}
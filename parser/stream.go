@@ -0,0 +1,91 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// documentSplitter splits a stream of YAML documents on "---" lines without reading the whole
+// stream into memory. The read and document buffers are reused for all documents and streams.
+type documentSplitter struct {
+	reader *bufio.Reader
+	buf    []byte
+	// maxDocumentSize is the maximum size of a document in bytes, zero is unlimited
+	maxDocumentSize int
+}
+
+func newDocumentSplitter() *documentSplitter {
+	return &documentSplitter{reader: bufio.NewReaderSize(nil, 64*1024)}
+}
+
+// split calls fn for every non-empty document of r with the line number that the document starts
+// on. Windows style newlines are converted to unix style newlines. raw is only valid until fn
+// returns, and must be copied to be kept.
+func (s *documentSplitter) split(r io.Reader, fn func(offset int, raw []byte) error) error {
+	s.reader.Reset(r)
+	s.buf = s.buf[:0]
+
+	line := 1 // Line numbers are 1 indexed
+	offset := line
+	lineStart := 0
+
+	flush := func() error {
+		doc := s.buf[:lineStart]
+		if s.tooLarge(len(doc)) {
+			return s.tooLargeError(offset)
+		}
+		if len(bytes.TrimSpace(doc)) > 0 {
+			if err := fn(offset, doc); err != nil {
+				return err
+			}
+		}
+		s.buf = s.buf[:0]
+		lineStart = 0
+		return nil
+	}
+
+	for {
+		chunk, err := s.reader.ReadSlice('\n')
+		s.buf = append(s.buf, chunk...)
+		// The completed lines, or the current line alone, are already too large
+		if s.tooLarge(lineStart) || s.tooLarge(len(s.buf)-lineStart) {
+			return s.tooLargeError(offset)
+		}
+		if errors.Is(err, bufio.ErrBufferFull) {
+			// The line is longer than the read buffer, continue reading it
+			continue
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+
+		if bytes.HasSuffix(s.buf, []byte("\r\n")) {
+			s.buf = append(s.buf[:len(s.buf)-2], '\n')
+		}
+
+		if bytes.Equal(bytes.TrimSuffix(s.buf[lineStart:], []byte("\n")), []byte("---")) {
+			if err := flush(); err != nil {
+				return err
+			}
+			offset = line + 1
+		} else {
+			lineStart = len(s.buf)
+		}
+		line++
+
+		if errors.Is(err, io.EOF) {
+			return flush()
+		}
+	}
+}
+
+func (s *documentSplitter) tooLarge(size int) bool {
+	return s.maxDocumentSize > 0 && size > s.maxDocumentSize
+}
+
+func (s *documentSplitter) tooLargeError(offset int) error {
+	return fmt.Errorf("%w: the document on line %d is larger than %d bytes", ErrLimitExceeded, offset, s.maxDocumentSize)
+}
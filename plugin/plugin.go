@@ -0,0 +1,507 @@
+// Package plugin runs checks that are implemented by external executables, so that checks can be
+// written in any language.
+//
+// A plugin is an executable that is called with one of two commands:
+//
+//	<plugin> describe
+//
+// prints the checks of the plugin as JSON, see Description, and
+//
+//	<plugin> check <check-id>
+//
+// reads the objects that the check scores from stdin, as JSON Lines with one object per line, and
+// prints their scores as JSON Lines, with one Result per line in the order of the objects. Every
+// check of a plugin is called once per run, with all objects.
+//
+// On Linux, the plugins that are executables run in a sandbox: they can only write to an empty
+// temporary working directory, and only read the system directories such as /usr and /etc and
+// their own executable. They inherit no file descriptors except stdin, stdout and stderr, and their
+// memory, CPU time, file sizes and open files are limited, see Limits. The sandbox uses Landlock,
+// that has to be enabled in the kernel. The network is not restricted. On other systems, the
+// executables can only run unconfined, see Options.Unconfined.
+//
+// A plugin can also be a WebAssembly module with the extension ".wasm", that implements the same
+// commands with the arguments, stdin and stdout of WASI. The modules are portable, and run in the
+// WASI runtime that is embedded in kube-score, on all systems. They have no access to the file
+// system and the network: they can only read their arguments, the allowed environment variables and
+// stdin, and write to stdout. Their memory is limited by Limits.Memory.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/romnn/kube-score/config"
+	"github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/score/checks"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// Prefix is the prefix of the names of the executables that are discovered as plugins
+const Prefix = "kube-score-check-"
+
+// ProtocolVersion is the version of the protocol that the plugins have to describe themselves with
+const ProtocolVersion = 1
+
+// WasmExtension is the extension of the plugins that are WebAssembly modules
+const WasmExtension = ".wasm"
+
+// DefaultTimeout is the default timeout of every invocation of a plugin
+const DefaultTimeout = 10 * time.Second
+
+// maxOutput is the maximum size of the description of a plugin, and of the result of every object
+const maxOutput = 1 << 20
+
+// Options are the options that the plugins are run with
+type Options struct {
+	// Timeout is the timeout of every invocation of a plugin, DefaultTimeout is used if it is zero
+	Timeout time.Duration
+	// Env are the names of the environment variables that are passed to the plugins. The plugins
+	// run with an otherwise empty environment, in an empty temporary working directory, that TMPDIR
+	// is set to.
+	Env []string
+	// Limits are the resource limits of the processes of the plugins
+	Limits Limits
+	// Unconfined runs the plugins that are executables without the sandbox and without the Limits,
+	// as the user that runs kube-score. Load fails without it if the sandbox is not supported. The
+	// WebAssembly modules are always confined.
+	Unconfined bool
+}
+
+// Description is the output of the describe command
+type Description struct {
+	Version int     `json:"version"`
+	Checks  []Check `json:"checks"`
+}
+
+// Check is a check of a plugin
+type Check struct {
+	Name     string `json:"name"`
+	Comment  string `json:"comment"`
+	Optional bool   `json:"optional,omitempty"`
+	// Kinds are the kinds of the objects that the check scores, all objects are scored if it is empty
+	Kinds []string `json:"kinds,omitempty"`
+	// MinKubernetesVersion and MaxKubernetesVersion are the versions of Kubernetes that the check
+	// applies to, e.g. v1.27, the check applies to all versions if they are empty
+	MinKubernetesVersion string `json:"minKubernetesVersion,omitempty"`
+	MaxKubernetesVersion string `json:"maxKubernetesVersion,omitempty"`
+}
+
+// versions returns the versions of Kubernetes that the check applies to
+func (c Check) versions() (checks.VersionRange, error) {
+	var versions checks.VersionRange
+	for _, v := range []struct {
+		value string
+		dst   *config.Semver
+	}{{c.MinKubernetesVersion, &versions.Min}, {c.MaxKubernetesVersion, &versions.Max}} {
+		if v.value == "" {
+			continue
+		}
+		version, err := config.ParseSemver(v.value)
+		if err != nil {
+			return versions, fmt.Errorf("invalid Kubernetes version %q of check %s, expected the format vN.NN", v.value, c.Name)
+		}
+		*v.dst = version
+	}
+	return versions, nil
+}
+
+// Result is the output of the check command. Grade is one of "critical", "warning", "info",
+// "almost-ok" or "ok".
+type Result struct {
+	Grade    string    `json:"grade"`
+	Skipped  bool      `json:"skipped,omitempty"`
+	Comments []Comment `json:"comments,omitempty"`
+}
+
+// Comment is a comment of the score of a check
+type Comment struct {
+	Path             string `json:"path,omitempty"`
+	Summary          string `json:"summary"`
+	Description      string `json:"description,omitempty"`
+	DocumentationURL string `json:"documentationURL,omitempty"`
+}
+
+// Plugin is an executable with the checks that it described
+type Plugin struct {
+	Path   string
+	Checks []Check
+
+	options Options
+	// wasm is the compiled module of a plugin that is a WebAssembly module
+	wasm *wasmModule
+}
+
+// Discover returns the plugins in the directories of path, which is a list of directories like the
+// PATH environment variable. Like with PATH, the first executable with a name is used. WebAssembly
+// modules do not have to be executable.
+func Discover(path string) []string {
+	var plugins []string
+	seen := make(map[string]struct{})
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if !strings.HasPrefix(name, Prefix) {
+				continue
+			}
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil || !info.Mode().IsRegular() {
+				continue
+			}
+			if info.Mode().Perm()&0o111 == 0 && !isWasm(name) {
+				continue
+			}
+			seen[name] = struct{}{}
+			plugins = append(plugins, filepath.Join(dir, name))
+		}
+	}
+	slices.Sort(plugins)
+	return plugins
+}
+
+// Load runs the describe command of the plugins
+func Load(paths []string, options Options) ([]*Plugin, error) {
+	if options.Timeout == 0 {
+		options.Timeout = DefaultTimeout
+	}
+	if !options.Unconfined && slices.ContainsFunc(paths, func(path string) bool { return !isWasm(path) }) {
+		if err := sandboxSupported(); err != nil {
+			return nil, fmt.Errorf("%w, the plugins can only run unconfined", err)
+		}
+	}
+
+	plugins := make([]*Plugin, 0, len(paths))
+	for _, path := range paths {
+		// The plugins run in a temporary working directory, that relative paths would be relative to
+		path, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		p := &Plugin{Path: path, options: options}
+		if isWasm(path) {
+			if p.wasm, err = compileWasm(path, options.Limits); err != nil {
+				return nil, err
+			}
+		}
+		out, err := p.run(nil, maxOutput, "describe")
+		if err != nil {
+			return nil, err
+		}
+		var desc Description
+		if err := json.Unmarshal(out, &desc); err != nil {
+			return nil, fmt.Errorf("invalid description of plugin %s: %w", path, err)
+		}
+		if desc.Version != ProtocolVersion {
+			return nil, fmt.Errorf(
+				"plugin %s uses protocol version %d, expected version %d",
+				path, desc.Version, ProtocolVersion,
+			)
+		}
+		for _, c := range desc.Checks {
+			if c.Name == "" {
+				return nil, fmt.Errorf("plugin %s describes a check without a name", path)
+			}
+			if _, err := c.versions(); err != nil {
+				return nil, fmt.Errorf("plugin %s: %w", path, err)
+			}
+		}
+		p.Checks = desc.Checks
+		plugins = append(plugins, p)
+	}
+	return plugins, nil
+}
+
+// Register registers the checks of the plugins. The checks score the documents of the objects, the
+// parser has to keep them, see parser.Config.KeepDocuments. Every check calls its plugin once per
+// run, with all objects, when it scores the first object.
+func Register(allChecks *checks.Checks, plugins []*Plugin) error {
+	registered := make(map[string]string)
+	for _, c := range allChecks.All() {
+		registered[c.ID] = "kube-score"
+	}
+
+	for _, p := range plugins {
+		for _, c := range p.Checks {
+			check := checks.NewCheck(c.Name, "all", c.Comment, c.Optional)
+			if err := checks.ValidateID(check.ID, checks.NamespaceCustom); err != nil {
+				return fmt.Errorf("invalid check of plugin %s: %w", p.Path, err)
+			}
+			if other, ok := registered[check.ID]; ok {
+				return fmt.Errorf("check %s of plugin %s is already registered by %s", check.ID, p.Path, other)
+			}
+			registered[check.ID] = p.Path
+
+			fn := p.check(check.ID, c.Kinds)
+			versions, err := c.versions()
+			if err != nil {
+				return fmt.Errorf("plugin %s: %w", p.Path, err)
+			}
+			allChecks.RegisterForKubernetesVersions(versions, func() {
+				if c.Optional {
+					allChecks.RegisterOptionalMetaContextCheck(c.Name, c.Comment, fn)
+				} else {
+					allChecks.RegisterMetaContextCheck(c.Name, c.Comment, fn)
+				}
+			})
+		}
+	}
+	return nil
+}
+
+// batchKey is the key of the results of a check of a plugin in the checks.Context
+type batchKey struct {
+	plugin *Plugin
+	check  string
+}
+
+// objectKey identifies an object in the results of a batch
+type objectKey struct {
+	apiVersion, kind, namespace, name string
+}
+
+func newObjectKey(meta domain.BothMeta) objectKey {
+	return objectKey{meta.TypeMeta.APIVersion, meta.TypeMeta.Kind, meta.ObjectMeta.Namespace, meta.ObjectMeta.Name}
+}
+
+// batch are the results of a check of a plugin for all objects. err is set if the plugin failed,
+// and applies to all objects.
+type batch struct {
+	scores map[objectKey]result
+	err    error
+}
+
+type result struct {
+	score scorecard.TestScore
+	err   error
+}
+
+func (p *Plugin) check(id string, kinds []string) checks.ContextCheckFunc[domain.BothMeta] {
+	scores := func(meta domain.BothMeta) bool {
+		return len(kinds) == 0 || slices.Contains(kinds, meta.TypeMeta.Kind)
+	}
+	return func(ctx *checks.Context, meta domain.BothMeta) (score scorecard.TestScore, err error) {
+		if !scores(meta) {
+			score.Grade = scorecard.GradeAllOK
+			score.Skipped = true
+			score.AddComment("", fmt.Sprintf("Skipped because the check does not score %s objects", meta.TypeMeta.Kind), "")
+			return
+		}
+
+		b := checks.Index(ctx, batchKey{plugin: p, check: id}, func(objects domain.AllTypes) batch {
+			return p.checkAll(id, objects, scores)
+		})
+		if b.err != nil {
+			return score, b.err
+		}
+		res, ok := b.scores[newObjectKey(meta)]
+		if !ok {
+			return score, errors.New("the object is not available to the plugin")
+		}
+		return res.score, res.err
+	}
+}
+
+// checkAll runs the check of the plugin once, with all objects that it scores. The objects that
+// are skipped are not passed to the plugin.
+func (p *Plugin) checkAll(id string, objects domain.AllTypes, scores func(domain.BothMeta) bool) batch {
+	var (
+		keys  []objectKey
+		stdin bytes.Buffer
+	)
+	for _, meta := range objects.Metas() {
+		if !scores(meta) || (meta.FileLocationer != nil && meta.FileLocation().Skip) {
+			continue
+		}
+		doc, ok := objects.Document(meta.TypeMeta, meta.ObjectMeta)
+		if !ok {
+			continue
+		}
+		// The documents are compact JSON, without line breaks
+		stdin.Write(doc)
+		stdin.WriteByte('\n')
+		keys = append(keys, newObjectKey(meta))
+	}
+	b := batch{scores: make(map[objectKey]result, len(keys))}
+	if len(keys) == 0 {
+		return b
+	}
+
+	out, err := p.run(stdin.Bytes(), maxOutput*len(keys), "check", id)
+	if err != nil {
+		b.err = err
+		return b
+	}
+	dec := json.NewDecoder(bytes.NewReader(out))
+	for _, key := range keys {
+		var res Result
+		if err := dec.Decode(&res); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = fmt.Errorf("the plugin printed %d results for %d objects", len(b.scores), len(keys))
+			}
+			b.err = fmt.Errorf("invalid result of plugin: %w", err)
+			return b
+		}
+		score, err := parseResult(res)
+		b.scores[key] = result{score: score, err: err}
+	}
+	return b
+}
+
+func parseResult(res Result) (score scorecard.TestScore, err error) {
+	score.Skipped = res.Skipped
+	switch strings.ToLower(res.Grade) {
+	case "critical":
+		score.Grade = scorecard.GradeCritical
+	case "warning":
+		score.Grade = scorecard.GradeWarning
+	case "info":
+		score.Grade = scorecard.GradeInfo
+	case "almost-ok":
+		score.Grade = scorecard.GradeAlmostOK
+	case "ok":
+		score.Grade = scorecard.GradeAllOK
+	case "":
+		if !res.Skipped {
+			return score, errors.New("invalid result of plugin: missing grade")
+		}
+		score.Grade = scorecard.GradeAllOK
+	default:
+		return score, fmt.Errorf(
+			"invalid result of plugin: invalid grade %q, expected critical, warning, info, almost-ok or ok",
+			res.Grade,
+		)
+	}
+
+	for _, c := range res.Comments {
+		score.AddCommentWithURL(c.Path, c.Summary, c.Description, c.DocumentationURL)
+	}
+	return score, nil
+}
+
+// run runs the plugin with the arguments and stdin, and returns its output, that is limited to
+// limit bytes
+func (p *Plugin) run(stdin []byte, limit int, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.options.Timeout)
+	defer cancel()
+
+	stdout := &limitedBuffer{limit: limit}
+	var err error
+	if p.wasm != nil {
+		err = p.wasm.run(ctx, p.env(), bytes.NewReader(stdin), stdout, append([]string{filepath.Base(p.Path)}, args...))
+		if err != nil {
+			err = fmt.Errorf("plugin %s failed: %w", p.Path, err)
+		}
+	} else {
+		err = p.exec(ctx, bytes.NewReader(stdin), stdout, args)
+	}
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("plugin %s timed out after %s", p.Path, p.options.Timeout)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if stdout.exceeded {
+		return nil, fmt.Errorf("the output of plugin %s exceeds %d bytes", p.Path, limit)
+	}
+	return stdout.Bytes(), nil
+}
+
+// exec runs the executable of the plugin in an empty temporary working directory and with only the
+// allowed environment variables, in the sandbox unless it runs unconfined
+func (p *Plugin) exec(ctx context.Context, stdin io.Reader, stdout io.Writer, args []string) error {
+	dir, err := os.MkdirTemp("", "kube-score-plugin-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	cmd, err := p.command(ctx, dir, args)
+	if err != nil {
+		return err
+	}
+	stderr := &limitedBuffer{limit: maxOutput}
+	cmd.Dir = dir
+	cmd.Env = append(p.env(), "TMPDIR="+dir)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// Do not wait for the processes that the plugin started, and that keep the output open
+	cmd.WaitDelay = time.Second
+	setProcessGroup(cmd)
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("plugin %s failed: %w: %s", p.Path, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// command returns the command that runs the plugin. Unless the plugin runs unconfined, kube-score
+// runs itself as the sandbox, that executes the plugin, see init.
+func (p *Plugin) command(ctx context.Context, dir string, args []string) (*exec.Cmd, error) {
+	if p.options.Unconfined {
+		return exec.CommandContext(ctx, p.Path, args...), nil
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start the sandbox of plugin %s: %w", p.Path, err)
+	}
+	config, err := json.Marshal(sandboxConfig{Dir: dir, Read: []string{p.Path}, Limits: p.options.Limits})
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.CommandContext(ctx, executable)
+	cmd.Args = append([]string{sandboxName, string(config), p.Path}, args...)
+	return cmd, nil
+}
+
+func isWasm(path string) bool {
+	return strings.HasSuffix(path, WasmExtension)
+}
+
+func (p *Plugin) env() []string {
+	env := []string{}
+	for _, name := range p.options.Env {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	return env
+}
+
+// limitedBuffer is a buffer that discards the writes after its limit, and does not fail them, so
+// that the plugin is not killed by a broken pipe
+type limitedBuffer struct {
+	bytes.Buffer
+	limit    int
+	exceeded bool
+}
+
+func (b *limitedBuffer) Write(data []byte) (int, error) {
+	if remaining := b.limit - b.Len(); len(data) > remaining {
+		b.exceeded = true
+		_, _ = b.Buffer.Write(data[:max(remaining, 0)])
+		return len(data), nil
+	}
+	return b.Buffer.Write(data)
+}
@@ -0,0 +1,337 @@
+package plugin
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/parser"
+	"github.com/romnn/kube-score/score"
+	"github.com/romnn/kube-score/score/checks"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+const teamLabelPlugin = `#!/bin/sh
+case "$1" in
+describe)
+	echo '{"version": 1, "checks": [{"name": "custom/Deployment has team label", "comment": "Makes sure that Deployments have a team label", "kinds": ["Deployment"]}]}'
+	;;
+check)
+	while IFS= read -r doc || [ -n "$doc" ]; do
+		case "$doc" in
+		*'"team"'*) echo '{"grade": "ok"}' ;;
+		*) echo '{"grade": "critical", "comments": [{"path": "metadata.labels", "summary": "The Deployment has no team label"}]}' ;;
+		esac
+	done
+	;;
+esac
+`
+
+const input = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: with-team
+  labels:
+    team: platform
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: without-team
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: service
+`
+
+type namedReader struct {
+	*strings.Reader
+}
+
+func (namedReader) Name() string {
+	return "input.yaml"
+}
+
+// writePlugin writes an executable script. The tests that run plugins are not parallel, as an
+// executable that is written while another test forks can fail to run with ETXTBSY.
+func writePlugin(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	assert.NoError(t, os.WriteFile(path, []byte(script), 0o755))
+	return path
+}
+
+func scoreWithPlugins(t *testing.T, plugins []*Plugin, runConfig *config.RunConfiguration) scorecard.Scorecard {
+	t.Helper()
+	p, err := parser.New(&parser.Config{KeepDocuments: true})
+	assert.NoError(t, err)
+	parsed, err := p.ParseFiles([]ks.NamedReader{namedReader{strings.NewReader(input)}})
+	assert.NoError(t, err)
+
+	allChecks := checks.New(nil)
+	assert.NoError(t, Register(allChecks, plugins))
+	card, err := score.Score(parsed, allChecks, runConfig)
+	assert.NoError(t, err)
+	return *card
+}
+
+func grades(card scorecard.Scorecard, id string) map[string]scorecard.TestScore {
+	scores := make(map[string]scorecard.TestScore)
+	for _, o := range card {
+		for _, ts := range o.Checks {
+			if ts.Check.ID == id {
+				scores[o.ObjectMeta.Name] = ts
+			}
+		}
+	}
+	return scores
+}
+
+func TestPlugin(t *testing.T) {
+	path := writePlugin(t, t.TempDir(), "kube-score-check-team", teamLabelPlugin)
+	plugins, err := Load([]string{path}, Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, []Check{{
+		Name:    "custom/Deployment has team label",
+		Comment: "Makes sure that Deployments have a team label",
+		Kinds:   []string{"Deployment"},
+	}}, plugins[0].Checks)
+
+	scores := grades(scoreWithPlugins(t, plugins, &config.RunConfiguration{}), "custom/deployment-has-team-label")
+	assert.Equal(t, scorecard.GradeAllOK, scores["with-team"].Grade)
+	assert.Equal(t, scorecard.GradeCritical, scores["without-team"].Grade)
+	if assert.Len(t, scores["without-team"].Comments, 1) {
+		assert.Equal(t, "metadata.labels", scores["without-team"].Comments[0].Path)
+		assert.Equal(t, "The Deployment has no team label", scores["without-team"].Comments[0].Summary)
+	}
+	assert.True(t, scores["service"].Skipped)
+}
+
+func TestPluginBatch(t *testing.T) {
+	// The plugin counts the objects that it reads, and prints the count in every result
+	script := `#!/bin/sh
+case "$1" in
+describe)
+	echo '{"version": 1, "checks": [{"name": "custom/Batch", "kinds": ["Deployment"]}]}'
+	;;
+check)
+	count=0
+	while IFS= read -r doc || [ -n "$doc" ]; do count=$((count + 1)); done
+	i=0
+	while [ "$i" -lt "$count" ]; do
+		echo "{\"grade\": \"warning\", \"comments\": [{\"summary\": \"$count objects\"}]}"
+		i=$((i + 1))
+	done
+	;;
+esac
+`
+	path := writePlugin(t, t.TempDir(), "kube-score-check-batch", script)
+	plugins, err := Load([]string{path}, Options{})
+	assert.NoError(t, err)
+
+	scores := grades(scoreWithPlugins(t, plugins, &config.RunConfiguration{}), "custom/batch")
+	for _, name := range []string{"with-team", "without-team"} {
+		assert.Equal(t, scorecard.GradeWarning, scores[name].Grade)
+		if assert.Len(t, scores[name].Comments, 1) {
+			assert.Equal(t, "2 objects", scores[name].Comments[0].Summary)
+		}
+	}
+
+	// A plugin that does not print a result for every object fails for all objects
+	script = strings.Replace(script, `i=0`, `i=1`, 1)
+	path = writePlugin(t, t.TempDir(), "kube-score-check-batch", script)
+	plugins, err = Load([]string{path}, Options{})
+	assert.NoError(t, err)
+
+	scores = grades(scoreWithPlugins(t, plugins, &config.RunConfiguration{}), "custom/batch")
+	assert.Contains(t, scores["with-team"].Error, "the plugin printed 1 results for 2 objects")
+	assert.Contains(t, scores["without-team"].Error, "the plugin printed 1 results for 2 objects")
+}
+
+func TestPluginKubernetesVersions(t *testing.T) {
+	script := strings.Replace(teamLabelPlugin, `"kinds"`, `"minKubernetesVersion": "v1.27", "kinds"`, 1)
+	path := writePlugin(t, t.TempDir(), "kube-score-check-team", script)
+	plugins, err := Load([]string{path}, Options{})
+	assert.NoError(t, err)
+
+	allChecks := checks.New(&checks.Config{KubernetesVersion: config.Semver{Major: 1, Minor: 26}})
+	assert.NoError(t, Register(allChecks, plugins))
+	check := allChecks.Metas()["custom/deployment-has-team-label"]
+	assert.Equal(t, config.Semver{Major: 1, Minor: 27}, check.MinKubernetesVersion)
+	score, err := check.Run(checks.NewContext(nil), ks.BothMeta{})
+	assert.NoError(t, err)
+	assert.True(t, score.Skipped)
+
+	// An invalid version fails to load the plugin
+	script = strings.Replace(teamLabelPlugin, `"kinds"`, `"maxKubernetesVersion": "1.x", "kinds"`, 1)
+	path = writePlugin(t, t.TempDir(), "kube-score-check-team", script)
+	_, err = Load([]string{path}, Options{})
+	assert.ErrorContains(t, err, `invalid Kubernetes version "1.x"`)
+}
+
+func TestPluginOptional(t *testing.T) {
+	script := strings.Replace(teamLabelPlugin, `"kinds"`, `"optional": true, "kinds"`, 1)
+	path := writePlugin(t, t.TempDir(), "kube-score-check-team", script)
+	plugins, err := Load([]string{path}, Options{})
+	assert.NoError(t, err)
+
+	scores := grades(scoreWithPlugins(t, plugins, &config.RunConfiguration{}), "custom/deployment-has-team-label")
+	assert.True(t, scores["without-team"].Skipped)
+
+	scores = grades(scoreWithPlugins(t, plugins, &config.RunConfiguration{
+		EnabledOptionalTests: map[string]struct{}{"custom/deployment-has-team-label": {}},
+	}), "custom/deployment-has-team-label")
+	assert.Equal(t, scorecard.GradeCritical, scores["without-team"].Grade)
+}
+
+func TestPluginTimeout(t *testing.T) {
+	script := strings.Replace(teamLabelPlugin, "check)\n", "check)\n\twhile :; do :; done\n", 1)
+	path := writePlugin(t, t.TempDir(), "kube-score-check-team", script)
+	plugins, err := Load([]string{path}, Options{Timeout: 200 * time.Millisecond})
+	assert.NoError(t, err)
+
+	scores := grades(scoreWithPlugins(t, plugins, &config.RunConfiguration{}), "custom/deployment-has-team-label")
+	assert.Equal(t, scorecard.GradeCritical, scores["with-team"].Grade)
+	assert.Contains(t, scores["with-team"].Error, "timed out after 200ms")
+}
+
+func TestPluginEnv(t *testing.T) {
+	t.Setenv("KUBE_SCORE_TEST_ALLOWED", "allowed")
+	t.Setenv("KUBE_SCORE_TEST_DENIED", "denied")
+
+	script := `#!/bin/sh
+case "$1" in
+describe)
+	echo '{"version": 1, "checks": [{"name": "custom/Plugin env"}]}'
+	;;
+check)
+	while IFS= read -r doc || [ -n "$doc" ]; do
+		if [ "$KUBE_SCORE_TEST_ALLOWED" = "allowed" ] && [ -z "$KUBE_SCORE_TEST_DENIED" ] && [ -z "$(ls -A)" ]; then
+			echo '{"grade": "ok"}'
+		else
+			echo '{"grade": "critical"}'
+		fi
+	done
+	;;
+esac
+`
+	path := writePlugin(t, t.TempDir(), "kube-score-check-env", script)
+	plugins, err := Load([]string{path}, Options{Env: []string{"PATH", "KUBE_SCORE_TEST_ALLOWED"}})
+	assert.NoError(t, err)
+
+	scores := grades(scoreWithPlugins(t, plugins, &config.RunConfiguration{}), "custom/plugin-env")
+	assert.Equal(t, scorecard.GradeAllOK, scores["service"].Grade)
+}
+
+// buildWasmPlugin builds the plugin of testdata/wasm as a WebAssembly module
+func buildWasmPlugin(t *testing.T) string {
+	t.Helper()
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("building the WebAssembly module needs the go command")
+	}
+	module := filepath.Join(t.TempDir(), "kube-score-check-team.wasm")
+	cmd := exec.Command(gobin, "build", "-o", module, "./testdata/wasm")
+	cmd.Env = append(os.Environ(), "GOOS=wasip1", "GOARCH=wasm")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("failed to build the WebAssembly module: %v: %s", err, out)
+	}
+	return module
+}
+
+func TestWasmPlugin(t *testing.T) {
+	t.Setenv("KUBE_SCORE_TEST_ALLOWED", "allowed")
+	t.Setenv("KUBE_SCORE_TEST_DENIED", "denied")
+	module := buildWasmPlugin(t)
+
+	plugins, err := Load([]string{module}, Options{Env: []string{"KUBE_SCORE_TEST_ALLOWED"}})
+	assert.NoError(t, err)
+
+	card := scoreWithPlugins(t, plugins, &config.RunConfiguration{})
+	scores := grades(card, "custom/deployment-has-team-label")
+	assert.Equal(t, scorecard.GradeAllOK, scores["with-team"].Grade)
+	assert.Equal(t, scorecard.GradeCritical, scores["without-team"].Grade)
+
+	// The module can neither read nor write files, and only sees the allowed environment variables
+	sandbox := grades(card, "custom/wasm-sandbox")["service"]
+	assert.Equal(t, scorecard.GradeAllOK, sandbox.Grade)
+	if assert.Len(t, sandbox.Comments, 1) {
+		assert.Equal(t, "env allowed", sandbox.Comments[0].Summary)
+	}
+
+	// The memory of the module is limited
+	_, err = Load([]string{module}, Options{Limits: Limits{Memory: 1 << 20}})
+	assert.ErrorContains(t, err, "over limit of 16 pages (1 Mi)")
+}
+
+func TestLoadInvalidPlugin(t *testing.T) {
+	dir := t.TempDir()
+	for name, script := range map[string]string{
+		"version": "#!/bin/sh\necho '{\"version\": 2, \"checks\": []}'\n",
+		"json":    "#!/bin/sh\necho 'checks'\n",
+		"name":    "#!/bin/sh\necho '{\"version\": 1, \"checks\": [{\"comment\": \"no name\"}]}'\n",
+		"exit":    "#!/bin/sh\necho 'failed' >&2\nexit 1\n",
+	} {
+		path := writePlugin(t, dir, name, script)
+		_, err := Load([]string{path}, Options{})
+		assert.Error(t, err, name)
+	}
+}
+
+func TestRegisterDuplicateCheck(t *testing.T) {
+	t.Parallel()
+
+	allChecks := score.RegisterAllChecks(nil, nil)
+	err := Register(allChecks, []*Plugin{
+		{Path: "kube-score-check-team", Checks: []Check{{Name: "custom/Team label"}}},
+		{Path: "kube-score-check-dup", Checks: []Check{{Name: "custom/Team label"}}},
+	})
+	assert.EqualError(t, err, "check custom/team-label of plugin kube-score-check-dup is already registered by kube-score-check-team")
+}
+
+func TestRegisterNamespacedCheck(t *testing.T) {
+	t.Parallel()
+
+	allChecks := score.RegisterAllChecks(nil, nil)
+	err := Register(allChecks, []*Plugin{{Path: "kube-score-check-team", Checks: []Check{{Name: "custom/Container Image Tag"}}}})
+	assert.NoError(t, err)
+	assert.NoError(t, allChecks.Err())
+
+	err = Register(allChecks, []*Plugin{{Path: "kube-score-check-acme", Checks: []Check{{Name: "acme/Team label"}}}})
+	assert.EqualError(t, err, `invalid check of plugin kube-score-check-acme: check ID "acme/team-label" has the unknown namespace "acme", expected one of custom, cel, rego`)
+
+	// Plugins can neither register checks without a namespace, nor claim the namespaces of the
+	// policy engines
+	err = Register(allChecks, []*Plugin{{Path: "kube-score-check-team", Checks: []Check{{Name: "Team label"}}}})
+	assert.ErrorContains(t, err, `check ID "team-label" must have a namespace`)
+	err = Register(allChecks, []*Plugin{{Path: "kube-score-check-cel", Checks: []Check{{Name: "cel/Team label"}}}})
+	assert.ErrorContains(t, err, `check ID "cel/team-label" can not be in the namespace "cel", expected custom`)
+}
+
+func TestDiscover(t *testing.T) {
+	t.Parallel()
+
+	first, second := t.TempDir(), t.TempDir()
+	writePlugin(t, first, "kube-score-check-a", "#!/bin/sh\n")
+	writePlugin(t, second, "kube-score-check-a", "#!/bin/sh\n")
+	writePlugin(t, second, "kube-score-check-b", "#!/bin/sh\n")
+	writePlugin(t, second, "other", "#!/bin/sh\n")
+	assert.NoError(t, os.WriteFile(filepath.Join(second, "kube-score-check-c"), nil, 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(second, "kube-score-check-d.wasm"), nil, 0o644))
+
+	path := strings.Join([]string{first, filepath.Join(first, "missing"), second}, string(os.PathListSeparator))
+	assert.Equal(t, []string{
+		filepath.Join(first, "kube-score-check-a"),
+		filepath.Join(second, "kube-score-check-b"),
+		filepath.Join(second, "kube-score-check-d.wasm"),
+	}, Discover(path))
+}
@@ -0,0 +1,53 @@
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Limits are the resource limits of the processes of a plugin. The zero value of every limit is
+// unlimited.
+type Limits struct {
+	// Memory is the maximum size of the virtual memory of every process in bytes
+	Memory int64
+	// CPUTime is the maximum CPU time of every process, it is rounded up to seconds
+	CPUTime time.Duration
+	// FileSize is the maximum size of a file that a process writes in bytes
+	FileSize int64
+	// OpenFiles is the maximum number of files that every process has open at the same time
+	OpenFiles int
+}
+
+// sandboxName is the name that kube-score runs itself with to start a plugin in the sandbox, see
+// init
+const sandboxName = "kube-score-plugin-sandbox"
+
+// sandboxConfig is passed to the sandbox by the arguments
+type sandboxConfig struct {
+	// Dir is the working directory of the plugin, the only directory that it can write to
+	Dir string `json:"dir"`
+	// Read are the files that the plugin can read and execute in addition to the system
+	// directories, such as the executable of the plugin
+	Read   []string `json:"read"`
+	Limits Limits   `json:"limits"`
+}
+
+// init runs the sandbox instead of kube-score if kube-score was started as the sandbox of a
+// plugin, with the arguments "<sandboxConfig> <executable> <args>". The sandbox confines itself
+// and then executes the plugin, that inherits the confinement. It is started by kube-score itself,
+// so that the plugins can be confined without a separate binary.
+func init() {
+	if len(os.Args) < 3 || os.Args[0] != sandboxName {
+		return
+	}
+	var config sandboxConfig
+	err := json.Unmarshal([]byte(os.Args[1]), &config)
+	if err == nil {
+		err = execConfined(config, os.Args[2], os.Args[2:])
+	}
+	// execConfined only returns if the plugin could not be executed
+	_, _ = fmt.Fprintf(os.Stderr, "%s: %v\n", sandboxName, err)
+	os.Exit(126)
+}
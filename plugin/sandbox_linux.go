@@ -0,0 +1,209 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// systemPaths are the directories that the plugins can read and execute, with the interpreters
+// and the libraries that they need
+var systemPaths = []string{"/bin", "/sbin", "/usr", "/lib", "/lib32", "/lib64", "/etc", "/nix/store"}
+
+// devices are the devices that the plugins can read and write
+var devices = []string{"/dev/null", "/dev/zero", "/dev/random", "/dev/urandom"}
+
+const (
+	accessRead = unix.LANDLOCK_ACCESS_FS_EXECUTE | unix.LANDLOCK_ACCESS_FS_READ_FILE | unix.LANDLOCK_ACCESS_FS_READ_DIR
+	// accessFile are the rights that apply to files, the other rights only apply to directories
+	accessFile = unix.LANDLOCK_ACCESS_FS_EXECUTE | unix.LANDLOCK_ACCESS_FS_READ_FILE |
+		unix.LANDLOCK_ACCESS_FS_WRITE_FILE | unix.LANDLOCK_ACCESS_FS_TRUNCATE | unix.LANDLOCK_ACCESS_FS_IOCTL_DEV
+	accessDevice = unix.LANDLOCK_ACCESS_FS_READ_FILE | unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_TRUNCATE | unix.LANDLOCK_ACCESS_FS_IOCTL_DEV
+)
+
+// landlockABI returns the version of the Landlock ABI of the kernel
+func landlockABI() (int, error) {
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		return 0, fmt.Errorf("the sandbox of the plugins needs Landlock, that is not enabled in the kernel: %w", errno)
+	}
+	return int(abi), nil
+}
+
+func sandboxSupported() error {
+	_, err := landlockABI()
+	return err
+}
+
+// handledAccess returns the file system rights that the Landlock ABI can restrict
+func handledAccess(abi int) uint64 {
+	access := uint64(unix.LANDLOCK_ACCESS_FS_MAKE_SYM<<1 - 1)
+	if abi >= 2 {
+		access |= unix.LANDLOCK_ACCESS_FS_REFER
+	}
+	if abi >= 3 {
+		access |= unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+	if abi >= 5 {
+		access |= unix.LANDLOCK_ACCESS_FS_IOCTL_DEV
+	}
+	return access
+}
+
+// execConfined limits the resources of the process, marks all file descriptors except stdin,
+// stdout and stderr to be closed, restricts the file system to the system directories, the files
+// of the config and the working directory, and executes the plugin
+func execConfined(config sandboxConfig, path string, args []string) error {
+	// The Landlock domain applies to the thread that executes the plugin
+	runtime.LockOSThread()
+
+	if err := setLimits(config.Limits); err != nil {
+		return err
+	}
+	closeInheritedFiles()
+	if err := restrictFileSystem(config); err != nil {
+		return err
+	}
+	return unix.Exec(path, args, os.Environ())
+}
+
+func setLimits(limits Limits) error {
+	for _, l := range []struct {
+		name     string
+		resource int
+		value    uint64
+	}{
+		{"memory", unix.RLIMIT_AS, uint64(max(limits.Memory, 0))},
+		{"CPU time", unix.RLIMIT_CPU, uint64(math.Ceil(max(limits.CPUTime.Seconds(), 0)))},
+		{"file size", unix.RLIMIT_FSIZE, uint64(max(limits.FileSize, 0))},
+		{"open files", unix.RLIMIT_NOFILE, uint64(max(limits.OpenFiles, 0))},
+	} {
+		if l.value == 0 {
+			continue
+		}
+		limit := unix.Rlimit{Cur: l.value, Max: l.value}
+		if l.resource == unix.RLIMIT_CPU {
+			// The process is sent SIGXCPU at the soft limit, and SIGKILL at the hard limit
+			limit.Max++
+		}
+		if err := unix.Setrlimit(l.resource, &limit); err != nil {
+			return fmt.Errorf("failed to limit the %s: %w", l.name, err)
+		}
+	}
+	return nil
+}
+
+// closeInheritedFiles marks all file descriptors from 3 to be closed when the plugin is executed,
+// also the descriptors that kube-score inherited or opened without O_CLOEXEC
+func closeInheritedFiles() {
+	_, _, errno := unix.Syscall(unix.SYS_CLOSE_RANGE, 3, math.MaxUint32, unix.CLOSE_RANGE_CLOEXEC)
+	if errno == 0 {
+		return
+	}
+	// Kernels before 5.11 do not have close_range
+	var limit unix.Rlimit
+	if err := unix.Getrlimit(unix.RLIMIT_NOFILE, &limit); err != nil || limit.Cur > 1<<16 {
+		limit.Cur = 1 << 16
+	}
+	for fd := 3; fd < int(limit.Cur); fd++ {
+		syscall.CloseOnExec(fd)
+	}
+}
+
+func restrictFileSystem(config sandboxConfig) error {
+	abi, err := landlockABI()
+	if err != nil {
+		return err
+	}
+	handled := handledAccess(abi)
+	attr := unix.LandlockRulesetAttr{Access_fs: handled}
+	ruleset, _, errno := unix.Syscall(
+		unix.SYS_LANDLOCK_CREATE_RULESET,
+		uintptr(unsafe.Pointer(&attr)),
+		unsafe.Sizeof(attr),
+		0,
+	)
+	if errno != 0 {
+		return fmt.Errorf("failed to create the Landlock ruleset: %w", errno)
+	}
+	defer func() { _ = unix.Close(int(ruleset)) }()
+
+	type rule struct {
+		path   string
+		access uint64
+	}
+	var rules []rule
+	for _, path := range append(systemPaths, config.Read...) {
+		rules = append(rules, rule{path, accessRead})
+	}
+	for _, path := range devices {
+		rules = append(rules, rule{path, accessDevice})
+	}
+	rules = append(rules, rule{config.Dir, handled})
+
+	for _, rule := range rules {
+		if err := allowPath(int(ruleset), rule.path, rule.access&handled); err != nil {
+			return err
+		}
+	}
+
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to set no_new_privs: %w", err)
+	}
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, ruleset, 0, 0); errno != 0 {
+		return fmt.Errorf("failed to restrict the file system: %w", errno)
+	}
+	return nil
+}
+
+// allowPath adds the rule that allows the access to the path, and to everything below it if it is
+// a directory. Paths that do not exist are ignored.
+func allowPath(ruleset int, path string, access uint64) error {
+	fd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if errors.Is(err, unix.ENOENT) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = unix.Close(fd) }()
+
+	var stat unix.Stat_t
+	if err := unix.Fstat(fd, &stat); err != nil {
+		return fmt.Errorf("failed to stat %s: %w", path, err)
+	}
+	if stat.Mode&unix.S_IFMT != unix.S_IFDIR {
+		access &= accessFile
+	}
+
+	attr := unix.LandlockPathBeneathAttr{Allowed_access: access, Parent_fd: int32(fd)}
+	_, _, errno := unix.Syscall6(
+		unix.SYS_LANDLOCK_ADD_RULE,
+		uintptr(ruleset),
+		unix.LANDLOCK_RULE_PATH_BENEATH,
+		uintptr(unsafe.Pointer(&attr)),
+		0, 0, 0,
+	)
+	if errno != 0 {
+		return fmt.Errorf("failed to allow the access to %s: %w", path, errno)
+	}
+	return nil
+}
+
+// setProcessGroup starts the plugin in its own process group, and kills the group instead of
+// only the plugin when the command is canceled, so that the processes that the plugin started do
+// not outlive the timeout
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
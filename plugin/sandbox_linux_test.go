@@ -0,0 +1,110 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sys/unix"
+
+	"github.com/romnn/kube-score/config"
+)
+
+// sandboxPlugin reports what it can access as the comments of its result
+const sandboxPlugin = `#!/bin/sh
+case "$1" in
+describe)
+	echo '{"version": 1, "checks": [{"name": "custom/Sandbox", "kinds": ["Service"]}]}'
+	;;
+check)
+	read -r doc
+	comments=""
+	comment() { comments="$comments{\"summary\": \"$1\"},"; }
+	cat "$KUBE_SCORE_TEST_SECRET" >/dev/null 2>&1 && comment "read outside"
+	(echo x >"$KUBE_SCORE_TEST_OUTSIDE") 2>/dev/null && comment "wrote outside"
+	(echo x >file) 2>/dev/null && comment "wrote working directory"
+	(: <&9) 2>/dev/null && comment "inherited fd"
+	comment "memory $(ulimit -v)"
+	comment "cpu $(ulimit -t)"
+	comment "file size $(ulimit -f)"
+	comment "open files $(ulimit -n)"
+	echo "{\"grade\": \"ok\", \"comments\": [${comments%,}]}"
+	;;
+esac
+`
+
+func TestPluginSandbox(t *testing.T) {
+	if err := sandboxSupported(); err != nil {
+		t.Skip(err)
+	}
+
+	dir := t.TempDir()
+	secret := filepath.Join(dir, "secret")
+	assert.NoError(t, os.WriteFile(secret, []byte("secret"), 0o600))
+	t.Setenv("KUBE_SCORE_TEST_SECRET", secret)
+	t.Setenv("KUBE_SCORE_TEST_OUTSIDE", filepath.Join(dir, "outside"))
+
+	// A file descriptor without O_CLOEXEC, that is inherited by the processes that kube-score
+	// starts
+	if _, err := unix.FcntlInt(9, unix.F_GETFD, 0); err == nil {
+		t.Skip("file descriptor 9 is in use")
+	}
+	f, err := os.Open(secret)
+	assert.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	assert.NoError(t, unix.Dup2(int(f.Fd()), 9))
+	defer func() { _ = unix.Close(9) }()
+
+	path := writePlugin(t, dir, "kube-score-check-sandbox", sandboxPlugin)
+	summaries := func(options Options) []string {
+		options.Env = []string{"PATH", "KUBE_SCORE_TEST_SECRET", "KUBE_SCORE_TEST_OUTSIDE"}
+		plugins, err := Load([]string{path}, options)
+		assert.NoError(t, err)
+		score := grades(scoreWithPlugins(t, plugins, &config.RunConfiguration{}), "custom/sandbox")["service"]
+		assert.Empty(t, score.Error)
+		var summaries []string
+		for _, c := range score.Comments {
+			summaries = append(summaries, c.Summary)
+		}
+		return summaries
+	}
+
+	assert.Equal(t, []string{
+		"wrote working directory",
+		"memory 524288",
+		"cpu 2",
+		"file size 2048",
+		"open files 64",
+	}, summaries(Options{Limits: Limits{
+		Memory:    512 << 20,
+		CPUTime:   1500 * time.Millisecond,
+		FileSize:  1 << 20,
+		OpenFiles: 64,
+	}}))
+	assert.NoFileExists(t, filepath.Join(dir, "outside"))
+
+	// Unconfined plugins have the access of kube-score, and the limits of the user
+	assert.Subset(t, summaries(Options{Unconfined: true}), []string{
+		"read outside",
+		"wrote outside",
+		"wrote working directory",
+		"inherited fd",
+	})
+}
+
+func TestPluginCPUTimeLimit(t *testing.T) {
+	if err := sandboxSupported(); err != nil {
+		t.Skip(err)
+	}
+
+	script := strings.Replace(teamLabelPlugin, "check)\n", "check)\n\twhile :; do :; done\n", 1)
+	path := writePlugin(t, t.TempDir(), "kube-score-check-team", script)
+	plugins, err := Load([]string{path}, Options{Limits: Limits{CPUTime: time.Second}})
+	assert.NoError(t, err)
+
+	scores := grades(scoreWithPlugins(t, plugins, &config.RunConfiguration{}), "custom/deployment-has-team-label")
+	assert.Contains(t, scores["with-team"].Error, "CPU time limit exceeded")
+}
@@ -0,0 +1,19 @@
+//go:build !linux
+
+package plugin
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+func sandboxSupported() error {
+	return fmt.Errorf("the sandbox of the plugins is not supported on %s", runtime.GOOS)
+}
+
+func execConfined(sandboxConfig, string, []string) error {
+	return sandboxSupported()
+}
+
+func setProcessGroup(*exec.Cmd) {}
@@ -0,0 +1,70 @@
+// Command wasm is a plugin that is built as a WebAssembly module by the tests, with
+// GOOS=wasip1 GOARCH=wasm
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+type comment struct {
+	Path    string `json:"path,omitempty"`
+	Summary string `json:"summary"`
+}
+
+type result struct {
+	Grade    string    `json:"grade"`
+	Comments []comment `json:"comments,omitempty"`
+}
+
+func main() {
+	switch {
+	case len(os.Args) == 2 && os.Args[1] == "describe":
+		fmt.Println(`{"version": 1, "checks": [` +
+			`{"name": "custom/Deployment has team label", "kinds": ["Deployment"]}, ` +
+			`{"name": "custom/Wasm sandbox", "kinds": ["Service"]}]}`)
+	case len(os.Args) == 3 && os.Args[1] == "check":
+		check(os.Args[2])
+	default:
+		os.Exit(1)
+	}
+}
+
+func check(id string) {
+	in := bufio.NewScanner(os.Stdin)
+	in.Buffer(nil, 1<<20)
+	out := json.NewEncoder(os.Stdout)
+	for in.Scan() {
+		var object struct {
+			Metadata struct {
+				Labels map[string]string `json:"labels"`
+			} `json:"metadata"`
+		}
+		if err := json.Unmarshal(in.Bytes(), &object); err != nil {
+			os.Exit(1)
+		}
+
+		res := result{Grade: "ok"}
+		switch id {
+		case "custom/deployment-has-team-label":
+			if _, ok := object.Metadata.Labels["team"]; !ok {
+				res = result{Grade: "critical", Comments: []comment{{Path: "metadata.labels", Summary: "The Deployment has no team label"}}}
+			}
+		case "custom/wasm-sandbox":
+			// The module reports what it can access
+			if _, err := os.ReadFile("/etc/hostname"); err == nil {
+				res.Comments = append(res.Comments, comment{Summary: "read /etc/hostname"})
+			}
+			if _, err := os.ReadDir("."); err == nil {
+				res.Comments = append(res.Comments, comment{Summary: "read the working directory"})
+			}
+			if err := os.WriteFile("file", nil, 0o600); err == nil {
+				res.Comments = append(res.Comments, comment{Summary: "wrote a file"})
+			}
+			res.Comments = append(res.Comments, comment{Summary: "env " + os.Getenv("KUBE_SCORE_TEST_ALLOWED") + os.Getenv("KUBE_SCORE_TEST_DENIED")})
+		}
+		_ = out.Encode(res)
+	}
+}
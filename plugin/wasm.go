@@ -0,0 +1,80 @@
+package plugin
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+)
+
+// wasmPageSize is the size of a page of the memory of a WebAssembly module
+const wasmPageSize = 64 << 10
+
+// wasmModule is a plugin that is a WebAssembly module. It runs in the WASI runtime that is
+// embedded in kube-score, without preopened directories and without sockets: it can only read its
+// arguments, its environment and stdin, and write to stdout.
+type wasmModule struct {
+	runtime wazero.Runtime
+	module  wazero.CompiledModule
+}
+
+// compileWasm compiles the module, its memory is limited to the memory of the limits
+func compileWasm(path string, limits Limits) (*wasmModule, error) {
+	code, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	config := wazero.NewRuntimeConfig().WithCloseOnContextDone(true)
+	if limits.Memory > 0 {
+		config = config.WithMemoryLimitPages(uint32(min(max(limits.Memory/wasmPageSize, 1), 1<<16)))
+	}
+	runtime := wazero.NewRuntimeWithConfig(ctx, config)
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		_ = runtime.Close(ctx)
+		return nil, err
+	}
+	module, err := runtime.CompileModule(ctx, code)
+	if err != nil {
+		_ = runtime.Close(ctx)
+		return nil, fmt.Errorf("invalid WebAssembly module %s: %w", path, err)
+	}
+	return &wasmModule{runtime: runtime, module: module}, nil
+}
+
+// run runs the module with the arguments, the first of which is the name of the program. The
+// module is stopped when ctx is done.
+func (m *wasmModule) run(ctx context.Context, env []string, stdin io.Reader, stdout io.Writer, args []string) error {
+	config := wazero.NewModuleConfig().
+		// Every run is a new instance of the module, that has no name so that the instances do not
+		// conflict
+		WithName("").
+		WithArgs(args...).
+		WithStdin(stdin).
+		WithStdout(stdout).
+		WithSysWalltime().
+		WithSysNanotime().
+		WithSysNanosleep().
+		WithRandSource(rand.Reader)
+	for _, e := range env {
+		name, value, _ := strings.Cut(e, "=")
+		config = config.WithEnv(name, value)
+	}
+
+	instance, err := m.runtime.InstantiateModule(ctx, m.module, config)
+	if instance != nil {
+		_ = instance.Close(ctx)
+	}
+	if exit := (*sys.ExitError)(nil); errors.As(err, &exit) && exit.ExitCode() == 0 {
+		return nil
+	}
+	return err
+}
@@ -33,7 +33,7 @@ func CI(scoreCard *scorecard.Scorecard) io.Reader {
 					)
 				} else {
 					fmt.Fprintf(w, "[%s] %s\n",
-						card.Grade.String(),
+						label(card),
 						scoredObject.HumanFriendlyRef(),
 					)
 				}
@@ -44,6 +44,9 @@ func CI(scoreCard *scorecard.Scorecard) io.Reader {
 				if comment.Path != "" {
 					message = "(" + comment.Path + ") " + comment.Summary
 				}
+				if comment.Fingerprint != "" {
+					message += " [" + comment.Fingerprint + "]"
+				}
 
 				if card.Skipped {
 					fmt.Fprintf(w, "[SKIPPED] %s: %s\n",
@@ -52,7 +55,7 @@ func CI(scoreCard *scorecard.Scorecard) io.Reader {
 					)
 				} else {
 					fmt.Fprintf(w, "[%s] %s: %s\n",
-						card.Grade.String(),
+						label(card),
 						scoredObject.HumanFriendlyRef(),
 						message,
 					)
@@ -63,3 +66,11 @@ func CI(scoreCard *scorecard.Scorecard) io.Reader {
 
 	return w
 }
+
+// label returns the label of the grade of the check, checks that failed to run are labeled ERROR
+func label(card scorecard.TestScore) string {
+	if card.Error != "" {
+		return "ERROR"
+	}
+	return card.Grade.String()
+}
@@ -1,6 +1,7 @@
 package ci
 
 import (
+	"errors"
 	"io"
 	"testing"
 
@@ -109,3 +110,38 @@ func TestCiOutput(t *testing.T) {
 [SKIPPED] bar-no-namespace v1/Testing
 `, string(all))
 }
+
+func TestCiOutputErrored(t *testing.T) {
+	t.Parallel()
+	errored := scorecard.Errored(errors.New("broken"))
+	errored.Check = domain.Check{Name: "test-errored"}
+	r := CI(&scorecard.Scorecard{
+		"a": &scorecard.ScoredObject{
+			TypeMeta:   v1.TypeMeta{Kind: "Testing", APIVersion: "v1"},
+			ObjectMeta: v1.ObjectMeta{Name: "foo"},
+			Checks:     []scorecard.TestScore{errored},
+		},
+	})
+	all, err := io.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, "[ERROR] foo v1/Testing: The check failed to run\n", string(all))
+}
+
+func TestCiOutputInfo(t *testing.T) {
+	t.Parallel()
+	info := scorecard.TestScore{Check: domain.Check{Name: "test-info"}, Grade: scorecard.GradeInfo}
+	info.AddComment("a", "advice", "")
+	r := CI(&scorecard.Scorecard{
+		"a": &scorecard.ScoredObject{
+			TypeMeta:   v1.TypeMeta{Kind: "Testing", APIVersion: "v1"},
+			ObjectMeta: v1.ObjectMeta{Name: "foo"},
+			Checks: []scorecard.TestScore{
+				info,
+				{Check: domain.Check{Name: "test-info-no-comment"}, Grade: scorecard.GradeInfo},
+			},
+		},
+	})
+	all, err := io.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Equal(t, "[INFO] foo v1/Testing: (a) advice\n[INFO] foo v1/Testing\n", string(all))
+}
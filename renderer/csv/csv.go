@@ -0,0 +1,74 @@
+// Package csv renders the findings as CSV, with one row per finding, to be imported into
+// spreadsheets and other tools that read columns
+package csv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// Header is the first row of the output
+var Header = []string{"file", "line", "kind", "namespace", "name", "check", "grade", "path", "summary"}
+
+// CSV returns a row for every comment of the checks that are not skipped, and a row without a
+// summary for the checks without comments that are not OK
+func CSV(scoreCard *scorecard.Scorecard) io.Reader {
+	var keys []string
+	for k := range *scoreCard {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	_ = w.Write(Header)
+
+	for _, key := range keys {
+		o := (*scoreCard)[key]
+		for _, card := range o.Checks {
+			if card.Skipped {
+				continue
+			}
+
+			row := func(path, summary string) {
+				_ = w.Write([]string{
+					o.FileLocation.Name,
+					strconv.Itoa(o.FileLocation.Line),
+					o.TypeMeta.Kind,
+					o.ObjectMeta.Namespace,
+					o.ObjectMeta.Name,
+					card.Check.ID,
+					label(card),
+					path,
+					summary,
+				})
+			}
+
+			if len(card.Comments) == 0 {
+				if card.Grade != scorecard.GradeAllOK {
+					row("", "")
+				}
+				continue
+			}
+			for _, comment := range card.Comments {
+				row(comment.Path, comment.Summary)
+			}
+		}
+	}
+
+	w.Flush()
+	return buf
+}
+
+// label returns the label of the grade of the check, checks that failed to run are labeled ERROR
+func label(card scorecard.TestScore) string {
+	if card.Error != "" {
+		return "ERROR"
+	}
+	return card.Grade.String()
+}
@@ -0,0 +1,55 @@
+package csv
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func TestCSV(t *testing.T) {
+	t.Parallel()
+
+	warning := scorecard.TestScore{Check: domain.Check{ID: "container-resources"}, Grade: scorecard.GradeWarning}
+	warning.AddComment("app", "CPU limit is not set", "description")
+	warning.AddComment("app", "Summary with \"quotes\", and a comma", "")
+
+	info := scorecard.TestScore{Check: domain.Check{ID: "pod-topology-spread-constraints"}, Grade: scorecard.GradeInfo}
+	info.AddComment("", "Spread the pods across zones", "")
+
+	errored := scorecard.Errored(errors.New("failed"))
+	errored.Check = domain.Check{ID: "plugin"}
+
+	card := &scorecard.Scorecard{
+		"a": &scorecard.ScoredObject{
+			TypeMeta:     metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta:   metav1.ObjectMeta{Name: "web", Namespace: "prod"},
+			FileLocation: domain.FileLocation{Name: "app.yaml", Line: 3},
+			Checks: []scorecard.TestScore{
+				warning,
+				errored,
+				{Check: domain.Check{ID: "pod-probes"}, Grade: scorecard.GradeCritical},
+				{Check: domain.Check{ID: "deployment-replicas"}, Grade: scorecard.GradeAllOK},
+				info,
+				{Check: domain.Check{ID: "service-traffic-distribution"}, Grade: scorecard.GradeInfo},
+				{Check: domain.Check{ID: "skipped"}, Grade: scorecard.GradeCritical, Skipped: true},
+			},
+		},
+	}
+
+	out, err := io.ReadAll(CSV(card))
+	assert.NoError(t, err)
+	assert.Equal(t, `file,line,kind,namespace,name,check,grade,path,summary
+app.yaml,3,Deployment,prod,web,container-resources,WARNING,app,CPU limit is not set
+app.yaml,3,Deployment,prod,web,container-resources,WARNING,app,"Summary with ""quotes"", and a comma"
+app.yaml,3,Deployment,prod,web,plugin,ERROR,,The check failed to run
+app.yaml,3,Deployment,prod,web,pod-probes,CRITICAL,,
+app.yaml,3,Deployment,prod,web,pod-topology-spread-constraints,INFO,,Spread the pods across zones
+app.yaml,3,Deployment,prod,web,service-traffic-distribution,INFO,,
+`, string(out))
+}
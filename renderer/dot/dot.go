@@ -0,0 +1,48 @@
+// Package dot renders the references between the objects of the input in the DOT language of Graphviz
+package dot
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/romnn/kube-score/score/graph"
+)
+
+// Dot renders the graph, the objects are grouped by namespace
+func Dot(g *graph.Graph) io.Reader {
+	w := bytes.NewBufferString("")
+	fmt.Fprintln(w, "digraph kube_score {")
+	fmt.Fprintln(w, "\trankdir=LR;")
+	fmt.Fprintln(w, "\tnode [shape=box];")
+
+	// The nodes are sorted by namespace
+	namespace, clusters := "", 0
+	seen := make(map[graph.Node]bool)
+	for _, n := range g.Nodes() {
+		if seen[n] {
+			continue
+		}
+		seen[n] = true
+
+		if clusters == 0 || n.Namespace != namespace {
+			if clusters > 0 {
+				fmt.Fprintln(w, "\t}")
+			}
+			namespace = n.Namespace
+			fmt.Fprintf(w, "\tsubgraph cluster_%d {\n", clusters)
+			fmt.Fprintf(w, "\t\tlabel=%q;\n", namespace)
+			clusters++
+		}
+		fmt.Fprintf(w, "\t\t%q [label=%q];\n", n.String(), n.Kind+"\n"+n.Name)
+	}
+	if clusters > 0 {
+		fmt.Fprintln(w, "\t}")
+	}
+
+	for _, e := range g.Edges() {
+		fmt.Fprintf(w, "\t%q -> %q [label=%q];\n", e.From.String(), e.To.String(), e.Relation)
+	}
+	fmt.Fprintln(w, "}")
+	return w
+}
@@ -0,0 +1,135 @@
+// Package events renders the scores as Kubernetes objects, that make the findings visible with
+// kubectl describe when they are applied with server-side apply: the grade of every object as a
+// "kube-score/grade" annotation, and a Warning Event for every object that fails critical checks.
+package events
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/scorecard"
+)
+
+const (
+	// GradeAnnotation is the annotation with the worst grade of the checks of the object
+	GradeAnnotation = "kube-score/grade"
+	// Reason is the reason of the Events of the objects that fail critical checks
+	Reason = "KubeScoreCritical"
+	// Component is the component that reports the Events
+	Component = "kube-score"
+)
+
+// partialObject is an object with only the fields that are applied, server-side apply does not
+// change the other fields of the object
+type partialObject struct {
+	metav1.TypeMeta `json:",inline"`
+	Metadata        partialObjectMeta `json:"metadata"`
+}
+
+type partialObjectMeta struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace,omitempty"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// Events returns a v1 List with the annotated objects and the Events, that is applied with
+// "kubectl apply --server-side --field-manager kube-score -f -"
+func Events(card *scorecard.Scorecard, now time.Time) io.Reader {
+	var keys []string
+	for k := range *card {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	items := []any{}
+	var events []any
+	for _, key := range keys {
+		o := (*card)[key]
+		grade, critical, ok := summarize(o)
+		if !ok {
+			continue
+		}
+
+		items = append(items, partialObject{
+			TypeMeta: o.TypeMeta,
+			Metadata: partialObjectMeta{
+				Name:        o.ObjectMeta.Name,
+				Namespace:   o.ObjectMeta.Namespace,
+				Annotations: map[string]string{GradeAnnotation: strings.ToLower(grade.String())},
+			},
+		})
+		if len(critical) > 0 {
+			events = append(events, event(o, critical, now))
+		}
+	}
+	items = append(items, events...)
+
+	list := map[string]any{
+		"apiVersion": "v1",
+		"kind":       "List",
+		"items":      items,
+	}
+	d, _ := json.MarshalIndent(list, "", "    ")
+	return bytes.NewBuffer(d)
+}
+
+// summarize returns the worst grade and the critical checks of the object, ok is false if all
+// checks of the object are skipped
+func summarize(o *scorecard.ScoredObject) (grade scorecard.Grade, critical []string, ok bool) {
+	grade = scorecard.GradeAllOK
+	for _, ts := range o.Checks {
+		if ts.Skipped {
+			continue
+		}
+		ok = true
+		grade = min(grade, ts.Grade)
+		if ts.Grade == scorecard.GradeCritical {
+			critical = append(critical, ts.Check.ID)
+		}
+	}
+	return grade, critical, ok
+}
+
+func event(o *scorecard.ScoredObject, critical []string, now time.Time) corev1.Event {
+	// Events of cluster scoped objects are in the default namespace
+	namespace := o.ObjectMeta.Namespace
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+
+	// The name is stable, so that applying the Events of the next run replaces the Event
+	ref := o.TypeMeta.APIVersion + "/" + o.TypeMeta.Kind + "/" + o.ObjectMeta.Namespace + "/" + o.ObjectMeta.Name
+	sum := sha256.Sum256([]byte(ref))
+
+	return corev1.Event{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Event"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.kube-score.%s", o.ObjectMeta.Name, hex.EncodeToString(sum[:])[:8]),
+			Namespace: namespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: o.TypeMeta.APIVersion,
+			Kind:       o.TypeMeta.Kind,
+			Name:       o.ObjectMeta.Name,
+			Namespace:  o.ObjectMeta.Namespace,
+		},
+		Reason:              Reason,
+		Message:             "Failed critical kube-score checks: " + strings.Join(critical, ", "),
+		Type:                corev1.EventTypeWarning,
+		Source:              corev1.EventSource{Component: Component},
+		ReportingController: Component,
+		FirstTimestamp:      metav1.NewTime(now),
+		LastTimestamp:       metav1.NewTime(now),
+		Count:               1,
+	}
+}
@@ -0,0 +1,104 @@
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+type location struct{}
+
+func (location) FileLocation() ks.FileLocation {
+	return ks.FileLocation{Name: "input.yaml", Line: 1}
+}
+
+func TestEvents(t *testing.T) {
+	t.Parallel()
+
+	card := scorecard.New()
+	add := func(kind, namespace, name string, scores ...scorecard.TestScore) {
+		o := card.NewObject(
+			metav1.TypeMeta{APIVersion: "apps/v1", Kind: kind},
+			metav1.ObjectMeta{Name: name, Namespace: namespace},
+			nil,
+		)
+		for _, ts := range scores {
+			o.Add(ts, ts.Check, location{})
+		}
+	}
+	critical := func(id string) scorecard.TestScore {
+		return scorecard.TestScore{Check: ks.Check{ID: id}, Grade: scorecard.GradeCritical}
+	}
+	add("Deployment", "prod", "web",
+		critical("container-resources"),
+		critical("pod-probes"),
+		scorecard.TestScore{Check: ks.Check{ID: "deployment-replicas"}, Grade: scorecard.GradeAllOK},
+	)
+	add("StatefulSet", "", "db",
+		scorecard.TestScore{Check: ks.Check{ID: "container-image-tag"}, Grade: scorecard.GradeWarning},
+	)
+	add("ReplicaSet", "prod", "batch",
+		scorecard.TestScore{Check: ks.Check{ID: "pod-topology-spread-constraints"}, Grade: scorecard.GradeInfo},
+		scorecard.TestScore{Check: ks.Check{ID: "deployment-replicas"}, Grade: scorecard.GradeAllOK},
+	)
+	add("DaemonSet", "prod", "skipped",
+		scorecard.TestScore{Check: ks.Check{ID: "container-resources"}, Grade: scorecard.GradeCritical, Skipped: true},
+	)
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	out, err := io.ReadAll(Events(&card, now))
+	assert.NoError(t, err)
+
+	var list struct {
+		APIVersion string            `json:"apiVersion"`
+		Kind       string            `json:"kind"`
+		Items      []json.RawMessage `json:"items"`
+	}
+	assert.NoError(t, json.Unmarshal(out, &list))
+	assert.Equal(t, "v1", list.APIVersion)
+	assert.Equal(t, "List", list.Kind)
+	if !assert.Len(t, list.Items, 4) {
+		return
+	}
+
+	// Objects with only info findings are annotated, but do not get an Event
+	var annotated []partialObject
+	for _, item := range list.Items[:3] {
+		var o partialObject
+		assert.NoError(t, json.Unmarshal(item, &o))
+		annotated = append(annotated, o)
+	}
+	assert.Equal(t, []partialObject{
+		{
+			TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			Metadata: partialObjectMeta{Name: "web", Namespace: "prod", Annotations: map[string]string{GradeAnnotation: "critical"}},
+		},
+		{
+			TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "ReplicaSet"},
+			Metadata: partialObjectMeta{Name: "batch", Namespace: "prod", Annotations: map[string]string{GradeAnnotation: "info"}},
+		},
+		{
+			TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "StatefulSet"},
+			Metadata: partialObjectMeta{Name: "db", Annotations: map[string]string{GradeAnnotation: "warning"}},
+		},
+	}, annotated)
+
+	var event corev1.Event
+	assert.NoError(t, json.Unmarshal(list.Items[3], &event))
+	assert.Equal(t, "Event", event.Kind)
+	assert.Equal(t, "prod", event.Namespace)
+	assert.Regexp(t, `^web\.kube-score\.[0-9a-f]{8}$`, event.Name)
+	assert.Equal(t, corev1.ObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Namespace: "prod"}, event.InvolvedObject)
+	assert.Equal(t, corev1.EventTypeWarning, event.Type)
+	assert.Equal(t, Reason, event.Reason)
+	assert.Equal(t, "Failed critical kube-score checks: container-resources, pod-probes", event.Message)
+	assert.True(t, event.LastTimestamp.Time.Equal(now))
+}
@@ -0,0 +1,156 @@
+// Package github writes the results of a run as a GitHub Actions job summary and step outputs
+package github
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// Environment is the subset of the GitHub Actions environment used to link to the files
+type Environment struct {
+	ServerURL  string // GITHUB_SERVER_URL
+	Repository string // GITHUB_REPOSITORY
+	SHA        string // GITHUB_SHA
+	Workspace  string // GITHUB_WORKSPACE
+}
+
+// fileURL returns a link to the line of the file in the repository, or an empty string if the
+// file is not part of the workspace
+func (e Environment) fileURL(name string, line int) string {
+	if e.ServerURL == "" || e.Repository == "" || e.SHA == "" || e.Workspace == "" {
+		return ""
+	}
+	// The input files are relative to the working directory
+	name, err := filepath.Abs(name)
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(e.Workspace, name)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	return fmt.Sprintf(
+		"%s/%s/blob/%s/%s#L%d",
+		e.ServerURL, e.Repository, e.SHA, filepath.ToSlash(rel), line,
+	)
+}
+
+type finding struct {
+	object  string
+	file    string
+	line    int
+	check   string
+	grade   scorecard.Grade
+	message string
+}
+
+// Counts returns the number of failed critical and warning checks, and of the checks with an
+// advisory info finding
+func Counts(scoreCard *scorecard.Scorecard) (critical, warning, info int) {
+	for _, so := range *scoreCard {
+		for _, check := range so.Checks {
+			if check.Skipped {
+				continue
+			}
+			switch {
+			case check.Grade <= scorecard.GradeCritical:
+				critical++
+			case check.Grade <= scorecard.GradeWarning:
+				warning++
+			case check.Grade <= scorecard.GradeInfo:
+				info++
+			}
+		}
+	}
+	return
+}
+
+// Outputs returns the step outputs in the format of $GITHUB_OUTPUT
+func Outputs(scoreCard *scorecard.Scorecard) io.Reader {
+	critical, warning, info := Counts(scoreCard)
+	return bytes.NewBufferString(fmt.Sprintf(
+		"critical_count=%d\nwarning_count=%d\ninfo_count=%d\n", critical, warning, info,
+	))
+}
+
+// Summary returns a Markdown summary of the scorecard, in the format of $GITHUB_STEP_SUMMARY. Info
+// findings are listed after the critical and warning findings, they do not fail the checks.
+func Summary(scoreCard *scorecard.Scorecard, env Environment) io.Reader {
+	w := bytes.NewBufferString("")
+
+	var findings []finding
+	for _, so := range *scoreCard {
+		for _, check := range so.Checks {
+			if check.Skipped || check.Grade > scorecard.GradeInfo {
+				continue
+			}
+			f := finding{
+				object: so.HumanFriendlyRef(),
+				file:   so.FileLocation.Name,
+				line:   so.FileLocation.Line,
+				check:  check.Check.Name,
+				grade:  check.Grade,
+			}
+			if len(check.Comments) == 0 {
+				findings = append(findings, f)
+			}
+			for _, comment := range check.Comments {
+				f.message = comment.Summary
+				if comment.Path != "" {
+					f.message = "(" + comment.Path + ") " + comment.Summary
+				}
+				findings = append(findings, f)
+			}
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].grade != findings[j].grade {
+			return findings[i].grade < findings[j].grade
+		}
+		if findings[i].object != findings[j].object {
+			return findings[i].object < findings[j].object
+		}
+		return findings[i].check < findings[j].check
+	})
+
+	critical, warning, info := Counts(scoreCard)
+	fmt.Fprintf(w, "## kube-score\n\n")
+	fmt.Fprintf(w, "| Objects | Critical | Warning | Info |\n")
+	fmt.Fprintf(w, "|---|---|---|---|\n")
+	fmt.Fprintf(w, "| %d | %d | %d | %d |\n\n", len(*scoreCard), critical, warning, info)
+
+	if len(findings) == 0 {
+		fmt.Fprintf(w, "All checks passed :white_check_mark:\n")
+		return w
+	}
+
+	fmt.Fprintf(w, "| Grade | Object | Check | Message | File |\n")
+	fmt.Fprintf(w, "|---|---|---|---|---|\n")
+	for _, f := range findings {
+		file := fmt.Sprintf("`%s:%d`", filepath.Base(f.file), f.line)
+		if url := env.fileURL(f.file, f.line); url != "" {
+			file = fmt.Sprintf("[%s:%d](%s)", filepath.Base(f.file), f.line, url)
+		}
+		fmt.Fprintf(w, "| %s | %s | %s | %s | %s |\n",
+			f.grade.String(),
+			escape(f.object),
+			escape(f.check),
+			escape(f.message),
+			file,
+		)
+	}
+
+	return w
+}
+
+// escape makes the text safe to use in a Markdown table cell
+func escape(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,112 @@
+package github
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func getTestCard() *scorecard.Scorecard {
+	return &scorecard.Scorecard{
+		"a": &scorecard.ScoredObject{
+			TypeMeta:     v1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+			ObjectMeta:   v1.ObjectMeta{Name: "foo", Namespace: "foofoo"},
+			FileLocation: domain.FileLocation{Name: "/workspace/deploy/app.yaml", Line: 12},
+			Checks: []scorecard.TestScore{
+				{
+					Check: domain.Check{Name: "test-warning"},
+					Grade: scorecard.GradeWarning,
+					Comments: []scorecard.TestScoreComment{
+						{Path: "a", Summary: "summary | with pipe"},
+					},
+				},
+				{
+					Check: domain.Check{Name: "test-critical"},
+					Grade: scorecard.GradeCritical,
+					Comments: []scorecard.TestScoreComment{
+						{Summary: "summary"},
+					},
+				},
+				{
+					Check: domain.Check{Name: "test-info"},
+					Grade: scorecard.GradeInfo,
+					Comments: []scorecard.TestScoreComment{
+						{Summary: "advice"},
+					},
+				},
+				{
+					Check:   domain.Check{Name: "test-skipped"},
+					Grade:   scorecard.GradeCritical,
+					Skipped: true,
+				},
+				{
+					Check: domain.Check{Name: "test-ok"},
+					Grade: scorecard.GradeAllOK,
+				},
+			},
+		},
+	}
+}
+
+func TestSummary(t *testing.T) {
+	t.Parallel()
+	env := Environment{
+		ServerURL:  "https://github.com",
+		Repository: "owner/repo",
+		SHA:        "abc123",
+		Workspace:  "/workspace",
+	}
+	all, err := io.ReadAll(Summary(getTestCard(), env))
+	assert.NoError(t, err)
+	assert.Equal(t, `## kube-score
+
+| Objects | Critical | Warning | Info |
+|---|---|---|---|
+| 1 | 1 | 1 | 1 |
+
+| Grade | Object | Check | Message | File |
+|---|---|---|---|---|
+| CRITICAL | foo/foofoo apps/v1/Deployment | test-critical | summary | [app.yaml:12](https://github.com/owner/repo/blob/abc123/deploy/app.yaml#L12) |
+| WARNING | foo/foofoo apps/v1/Deployment | test-warning | (a) summary \| with pipe | [app.yaml:12](https://github.com/owner/repo/blob/abc123/deploy/app.yaml#L12) |
+| INFO | foo/foofoo apps/v1/Deployment | test-info | advice | [app.yaml:12](https://github.com/owner/repo/blob/abc123/deploy/app.yaml#L12) |
+`, string(all))
+}
+
+func TestSummaryOutsideOfWorkspace(t *testing.T) {
+	t.Parallel()
+	all, err := io.ReadAll(Summary(getTestCard(), Environment{}))
+	assert.NoError(t, err)
+	assert.Contains(t, string(all), "| `app.yaml:12` |")
+}
+
+func TestSummaryRelativePath(t *testing.T) {
+	t.Parallel()
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+
+	card := getTestCard()
+	(*card)["a"].FileLocation.Name = filepath.Join("deploy", "app.yaml")
+	env := Environment{
+		ServerURL:  "https://github.com",
+		Repository: "owner/repo",
+		SHA:        "abc123",
+		Workspace:  wd,
+	}
+	all, err := io.ReadAll(Summary(card, env))
+	assert.NoError(t, err)
+	assert.Contains(t, string(all), "[app.yaml:12](https://github.com/owner/repo/blob/abc123/deploy/app.yaml#L12)")
+}
+
+func TestOutputs(t *testing.T) {
+	t.Parallel()
+	all, err := io.ReadAll(Outputs(getTestCard()))
+	assert.NoError(t, err)
+	assert.Equal(t, "critical_count=1\nwarning_count=1\ninfo_count=1\n", string(all))
+}
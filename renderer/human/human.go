@@ -73,6 +73,10 @@ func Human(
 				_, _ = color.New(color.FgHiBlack).
 					Fprintf(w, "    path=%s\n", scoredObject.FileLocation.Name)
 			}
+			for _, duplicate := range scoredObject.FileLocation.Duplicates {
+				_, _ = color.New(color.FgHiBlack).
+					Fprintf(w, "    path=%s (identical copy)\n", duplicate.Name)
+			}
 		}
 
 		if scoredObject.FileLocation.Skip {
@@ -122,6 +126,9 @@ func outputHumanStep(
 			return w
 		}
 
+	case card.Grade == scorecard.GradeInfo:
+		col = color.FgCyan
+
 	case card.Grade >= scorecard.GradeWarning:
 		// Higher than or equal to --threshold-warning
 		col = color.FgYellow
@@ -132,6 +139,8 @@ func outputHumanStep(
 
 	if card.Skipped {
 		color.New(col).Fprintf(w, "    [SKIPPED] %s\n", card.Check.Name)
+	} else if card.Error != "" {
+		color.New(col).Fprintf(w, "    [ERROR] %s\n", card.Check.Name)
 	} else {
 		color.New(col).Fprintf(w, "    [%s] %s\n", card.Grade.String(), card.Check.Name)
 	}
@@ -166,6 +175,11 @@ func outputHumanStep(
 			)
 		}
 
+		if len(comment.Fingerprint) > 0 {
+			fmt.Fprintln(w)
+			fmt.Fprintf(w, "%sFingerprint: %s", strings.Repeat(" ", 12), comment.Fingerprint)
+		}
+
 		fmt.Fprintln(w)
 	}
 
@@ -460,3 +460,36 @@ func TestHumanOutputWithLongObjectNames(t *testing.T) {
 		string(all),
 	)
 }
+
+func TestHumanOutputInfo(t *testing.T) {
+	t.Parallel()
+	card := &scorecard.Scorecard{
+		"a": &scorecard.ScoredObject{
+			TypeMeta:   v1.TypeMeta{Kind: "Testing", APIVersion: "v1"},
+			ObjectMeta: v1.ObjectMeta{Name: "foo", Namespace: "foofoo"},
+			Checks: []scorecard.TestScore{
+				{
+					Check: domain.Check{Name: "test-info"},
+					Grade: scorecard.GradeInfo,
+					Comments: []scorecard.TestScoreComment{
+						{Summary: "advisory"},
+					},
+				},
+			},
+		},
+	}
+
+	r, err := Human(card, 0, 100, false)
+	assert.Nil(t, err)
+	all, err := io.ReadAll(r)
+	assert.Nil(t, err)
+	// Info findings are shown, but do not change the status of the object
+	assert.Equal(
+		t,
+		`v1/Testing foo in foofoo                                                      ✅
+    [INFO] test-info
+        · advisory
+`,
+		string(all),
+	)
+}
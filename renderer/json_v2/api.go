@@ -17,6 +17,8 @@ type Check struct {
 	TargetType string `json:"target_type"`
 	Comment    string `json:"comment"`
 	Optional   bool   `json:"optional"`
+	// Namespace is the namespace of the ID of a custom check, and empty for the built-in checks
+	Namespace string `json:"namespace,omitempty"`
 }
 
 type ScoredObject struct {
@@ -26,6 +28,15 @@ type ScoredObject struct {
 	Checks     []TestScore       `json:"checks"`
 	FileName   string            `json:"file_name"`
 	FileRow    int               `json:"file_row"`
+	// Duplicates are the locations of identical copies of the object, that were only scored once
+	Duplicates []FileLocation `json:"duplicates,omitempty"`
+	// ObjectID is the stable identifier of the object, see scorecard.ScoredObject.ID
+	ObjectID string `json:"object_id"`
+}
+
+type FileLocation struct {
+	FileName string `json:"file_name"`
+	FileRow  int    `json:"file_row"`
 }
 
 type TestScore struct {
@@ -33,33 +44,49 @@ type TestScore struct {
 	Grade    scorecard.Grade    `json:"grade"`
 	Skipped  bool               `json:"skipped"`
 	Comments []TestScoreComment `json:"comments"`
+	// Error is the error of a check that failed to run on the object
+	Error string `json:"error,omitempty"`
 }
 
 type TestScoreComment struct {
 	Path        string `json:"path"`
 	Summary     string `json:"summary"`
 	Description string `json:"description"`
+	Fingerprint string `json:"fingerprint,omitempty"`
 }
 
 func Output(input *scorecard.Scorecard) io.Reader {
+	j, err := json.MarshalIndent(Convert(input), "", "    ")
+	if err != nil {
+		panic(err)
+	}
+	return bytes.NewBuffer(j)
+}
+
+// Convert converts the scorecard to the objects of the v2 format
+func Convert(input *scorecard.Scorecard) []ScoredObject {
 	var objs []ScoredObject
 
 	for k, v := range *input {
 		objs = append(objs, ScoredObject{
 			ObjectName: k,
+			ObjectID:   v.ID(),
 			TypeMeta:   v.TypeMeta,
 			ObjectMeta: v.ObjectMeta,
 			Checks:     convertTestScore(v.Checks),
 			FileName:   v.FileLocation.Name,
 			FileRow:    v.FileLocation.Line,
+			Duplicates: convertDuplicates(v.FileLocation.Duplicates),
 		})
 	}
+	return objs
+}
 
-	j, err := json.MarshalIndent(objs, "", "    ")
-	if err != nil {
-		panic(err)
+func convertDuplicates(in []ks.FileLocation) (res []FileLocation) {
+	for _, l := range in {
+		res = append(res, FileLocation{FileName: l.Name, FileRow: l.Line})
 	}
-	return bytes.NewBuffer(j)
+	return
 }
 
 func convertTestScore(in []scorecard.TestScore) (res []TestScore) {
@@ -69,6 +96,7 @@ func convertTestScore(in []scorecard.TestScore) (res []TestScore) {
 			Grade:    v.Grade,
 			Skipped:  v.Skipped,
 			Comments: convertComments(v.Comments),
+			Error:    v.Error,
 		})
 	}
 	return
@@ -80,6 +108,7 @@ func convertComments(in []scorecard.TestScoreComment) (res []TestScoreComment) {
 			Path:        v.Path,
 			Summary:     v.Summary,
 			Description: v.Description,
+			Fingerprint: v.Fingerprint,
 		})
 	}
 	return
@@ -92,5 +121,6 @@ func convertCheck(v ks.Check) Check {
 		TargetType: v.TargetType,
 		Comment:    v.Comment,
 		Optional:   v.Optional,
+		Namespace:  v.Namespace,
 	}
 }
@@ -0,0 +1,28 @@
+package json_v3
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/romnn/kube-score/renderer/json_v2"
+	"github.com/romnn/kube-score/renderer/metadata"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// Envelope wraps the scored objects of the v2 format with the metadata of the run
+type Envelope struct {
+	Metadata  *metadata.Metadata     `json:"metadata"`
+	Scorecard []json_v2.ScoredObject `json:"scorecard"`
+}
+
+func Output(input *scorecard.Scorecard, md *metadata.Metadata) io.Reader {
+	j, err := json.MarshalIndent(Envelope{
+		Metadata:  md,
+		Scorecard: json_v2.Convert(input),
+	}, "", "    ")
+	if err != nil {
+		panic(err)
+	}
+	return bytes.NewBuffer(j)
+}
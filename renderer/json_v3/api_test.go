@@ -0,0 +1,109 @@
+package json_v3
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/renderer/metadata"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func TestOutput(t *testing.T) {
+	t.Parallel()
+
+	input, r := metadata.NewInput("deployment.yaml", strings.NewReader("kind: Deployment\n"))
+	_, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	input.Finish()
+
+	md := &metadata.Metadata{
+		Tool:              metadata.Tool{Name: "kube-score", Version: "v1.0.0"},
+		Flags:             map[string]string{"output-format": "json"},
+		KubernetesVersion: "v1.29",
+		Inputs:            []*metadata.Input{input},
+		StartTime:         time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		EndTime:           time.Date(2024, time.January, 1, 0, 0, 1, 0, time.UTC),
+	}
+	card := &scorecard.Scorecard{
+		"a": &scorecard.ScoredObject{
+			TypeMeta:   v1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+			ObjectMeta: v1.ObjectMeta{Name: "foo"},
+		},
+	}
+
+	var envelope struct {
+		Metadata struct {
+			Tool struct {
+				Version string `json:"version"`
+			} `json:"tool"`
+			Flags             map[string]string `json:"flags"`
+			KubernetesVersion string            `json:"kubernetes_version"`
+			Inputs            []struct {
+				Name   string `json:"name"`
+				SHA256 string `json:"sha256"`
+			} `json:"inputs"`
+			StartTime string `json:"start_time"`
+		} `json:"metadata"`
+		Scorecard []struct {
+			ObjectName string `json:"object_name"`
+			ObjectID   string `json:"object_id"`
+		} `json:"scorecard"`
+	}
+	out, err := io.ReadAll(Output(card, md))
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(out, &envelope))
+
+	assert.Equal(t, "v1.0.0", envelope.Metadata.Tool.Version)
+	assert.Equal(t, map[string]string{"output-format": "json"}, envelope.Metadata.Flags)
+	assert.Equal(t, "v1.29", envelope.Metadata.KubernetesVersion)
+	assert.Equal(t, "deployment.yaml", envelope.Metadata.Inputs[0].Name)
+	// sha256sum of "kind: Deployment\n"
+	assert.Equal(t, "2e15259aa2d978f7affbf2000945c972ebf0beed0e2dcb7b0c490ee33871f120", envelope.Metadata.Inputs[0].SHA256)
+	assert.Equal(t, "2024-01-01T00:00:00Z", envelope.Metadata.StartTime)
+	assert.Equal(t, "a", envelope.Scorecard[0].ObjectName)
+	assert.Equal(t, "apps/v1/Deployment//foo@", envelope.Scorecard[0].ObjectID)
+}
+
+func TestOutputInfo(t *testing.T) {
+	t.Parallel()
+
+	info := scorecard.TestScore{Check: domain.Check{ID: "labels"}, Grade: scorecard.GradeInfo}
+	info.AddComment("", "advice", "")
+	card := &scorecard.Scorecard{
+		"a": &scorecard.ScoredObject{
+			TypeMeta:   v1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+			ObjectMeta: v1.ObjectMeta{Name: "foo"},
+			Checks:     []scorecard.TestScore{info},
+		},
+	}
+
+	var envelope struct {
+		Scorecard []struct {
+			Checks []struct {
+				Check struct {
+					ID string `json:"id"`
+				} `json:"check"`
+				Grade    int `json:"grade"`
+				Comments []struct {
+					Summary string `json:"summary"`
+				} `json:"comments"`
+			} `json:"checks"`
+		} `json:"scorecard"`
+	}
+	out, err := io.ReadAll(Output(card, &metadata.Metadata{}))
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(out, &envelope))
+
+	check := envelope.Scorecard[0].Checks[0]
+	assert.Equal(t, "labels", check.Check.ID)
+	// The grades are the numbers of scorecard.Grade, info is between warning and almost OK
+	assert.Equal(t, int(scorecard.GradeInfo), check.Grade)
+	assert.Equal(t, "advice", check.Comments[0].Summary)
+}
@@ -0,0 +1,50 @@
+// Package mermaid renders the references between the objects of the input as a Mermaid flowchart
+package mermaid
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/romnn/kube-score/score/graph"
+)
+
+// Mermaid renders the graph, the objects are grouped by namespace
+func Mermaid(g *graph.Graph) io.Reader {
+	w := bytes.NewBufferString("")
+	fmt.Fprintln(w, "flowchart LR")
+
+	// Mermaid IDs can not contain all characters of object names, the nodes are numbered instead
+	ids := make(map[graph.Node]string)
+	namespace := ""
+	for _, n := range g.Nodes() {
+		if _, ok := ids[n]; ok {
+			continue
+		}
+
+		// The nodes are sorted by namespace
+		if len(ids) == 0 || n.Namespace != namespace {
+			if len(ids) > 0 {
+				fmt.Fprintln(w, "    end")
+			}
+			namespace = n.Namespace
+			fmt.Fprintf(w, "    subgraph ns%d[\"%s\"]\n", len(ids), escape(namespace))
+		}
+		ids[n] = fmt.Sprintf("n%d", len(ids))
+		fmt.Fprintf(w, "        %s[\"%s/%s\"]\n", ids[n], escape(n.Kind), escape(n.Name))
+	}
+	if len(ids) > 0 {
+		fmt.Fprintln(w, "    end")
+	}
+
+	for _, e := range g.Edges() {
+		fmt.Fprintf(w, "    %s -->|%s| %s\n", ids[e.From], e.Relation, ids[e.To])
+	}
+	return w
+}
+
+// escape replaces the quotes, that can not be escaped in the labels of Mermaid
+func escape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
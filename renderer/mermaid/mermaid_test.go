@@ -0,0 +1,65 @@
+package mermaid
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/parser"
+	"github.com/romnn/kube-score/score/graph"
+)
+
+type namedReader struct {
+	io.Reader
+	name string
+}
+
+func (n namedReader) Name() string {
+	return n.name
+}
+
+const manifests = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+spec:
+  template:
+    metadata:
+      labels:
+        app: web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: prod
+spec:
+  selector:
+    app: web
+`
+
+func TestOutput(t *testing.T) {
+	t.Parallel()
+	p, err := parser.New(nil)
+	assert.NoError(t, err)
+	parsed, err := p.ParseFiles([]ks.NamedReader{
+		namedReader{Reader: strings.NewReader(manifests), name: "manifests.yaml"},
+	})
+	assert.NoError(t, err)
+
+	r := Mermaid(graph.Build(parsed, "default"))
+	all, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, `flowchart LR
+    subgraph ns0["prod"]
+        n0["Deployment/web"]
+        n1["Service/web"]
+    end
+    n1 -->|selects| n0
+`, string(all))
+}
@@ -0,0 +1,46 @@
+// Package metadata describes how a scorecard was produced, so that a run can be reproduced and audited
+package metadata
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"time"
+)
+
+type Metadata struct {
+	Tool              Tool              `json:"tool"`
+	Flags             map[string]string `json:"flags"`
+	KubernetesVersion string            `json:"kubernetes_version"`
+	Inputs            []*Input          `json:"inputs"`
+	StartTime         time.Time         `json:"start_time"`
+	EndTime           time.Time         `json:"end_time"`
+}
+
+type Tool struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+	Date    string `json:"date"`
+}
+
+// Input is an input file, and the digest of its contents
+type Input struct {
+	Name   string `json:"name"`
+	SHA256 string `json:"sha256"`
+
+	hash hash.Hash
+}
+
+// NewInput returns a reader that computes the digest of everything read from r.
+// The digest is set on the Input by calling Finish, after r has been fully read.
+func NewInput(name string, r io.Reader) (*Input, io.Reader) {
+	in := &Input{Name: name, hash: sha256.New()}
+	return in, io.TeeReader(r, in.hash)
+}
+
+// Finish sets the digest of all data read so far
+func (in *Input) Finish() {
+	in.SHA256 = hex.EncodeToString(in.hash.Sum(nil))
+}
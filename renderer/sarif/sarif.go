@@ -4,13 +4,54 @@ import (
 	"bytes"
 	"encoding/json"
 	"io"
+	"strconv"
 
+	"github.com/romnn/kube-score/config"
 	"github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/renderer/metadata"
 	"github.com/romnn/kube-score/sarif"
 	"github.com/romnn/kube-score/scorecard"
 )
 
+const (
+	// objectIDFingerprint is the key of the ID of the object in the partial fingerprints of a result
+	objectIDFingerprint = "kubeScoreObjectId/v1"
+	// findingFingerprint is the key of the fingerprint of the finding, that --suppress accepts
+	findingFingerprint = "kubeScoreFinding/v1"
+)
+
 func Output(input *scorecard.Scorecard) io.Reader {
+	return marshal(convert(input, nil))
+}
+
+// OutputWithMetadata adds the tool version, the invocation and the digests of the input files
+// of the run to the output. The severities override the levels and security severities of the
+// checks, see config.SarifSeverities.
+func OutputWithMetadata(input *scorecard.Scorecard, md *metadata.Metadata, severities config.SarifSeverities) io.Reader {
+	res := convert(input, severities)
+	run := &res.Runs[0]
+
+	run.Tool.Driver.Version = md.Tool.Version
+	run.Invocations = []sarif.Invocations{{
+		ExecutionSuccessful: true,
+		StartTimeUtc:        md.StartTime.UTC(),
+		EndTimeUtc:          md.EndTime.UTC(),
+	}}
+	for _, in := range md.Inputs {
+		run.Artifacts = append(run.Artifacts, sarif.Artifact{
+			Location: sarif.ArtifactLocation{URI: "file://" + in.Name},
+			Hashes:   map[string]string{"sha-256": in.SHA256},
+		})
+	}
+	run.Properties = sarif.Properties{
+		KubernetesVersion: md.KubernetesVersion,
+		Flags:             md.Flags,
+	}
+
+	return marshal(res)
+}
+
+func convert(input *scorecard.Scorecard, severities config.SarifSeverities) sarif.Sarif {
 	var results []sarif.Results
 	var rules []sarif.Rules
 
@@ -21,10 +62,16 @@ func Output(input *scorecard.Scorecard) io.Reader {
 			}
 		}
 
-		rules = append(rules, sarif.Rules{
+		rule := sarif.Rules{
 			ID:   check.ID,
 			Name: check.Name,
-		})
+		}
+		if v := severities[check.ID].SecuritySeverity; v != nil {
+			rule.Properties = &sarif.RulesProperties{
+				SecuritySeverity: strconv.FormatFloat(*v, 'f', -1, 64),
+			}
+		}
+		rules = append(rules, rule)
 	}
 
 	for _, v := range *input {
@@ -39,9 +86,16 @@ func Output(input *scorecard.Scorecard) io.Reader {
 				level = "error"
 			case scorecard.GradeWarning:
 				level = "warning"
+			case scorecard.GradeInfo:
+				level = "note"
 			default:
 				continue
 			}
+			if l := severities[check.Check.ID].Level; l == "none" {
+				continue
+			} else if l != "" {
+				level = l
+			}
 
 			addRule(check.Check)
 
@@ -52,22 +106,18 @@ func Output(input *scorecard.Scorecard) io.Reader {
 					},
 					RuleID: check.Check.ID,
 					Level:  level,
+					// The object is identified by its ID, and not by its location that changes with
+					// unrelated edits of the file
+					PartialFingerprints: map[string]string{
+						objectIDFingerprint: v.ID(),
+						findingFingerprint:  comment.Fingerprint,
+					},
 					Properties: sarif.ResultsProperties{
 						IssueConfidence: "HIGH",
 						IssueSeverity:   "HIGH",
+						ObjectID:        v.ID(),
 					},
-					Locations: []sarif.Locations{
-						{
-							PhysicalLocation: sarif.PhysicalLocation{
-								ArtifactLocation: sarif.ArtifactLocation{
-									URI: "file://" + v.FileLocation.Name,
-								},
-								ContextRegion: sarif.ContextRegion{
-									StartLine: v.FileLocation.Line,
-								},
-							},
-						},
-					},
+					Locations: locations(v.FileLocation),
 				})
 			}
 		}
@@ -88,15 +138,35 @@ func Output(input *scorecard.Scorecard) io.Reader {
 		},
 		Results: results,
 	}
-	res := sarif.Sarif{
+	return sarif.Sarif{
 		Runs:    []sarif.Run{run},
 		Version: "2.1.0",
 		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
 	}
+}
 
+func marshal(res sarif.Sarif) io.Reader {
 	j, err := json.MarshalIndent(res, "", "    ")
 	if err != nil {
 		panic(err)
 	}
 	return bytes.NewBuffer(j)
 }
+
+// locations returns the location of the object, and of its identical copies
+func locations(l domain.FileLocation) []sarif.Locations {
+	var res []sarif.Locations
+	for _, l := range append([]domain.FileLocation{l}, l.Duplicates...) {
+		res = append(res, sarif.Locations{
+			PhysicalLocation: sarif.PhysicalLocation{
+				ArtifactLocation: sarif.ArtifactLocation{
+					URI: "file://" + l.Name,
+				},
+				ContextRegion: sarif.ContextRegion{
+					StartLine: l.Line,
+				},
+			},
+		})
+	}
+	return res
+}
@@ -0,0 +1,84 @@
+package sarif
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/config"
+	"github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/sarif"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func TestConvertSeverities(t *testing.T) {
+	t.Parallel()
+
+	score := func(id string, grade scorecard.Grade) scorecard.TestScore {
+		ts := scorecard.TestScore{Check: domain.Check{ID: id, Name: id}, Grade: grade}
+		ts.AddComment("app", "summary of "+id, "")
+		return ts
+	}
+	card := &scorecard.Scorecard{
+		"a": &scorecard.ScoredObject{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Name: "web"},
+			Checks: []scorecard.TestScore{
+				score("privileged", scorecard.GradeCritical),
+				score("image-tag", scorecard.GradeWarning),
+				score("probes", scorecard.GradeWarning),
+				score("labels", scorecard.GradeInfo),
+			},
+		},
+	}
+
+	severity := 8.75
+	res := convert(card, config.SarifSeverities{
+		"privileged": {SecuritySeverity: &severity},
+		"image-tag":  {Level: "error"},
+		"labels":     {Level: "none"},
+	})
+
+	run := res.Runs[0]
+	assert.Equal(t, []sarif.Rules{
+		{ID: "privileged", Name: "privileged", Properties: &sarif.RulesProperties{SecuritySeverity: "8.75"}},
+		{ID: "image-tag", Name: "image-tag"},
+		{ID: "probes", Name: "probes"},
+	}, run.Tool.Driver.Rules)
+
+	levels := make(map[string]string)
+	for _, r := range run.Results {
+		levels[r.RuleID] = r.Level
+	}
+	assert.Equal(t, map[string]string{
+		"privileged": "error",
+		"image-tag":  "error",
+		"probes":     "warning",
+	}, levels)
+}
+
+func TestConvertInfo(t *testing.T) {
+	t.Parallel()
+
+	info := scorecard.TestScore{Check: domain.Check{ID: "labels", Name: "labels"}, Grade: scorecard.GradeInfo}
+	info.AddComment("", "advice", "")
+	card := &scorecard.Scorecard{
+		"a": &scorecard.ScoredObject{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Name: "web"},
+			Checks: []scorecard.TestScore{
+				info,
+				{Check: domain.Check{ID: "ok", Name: "ok"}, Grade: scorecard.GradeAllOK},
+			},
+		},
+	}
+
+	// Info findings are notes, and OK checks are not reported
+	run := convert(card, nil).Runs[0]
+	assert.Equal(t, []sarif.Rules{{ID: "labels", Name: "labels"}}, run.Tool.Driver.Rules)
+	if assert.Len(t, run.Results, 1) {
+		assert.Equal(t, "labels", run.Results[0].RuleID)
+		assert.Equal(t, "note", run.Results[0].Level)
+	}
+}
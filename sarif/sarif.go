@@ -11,14 +11,22 @@ type Sarif struct {
 }
 
 type Rules struct {
-	ID      string `json:"id,omitempty"`
-	Name    string `json:"name,omitempty"`
-	HelpURI string `json:"helpUri,omitempty"`
+	ID         string           `json:"id,omitempty"`
+	Name       string           `json:"name,omitempty"`
+	HelpURI    string           `json:"helpUri,omitempty"`
+	Properties *RulesProperties `json:"properties,omitempty"`
+}
+
+// RulesProperties are the properties of a rule. GitHub code scanning ranks the alerts of the
+// rule by the security severity, a number between 0 and 10 formatted as a string.
+type RulesProperties struct {
+	SecuritySeverity string `json:"security-severity,omitempty"`
 }
 
 type Driver struct {
-	Name  string  `json:"name,omitempty"`
-	Rules []Rules `json:"rules,omitempty"`
+	Name    string  `json:"name,omitempty"`
+	Version string  `json:"version,omitempty"`
+	Rules   []Rules `json:"rules,omitempty"`
 }
 
 type Tool struct {
@@ -44,11 +52,19 @@ type Conversion struct {
 
 type Invocations struct {
 	ExecutionSuccessful bool             `json:"executionSuccessful,omitempty"`
+	StartTimeUtc        time.Time        `json:"startTimeUtc,omitempty"`
 	EndTimeUtc          time.Time        `json:"endTimeUtc,omitempty"`
 	WorkingDirectory    WorkingDirectory `json:"workingDirectory,omitempty"`
 }
 
 type Properties struct {
+	KubernetesVersion string            `json:"kubernetesVersion,omitempty"`
+	Flags             map[string]string `json:"flags,omitempty"`
+}
+
+type Artifact struct {
+	Location ArtifactLocation  `json:"location,omitempty"`
+	Hashes   map[string]string `json:"hashes,omitempty"`
 }
 
 type Message struct {
@@ -87,21 +103,24 @@ type Locations struct {
 type ResultsProperties struct {
 	IssueConfidence string `json:"issue_confidence,omitempty"`
 	IssueSeverity   string `json:"issue_severity,omitempty"`
+	ObjectID        string `json:"object_id,omitempty"`
 }
 
 type Results struct {
-	Message    Message           `json:"message,omitempty"`
-	Level      string            `json:"level,omitempty"`
-	Locations  []Locations       `json:"locations,omitempty"`
-	Properties ResultsProperties `json:"properties,omitempty"`
-	RuleID     string            `json:"ruleId,omitempty"`
-	RuleIndex  int               `json:"ruleIndex,omitempty"`
+	Message             Message           `json:"message,omitempty"`
+	Level               string            `json:"level,omitempty"`
+	Locations           []Locations       `json:"locations,omitempty"`
+	PartialFingerprints map[string]string `json:"partialFingerprints,omitempty"`
+	Properties          ResultsProperties `json:"properties,omitempty"`
+	RuleID              string            `json:"ruleId,omitempty"`
+	RuleIndex           int               `json:"ruleIndex,omitempty"`
 }
 
 type Run struct {
 	Tool        Tool          `json:"tool,omitempty"`
 	Conversion  Conversion    `json:"conversion,omitempty"`
 	Invocations []Invocations `json:"invocations,omitempty"`
+	Artifacts   []Artifact    `json:"artifacts,omitempty"`
 	Properties  Properties    `json:"properties,omitempty"`
 	Results     []Results     `json:"results,omitempty"`
 }
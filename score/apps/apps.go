@@ -2,6 +2,7 @@ package apps
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -11,20 +12,19 @@ import (
 
 	ks "github.com/romnn/kube-score/domain"
 	"github.com/romnn/kube-score/score/checks"
+	"github.com/romnn/kube-score/score/graph"
 	"github.com/romnn/kube-score/score/internal"
 	"github.com/romnn/kube-score/scorecard"
 )
 
 type Options struct {
-	Namespace string
+	Namespace ks.DefaultNamespace
+	// DeploymentTopologyKeys and StatefulSetTopologyKeys are the topology keys that are accepted for the podAntiAffinity
+	DeploymentTopologyKeys  []string
+	StatefulSetTopologyKeys []string
 }
 
-func Register(
-	allChecks *checks.Checks,
-	allHPAs []ks.HpaTargeter,
-	allServices []ks.Service,
-	options Options,
-) {
+func Register(allChecks *checks.Checks, options Options) {
 	allChecks.RegisterDeploymentCheck(
 		"Deployment has host PodAntiAffinity",
 		"Makes sure that a podAntiAffinity has been set that prevents multiple pods from being scheduled on the same node. https://kubernetes.io/docs/concepts/configuration/assign-pod-node/",
@@ -36,15 +36,55 @@ func Register(
 		statefulsetHasAntiAffinity(options),
 	)
 
-	allChecks.RegisterDeploymentCheck(
+	allChecks.RegisterDeploymentContextCheck(
 		"Deployment targeted by HPA does not have replicas configured",
 		"Makes sure that Deployments using a HorizontalPodAutoscaler doesn't have a statically configured replica count set",
-		hpaDeploymentNoReplicas(allHPAs, options),
+		func(ctx *checks.Context, deployment appsv1.Deployment) (scorecard.TestScore, error) {
+			return hpaDeploymentNoReplicas(hpasIn(ctx, options, deployment.Namespace), options)(deployment)
+		},
 	)
-	allChecks.RegisterStatefulSetCheck(
+	allChecks.RegisterStatefulSetContextCheck(
 		"StatefulSet has ServiceName",
 		"Makes sure that StatefulSets have an existing headless serviceName.",
-		statefulsetHasServiceName(allServices, options),
+		func(ctx *checks.Context, statefulset appsv1.StatefulSet) (scorecard.TestScore, error) {
+			return statefulsetHasServiceName(servicesIn(ctx, options, statefulset.Namespace), options)(statefulset)
+		},
+	)
+	allChecks.RegisterOptionalStatefulSetContextCheck(
+		"StatefulSet Service publishes not ready addresses",
+		"Makes sure that the headless Service of StatefulSets sets publishNotReadyAddresses, so that the pods can discover their peers before they are ready. Enable it for clustered applications that form a cluster on startup",
+		func(ctx *checks.Context, statefulset appsv1.StatefulSet) (scorecard.TestScore, error) {
+			return statefulsetServicePublishesNotReadyAddresses(servicesIn(ctx, options, statefulset.Namespace))(statefulset)
+		},
+	)
+
+	allChecks.RegisterOptionalDeploymentContextCheck(
+		"Deployment has minReadySeconds",
+		"Makes sure that Deployments that are targeted by a Service set minReadySeconds, so that new pods have to stay ready for a while before the rollout continues",
+		func(ctx *checks.Context, deployment appsv1.Deployment) (scorecard.TestScore, error) {
+			return minReadySeconds(servicesIn(ctx, options, deployment.Namespace), deployment.Spec.MinReadySeconds, deployment.Spec.Template)
+		},
+	)
+	allChecks.RegisterOptionalStatefulSetContextCheck(
+		"StatefulSet has minReadySeconds",
+		"Makes sure that StatefulSets that are targeted by a Service set minReadySeconds, so that new pods have to stay ready for a while before the rollout continues",
+		func(ctx *checks.Context, statefulset appsv1.StatefulSet) (scorecard.TestScore, error) {
+			return minReadySeconds(servicesIn(ctx, options, statefulset.Namespace), statefulset.Spec.MinReadySeconds, statefulset.Spec.Template)
+		},
+	)
+	allChecks.RegisterOptionalDeploymentCheck(
+		"Deployment Readiness Gates",
+		"Makes sure that the readinessGates of Deployments reference valid and unique condition types",
+		func(deployment appsv1.Deployment) (scorecard.TestScore, error) {
+			return readinessGates(deployment.Spec.Template)
+		},
+	)
+	allChecks.RegisterOptionalStatefulSetCheck(
+		"StatefulSet Readiness Gates",
+		"Makes sure that the readinessGates of StatefulSets reference valid and unique condition types",
+		func(statefulset appsv1.StatefulSet) (scorecard.TestScore, error) {
+			return readinessGates(statefulset.Spec.Template)
+		},
 	)
 
 	allChecks.RegisterDeploymentCheck(
@@ -57,6 +97,51 @@ func Register(
 		"Ensure the StatefulSet selector labels match the template metadata labels.",
 		statefulSetSelectorLabelsMatching(options),
 	)
+
+	allChecks.RegisterOptionalDeploymentContextCheck(
+		"Deployment Orphan",
+		"Makes sure that the pods of Deployments are selected by at least one Service, PodDisruptionBudget or NetworkPolicy",
+		func(ctx *checks.Context, deployment appsv1.Deployment) (scorecard.TestScore, error) {
+			return orphanCheck(refsOf(ctx, options))(deployment.TypeMeta, deployment.ObjectMeta), nil
+		},
+	)
+	allChecks.RegisterOptionalStatefulSetContextCheck(
+		"StatefulSet Orphan",
+		"Makes sure that the pods of StatefulSets are selected by at least one Service, PodDisruptionBudget or NetworkPolicy",
+		func(ctx *checks.Context, statefulset appsv1.StatefulSet) (scorecard.TestScore, error) {
+			return orphanCheck(refsOf(ctx, options))(statefulset.TypeMeta, statefulset.ObjectMeta), nil
+		},
+	)
+}
+
+// The keys of the indexes of the context, by the default namespace that they are built with
+type (
+	hpasKey     struct{ namespace ks.DefaultNamespace }
+	servicesKey struct{ namespace ks.DefaultNamespace }
+	refsKey     struct{ namespace ks.DefaultNamespace }
+)
+
+// hpasIn returns the HPAs of the run in the namespace
+func hpasIn(ctx *checks.Context, options Options, namespace string) []ks.HpaTargeter {
+	return checks.Index(ctx, hpasKey{options.Namespace}, func(all ks.AllTypes) map[string][]ks.HpaTargeter {
+		return checks.ByNamespace(all.HorizontalPodAutoscalers(), options.Namespace, ks.HpaTargeter.GetObjectMeta)
+	})[options.Namespace.Of(namespace)]
+}
+
+// servicesIn returns the Services of the run in the namespace
+func servicesIn(ctx *checks.Context, options Options, namespace string) []ks.Service {
+	return checks.Index(ctx, servicesKey{options.Namespace}, func(all ks.AllTypes) map[string][]ks.Service {
+		return checks.ByNamespace(all.Services(), options.Namespace, func(s ks.Service) metav1.ObjectMeta {
+			return s.Service().ObjectMeta
+		})
+	})[options.Namespace.Of(namespace)]
+}
+
+// refsOf returns the graph of the references between the objects of the run
+func refsOf(ctx *checks.Context, options Options) *graph.Graph {
+	return checks.Index(ctx, refsKey{options.Namespace}, func(all ks.AllTypes) *graph.Graph {
+		return graph.Build(all, string(options.Namespace))
+	})
 }
 
 func hpaDeploymentNoReplicas(
@@ -69,15 +154,8 @@ func hpaDeploymentNoReplicas(
 		for _, hpa := range allHPAs {
 			target := hpa.HpaTarget()
 
-			hpaNamespace := hpa.GetObjectMeta().Namespace
-			if hpaNamespace == "" {
-				hpaNamespace = options.Namespace
-			}
-
-			deploymentNamespace := deployment.Namespace
-			if deploymentNamespace == "" {
-				deploymentNamespace = options.Namespace
-			}
+			hpaNamespace := options.Namespace.Of(hpa.GetObjectMeta().Namespace)
+			deploymentNamespace := options.Namespace.Of(deployment.Namespace)
 
 			if hpaNamespace == deploymentNamespace &&
 				strings.EqualFold(target.Kind, deployment.Kind) &&
@@ -143,7 +221,7 @@ func deploymentHasAntiAffinity(
 		}
 
 		labels := k8slabels.Set(deployment.Spec.Template.GetObjectMeta().GetLabels())
-		if hasPodAntiAffinity(labels, affinity) {
+		if hasPodAntiAffinity(labels, affinity, options.DeploymentTopologyKeys) {
 			score.Grade = scorecard.GradeAllOK
 			return score, nil
 		}
@@ -188,7 +266,7 @@ func statefulsetHasAntiAffinity(
 
 		labels := k8slabels.Set(statefulset.Spec.Template.GetObjectMeta().GetLabels())
 
-		if hasPodAntiAffinity(labels, affinity) {
+		if hasPodAntiAffinity(labels, affinity, options.StatefulSetTopologyKeys) {
 			score.Grade = scorecard.GradeAllOK
 			return score, nil
 		}
@@ -198,15 +276,14 @@ func statefulsetHasAntiAffinity(
 	}
 }
 
-func hasPodAntiAffinity(selfLabels k8slabels.Labels, affinity *corev1.Affinity) bool {
-	approvedTopologyKeys := map[string]struct{}{
-		"kubernetes.io/hostname":        {},
-		"topology.kubernetes.io/region": {},
-		"topology.kubernetes.io/zone":   {},
-
-		// Deprecated in Kubernetes v1.17
-		"failure-domain.beta.kubernetes.io/region": {},
-		"failure-domain.beta.kubernetes.io/zone":   {},
+func hasPodAntiAffinity(
+	selfLabels k8slabels.Labels,
+	affinity *corev1.Affinity,
+	topologyKeys []string,
+) bool {
+	approvedTopologyKeys := make(map[string]struct{}, len(topologyKeys))
+	for _, key := range topologyKeys {
+		approvedTopologyKeys[key] = struct{}{}
 	}
 
 	for _, pref := range affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
@@ -241,24 +318,17 @@ func statefulsetHasServiceName(
 		var score scorecard.TestScore
 		for _, service := range allServices {
 			svc := service.Service()
-			serviceNamespace := svc.Namespace
-			if serviceNamespace == "" {
-				serviceNamespace = options.Namespace
-			}
-
-			sfsNamespace := statefulset.Namespace
-			if sfsNamespace == "" {
-				sfsNamespace = options.Namespace
-			}
+			serviceNamespace := options.Namespace.Of(svc.Namespace)
+			sfsNamespace := options.Namespace.Of(statefulset.Namespace)
 
 			labels := statefulset.Spec.Template.GetObjectMeta().GetLabels()
 
 			if verbose {
-				fmt.Printf("service %q\n", svc.Name)
-				fmt.Printf("\t name: %q == %q\n", svc.Name, statefulset.Spec.ServiceName)
-				fmt.Printf("\t clusterIP: %q\n", svc.Spec.ClusterIP)
-				fmt.Printf("\t selector: %+q\n", svc.Spec.Selector)
-				fmt.Printf("\t labels: %+q\n", labels)
+				fmt.Fprintf(os.Stderr, "service %q\n", svc.Name)
+				fmt.Fprintf(os.Stderr, "\t name: %q == %q\n", svc.Name, statefulset.Spec.ServiceName)
+				fmt.Fprintf(os.Stderr, "\t clusterIP: %q\n", svc.Spec.ClusterIP)
+				fmt.Fprintf(os.Stderr, "\t selector: %+q\n", svc.Spec.Selector)
+				fmt.Fprintf(os.Stderr, "\t labels: %+q\n", labels)
 			}
 
 			if serviceNamespace != sfsNamespace ||
@@ -268,7 +338,7 @@ func statefulsetHasServiceName(
 			}
 
 			if verbose {
-				fmt.Printf("\t match: %t\n", internal.LabelSelectorMatchesLabels(
+				fmt.Fprintf(os.Stderr, "\t match: %t\n", internal.LabelSelectorMatchesLabels(
 					svc.Spec.Selector,
 					labels,
 				))
@@ -293,6 +363,44 @@ func statefulsetHasServiceName(
 	}
 }
 
+// statefulsetServicePublishesNotReadyAddresses checks that the headless Service of the StatefulSet
+// publishes the addresses of pods that are not ready. The pods of clustered applications, such as
+// etcd or ZooKeeper, often only become ready after they have joined the cluster, which they can not
+// do if the addresses of their peers are not published.
+func statefulsetServicePublishesNotReadyAddresses(
+	services []ks.Service,
+) func(statefulset appsv1.StatefulSet) (scorecard.TestScore, error) {
+	return func(statefulset appsv1.StatefulSet) (scorecard.TestScore, error) {
+		var score scorecard.TestScore
+		for _, service := range services {
+			svc := service.Service()
+			if svc.Name != statefulset.Spec.ServiceName || svc.Spec.ClusterIP != corev1.ClusterIPNone {
+				continue
+			}
+			if svc.Spec.PublishNotReadyAddresses {
+				score.Grade = scorecard.GradeAllOK
+				return score, nil
+			}
+			score.Grade = scorecard.GradeWarning
+			score.AddComment(
+				"",
+				"The headless Service does not publish not ready addresses",
+				fmt.Sprintf(
+					"Set publishNotReadyAddresses to true on the Service %s, so that the pods can discover their peers before they are ready",
+					svc.Name,
+				),
+			)
+			return score, nil
+		}
+
+		// A missing headless Service is reported by statefulset-has-servicename
+		score.Grade = scorecard.GradeAllOK
+		score.Skipped = true
+		score.AddComment("", "Skipped because the StatefulSet has no headless Service", "")
+		return score, nil
+	}
+}
+
 func statefulSetSelectorLabelsMatching(
 	opions Options,
 ) func(statefulset appsv1.StatefulSet) (scorecard.TestScore, error) {
@@ -9,6 +9,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/romnn/kube-score/config"
 	ks "github.com/romnn/kube-score/domain"
 	"github.com/romnn/kube-score/scorecard"
 )
@@ -183,7 +184,9 @@ func TestStatefulsetHasAntiAffinity(t *testing.T) {
 			},
 		}
 
-		f := statefulsetHasAntiAffinity(Options{})
+		f := statefulsetHasAntiAffinity(Options{
+			StatefulSetTopologyKeys: config.CheckParameters{}.Strings("statefulset-has-host-podantiaffinity", "topologyKeys"),
+		})
 		score, err := f(s)
 		assert.Nil(t, err)
 		assert.Equal(t, tc.expectedGrade, score.Grade, "caseID=%d", caseID)
@@ -209,7 +212,9 @@ func TestDeploymentHasAntiAffinity(t *testing.T) {
 			},
 		}
 
-		f := deploymentHasAntiAffinity(Options{})
+		f := deploymentHasAntiAffinity(Options{
+			DeploymentTopologyKeys: config.CheckParameters{}.Strings("deployment-has-host-podantiaffinity", "topologyKeys"),
+		})
 		score, err := f(s)
 		assert.Nil(t, err)
 		assert.Equal(
@@ -0,0 +1,30 @@
+package apps
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/score/graph"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// orphanCheck checks that the pods of a workload are selected by at least one Service,
+// PodDisruptionBudget or NetworkPolicy in the input
+func orphanCheck(refs *graph.Graph) func(typeMeta metav1.TypeMeta, objectMeta metav1.ObjectMeta) scorecard.TestScore {
+	return func(typeMeta metav1.TypeMeta, objectMeta metav1.ObjectMeta) (score scorecard.TestScore) {
+		node := refs.Node(typeMeta, objectMeta)
+		if len(refs.To(node, graph.RelationSelects, graph.RelationProtects, graph.RelationAppliesTo)) > 0 {
+			score.Grade = scorecard.GradeAllOK
+			return
+		}
+
+		score.Grade = scorecard.GradeWarning
+		score.AddComment(
+			"",
+			fmt.Sprintf("The %s is not selected by any Service, PodDisruptionBudget or NetworkPolicy", typeMeta.Kind),
+			"No object in the input references the pods. This can be a leftover, or the labels of the pod template do not match the selectors of the objects that should target it",
+		)
+		return
+	}
+}
@@ -0,0 +1,87 @@
+package apps
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/score/internal"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// minReadySeconds checks that workloads that are targeted by a Service wait for minReadySeconds
+// before their new pods are available. Without it, a rollout continues as soon as the readiness
+// probe of a new pod succeeds once, and a pod that crashes shortly after becoming ready is already
+// receiving traffic and replacing the old pods.
+func minReadySeconds(
+	services []ks.Service,
+	minReadySeconds int32,
+	template corev1.PodTemplateSpec,
+) (scorecard.TestScore, error) {
+	var score scorecard.TestScore
+
+	targeted := ""
+	for _, service := range services {
+		svc := service.Service()
+		if len(svc.Spec.Selector) > 0 && internal.LabelSelectorMatchesLabels(svc.Spec.Selector, template.Labels) {
+			targeted = svc.Name
+			break
+		}
+	}
+	if targeted == "" {
+		score.Grade = scorecard.GradeAllOK
+		score.Skipped = true
+		score.AddComment("", "Skipped because the pods are not targeted by a Service", "")
+		return score, nil
+	}
+
+	if minReadySeconds > 0 {
+		score.Grade = scorecard.GradeAllOK
+		return score, nil
+	}
+	score.Grade = scorecard.GradeWarning
+	score.AddComment(
+		"",
+		"minReadySeconds is not set",
+		fmt.Sprintf(
+			"The pods are targeted by the Service %s. Set minReadySeconds, so that a new pod has to stay ready for a while before the rollout continues, and pods that crash shortly after becoming ready do not replace all old pods",
+			targeted,
+		),
+	)
+	return score, nil
+}
+
+// readinessGates checks that the readinessGates of the pod template reference condition types that
+// are valid qualified names, and that no condition type is referenced twice. A pod with a malformed
+// readiness gate is rejected by the API server.
+func readinessGates(template corev1.PodTemplateSpec) (scorecard.TestScore, error) {
+	var score scorecard.TestScore
+	score.Grade = scorecard.GradeAllOK
+
+	seen := make(map[corev1.PodConditionType]bool)
+	for i, gate := range template.Spec.ReadinessGates {
+		conditionType := gate.ConditionType
+		if errs := validation.IsQualifiedName(string(conditionType)); len(errs) > 0 {
+			score.Grade = scorecard.GradeCritical
+			score.AddComment(
+				fmt.Sprintf("readinessGates[%d]", i),
+				fmt.Sprintf("The readiness gate %q is not a valid condition type", conditionType),
+				strings.Join(errs, ", "),
+			)
+			continue
+		}
+		if seen[conditionType] {
+			score.Grade = scorecard.GradeCritical
+			score.AddComment(
+				fmt.Sprintf("readinessGates[%d]", i),
+				fmt.Sprintf("The readiness gate %q is defined more than once", conditionType),
+				"Remove the duplicate readiness gate",
+			)
+		}
+		seen[conditionType] = true
+	}
+	return score, nil
+}
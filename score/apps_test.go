@@ -1,6 +1,7 @@
 package score
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/romnn/kube-score/config"
@@ -253,7 +254,8 @@ func TestStatefulsetTemplateNestedSkip(t *testing.T) {
 			)
 			switch s.Check.TargetType {
 			case "StatefulSet", "all":
-				assert.False(t, s.Skipped)
+				// Optional checks are skipped unless enabled
+				assert.Equal(t, s.Check.Optional, s.Skipped)
 			default:
 				assert.True(t, s.Skipped)
 			}
@@ -274,3 +276,123 @@ func TestStatefulsetTemplateSkip(t *testing.T) {
 	)
 	assert.True(t, skipped)
 }
+
+func TestWorkloadOrphan(t *testing.T) {
+	t.Parallel()
+
+	sc, err := testScore(
+		[]ks.NamedReader{testFile("workload-orphan.yaml")},
+		nil,
+		&config.RunConfiguration{
+			EnabledOptionalTests: map[string]struct{}{
+				"deployment-orphan":  {},
+				"statefulset-orphan": {},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]scorecard.Grade{
+		"with-service":    scorecard.GradeAllOK,
+		"orphan":          scorecard.GradeWarning,
+		"with-pdb":        scorecard.GradeAllOK,
+		"other-namespace": scorecard.GradeWarning,
+	}
+	scored := 0
+	for _, o := range sc {
+		for _, c := range o.Checks {
+			if c.Check.ID != "deployment-orphan" && c.Check.ID != "statefulset-orphan" {
+				continue
+			}
+			scored++
+			if c.Grade != expected[o.ObjectMeta.Name] {
+				t.Errorf("%s: expected %s, got %s", o.ObjectMeta.Name, expected[o.ObjectMeta.Name], c.Grade)
+			}
+		}
+	}
+	if scored != len(expected) {
+		t.Errorf("expected %d scored workloads, got %d", len(expected), scored)
+	}
+}
+
+func TestStatefulSetServicePublishesNotReadyAddresses(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore(
+		[]ks.NamedReader{testFile("statefulset-publish-not-ready.yaml")},
+		nil,
+		&config.RunConfiguration{
+			EnabledOptionalTests: map[string]struct{}{
+				"statefulset-service-publishes-not-ready-addresses": {},
+			},
+		},
+	)
+	assert.NoError(t, err)
+
+	type result struct {
+		grade   scorecard.Grade
+		skipped bool
+	}
+	results := make(map[string]result)
+	for _, o := range sc {
+		for _, c := range o.Checks {
+			if c.Check.ID == "statefulset-service-publishes-not-ready-addresses" {
+				results[o.ObjectMeta.Name] = result{c.Grade, c.Skipped}
+			}
+		}
+	}
+	assert.Equal(t, map[string]result{
+		"etcd":       {scorecard.GradeAllOK, false},
+		"zookeeper":  {scorecard.GradeWarning, false},
+		"no-service": {scorecard.GradeAllOK, true},
+	}, results)
+}
+
+func TestWorkloadReadiness(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore(
+		[]ks.NamedReader{testFile("workload-readiness.yaml")},
+		nil,
+		&config.RunConfiguration{
+			EnabledOptionalTests: map[string]struct{}{
+				"deployment-has-minreadyseconds":  {},
+				"statefulset-has-minreadyseconds": {},
+				"deployment-readiness-gates":      {},
+				"statefulset-readiness-gates":     {},
+			},
+		},
+	)
+	assert.NoError(t, err)
+
+	type result struct {
+		grade    scorecard.Grade
+		skipped  bool
+		comments []string
+	}
+	results := make(map[string]result)
+	for _, o := range sc {
+		for _, c := range o.Checks {
+			if !strings.HasSuffix(c.Check.ID, "-minreadyseconds") && !strings.HasSuffix(c.Check.ID, "-readiness-gates") {
+				continue
+			}
+			var comments []string
+			for _, comment := range c.Comments {
+				comments = append(comments, comment.Summary)
+			}
+			results[o.ObjectMeta.Name+"/"+c.Check.ID] = result{c.Grade, c.Skipped, comments}
+		}
+	}
+
+	assert.Equal(t, map[string]result{
+		"no-min-ready/deployment-has-minreadyseconds": {scorecard.GradeWarning, false, []string{"minReadySeconds is not set"}},
+		"no-min-ready/deployment-readiness-gates":     {scorecard.GradeAllOK, false, nil},
+		"min-ready/deployment-has-minreadyseconds":    {scorecard.GradeAllOK, false, nil},
+		"min-ready/deployment-readiness-gates": {scorecard.GradeCritical, false, []string{
+			`The readiness gate "load balancer ready" is not a valid condition type`,
+			`The readiness gate "example.com/ready" is defined more than once`,
+		}},
+		"not-targeted/statefulset-has-minreadyseconds": {scorecard.GradeAllOK, true, []string{"Skipped because the pods are not targeted by a Service"}},
+		"not-targeted/statefulset-readiness-gates":     {scorecard.GradeAllOK, false, nil},
+	}, results)
+}
@@ -0,0 +1,21 @@
+package checks
+
+// aliases are the old IDs of the renamed built-in checks, keyed by the current ID. The old IDs
+// keep working in --ignore-test, --enable-optional-test and the annotations, with a deprecation
+// note, until they are removed after a deprecation period, e.g.
+//
+//	"container-ports": {"container-ports-check"},
+var aliases = map[string][]string{
+	"service-port-name": {"service-port-protocol"},
+}
+
+// Aliases returns the current IDs of the renamed built-in checks, keyed by their old IDs
+func Aliases() map[string]string {
+	current := make(map[string]string)
+	for id, old := range aliases {
+		for _, alias := range old {
+			current[alias] = id
+		}
+	}
+	return current
+}
@@ -1,17 +1,28 @@
 package checks
 
 import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
 	"strings"
+	"sync"
 
+	"github.com/romnn/kube-score/config"
 	ks "github.com/romnn/kube-score/domain"
 	"github.com/romnn/kube-score/scorecard"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	storagev1 "k8s.io/api/storage/v1"
 )
 
 type Config struct {
 	IgnoredTests map[string]struct{}
+	// KubernetesVersion is the target version of Kubernetes. The checks that do not apply to it,
+	// see ks.Check.MinKubernetesVersion, are skipped.
+	KubernetesVersion config.Semver
 }
 
 func New(cnf *Config) *Checks {
@@ -24,24 +35,39 @@ func New(cnf *Config) *Checks {
 		all:                      make([]ks.Check, 0),
 		metas:                    make(map[string]GenCheck[ks.BothMeta]),
 		pods:                     make(map[string]GenCheck[ks.PodSpecer]),
+		podObjects:               make(map[string]GenCheck[corev1.Pod]),
 		services:                 make(map[string]GenCheck[corev1.Service]),
 		statefulsets:             make(map[string]GenCheck[appsv1.StatefulSet]),
 		deployments:              make(map[string]GenCheck[appsv1.Deployment]),
 		networkpolicies:          make(map[string]GenCheck[networkingv1.NetworkPolicy]),
 		ingresses:                make(map[string]GenCheck[ks.Ingress]),
+		jobs:                     make(map[string]GenCheck[ks.Job]),
 		cronjobs:                 make(map[string]GenCheck[ks.CronJob]),
 		horizontalPodAutoscalers: make(map[string]GenCheck[ks.HpaTargeter]),
 		poddisruptionbudgets:     make(map[string]GenCheck[ks.PodDisruptionBudget]),
+		priorityclasses:          make(map[string]GenCheck[schedulingv1.PriorityClass]),
+		storageclasses:           make(map[string]GenCheck[storagev1.StorageClass]),
+		webhooks:                 make(map[string]GenCheck[ks.WebhookConfiguration]),
+		crds:                     make(map[string]GenCheck[ks.CustomResourceDefinition]),
+		serviceMonitors:          make(map[string]GenCheck[ks.ServiceMonitor]),
+		podMonitors:              make(map[string]GenCheck[ks.PodMonitor]),
 	}
 }
 
 func NewCheck(name, targetType, comment string, optional bool) ks.Check {
+	id := machineFriendlyName(name)
 	return ks.Check{
-		Name:       name,
-		ID:         machineFriendlyName(name),
-		TargetType: targetType,
-		Comment:    comment,
-		Optional:   optional,
+		Name:                 name,
+		ID:                   id,
+		TargetType:           targetType,
+		Comment:              comment,
+		Optional:             optional,
+		Aliases:              slices.Clone(aliases[id]),
+		Namespace:            Namespace(id),
+		Category:             metadata[id].category,
+		Remediation:          metadata[id].remediation,
+		MinKubernetesVersion: kubernetesVersions[id].Min,
+		MaxKubernetesVersion: kubernetesVersions[id].Max,
 	}
 }
 
@@ -62,26 +88,67 @@ type CheckFunc[T any] func(T) (scorecard.TestScore, error)
 type GenCheck[T any] struct {
 	ks.Check
 	Fn CheckFunc[T]
+	// ContextFn is set instead of Fn for checks that are registered with the context of the run
+	ContextFn ContextCheckFunc[T]
 }
 
+// Checks are the registered checks by target type. Checks is safe for concurrent use, the
+// accessors return copies of the registered checks, that are not changed by later registrations.
 type Checks struct {
 	all                      []ks.Check
 	metas                    map[string]GenCheck[ks.BothMeta]
 	pods                     map[string]GenCheck[ks.PodSpecer]
+	podObjects               map[string]GenCheck[corev1.Pod]
 	services                 map[string]GenCheck[corev1.Service]
 	statefulsets             map[string]GenCheck[appsv1.StatefulSet]
 	deployments              map[string]GenCheck[appsv1.Deployment]
 	networkpolicies          map[string]GenCheck[networkingv1.NetworkPolicy]
 	ingresses                map[string]GenCheck[ks.Ingress]
+	jobs                     map[string]GenCheck[ks.Job]
 	cronjobs                 map[string]GenCheck[ks.CronJob]
 	horizontalPodAutoscalers map[string]GenCheck[ks.HpaTargeter]
 	poddisruptionbudgets     map[string]GenCheck[ks.PodDisruptionBudget]
+	priorityclasses          map[string]GenCheck[schedulingv1.PriorityClass]
+	storageclasses           map[string]GenCheck[storagev1.StorageClass]
+	webhooks                 map[string]GenCheck[ks.WebhookConfiguration]
+	crds                     map[string]GenCheck[ks.CustomResourceDefinition]
+	serviceMonitors          map[string]GenCheck[ks.ServiceMonitor]
+	podMonitors              map[string]GenCheck[ks.PodMonitor]
 	cnf                      *Config
-}
-
-func (c Checks) isEnabled(check ks.Check) bool {
-	_, ok := c.cnf.IgnoredTests[check.ID]
-	return !ok
+	// errs are the errors of the registrations of checks with invalid or duplicate IDs, see Err
+	errs []error
+	// builtin is set while the built-in checks are registered, see RegisterBuiltin
+	builtin bool
+	// versions is set while checks are registered with RegisterForKubernetesVersions
+	versions *VersionRange
+
+	// mu guards the registered checks, so that checks can be registered, removed and wrapped while
+	// other goroutines read them
+	mu sync.RWMutex
+}
+
+// RegisterBuiltin runs register, that registers the built-in checks. The IDs of the built-in checks
+// do not have a namespace, all other checks, that are registered with the library API or by
+// plugins, need an ID in the custom namespace, see ValidateID.
+func (c *Checks) RegisterBuiltin(register func()) {
+	c.mu.Lock()
+	c.builtin = true
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.builtin = false
+		c.mu.Unlock()
+	}()
+	register()
+}
+
+func (c *Checks) isEnabled(check ks.Check) bool {
+	for id := range c.cnf.IgnoredTests {
+		if check.HasID(id) {
+			return false
+		}
+	}
+	return true
 }
 
 func (c *Checks) RegisterMetaCheck(name, comment string, fn CheckFunc[ks.BothMeta]) {
@@ -95,8 +162,22 @@ func (c *Checks) RegisterOptionalMetaCheck(
 	reg(c, "all", name, comment, true, fn, c.metas)
 }
 
+func (c *Checks) RegisterMetaContextCheck(
+	name, comment string,
+	fn ContextCheckFunc[ks.BothMeta],
+) {
+	regContext(c, "all", name, comment, false, fn, c.metas)
+}
+
+func (c *Checks) RegisterOptionalMetaContextCheck(
+	name, comment string,
+	fn ContextCheckFunc[ks.BothMeta],
+) {
+	regContext(c, "all", name, comment, true, fn, c.metas)
+}
+
 func (c *Checks) Metas() map[string]GenCheck[ks.BothMeta] {
-	return c.metas
+	return snapshot(c, c.metas)
 }
 
 func reg[T any](
@@ -106,19 +187,58 @@ func reg[T any](
 	fn CheckFunc[T],
 	mp map[string]GenCheck[T],
 ) {
-	ch := NewCheck(name, targetType, comment, optional)
-	check := GenCheck[T]{Check: ch, Fn: fn}
+	add(c, GenCheck[T]{Check: NewCheck(name, targetType, comment, optional), Fn: fn}, mp)
+}
+
+func regContext[T any](
+	c *Checks,
+	targetType, name, comment string,
+	optional bool,
+	fn ContextCheckFunc[T],
+	mp map[string]GenCheck[T],
+) {
+	add(c, GenCheck[T]{Check: NewCheck(name, targetType, comment, optional), ContextFn: fn}, mp)
+}
+
+func add[T any](c *Checks, check GenCheck[T], mp map[string]GenCheck[T]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	namespaces := []string{NamespaceCustom}
+	if c.builtin {
+		namespaces = []string{""}
+	}
+	if err := ValidateID(check.ID, namespaces...); err != nil {
+		c.errs = append(c.errs, err)
+		return
+	}
+	if c.isRegistered(check.ID) {
+		c.errs = append(c.errs, fmt.Errorf("check ID %q is already registered", check.ID))
+		return
+	}
+	if c.versions != nil {
+		check.MinKubernetesVersion = c.versions.Min
+		check.MaxKubernetesVersion = c.versions.Max
+	}
 	c.all = append(c.all, check.Check)
 	if !c.isEnabled(check.Check) {
 		return
 	}
-	mp[machineFriendlyName(ch.Name)] = check
+	if versions := KubernetesVersions(check.Check); !versions.Contains(c.cnf.KubernetesVersion) {
+		check.Fn = outOfVersionRange[T](versions, c.cnf.KubernetesVersion)
+		check.ContextFn = nil
+	}
+	mp[check.ID] = check
 }
 
 func (c *Checks) RegisterPodCheck(name, comment string, fn CheckFunc[ks.PodSpecer]) {
 	reg(c, "Pod", name, comment, false, fn, c.pods)
 }
 
+// RegisterPodContextCheck registers a Pod check that gets the context of the run
+func (c *Checks) RegisterPodContextCheck(name, comment string, fn ContextCheckFunc[ks.PodSpecer]) {
+	regContext(c, "Pod", name, comment, false, fn, c.pods)
+}
+
 func (c *Checks) RegisterOptionalPodCheck(
 	name, comment string,
 	fn CheckFunc[ks.PodSpecer],
@@ -126,8 +246,25 @@ func (c *Checks) RegisterOptionalPodCheck(
 	reg(c, "Pod", name, comment, true, fn, c.pods)
 }
 
+func (c *Checks) RegisterOptionalPodContextCheck(
+	name, comment string,
+	fn ContextCheckFunc[ks.PodSpecer],
+) {
+	regContext(c, "Pod", name, comment, true, fn, c.pods)
+}
+
 func (c *Checks) Pods() map[string]GenCheck[ks.PodSpecer] {
-	return c.pods
+	return snapshot(c, c.pods)
+}
+
+// RegisterPodObjectCheck registers a check that only runs on Pod objects, and not on the
+// pod templates of Deployments, StatefulSets, Jobs and other objects
+func (c *Checks) RegisterPodObjectCheck(name, comment string, fn CheckFunc[corev1.Pod]) {
+	reg(c, "Pod", name, comment, false, fn, c.podObjects)
+}
+
+func (c *Checks) PodObjects() map[string]GenCheck[corev1.Pod] {
+	return snapshot(c, c.podObjects)
 }
 
 func (c *Checks) RegisterHorizontalPodAutoscalerCheck(
@@ -145,6 +282,15 @@ func (c *Checks) RegisterHorizontalPodAutoscalerCheck(
 	)
 }
 
+// RegisterHorizontalPodAutoscalerContextCheck registers a HorizontalPodAutoscaler check that gets
+// the context of the run
+func (c *Checks) RegisterHorizontalPodAutoscalerContextCheck(
+	name, comment string,
+	fn ContextCheckFunc[ks.HpaTargeter],
+) {
+	regContext(c, "HorizontalPodAutoscaler", name, comment, false, fn, c.horizontalPodAutoscalers)
+}
+
 func (c *Checks) RegisterOptionalHorizontalPodAutoscalerCheck(
 	name, comment string,
 	fn CheckFunc[ks.HpaTargeter],
@@ -161,7 +307,22 @@ func (c *Checks) RegisterOptionalHorizontalPodAutoscalerCheck(
 }
 
 func (c *Checks) HorizontalPodAutoscalers() map[string]GenCheck[ks.HpaTargeter] {
-	return c.horizontalPodAutoscalers
+	return snapshot(c, c.horizontalPodAutoscalers)
+}
+
+func (c *Checks) RegisterJobCheck(name, comment string, fn CheckFunc[ks.Job]) {
+	reg(c, "Job", name, comment, false, fn, c.jobs)
+}
+
+func (c *Checks) RegisterOptionalJobCheck(
+	name, comment string,
+	fn CheckFunc[ks.Job],
+) {
+	reg(c, "Job", name, comment, true, fn, c.jobs)
+}
+
+func (c *Checks) Jobs() map[string]GenCheck[ks.Job] {
+	return snapshot(c, c.jobs)
 }
 
 func (c *Checks) RegisterCronJobCheck(name, comment string, fn CheckFunc[ks.CronJob]) {
@@ -176,7 +337,7 @@ func (c *Checks) RegisterOptionalCronJobCheck(
 }
 
 func (c *Checks) CronJobs() map[string]GenCheck[ks.CronJob] {
-	return c.cronjobs
+	return snapshot(c, c.cronjobs)
 }
 
 func (c *Checks) RegisterStatefulSetCheck(
@@ -186,6 +347,23 @@ func (c *Checks) RegisterStatefulSetCheck(
 	reg(c, "StatefulSet", name, comment, false, fn, c.statefulsets)
 }
 
+// RegisterStatefulSetContextCheck registers a StatefulSet check that gets the context of the run
+func (c *Checks) RegisterStatefulSetContextCheck(
+	name, comment string,
+	fn ContextCheckFunc[appsv1.StatefulSet],
+) {
+	regContext(c, "StatefulSet", name, comment, false, fn, c.statefulsets)
+}
+
+// RegisterOptionalStatefulSetContextCheck registers an optional StatefulSet check that gets the
+// context of the run
+func (c *Checks) RegisterOptionalStatefulSetContextCheck(
+	name, comment string,
+	fn ContextCheckFunc[appsv1.StatefulSet],
+) {
+	regContext(c, "StatefulSet", name, comment, true, fn, c.statefulsets)
+}
+
 func (c *Checks) RegisterOptionalStatefulSetCheck(
 	name, comment string,
 	fn CheckFunc[appsv1.StatefulSet],
@@ -194,7 +372,7 @@ func (c *Checks) RegisterOptionalStatefulSetCheck(
 }
 
 func (c *Checks) StatefulSets() map[string]GenCheck[appsv1.StatefulSet] {
-	return c.statefulsets
+	return snapshot(c, c.statefulsets)
 }
 
 func (c *Checks) RegisterDeploymentCheck(
@@ -204,6 +382,23 @@ func (c *Checks) RegisterDeploymentCheck(
 	reg(c, "Deployment", name, comment, false, fn, c.deployments)
 }
 
+// RegisterDeploymentContextCheck registers a Deployment check that gets the context of the run
+func (c *Checks) RegisterDeploymentContextCheck(
+	name, comment string,
+	fn ContextCheckFunc[appsv1.Deployment],
+) {
+	regContext(c, "Deployment", name, comment, false, fn, c.deployments)
+}
+
+// RegisterOptionalDeploymentContextCheck registers an optional Deployment check that gets the
+// context of the run
+func (c *Checks) RegisterOptionalDeploymentContextCheck(
+	name, comment string,
+	fn ContextCheckFunc[appsv1.Deployment],
+) {
+	regContext(c, "Deployment", name, comment, true, fn, c.deployments)
+}
+
 func (c *Checks) RegisterOptionalDeploymentCheck(
 	name, comment string,
 	fn CheckFunc[appsv1.Deployment],
@@ -212,7 +407,7 @@ func (c *Checks) RegisterOptionalDeploymentCheck(
 }
 
 func (c *Checks) Deployments() map[string]GenCheck[appsv1.Deployment] {
-	return c.deployments
+	return snapshot(c, c.deployments)
 }
 
 func (c *Checks) RegisterIngressCheck(name, comment string, fn CheckFunc[ks.Ingress]) {
@@ -226,8 +421,25 @@ func (c *Checks) RegisterOptionalIngressCheck(
 	reg(c, "Ingress", name, comment, true, fn, c.ingresses)
 }
 
+// RegisterIngressContextCheck registers an Ingress check that gets the context of the run
+func (c *Checks) RegisterIngressContextCheck(
+	name, comment string,
+	fn ContextCheckFunc[ks.Ingress],
+) {
+	regContext(c, "Ingress", name, comment, false, fn, c.ingresses)
+}
+
+// RegisterOptionalIngressContextCheck registers an optional Ingress check that gets the context of
+// the run
+func (c *Checks) RegisterOptionalIngressContextCheck(
+	name, comment string,
+	fn ContextCheckFunc[ks.Ingress],
+) {
+	regContext(c, "Ingress", name, comment, true, fn, c.ingresses)
+}
+
 func (c *Checks) Ingresses() map[string]GenCheck[ks.Ingress] {
-	return c.ingresses
+	return snapshot(c, c.ingresses)
 }
 
 func (c *Checks) RegisterNetworkPolicyCheck(
@@ -237,6 +449,14 @@ func (c *Checks) RegisterNetworkPolicyCheck(
 	reg(c, "NetworkPolicy", name, comment, false, fn, c.networkpolicies)
 }
 
+// RegisterNetworkPolicyContextCheck registers a NetworkPolicy check that gets the context of the run
+func (c *Checks) RegisterNetworkPolicyContextCheck(
+	name, comment string,
+	fn ContextCheckFunc[networkingv1.NetworkPolicy],
+) {
+	regContext(c, "NetworkPolicy", name, comment, false, fn, c.networkpolicies)
+}
+
 func (c *Checks) RegisterOptionalNetworkPolicyCheck(
 	name, comment string,
 	fn CheckFunc[networkingv1.NetworkPolicy],
@@ -245,7 +465,7 @@ func (c *Checks) RegisterOptionalNetworkPolicyCheck(
 }
 
 func (c *Checks) NetworkPolicies() map[string]GenCheck[networkingv1.NetworkPolicy] {
-	return c.networkpolicies
+	return snapshot(c, c.networkpolicies)
 }
 
 func (c *Checks) RegisterPodDisruptionBudgetCheck(
@@ -256,7 +476,94 @@ func (c *Checks) RegisterPodDisruptionBudgetCheck(
 }
 
 func (c *Checks) PodDisruptionBudgets() map[string]GenCheck[ks.PodDisruptionBudget] {
-	return c.poddisruptionbudgets
+	return snapshot(c, c.poddisruptionbudgets)
+}
+
+func (c *Checks) RegisterPriorityClassCheck(
+	name, comment string,
+	fn CheckFunc[schedulingv1.PriorityClass],
+) {
+	reg(c, "PriorityClass", name, comment, false, fn, c.priorityclasses)
+}
+
+// RegisterPriorityClassContextCheck registers a PriorityClass check that gets the context of the run
+func (c *Checks) RegisterPriorityClassContextCheck(
+	name, comment string,
+	fn ContextCheckFunc[schedulingv1.PriorityClass],
+) {
+	regContext(c, "PriorityClass", name, comment, false, fn, c.priorityclasses)
+}
+
+func (c *Checks) PriorityClasses() map[string]GenCheck[schedulingv1.PriorityClass] {
+	return snapshot(c, c.priorityclasses)
+}
+
+func (c *Checks) RegisterStorageClassCheck(
+	name, comment string,
+	fn CheckFunc[storagev1.StorageClass],
+) {
+	reg(c, "StorageClass", name, comment, false, fn, c.storageclasses)
+}
+
+func (c *Checks) StorageClasses() map[string]GenCheck[storagev1.StorageClass] {
+	return snapshot(c, c.storageclasses)
+}
+
+// RegisterWebhookConfigurationCheck registers a check of MutatingWebhookConfigurations and
+// ValidatingWebhookConfigurations
+func (c *Checks) RegisterWebhookConfigurationCheck(
+	name, comment string,
+	fn CheckFunc[ks.WebhookConfiguration],
+) {
+	reg(c, "WebhookConfiguration", name, comment, false, fn, c.webhooks)
+}
+
+func (c *Checks) WebhookConfigurations() map[string]GenCheck[ks.WebhookConfiguration] {
+	return snapshot(c, c.webhooks)
+}
+
+func (c *Checks) RegisterCustomResourceDefinitionCheck(
+	name, comment string,
+	fn CheckFunc[ks.CustomResourceDefinition],
+) {
+	reg(c, "CustomResourceDefinition", name, comment, false, fn, c.crds)
+}
+
+// RegisterCustomResourceDefinitionContextCheck registers a CustomResourceDefinition check that gets
+// the context of the run
+func (c *Checks) RegisterCustomResourceDefinitionContextCheck(
+	name, comment string,
+	fn ContextCheckFunc[ks.CustomResourceDefinition],
+) {
+	regContext(c, "CustomResourceDefinition", name, comment, false, fn, c.crds)
+}
+
+func (c *Checks) CustomResourceDefinitions() map[string]GenCheck[ks.CustomResourceDefinition] {
+	return snapshot(c, c.crds)
+}
+
+// RegisterServiceMonitorContextCheck registers a ServiceMonitor check that gets the context of the run
+func (c *Checks) RegisterServiceMonitorContextCheck(
+	name, comment string,
+	fn ContextCheckFunc[ks.ServiceMonitor],
+) {
+	regContext(c, "ServiceMonitor", name, comment, false, fn, c.serviceMonitors)
+}
+
+func (c *Checks) ServiceMonitors() map[string]GenCheck[ks.ServiceMonitor] {
+	return snapshot(c, c.serviceMonitors)
+}
+
+// RegisterPodMonitorContextCheck registers a PodMonitor check that gets the context of the run
+func (c *Checks) RegisterPodMonitorContextCheck(
+	name, comment string,
+	fn ContextCheckFunc[ks.PodMonitor],
+) {
+	regContext(c, "PodMonitor", name, comment, false, fn, c.podMonitors)
+}
+
+func (c *Checks) PodMonitors() map[string]GenCheck[ks.PodMonitor] {
+	return snapshot(c, c.podMonitors)
 }
 
 func (c *Checks) RegisterServiceCheck(
@@ -266,6 +573,21 @@ func (c *Checks) RegisterServiceCheck(
 	reg(c, "Service", name, comment, false, fn, c.services)
 }
 
+// RegisterServiceContextCheck registers a Service check that gets the context of the run
+func (c *Checks) RegisterServiceContextCheck(
+	name, comment string,
+	fn ContextCheckFunc[corev1.Service],
+) {
+	regContext(c, "Service", name, comment, false, fn, c.services)
+}
+
+func (c *Checks) RegisterOptionalServiceContextCheck(
+	name, comment string,
+	fn ContextCheckFunc[corev1.Service],
+) {
+	regContext(c, "Service", name, comment, true, fn, c.services)
+}
+
 func (c *Checks) RegisterOptionalServiceCheck(
 	name, comment string,
 	fn CheckFunc[corev1.Service],
@@ -274,9 +596,179 @@ func (c *Checks) RegisterOptionalServiceCheck(
 }
 
 func (c *Checks) Services() map[string]GenCheck[corev1.Service] {
-	return c.services
+	return snapshot(c, c.services)
+}
+
+// Err returns the errors of the registrations of checks with IDs that are invalid, see ValidateID,
+// or that are already registered. These checks are not registered.
+func (c *Checks) Err() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return errors.Join(c.errs...)
 }
 
 func (c *Checks) All() []ks.Check {
-	return c.all
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return slices.Clone(c.all)
+}
+
+// snapshot returns a copy of the checks of a target type, that is not changed by later registrations
+func snapshot[T any](c *Checks, mp map[string]GenCheck[T]) map[string]GenCheck[T] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return maps.Clone(mp)
+}
+
+// registries returns the check maps of all target types
+func (c *Checks) registries() []any {
+	return []any{
+		c.metas,
+		c.pods,
+		c.podObjects,
+		c.services,
+		c.statefulsets,
+		c.deployments,
+		c.networkpolicies,
+		c.ingresses,
+		c.jobs,
+		c.cronjobs,
+		c.horizontalPodAutoscalers,
+		c.poddisruptionbudgets,
+		c.priorityclasses,
+		c.storageclasses,
+		c.webhooks,
+		c.crds,
+		c.serviceMonitors,
+		c.podMonitors,
+	}
+}
+
+// registryOf returns the check map for checks of type T
+func registryOf[T any](c *Checks) map[string]GenCheck[T] {
+	for _, r := range c.registries() {
+		if typed, ok := r.(map[string]GenCheck[T]); ok {
+			return typed
+		}
+	}
+	return nil
+}
+
+func (c *Checks) isRegistered(id string) bool {
+	return slices.ContainsFunc(c.all, func(check ks.Check) bool {
+		return check.ID == id
+	})
+}
+
+// hasFunc returns true if the check with the ID is registered and not ignored
+func (c *Checks) hasFunc(id string) bool {
+	for _, ok := range []bool{
+		hasKey(c.metas, id),
+		hasKey(c.pods, id),
+		hasKey(c.podObjects, id),
+		hasKey(c.services, id),
+		hasKey(c.statefulsets, id),
+		hasKey(c.deployments, id),
+		hasKey(c.networkpolicies, id),
+		hasKey(c.ingresses, id),
+		hasKey(c.jobs, id),
+		hasKey(c.cronjobs, id),
+		hasKey(c.horizontalPodAutoscalers, id),
+		hasKey(c.poddisruptionbudgets, id),
+		hasKey(c.priorityclasses, id),
+		hasKey(c.storageclasses, id),
+		hasKey(c.webhooks, id),
+		hasKey(c.crds, id),
+		hasKey(c.serviceMonitors, id),
+		hasKey(c.podMonitors, id),
+	} {
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func hasKey[T any](m map[string]GenCheck[T], id string) bool {
+	_, ok := m[id]
+	return ok
+}
+
+// Remove deregisters the check with the ID, so that it is neither run nor listed.
+// It returns false if no check with the ID is registered.
+func (c *Checks) Remove(id string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.isRegistered(id) {
+		return false
+	}
+	c.all = slices.DeleteFunc(c.all, func(check ks.Check) bool {
+		return check.ID == id
+	})
+	delete(c.metas, id)
+	delete(c.pods, id)
+	delete(c.podObjects, id)
+	delete(c.services, id)
+	delete(c.statefulsets, id)
+	delete(c.deployments, id)
+	delete(c.networkpolicies, id)
+	delete(c.ingresses, id)
+	delete(c.jobs, id)
+	delete(c.cronjobs, id)
+	delete(c.horizontalPodAutoscalers, id)
+	delete(c.poddisruptionbudgets, id)
+	delete(c.priorityclasses, id)
+	delete(c.storageclasses, id)
+	delete(c.webhooks, id)
+	delete(c.crds, id)
+	delete(c.serviceMonitors, id)
+	delete(c.podMonitors, id)
+	return true
+}
+
+// Override replaces the function of the registered check with the ID.
+// The type of fn has to match the target type of the check, e.g. CheckFunc[ks.PodSpecer] for Pod checks.
+// Checks that are ignored by the Config stay ignored.
+func Override[T any](c *Checks, id string, fn CheckFunc[T]) error {
+	return Wrap(c, id, func(CheckFunc[T]) CheckFunc[T] {
+		return fn
+	})
+}
+
+// Wrap replaces the function of the registered check with the ID with the result of middleware,
+// which is called with the current function of the check.
+// Checks that are ignored by the Config stay ignored.
+func Wrap[T any](c *Checks, id string, middleware func(CheckFunc[T]) CheckFunc[T]) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.isRegistered(id) {
+		return fmt.Errorf("no check with ID %q is registered", id)
+	}
+
+	registry := registryOf[T](c)
+	if registry == nil {
+		return fmt.Errorf("checks of type %T are not supported", *new(T))
+	}
+
+	check, ok := registry[id]
+	if !ok {
+		if c.hasFunc(id) {
+			return fmt.Errorf("check %q is not of type %T", id, *new(T))
+		}
+		// The check is ignored
+		return nil
+	}
+
+	if fn := check.ContextFn; fn != nil {
+		// The middleware wraps the check with the context of each run
+		check.ContextFn = func(ctx *Context, obj T) (scorecard.TestScore, error) {
+			return middleware(func(obj T) (scorecard.TestScore, error) {
+				return fn(ctx, obj)
+			})(obj)
+		}
+	} else {
+		check.Fn = middleware(check.Fn)
+	}
+	registry[id] = check
+	return nil
 }
@@ -0,0 +1,182 @@
+package checks
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func gradeFn(grade scorecard.Grade) CheckFunc[appsv1.Deployment] {
+	return func(appsv1.Deployment) (scorecard.TestScore, error) {
+		return scorecard.TestScore{Grade: grade}, nil
+	}
+}
+
+func TestRemove(t *testing.T) {
+	t.Parallel()
+
+	c := New(nil)
+	c.RegisterBuiltin(func() {
+		c.RegisterDeploymentCheck("Test A", "", gradeFn(scorecard.GradeCritical))
+		c.RegisterDeploymentCheck("Test B", "", gradeFn(scorecard.GradeCritical))
+	})
+
+	assert.True(t, c.Remove("test-a"))
+	assert.False(t, c.Remove("test-a"))
+
+	assert.Len(t, c.All(), 1)
+	assert.Equal(t, "test-b", c.All()[0].ID)
+	assert.NotContains(t, c.Deployments(), "test-a")
+	assert.Contains(t, c.Deployments(), "test-b")
+}
+
+func TestOverride(t *testing.T) {
+	t.Parallel()
+
+	c := New(nil)
+	c.RegisterBuiltin(func() {
+		c.RegisterDeploymentCheck("Test A", "", gradeFn(scorecard.GradeCritical))
+	})
+
+	assert.NoError(t, Override(c, "test-a", gradeFn(scorecard.GradeAllOK)))
+	score, err := c.Deployments()["test-a"].Fn(appsv1.Deployment{})
+	assert.NoError(t, err)
+	assert.Equal(t, scorecard.GradeAllOK, score.Grade)
+
+	assert.Error(t, Override(c, "test-b", gradeFn(scorecard.GradeAllOK)))
+	assert.Error(t, Override(c, "test-a", func(ks.PodSpecer) (scorecard.TestScore, error) {
+		return scorecard.TestScore{}, nil
+	}))
+}
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+
+	c := New(nil)
+	c.RegisterBuiltin(func() {
+		c.RegisterDeploymentCheck("Test A", "", gradeFn(scorecard.GradeCritical))
+	})
+
+	// Downgrade critical to warning
+	err := Wrap(c, "test-a", func(next CheckFunc[appsv1.Deployment]) CheckFunc[appsv1.Deployment] {
+		return func(d appsv1.Deployment) (scorecard.TestScore, error) {
+			score, err := next(d)
+			if score.Grade == scorecard.GradeCritical {
+				score.Grade = scorecard.GradeWarning
+			}
+			return score, err
+		}
+	})
+	assert.NoError(t, err)
+
+	score, err := c.Deployments()["test-a"].Fn(appsv1.Deployment{})
+	assert.NoError(t, err)
+	assert.Equal(t, scorecard.GradeWarning, score.Grade)
+}
+
+func TestOverrideIgnored(t *testing.T) {
+	t.Parallel()
+
+	c := New(&Config{IgnoredTests: map[string]struct{}{"test-a": {}}})
+	c.RegisterBuiltin(func() {
+		c.RegisterDeploymentCheck("Test A", "", gradeFn(scorecard.GradeCritical))
+	})
+
+	assert.NoError(t, Override(c, "test-a", gradeFn(scorecard.GradeAllOK)))
+	assert.NotContains(t, c.Deployments(), "test-a")
+}
+
+func TestContextCheck(t *testing.T) {
+	t.Parallel()
+
+	builds := 0
+	replicas := func(ctx *Context, d appsv1.Deployment) (scorecard.TestScore, error) {
+		// The index is only built once per context
+		names := Index(ctx, "names", func(ks.AllTypes) map[string]bool {
+			builds++
+			return map[string]bool{"foo": true}
+		})
+		if names[d.Name] {
+			return scorecard.TestScore{Grade: scorecard.GradeAllOK}, nil
+		}
+		return scorecard.TestScore{Grade: scorecard.GradeCritical}, nil
+	}
+
+	c := New(nil)
+	c.RegisterDeploymentContextCheck("custom/Test A", "", replicas)
+	check := c.Deployments()["custom/test-a"]
+
+	ctx := NewContext(nil)
+	for _, name := range []string{"foo", "bar"} {
+		_, err := check.Run(ctx, appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1, builds)
+
+	// Every run has its own indexes
+	_, err := check.Run(NewContext(nil), appsv1.Deployment{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, builds)
+
+	// Middleware wraps the check with the context of the run
+	err = Wrap(c, "custom/test-a", func(next CheckFunc[appsv1.Deployment]) CheckFunc[appsv1.Deployment] {
+		return func(d appsv1.Deployment) (scorecard.TestScore, error) {
+			score, err := next(d)
+			if score.Grade == scorecard.GradeCritical {
+				score.Grade = scorecard.GradeWarning
+			}
+			return score, err
+		}
+	})
+	assert.NoError(t, err)
+	score, err := c.Deployments()["custom/test-a"].Run(ctx, appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "bar"}})
+	assert.NoError(t, err)
+	assert.Equal(t, scorecard.GradeWarning, score.Grade)
+}
+
+func TestConcurrent(t *testing.T) {
+	t.Parallel()
+
+	c := New(nil)
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.RegisterDeploymentCheck(fmt.Sprintf("custom/Test %d", i), "", gradeFn(scorecard.GradeCritical))
+		}()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// The snapshots are not changed by concurrent registrations
+			for _, check := range c.Deployments() {
+				_, err := check.Run(NewContext(nil), appsv1.Deployment{})
+				assert.NoError(t, err)
+			}
+			_ = c.All()
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, c.All(), 50)
+
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, Override(c, fmt.Sprintf("custom/test-%d", i), gradeFn(scorecard.GradeAllOK)))
+		}()
+	}
+	wg.Wait()
+	for _, check := range c.Deployments() {
+		score, err := check.Fn(appsv1.Deployment{})
+		assert.NoError(t, err)
+		assert.Equal(t, scorecard.GradeAllOK, score.Grade)
+	}
+}
@@ -0,0 +1,83 @@
+package checks
+
+import (
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// Context is the input of one run of the checks. Checks that depend on other objects than the
+// scored object, such as the Services, NetworkPolicies, HPAs and PDBs that target it, get them
+// from the context instead of capturing them when they are registered, so that the same
+// registered checks can score different inputs.
+type Context struct {
+	objects ks.AllTypes
+
+	mu      sync.Mutex
+	indexes map[any]*index
+}
+
+type index struct {
+	once  sync.Once
+	value any
+}
+
+// NewContext returns the context of a run that scores the objects
+func NewContext(objects ks.AllTypes) *Context {
+	return &Context{
+		objects: objects,
+		indexes: make(map[any]*index),
+	}
+}
+
+// Objects returns all objects of the run, including the objects that are not scored
+func (c *Context) Objects() ks.AllTypes {
+	return c.objects
+}
+
+// Index returns the index with the key, which is built from the objects of the context the first
+// time that it is used and shared by all checks of the run. The key has to be comparable, and
+// identifies both the index and the options that it is built with.
+func Index[T any](c *Context, key any, build func(ks.AllTypes) T) T {
+	c.mu.Lock()
+	i, ok := c.indexes[key]
+	if !ok {
+		i = &index{}
+		c.indexes[key] = i
+	}
+	c.mu.Unlock()
+
+	i.once.Do(func() {
+		i.value = build(c.objects)
+	})
+	return i.value.(T)
+}
+
+// ByNamespace groups the objects by their namespace, objects without a namespace are in the
+// default namespace
+func ByNamespace[T any](
+	objects []T,
+	namespace ks.DefaultNamespace,
+	objectMeta func(T) metav1.ObjectMeta,
+) map[string][]T {
+	grouped := make(map[string][]T)
+	for _, o := range objects {
+		ns := namespace.Of(objectMeta(o).Namespace)
+		grouped[ns] = append(grouped[ns], o)
+	}
+	return grouped
+}
+
+// ContextCheckFunc is a check that gets the context of the run
+type ContextCheckFunc[T any] func(*Context, T) (scorecard.TestScore, error)
+
+// Run runs the check on the object
+func (g GenCheck[T]) Run(ctx *Context, obj T) (scorecard.TestScore, error) {
+	if g.ContextFn != nil {
+		return g.ContextFn(ctx, obj)
+	}
+	return g.Fn(obj)
+}
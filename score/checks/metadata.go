@@ -0,0 +1,489 @@
+package checks
+
+import (
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// Categories group the checks by the kind of problem that they detect
+const (
+	CategorySecurity     = "security"
+	CategoryReliability  = "reliability"
+	CategoryResources    = "resources"
+	CategoryNetworking   = "networking"
+	CategoryBestPractice = "best-practice"
+)
+
+// Categories are all categories of the checks
+var Categories = []string{
+	CategorySecurity,
+	CategoryReliability,
+	CategoryResources,
+	CategoryNetworking,
+	CategoryBestPractice,
+}
+
+// warningChecks are the built-in checks that are at most a warning when they fail, all other
+// checks are critical
+var warningChecks = map[string]struct{}{
+	"deployment-strategy":                               {},
+	"deployment-replicas":                               {},
+	"deployment-paused":                                 {},
+	"deployment-has-host-podantiaffinity":               {},
+	"statefulset-has-host-podantiaffinity":              {},
+	"deployment-orphan":                                 {},
+	"statefulset-orphan":                                {},
+	"ingress-pathtype":                                  {},
+	"cronjob-has-timezone":                              {},
+	"severity-annotation":                               {},
+	"networkpolicy-allows-service-mesh-ports":           {},
+	"container-seccomp-profile":                         {},
+	"container-secrets-in-environment":                  {},
+	"pod-fsgroup":                                       {},
+	"pod-share-process-namespace":                       {},
+	"pod-has-controller":                                {},
+	"pod-node-placement":                                {},
+	"container-liveness-probe-exec":                     {},
+	"service-type":                                      {},
+	"service-port-name":                                 {},
+	"service-session-affinity-timeout":                  {},
+	"service-traffic-distribution":                      {},
+	"stable-version":                                    {},
+	"replicaset-managed-by-deployment":                  {},
+	"service-has-endpoints":                             {},
+	"priorityclass-preemption-policy":                   {},
+	"storageclass-reclaim-policy":                       {},
+	"storageclass-volume-expansion":                     {},
+	"storageclass-volume-binding-mode":                  {},
+	"webhook-failure-policy":                            {},
+	"webhook-timeout":                                   {},
+	"customresourcedefinition-deprecation":              {},
+	"ingress-tls-certificate":                           {},
+	"statefulset-service-publishes-not-ready-addresses": {},
+	"horizontalpodautoscaler-replicas":                  {},
+	"deployment-has-minreadyseconds":                    {},
+	"statefulset-has-minreadyseconds":                   {},
+}
+
+// infoChecks are the built-in checks that are advisories, which are at most info when they fail
+var infoChecks = map[string]struct{}{
+	"pod-anti-affinity-and-topology-spread-duplication": {},
+	"pod-ephemeral-containers":                          {},
+}
+
+// DefaultGrade returns the worst grade that the check gives when it fails, before it is changed
+// by annotations. Checks that are not built-in are assumed to be critical.
+func DefaultGrade(id string) scorecard.Grade {
+	if _, ok := infoChecks[id]; ok {
+		return scorecard.GradeInfo
+	}
+	if _, ok := warningChecks[id]; ok {
+		return scorecard.GradeWarning
+	}
+	return scorecard.GradeCritical
+}
+
+type checkMetadata struct {
+	category    string
+	remediation string
+}
+
+// metadata is the documentation of the built-in checks, keyed by the check ID
+var metadata = map[string]checkMetadata{
+	// Security
+	"container-security-context-user-group-id": {
+		CategorySecurity,
+		"Set securityContext.runAsUser and securityContext.runAsGroup to values above 10000, either on the container or the pod.",
+	},
+	"container-security-context-privileged": {
+		CategorySecurity,
+		"Remove securityContext.privileged, or set it to false.",
+	},
+	"container-security-context-readonlyrootfilesystem": {
+		CategorySecurity,
+		"Set securityContext.readOnlyRootFilesystem to true, and mount an emptyDir volume where the container needs to write.",
+	},
+	"container-seccomp-profile": {
+		CategorySecurity,
+		"Set securityContext.seccompProfile.type to RuntimeDefault or Localhost on the pod.",
+	},
+	"container-image-pull-policy": {
+		CategorySecurity,
+		"Set imagePullPolicy to Always on all containers.",
+	},
+	"container-image-vulnerabilities": {
+		CategorySecurity,
+		"Update the image, or the packages in the image, to a version that fixes the reported vulnerabilities.",
+	},
+	"container-secrets-in-environment": {
+		CategorySecurity,
+		"Mount the Secret as a volume with a restrictive defaultMode, e.g. 0400, instead of using secretKeyRef or envFrom.secretRef.",
+	},
+	"pod-service-account-token": {
+		CategorySecurity,
+		"Mount service account tokens with a projected serviceAccountToken volume, with an audience, a short expirationSeconds and a defaultMode such as 0400.",
+	},
+	"pod-fsgroup": {
+		CategorySecurity,
+		"Set securityContext.fsGroup and securityContext.fsGroupChangePolicy: OnRootMismatch on pods that mount PersistentVolumeClaims.",
+	},
+	"pod-share-process-namespace": {
+		CategorySecurity,
+		"Remove spec.shareProcessNamespace, or set it to false.",
+	},
+	"pod-security-admission": {
+		CategorySecurity,
+		"Change the pod to satisfy the Pod Security Standard of its namespace, e.g. run as a non-root user without privilege escalation, drop all capabilities and use the RuntimeDefault seccomp profile for the restricted level.",
+	},
+	"pod-sysctls": {
+		CategorySecurity,
+		"Remove the unsafe sysctls from securityContext.sysctls, or allow them with the safeSysctls parameter if the node is dedicated to the workload.",
+	},
+	"pod-runtimeclass": {
+		CategorySecurity,
+		"Set runtimeClassName to one of the allowed runtime classes of the pod-runtimeclass check, e.g. gvisor for untrusted workloads.",
+	},
+	"pod-networkpolicy": {
+		CategorySecurity,
+		"Create a NetworkPolicy that selects the pod, with both ingress and egress rules.",
+	},
+	"networkpolicy-targets-pod": {
+		CategorySecurity,
+		"Change the podSelector of the NetworkPolicy to match the labels of at least one pod, or remove the policy.",
+	},
+
+	// Reliability
+	"deployment-strategy": {
+		CategoryReliability,
+		"Set spec.strategy.type to RollingUpdate.",
+	},
+	"deployment-replicas": {
+		CategoryReliability,
+		"Set spec.replicas to at least 2, or use a HorizontalPodAutoscaler.",
+	},
+	"deployment-has-poddisruptionbudget": {
+		CategoryReliability,
+		"Create a PodDisruptionBudget that matches the labels of the pods of the Deployment.",
+	},
+	"statefulset-has-poddisruptionbudget": {
+		CategoryReliability,
+		"Create a PodDisruptionBudget that matches the labels of the pods of the StatefulSet.",
+	},
+	"deployment-has-host-podantiaffinity": {
+		CategoryReliability,
+		"Add a podAntiAffinity with the kubernetes.io/hostname topology key that matches the labels of the pods.",
+	},
+	"statefulset-has-host-podantiaffinity": {
+		CategoryReliability,
+		"Add a podAntiAffinity with the kubernetes.io/hostname topology key that matches the labels of the pods.",
+	},
+	"deployment-paused": {
+		CategoryReliability,
+		"Remove spec.paused from the Deployment.",
+	},
+	"deployment-targeted-by-hpa-does-not-have-replicas-configured": {
+		CategoryReliability,
+		"Remove spec.replicas from the Deployment, and let the HorizontalPodAutoscaler manage the replica count.",
+	},
+	"horizontalpodautoscaler-target-scalable": {
+		CategoryReliability,
+		"Target a Deployment, StatefulSet or ReplicaSet, or add the custom resource with a scale subresource to the scalableKinds parameter.",
+	},
+	"horizontalpodautoscaler-replicas": {
+		CategoryReliability,
+		"Set spec.minReplicas to at least 2.",
+	},
+	"pod-probes": {
+		CategoryReliability,
+		"Add a readinessProbe to all containers of pods that are targeted by a Service, and make sure that it differs from the livenessProbe.",
+	},
+	"container-liveness-probe-exec": {
+		CategoryReliability,
+		"Use an httpGet or tcpSocket livenessProbe, or an exec command that only checks the container itself, without pipelines or requests to other hosts.",
+	},
+	"pod-ephemeral-containers": {
+		CategoryBestPractice,
+		"Remove spec.ephemeralContainers from the manifest, and add debug containers to running pods with kubectl debug.",
+	},
+	"pod-overhead": {
+		CategoryResources,
+		"Lower the requests and limits of the containers, or raise the hard limits of the ResourceQuota, so that the pods fit with the overhead of their RuntimeClass.",
+	},
+	"pod-dns": {
+		CategoryNetworking,
+		"Use the dnsPolicy ClusterFirst, or ClusterFirstWithHostNet with hostNetwork, and set a lower ndots option in dnsConfig.",
+	},
+	"pod-node-placement": {
+		CategoryReliability,
+		"Add a nodeSelector or required node affinity for the tainted nodes that the pod tolerates, and use the kubernetes.io/os, kubernetes.io/arch and topology.kubernetes.io labels.",
+	},
+	"pod-topology-spread-constraints": {
+		CategoryReliability,
+		"Set a valid topologyKey, labelSelector, maxSkew and whenUnsatisfiable on all topologySpreadConstraints.",
+	},
+	"pod-anti-affinity-and-topology-spread-duplication": {
+		CategoryReliability,
+		"Remove the hostname podAntiAffinity and keep the topologySpreadConstraint with the topologyKey kubernetes.io/hostname.",
+	},
+	"cronjob-has-deadline": {
+		CategoryReliability,
+		"Set spec.startingDeadlineSeconds.",
+	},
+	"cronjob-deadline-matches-schedule": {
+		CategoryReliability,
+		"Set spec.startingDeadlineSeconds to a value that is not shorter than the interval of the schedule.",
+	},
+	"cronjob-has-timezone": {
+		CategoryReliability,
+		"Set spec.timeZone to the timezone of the schedule, e.g. Etc/UTC.",
+	},
+	"pod-has-controller": {
+		CategoryReliability,
+		"Create the pod with a Deployment, StatefulSet or Job instead of a bare Pod.",
+	},
+	"pod-restartpolicy": {
+		CategoryReliability,
+		"Use restartPolicy Always for pods of Deployments, StatefulSets and DaemonSets, OnFailure or Never for pods of Jobs, and only set the restartPolicy Always on init containers.",
+	},
+	"job-parallelism": {
+		CategoryReliability,
+		"Set spec.completions when using the Indexed completionMode, and do not set spec.parallelism higher than spec.completions.",
+	},
+
+	// Resources
+	"container-resources": {
+		CategoryResources,
+		"Set resources.requests and resources.limits for cpu and memory on all containers.",
+	},
+	"container-resource-requests-equal-limits": {
+		CategoryResources,
+		"Set resources.requests to the same values as resources.limits.",
+	},
+	"container-cpu-requests-equal-limits": {
+		CategoryResources,
+		"Set resources.requests.cpu to the same value as resources.limits.cpu.",
+	},
+	"container-memory-requests-equal-limits": {
+		CategoryResources,
+		"Set resources.requests.memory to the same value as resources.limits.memory.",
+	},
+	"container-ephemeral-storage-request-and-limit": {
+		CategoryResources,
+		"Set resources.requests.ephemeral-storage and resources.limits.ephemeral-storage on all containers.",
+	},
+	"container-ephemeral-storage-request-equals-limit": {
+		CategoryResources,
+		"Set resources.requests.ephemeral-storage to the same value as resources.limits.ephemeral-storage.",
+	},
+
+	// Networking
+	"ingress-targets-service": {
+		CategoryNetworking,
+		"Change the backend of the Ingress to the name and port of an existing Service.",
+	},
+	"ingress-has-no-conflicts": {
+		CategoryNetworking,
+		"Route each host and path of an ingress class to a single backend, and use a single TLS secret per host.",
+	},
+	"ingress-pathtype": {
+		CategoryNetworking,
+		"Set pathType to Prefix or Exact on all paths, and use ImplementationSpecific for regular expressions.",
+	},
+	"service-targets-pod": {
+		CategoryNetworking,
+		"Change the selector of the Service to match the labels of at least one pod.",
+	},
+	"service-type": {
+		CategoryNetworking,
+		"Use a Service of type ClusterIP, and expose it with an Ingress or a LoadBalancer.",
+	},
+	"service-port-name": {
+		CategoryNetworking,
+		"Name the ports of the Service with their protocol prefix, e.g. http-web, or set appProtocol.",
+	},
+	"networkpolicy-allows-service-mesh-ports": {
+		CategoryNetworking,
+		"Allow ingress to the ports of the service mesh sidecar in the NetworkPolicy.",
+	},
+	"container-volume-mounts": {
+		CategoryBestPractice,
+		"Mount every volume at a separate path, and remove the volumes that no container mounts. Mount volumes inside the path of another mount only if the files that they hide are not needed.",
+	},
+	"container-ports-check": {
+		CategoryNetworking,
+		"Set containerPort on all ports, use unique port names of at most 15 characters, and declare every containerPort and protocol in only one container of the pod.",
+	},
+	"statefulset-has-servicename": {
+		CategoryNetworking,
+		"Set spec.serviceName to the name of a headless Service that matches the labels of the pods.",
+	},
+	"statefulset-service-publishes-not-ready-addresses": {
+		CategoryNetworking,
+		"Set spec.publishNotReadyAddresses to true on the headless Service of the StatefulSet.",
+	},
+	"deployment-has-minreadyseconds": {
+		CategoryReliability,
+		"Set spec.minReadySeconds to the time that a new pod has to stay ready before it is available, e.g. 10.",
+	},
+	"statefulset-has-minreadyseconds": {
+		CategoryReliability,
+		"Set spec.minReadySeconds to the time that a new pod has to stay ready before it is available, e.g. 10.",
+	},
+	"deployment-readiness-gates": {
+		CategoryReliability,
+		"Use qualified names such as example.com/load-balancer-ready as the conditionType of readinessGates, and reference every condition type once.",
+	},
+	"statefulset-readiness-gates": {
+		CategoryReliability,
+		"Use qualified names such as example.com/load-balancer-ready as the conditionType of readinessGates, and reference every condition type once.",
+	},
+	"service-has-endpoints": {
+		CategoryNetworking,
+		"Add a selector to the Service, or define Endpoints with the same name or an EndpointSlice with the label kubernetes.io/service-name.",
+	},
+	"service-dual-stack": {
+		CategoryNetworking,
+		"Set ipFamilyPolicy to PreferDualStack or RequireDualStack in dual-stack clusters, and list at most one IPv4 and one IPv6 family.",
+	},
+	"service-session-affinity-timeout": {
+		CategoryNetworking,
+		"Set spec.sessionAffinityConfig.clientIP.timeoutSeconds on Services with sessionAffinity ClientIP.",
+	},
+	"service-traffic-distribution": {
+		CategoryNetworking,
+		"Set spec.trafficDistribution to PreferClose, or enable topology aware hints with the service.kubernetes.io/topology-mode annotation on older clusters.",
+	},
+
+	// Best practice
+	"deployment-orphan": {
+		CategoryBestPractice,
+		"Remove the Deployment if it is unused, or fix the labels of the pod template to match the selectors of its Service, PodDisruptionBudget or NetworkPolicy.",
+	},
+	"statefulset-orphan": {
+		CategoryBestPractice,
+		"Remove the StatefulSet if it is unused, or fix the labels of the pod template to match the selectors of its Service, PodDisruptionBudget or NetworkPolicy.",
+	},
+	"container-image-tag": {
+		CategoryBestPractice,
+		"Use an explicit image tag or digest that is not latest. With the floating tag policy, pin a full version such as 1.2.3 or a digest.",
+	},
+	"container-image-pull-secrets": {
+		CategorySecurity,
+		"Set imagePullSecrets on the pod or its ServiceAccount for images from private registries, and add the pull Secrets of type kubernetes.io/dockerconfigjson, or add the registry to the publicRegistries parameter.",
+	},
+	"container-image-exists": {
+		CategoryBestPractice,
+		"Push the image to the registry, or fix the name or tag of the image.",
+	},
+	"environment-variable-key-duplication": {
+		CategoryBestPractice,
+		"Remove the duplicated environment variables.",
+	},
+	"stable-version": {
+		CategoryBestPractice,
+		"Change the apiVersion of the object to the stable API version.",
+	},
+	"replicaset-managed-by-deployment": {
+		CategoryBestPractice,
+		"Replace the ReplicaSet or ReplicationController with a Deployment with the same pod template.",
+	},
+	"label-values": {
+		CategoryBestPractice,
+		"Use label values of at most 63 alphanumeric characters, '-', '_' or '.', starting and ending with an alphanumeric character.",
+	},
+	"severity-annotation": {
+		CategoryBestPractice,
+		"Write every entry of the severity annotation as check-id=grade[@YYYY-MM-DD], with the grade warning, info or ok.",
+	},
+	"deployment-pod-selector-labels-match-template-metadata-labels": {
+		CategoryBestPractice,
+		"Change spec.selector to match spec.template.metadata.labels.",
+	},
+	"statefulset-pod-selector-labels-match-template-metadata-labels": {
+		CategoryBestPractice,
+		"Change spec.selector to match spec.template.metadata.labels.",
+	},
+	"horizontalpodautoscaler-has-target": {
+		CategoryBestPractice,
+		"Change spec.scaleTargetRef to the apiVersion, kind and name of an existing object in the same namespace.",
+	},
+	"poddisruptionbudget-has-policy": {
+		CategoryBestPractice,
+		"Set either spec.minAvailable or spec.maxUnavailable.",
+	},
+	"priorityclass-global-default": {
+		CategoryReliability,
+		"Set globalDefault to true on only one PriorityClass.",
+	},
+	"priorityclass-value": {
+		CategoryReliability,
+		"Set the value of the PriorityClass to at most 1000000000, or use system-cluster-critical or system-node-critical.",
+	},
+	"priorityclass-preemption-policy": {
+		CategoryReliability,
+		"Set preemptionPolicy on PriorityClasses of batch workloads, usually to Never.",
+	},
+	"storageclass-reclaim-policy": {
+		CategoryReliability,
+		"Set reclaimPolicy on the StorageClass, and use Retain for critical data.",
+	},
+	"storageclass-volume-expansion": {
+		CategoryReliability,
+		"Set allowVolumeExpansion to true if the provisioner supports it.",
+	},
+	"storageclass-volume-binding-mode": {
+		CategoryReliability,
+		"Set volumeBindingMode to WaitForFirstConsumer.",
+	},
+	"webhook-failure-policy": {
+		CategoryReliability,
+		"Set failurePolicy on all webhooks, to Fail for policies that must be enforced and to Ignore otherwise.",
+	},
+	"webhook-timeout": {
+		CategoryReliability,
+		"Set timeoutSeconds on all webhooks to at most the maxTimeoutSeconds parameter.",
+	},
+	"webhook-namespace-selector": {
+		CategoryReliability,
+		"Exclude kube-system with a namespaceSelector on the kubernetes.io/metadata.name label.",
+	},
+	"webhook-side-effects": {
+		CategoryReliability,
+		"Set sideEffects to None, or to NoneOnDryRun if the webhook has side effects.",
+	},
+	"customresourcedefinition-structural-schema": {
+		CategoryReliability,
+		"Set schema.openAPIV3Schema on all versions, with a type on every field.",
+	},
+	"customresourcedefinition-versions": {
+		CategoryReliability,
+		"Set storage to true on exactly one version, and serve the storage version.",
+	},
+	"customresourcedefinition-conversion": {
+		CategoryReliability,
+		"Set conversion.strategy to Webhook if the schemas of the versions differ, and deploy the Service of the conversion webhook.",
+	},
+	"ingress-tls-certificate": {
+		CategoryNetworking,
+		"Add a cert-manager Certificate for the TLS secret, or the cert-manager.io/issuer or cert-manager.io/cluster-issuer annotation to the Ingress.",
+	},
+	"servicemonitor-targets-service": {
+		CategoryBestPractice,
+		"Change the selector of the ServiceMonitor to match the labels of a Service, and the port of its endpoints to the name of a port of the Service.",
+	},
+	"podmonitor-targets-pod": {
+		CategoryBestPractice,
+		"Change the selector of the PodMonitor to match the labels of the pods, and the port of its endpoints to the name of a container port.",
+	},
+	"customresourcedefinition-deprecation": {
+		CategoryBestPractice,
+		"Set deprecationWarning on deprecated versions, with the version to migrate to.",
+	},
+	"job-restartpolicy": {
+		CategoryBestPractice,
+		"Set spec.template.spec.restartPolicy to OnFailure or Never.",
+	},
+	"cronjob-restartpolicy": {
+		CategoryBestPractice,
+		"Set spec.jobTemplate.spec.template.spec.restartPolicy to OnFailure or Never.",
+	},
+}
@@ -0,0 +1,80 @@
+package checks
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Namespaces of the IDs of the checks that are not built-in. The built-in checks have IDs without a
+// namespace, custom checks use a namespace so that they do not collide with the built-in checks
+// and the checks of other policy bundles.
+const (
+	// NamespaceCustom is the namespace of the checks that are registered with the library API or
+	// by plugins, with IDs like custom/<name>
+	NamespaceCustom = "custom"
+	// NamespaceCEL is the namespace of the checks that are implemented by CEL expressions, with IDs
+	// like cel/<name>
+	NamespaceCEL = "cel"
+	// NamespaceRego is the namespace of the checks that are implemented by Rego policies, with IDs
+	// like rego/<package>/<rule>
+	NamespaceRego = "rego"
+)
+
+// Namespaces are all namespaces of the IDs of custom checks
+var Namespaces = []string{
+	NamespaceCustom,
+	NamespaceCEL,
+	NamespaceRego,
+}
+
+// Namespace returns the namespace of the ID of a check, it is empty for the IDs without a namespace
+func Namespace(id string) string {
+	namespace, _, ok := strings.Cut(id, "/")
+	if !ok {
+		return ""
+	}
+	return namespace
+}
+
+// ValidateID returns an error if the ID does not follow the convention of its namespace, or if its
+// namespace is not one of the namespaces that the check can be registered in. The empty namespace
+// is reserved for the built-in checks, see Checks.RegisterBuiltin.
+func ValidateID(id string, namespaces ...string) error {
+	if id == "" {
+		return fmt.Errorf("check ID is empty")
+	}
+	namespace := Namespace(id)
+	if namespace != "" {
+		segments := strings.Split(id, "/")
+		if slices.Contains(segments, "") {
+			return fmt.Errorf("check ID %q has an empty segment", id)
+		}
+		switch namespace {
+		case NamespaceCustom, NamespaceCEL:
+			if len(segments) != 2 {
+				return fmt.Errorf("check ID %q must be on the format %s/<name>", id, namespace)
+			}
+		case NamespaceRego:
+			if len(segments) != 3 {
+				return fmt.Errorf("check ID %q must be on the format %s/<package>/<rule>", id, namespace)
+			}
+		default:
+			return fmt.Errorf("check ID %q has the unknown namespace %q, expected one of %s",
+				id, namespace, strings.Join(Namespaces, ", "))
+		}
+	}
+
+	switch {
+	case slices.Contains(namespaces, namespace):
+		return nil
+	case namespace == "":
+		return fmt.Errorf("check ID %q must have a namespace, e.g. %s/%s, IDs without a namespace are reserved for the built-in checks",
+			id, NamespaceCustom, id)
+	case slices.Contains(namespaces, ""):
+		return fmt.Errorf("check ID %q of a built-in check can not have a namespace", id)
+	default:
+		return fmt.Errorf("check ID %q can not be in the namespace %q, expected %s",
+			id, namespace, strings.Join(namespaces, ", "))
+	}
+}
@@ -0,0 +1,80 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func TestValidateID(t *testing.T) {
+	t.Parallel()
+
+	builtin := []string{""}
+	custom := []string{NamespaceCustom}
+	tests := []struct {
+		id         string
+		namespaces []string
+		namespace  string
+		err        string
+	}{
+		{"container-resources", builtin, "", ""},
+		{"custom/team-label", custom, "custom", ""},
+		{"cel/replicas", []string{NamespaceCEL}, "cel", ""},
+		{"rego/kubernetes.admission/deny", []string{NamespaceRego}, "rego", ""},
+		{"", custom, "", "check ID is empty"},
+		{"custom/", custom, "custom", `check ID "custom/" has an empty segment`},
+		{"custom/a/b", custom, "custom", `check ID "custom/a/b" must be on the format custom/<name>`},
+		{"rego/deny", []string{NamespaceRego}, "rego", `check ID "rego/deny" must be on the format rego/<package>/<rule>`},
+		{"acme/team-label", custom, "acme", `check ID "acme/team-label" has the unknown namespace "acme", expected one of custom, cel, rego`},
+		// The empty namespace is reserved for the built-in checks, and custom checks can not claim
+		// the namespaces of the policy engines
+		{"team-label", custom, "", `check ID "team-label" must have a namespace, e.g. custom/team-label, IDs without a namespace are reserved for the built-in checks`},
+		{"cel/replicas", custom, "cel", `check ID "cel/replicas" can not be in the namespace "cel", expected custom`},
+		{"rego/kubernetes.admission/deny", custom, "rego", `check ID "rego/kubernetes.admission/deny" can not be in the namespace "rego", expected custom`},
+		{"custom/team-label", builtin, "custom", `check ID "custom/team-label" of a built-in check can not have a namespace`},
+	}
+	for _, tc := range tests {
+		assert.Equal(t, tc.namespace, Namespace(tc.id), tc.id)
+		err := ValidateID(tc.id, tc.namespaces...)
+		if tc.err == "" {
+			assert.NoError(t, err, tc.id)
+		} else {
+			assert.EqualError(t, err, tc.err, tc.id)
+		}
+	}
+}
+
+func TestRegisterNamespacedIDs(t *testing.T) {
+	t.Parallel()
+
+	c := New(nil)
+	c.RegisterBuiltin(func() {
+		c.RegisterDeploymentCheck("Test A", "", gradeFn(scorecard.GradeCritical))
+	})
+	c.RegisterDeploymentCheck("custom/Test A", "", gradeFn(scorecard.GradeWarning))
+	assert.NoError(t, c.Err())
+	assert.Equal(t, "custom", c.Deployments()["custom/test-a"].Namespace)
+	assert.Empty(t, c.Deployments()["test-a"].Namespace)
+
+	// Collisions and invalid IDs are rejected, and the first registration is kept
+	c.RegisterDeploymentCheck("custom/Test A", "", gradeFn(scorecard.GradeAllOK))
+	c.RegisterBuiltin(func() {
+		c.RegisterServiceCheck("Test A", "", nil)
+	})
+	c.RegisterDeploymentCheck("acme/Test B", "", gradeFn(scorecard.GradeAllOK))
+	c.RegisterDeploymentCheck("Test C", "", gradeFn(scorecard.GradeAllOK))
+	c.RegisterDeploymentCheck("cel/Test D", "", gradeFn(scorecard.GradeAllOK))
+	assert.EqualError(t, c.Err(), `check ID "custom/test-a" is already registered
+check ID "test-a" is already registered
+check ID "acme/test-b" has the unknown namespace "acme", expected one of custom, cel, rego
+check ID "test-c" must have a namespace, e.g. custom/test-c, IDs without a namespace are reserved for the built-in checks
+check ID "cel/test-d" can not be in the namespace "cel", expected custom`)
+	assert.Len(t, c.All(), 2)
+	assert.Empty(t, c.Services())
+	score, err := c.Deployments()["custom/test-a"].Fn(appsv1.Deployment{})
+	assert.NoError(t, err)
+	assert.Equal(t, scorecard.GradeWarning, score.Grade)
+}
@@ -0,0 +1,91 @@
+package checks
+
+import (
+	"fmt"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// VersionRange is the range of Kubernetes versions that a check applies to. Both bounds are
+// inclusive, and a zero bound is unbounded.
+type VersionRange struct {
+	Min config.Semver
+	Max config.Semver
+}
+
+// Contains returns true if the check applies to the version
+func (r VersionRange) Contains(version config.Semver) bool {
+	if r.Min != (config.Semver{}) && version.LessThan(r.Min) {
+		return false
+	}
+	if r.Max != (config.Semver{}) && r.Max.LessThan(version) {
+		return false
+	}
+	return true
+}
+
+func (r VersionRange) String() string {
+	switch {
+	case r.Min == (config.Semver{}) && r.Max == (config.Semver{}):
+		return "all versions"
+	case r.Max == (config.Semver{}):
+		return r.Min.String() + " and newer"
+	case r.Min == (config.Semver{}):
+		return r.Max.String() + " and older"
+	default:
+		return r.Min.String() + " to " + r.Max.String()
+	}
+}
+
+// kubernetesVersions are the versions of Kubernetes that the built-in checks apply to, keyed by
+// the check ID, NewCheck sets them on the checks. Checks that are not listed apply to all
+// versions. stable-version is not listed:
+// it applies to all versions, and only recommends the replacements of a deprecated apiVersion
+// that are available in the version, which is a range per apiVersion and not per check.
+var kubernetesVersions = map[string]VersionRange{
+	// pathType was introduced in Kubernetes v1.18
+	"ingress-pathtype": {Min: config.Semver{Major: 1, Minor: 18}},
+	// timeZone is stable since Kubernetes v1.27
+	"cronjob-has-timezone": {Min: config.Semver{Major: 1, Minor: 27}},
+	// Topology aware hints are beta since Kubernetes v1.23
+	"service-traffic-distribution": {Min: config.Semver{Major: 1, Minor: 23}},
+	// The Pod Security Admission is beta since Kubernetes v1.23
+	"pod-security-admission": {Min: config.Semver{Major: 1, Minor: 23}},
+}
+
+// KubernetesVersions returns the versions of Kubernetes that the check applies to
+func KubernetesVersions(check ks.Check) VersionRange {
+	return VersionRange{Min: check.MinKubernetesVersion, Max: check.MaxKubernetesVersion}
+}
+
+// RegisterForKubernetesVersions runs register, and the checks that it registers only apply to the
+// versions of Kubernetes. They are skipped when the target version is outside of the versions.
+func (c *Checks) RegisterForKubernetesVersions(versions VersionRange, register func()) {
+	c.mu.Lock()
+	previous := c.versions
+	c.versions = &versions
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.versions = previous
+		c.mu.Unlock()
+	}()
+	register()
+}
+
+// outOfVersionRange is the function of the checks that do not apply to the version of Kubernetes
+// of the run, they are skipped instead of being run
+func outOfVersionRange[T any](versions VersionRange, version config.Semver) CheckFunc[T] {
+	return func(T) (score scorecard.TestScore, err error) {
+		score.Grade = scorecard.GradeAllOK
+		score.Skipped = true
+		score.AddComment(
+			"",
+			fmt.Sprintf("Skipped because the check applies to Kubernetes %s, and the target version is %s", versions, version),
+			"",
+		)
+		return
+	}
+}
@@ -0,0 +1,91 @@
+package checks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+
+	"github.com/romnn/kube-score/config"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func TestVersionRange(t *testing.T) {
+	t.Parallel()
+
+	v := func(minor int) config.Semver { return config.Semver{Major: 1, Minor: minor} }
+
+	tests := []struct {
+		versions VersionRange
+		str      string
+		contains map[int]bool
+	}{
+		{VersionRange{}, "all versions", map[int]bool{10: true, 30: true}},
+		{VersionRange{Min: v(23)}, "v1.23 and newer", map[int]bool{22: false, 23: true, 30: true}},
+		{VersionRange{Max: v(24)}, "v1.24 and older", map[int]bool{18: true, 24: true, 25: false}},
+		{VersionRange{Min: v(20), Max: v(24)}, "v1.20 to v1.24", map[int]bool{19: false, 20: true, 24: true, 25: false}},
+	}
+
+	for _, tc := range tests {
+		assert.Equal(t, tc.str, tc.versions.String())
+		for minor, contains := range tc.contains {
+			assert.Equal(t, contains, tc.versions.Contains(v(minor)), "%s contains v1.%d", tc.str, minor)
+		}
+	}
+}
+
+func TestOutOfVersionRange(t *testing.T) {
+	t.Parallel()
+
+	register := func(version config.Semver) *Checks {
+		c := New(&Config{KubernetesVersion: version})
+		c.RegisterBuiltin(func() {
+			c.RegisterDeploymentCheck("CronJob has timezone", "", gradeFn(scorecard.GradeCritical))
+			c.RegisterDeploymentCheck("Test A", "", gradeFn(scorecard.GradeCritical))
+		})
+		return c
+	}
+
+	// The checks that apply to the version are run
+	c := register(config.Semver{Major: 1, Minor: 27})
+	for _, id := range []string{"cronjob-has-timezone", "test-a"} {
+		score, err := c.Deployments()[id].Run(NewContext(nil), appsv1.Deployment{})
+		assert.NoError(t, err)
+		assert.False(t, score.Skipped, id)
+		assert.Equal(t, scorecard.GradeCritical, score.Grade, id)
+	}
+
+	// The checks that do not apply to the version are skipped
+	c = register(config.Semver{Major: 1, Minor: 26})
+	score, err := c.Deployments()["cronjob-has-timezone"].Run(NewContext(nil), appsv1.Deployment{})
+	assert.NoError(t, err)
+	assert.True(t, score.Skipped)
+	assert.Equal(t, scorecard.GradeAllOK, score.Grade)
+	assert.Equal(t,
+		"Skipped because the check applies to Kubernetes v1.27 and newer, and the target version is v1.26",
+		score.Comments[0].Summary,
+	)
+	score, err = c.Deployments()["test-a"].Run(NewContext(nil), appsv1.Deployment{})
+	assert.NoError(t, err)
+	assert.False(t, score.Skipped)
+}
+
+func TestRegisterForKubernetesVersions(t *testing.T) {
+	t.Parallel()
+
+	c := New(&Config{KubernetesVersion: config.Semver{Major: 1, Minor: 29}})
+	c.RegisterForKubernetesVersions(VersionRange{Min: config.Semver{Major: 1, Minor: 30}}, func() {
+		c.RegisterDeploymentCheck("custom/New", "", gradeFn(scorecard.GradeCritical))
+	})
+	c.RegisterDeploymentCheck("custom/All", "", gradeFn(scorecard.GradeCritical))
+	assert.NoError(t, c.Err())
+
+	assert.Equal(t, "v1.30 and newer", KubernetesVersions(c.Deployments()["custom/new"].Check).String())
+	assert.Equal(t, VersionRange{}, KubernetesVersions(c.Deployments()["custom/all"].Check))
+	score, err := c.Deployments()["custom/new"].Run(NewContext(nil), appsv1.Deployment{})
+	assert.NoError(t, err)
+	assert.True(t, score.Skipped)
+	score, err = c.Deployments()["custom/all"].Run(NewContext(nil), appsv1.Deployment{})
+	assert.NoError(t, err)
+	assert.False(t, score.Skipped)
+}
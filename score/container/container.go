@@ -2,18 +2,31 @@ package container
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
 
 	ks "github.com/romnn/kube-score/domain"
 	"github.com/romnn/kube-score/score/checks"
 	"github.com/romnn/kube-score/scorecard"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type Options struct {
+	Namespace                             ks.DefaultNamespace
 	SkipInitContainers                    bool
 	IgnoreContainerCpuLimitRequirement    bool
 	IgnoreContainerMemoryLimitRequirement bool
+	MaxPortNameLength                     int
+	// ImageTagPolicy is latest to only flag the latest tag, or floating to also flag the
+	// FloatingImageTags and versions that are not a full version, e.g. 1 or 1.2
+	ImageTagPolicy    string
+	FloatingImageTags []string
+	// NestedMounts is allow to only flag volumeMounts at the same path, or warn to also flag
+	// volumeMounts inside the path of another mount
+	NestedMounts string
 }
 
 func Register(allChecks *checks.Checks, options Options) {
@@ -39,7 +52,7 @@ func Register(allChecks *checks.Checks, options Options) {
 	)
 	allChecks.RegisterPodCheck(
 		"Container Image Tag",
-		`Makes sure that a explicit non-latest tag is used`,
+		`Makes sure that a explicit non-latest tag is used. With the tagPolicy parameter set to floating, tags that are not a full version or digest, such as 1 or stable, are flagged too`,
 		containerImageTag(options),
 	)
 	allChecks.RegisterPodCheck(
@@ -63,12 +76,42 @@ func Register(allChecks *checks.Checks, options Options) {
 		containerPortsCheck(options),
 	)
 	allChecks.RegisterPodCheck(
+		"Container Volume Mounts",
+		"Makes sure that no two volumeMounts of a container use the same path, and that all volumes of the pod are mounted. With the nestedMounts parameter, volumeMounts inside other mounts are also flagged",
+		containerVolumeMounts(options),
+	)
+	allChecks.RegisterPodContextCheck(
 		"Environment Variable Key Duplication",
 		"Makes sure that duplicated environment variable keys are not duplicated",
-		environmentVariableKeyDuplication(options),
+		func(ctx *checks.Context, ps ks.PodSpecer) (scorecard.TestScore, error) {
+			configMaps, secrets := envFromSourcesIn(ctx, options, ps.GetObjectMeta().Namespace)
+			return environmentVariableKeyDuplication(configMaps, secrets, options)(ps)
+		},
 	)
 }
 
+// configMapsKey and secretsKey are the keys of the indexes of the ConfigMaps and Secrets by namespace
+type (
+	configMapsKey struct{ namespace ks.DefaultNamespace }
+	secretsKey    struct{ namespace ks.DefaultNamespace }
+)
+
+// envFromSourcesIn returns the ConfigMaps and Secrets of the run in the namespace
+func envFromSourcesIn(ctx *checks.Context, options Options, namespace string) ([]ks.ConfigMap, []ks.Secret) {
+	configMaps := checks.Index(ctx, configMapsKey{options.Namespace}, func(all ks.AllTypes) map[string][]ks.ConfigMap {
+		return checks.ByNamespace(all.ConfigMaps(), options.Namespace, func(c ks.ConfigMap) metav1.ObjectMeta {
+			return c.ConfigMap().ObjectMeta
+		})
+	})
+	secrets := checks.Index(ctx, secretsKey{options.Namespace}, func(all ks.AllTypes) map[string][]ks.Secret {
+		return checks.ByNamespace(all.Secrets(), options.Namespace, func(s ks.Secret) metav1.ObjectMeta {
+			return s.Secret().ObjectMeta
+		})
+	})
+	namespace = options.Namespace.Of(namespace)
+	return configMaps[namespace], secrets[namespace]
+}
+
 // containerResources makes sure that the container has resource requests and limits set
 // The check for a CPU limit requirement can be enabled via the requireCPULimit flag parameter
 func containerResources(
@@ -260,22 +303,37 @@ func containerImageTag(
 		allContainers = append(allContainers, pod.Containers...)
 
 		hasTagLatest := false
+		hasFloatingTag := false
 
 		for _, container := range allContainers {
 			tag := containerTag(container.Image)
-			if tag == "" || tag == "latest" {
+			switch {
+			case tag == "" || tag == "latest":
 				score.AddComment(
 					container.Name,
 					"Image with latest tag",
 					"Using a fixed tag is recommended to avoid accidental upgrades",
 				)
 				hasTagLatest = true
+			case options.ImageTagPolicy == "floating" && isFloatingTag(container.Image, tag, options.FloatingImageTags):
+				score.AddComment(
+					container.Name,
+					"Image with floating tag",
+					fmt.Sprintf(
+						"The tag %s can be moved to a new image. Pin a full version, e.g. 1.2.3, or a digest to avoid accidental upgrades",
+						tag,
+					),
+				)
+				hasFloatingTag = true
 			}
 		}
 
-		if hasTagLatest {
+		switch {
+		case hasTagLatest:
 			score.Grade = scorecard.GradeCritical
-		} else {
+		case hasFloatingTag:
+			score.Grade = scorecard.GradeWarning
+		default:
 			score.Grade = scorecard.GradeAllOK
 		}
 
@@ -329,6 +387,19 @@ func containerImagePullPolicy(
 
 // containerTag returns the image tag
 // An empty string is returned if the image has no tag
+// partialVersionTag matches versions with only a major or major and minor version, e.g. 1, v1.2
+// or 1.2-alpine. Longer numbers, such as dates, are not versions.
+var partialVersionTag = regexp.MustCompile(`^v?\d{1,3}(\.\d+)?([-_].*)?$`)
+
+// isFloatingTag returns true if the tag of the image is a release channel or a partial version.
+// Images pinned by digest are never floating.
+func isFloatingTag(image, tag string, floatingTags []string) bool {
+	if strings.Contains(image, "@") {
+		return false
+	}
+	return slices.Contains(floatingTags, tag) || partialVersionTag.MatchString(tag)
+}
+
 func containerTag(image string) string {
 	imageParts := strings.Split(image, ":")
 	if len(imageParts) > 1 {
@@ -435,8 +506,6 @@ func containerPortsCheck(
 	options Options,
 ) func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
 	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
-		const maxPortNameLength = 15
-
 		var allContainers []corev1.Container
 		if !options.SkipInitContainers {
 			allContainers = append(
@@ -460,7 +529,7 @@ func containerPortsCheck(
 						score.Grade = scorecard.GradeCritical
 					}
 				}
-				if len(port.Name) > maxPortNameLength {
+				if len(port.Name) > options.MaxPortNameLength {
 					score.AddComment(
 						container.Name,
 						"Container Port Check",
@@ -479,12 +548,62 @@ func containerPortsCheck(
 			}
 		}
 
+		// The containers of a pod share the network namespace, so the containers that run at the
+		// same time can not listen on the same port
+		var running []corev1.Container
+		if !options.SkipInitContainers {
+			for _, c := range ps.GetPodTemplateSpec().Spec.InitContainers {
+				if c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+					running = append(running, c)
+				}
+			}
+		}
+		running = append(running, ps.GetPodTemplateSpec().Spec.Containers...)
+
+		type portKey struct {
+			port     int32
+			protocol corev1.Protocol
+		}
+		declaredBy := make(map[portKey]string)
+		for _, container := range running {
+			for _, port := range container.Ports {
+				if port.ContainerPort == 0 {
+					continue
+				}
+				key := portKey{port.ContainerPort, port.Protocol}
+				if key.protocol == "" {
+					key.protocol = corev1.ProtocolTCP
+				}
+				other, ok := declaredBy[key]
+				if !ok {
+					declaredBy[key] = container.Name
+					continue
+				}
+				if other == container.Name {
+					continue
+				}
+				score.AddComment(
+					container.Name,
+					"Container Port Check",
+					fmt.Sprintf(
+						"Container port %d/%s is also declared by the container %s. The containers of a pod share the network, only one of them can listen on the port",
+						key.port, key.protocol, other,
+					),
+				)
+				score.Grade = scorecard.GradeCritical
+			}
+		}
+
 		return
 	}
 }
 
 // environmentVariableKeyDuplication checks that no duplicated environment variable keys.
+// The keys of envFrom sources are resolved if the ConfigMap or Secret is part of the input,
+// as they are silently shadowed by later sources and by the env of the container.
 func environmentVariableKeyDuplication(
+	configMaps []ks.ConfigMap,
+	secrets []ks.Secret,
 	options Options,
 ) func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
 	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
@@ -500,6 +619,13 @@ func environmentVariableKeyDuplication(
 
 		score.Grade = scorecard.GradeAllOK
 
+		shadowed := func(container corev1.Container, msg string) {
+			score.AddComment(container.Name, "Environment Variable Key Duplication", msg)
+			if score.Grade > scorecard.GradeWarning {
+				score.Grade = scorecard.GradeWarning
+			}
+		}
+
 		for _, container := range allContainers {
 			envs := make(map[string]struct{})
 			for _, env := range container.Env {
@@ -518,7 +644,81 @@ func environmentVariableKeyDuplication(
 				}
 				envs[env.Name] = struct{}{}
 			}
+
+			// The source of each key of envFrom, later sources take precedence over earlier ones
+			envFromSources := make(map[string]string)
+			for _, envFrom := range container.EnvFrom {
+				source, keys := envFromKeys(envFrom, configMaps, secrets)
+				for _, key := range keys {
+					key = envFrom.Prefix + key
+					if previous, ok := envFromSources[key]; ok {
+						shadowed(container, fmt.Sprintf(
+							"Container environment variable key '%s' of %s overrides the key of %s",
+							key, source, previous,
+						))
+					}
+					envFromSources[key] = source
+				}
+			}
+
+			for _, env := range container.Env {
+				if source, ok := envFromSources[env.Name]; ok {
+					shadowed(container, fmt.Sprintf(
+						"Container environment variable key '%s' overrides the key of %s",
+						env.Name, source,
+					))
+					// Duplicated env keys are only reported once
+					delete(envFromSources, env.Name)
+				}
+			}
 		}
 		return
 	}
 }
+
+// envFromKeys returns a description of the source, and its keys. No keys are returned if the
+// ConfigMap or Secret is not part of the input. The ConfigMaps and Secrets are the ones in the
+// namespace of the pod.
+func envFromKeys(
+	envFrom corev1.EnvFromSource,
+	configMaps []ks.ConfigMap,
+	secrets []ks.Secret,
+) (string, []string) {
+	var keys []string
+	switch {
+	case envFrom.ConfigMapRef != nil:
+		for _, c := range configMaps {
+			cm := c.ConfigMap()
+			if cm.Name != envFrom.ConfigMapRef.Name {
+				continue
+			}
+			for key := range cm.Data {
+				keys = append(keys, key)
+			}
+			for key := range cm.BinaryData {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		return fmt.Sprintf("ConfigMap '%s'", envFrom.ConfigMapRef.Name), keys
+	case envFrom.SecretRef != nil:
+		for _, s := range secrets {
+			secret := s.Secret()
+			if secret.Name != envFrom.SecretRef.Name {
+				continue
+			}
+			for key := range secret.Data {
+				keys = append(keys, key)
+			}
+			for key := range secret.StringData {
+				if _, ok := secret.Data[key]; !ok {
+					keys = append(keys, key)
+				}
+			}
+		}
+		sort.Strings(keys)
+		return fmt.Sprintf("Secret '%s'", envFrom.SecretRef.Name), keys
+	default:
+		return "", nil
+	}
+}
@@ -0,0 +1,93 @@
+package container
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// containerVolumeMounts checks that the volumeMounts of a container do not collide: two mounts at
+// the same path are rejected by the API server. A mount inside the path of another mount hides the
+// files of the other volume at that path, which is common and usually intended, e.g. a ConfigMap in
+// a directory of a data volume, and is only flagged with the nestedMounts policy warn. Volumes of
+// the pod that no container mounts are reported as dead configuration.
+func containerVolumeMounts(options Options) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		spec := ps.GetPodTemplateSpec().Spec
+		score.Grade = scorecard.GradeAllOK
+
+		var allContainers []corev1.Container
+		if !options.SkipInitContainers {
+			allContainers = append(allContainers, spec.InitContainers...)
+		}
+		allContainers = append(allContainers, spec.Containers...)
+
+		for _, container := range allContainers {
+			mounts := container.VolumeMounts
+			for i, mount := range mounts {
+				mountPath := path.Clean(mount.MountPath)
+				for _, other := range mounts[:i] {
+					otherPath := path.Clean(other.MountPath)
+					switch {
+					case mountPath == otherPath:
+						score.Grade = scorecard.GradeCritical
+						score.AddComment(
+							container.Name,
+							fmt.Sprintf("The volumes %s and %s are mounted at the same path %s", other.Name, mount.Name, mountPath),
+							"The mountPath of the volumeMounts of a container must be unique",
+						)
+					case options.NestedMounts == "warn" && (isWithin(mountPath, otherPath) || isWithin(otherPath, mountPath)):
+						outer, inner := other, mount
+						if isWithin(otherPath, mountPath) {
+							outer, inner = mount, other
+						}
+						score.Grade = min(score.Grade, scorecard.GradeWarning)
+						score.AddComment(
+							container.Name,
+							fmt.Sprintf("The volume %s is mounted inside the mount of the volume %s", inner.Name, outer.Name),
+							fmt.Sprintf(
+								"The mount at %s hides the files of the volume %s at that path. Mount the volumes at separate paths, or use subPath to mount a single file",
+								path.Clean(inner.MountPath), outer.Name,
+							),
+						)
+					}
+				}
+			}
+		}
+
+		mounted := make(map[string]bool)
+		for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+			for _, container := range containers {
+				for _, mount := range container.VolumeMounts {
+					mounted[mount.Name] = true
+				}
+				for _, device := range container.VolumeDevices {
+					mounted[device.Name] = true
+				}
+			}
+		}
+		for _, volume := range spec.Volumes {
+			if mounted[volume.Name] {
+				continue
+			}
+			score.Grade = min(score.Grade, scorecard.GradeWarning)
+			score.AddComment(
+				"",
+				fmt.Sprintf("The volume %s is not mounted by any container", volume.Name),
+				"Remove the unused volume, or mount it in the containers that need it",
+			)
+		}
+
+		return score, nil
+	}
+}
+
+// isWithin returns true if the path p is inside the directory dir
+func isWithin(p, dir string) bool {
+	return strings.HasPrefix(p, strings.TrimSuffix(dir, "/")+"/")
+}
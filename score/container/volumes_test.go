@@ -0,0 +1,80 @@
+package container
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func TestContainerVolumeMounts(t *testing.T) {
+	t.Parallel()
+
+	score := func(spec corev1.PodSpec, options Options) scorecard.TestScore {
+		s, err := containerVolumeMounts(options)(&podSpeccer{spec: corev1.PodTemplateSpec{Spec: spec}})
+		assert.NoError(t, err)
+		return s
+	}
+	summaries := func(s scorecard.TestScore) []string {
+		var res []string
+		for _, c := range s.Comments {
+			res = append(res, c.Summary)
+		}
+		return res
+	}
+	volumes := []corev1.Volume{{Name: "config"}, {Name: "data"}, {Name: "cache"}}
+
+	// Separate paths, and a volume that is only mounted by an init container
+	s := score(corev1.PodSpec{
+		Volumes:        volumes,
+		InitContainers: []corev1.Container{{Name: "init", VolumeMounts: []corev1.VolumeMount{{Name: "cache", MountPath: "/cache"}}}},
+		Containers: []corev1.Container{{Name: "app", VolumeMounts: []corev1.VolumeMount{
+			{Name: "config", MountPath: "/etc/app"},
+			{Name: "data", MountPath: "/etc/application"},
+		}}},
+	}, Options{})
+	assert.Equal(t, scorecard.GradeAllOK, s.Grade)
+	assert.Empty(t, s.Comments)
+
+	// The same path, a nested mount and an unmounted volume
+	spec := corev1.PodSpec{
+		Volumes: volumes,
+		Containers: []corev1.Container{{Name: "app", VolumeMounts: []corev1.VolumeMount{
+			{Name: "config", MountPath: "/etc/app/conf.d"},
+			{Name: "data", MountPath: "/etc/app/"},
+			{Name: "config", MountPath: "/etc/app"},
+		}}},
+	}
+	s = score(spec, Options{NestedMounts: "allow"})
+	assert.Equal(t, scorecard.GradeCritical, s.Grade)
+	assert.Equal(t, []string{
+		"The volumes data and config are mounted at the same path /etc/app",
+		"The volume cache is not mounted by any container",
+	}, summaries(s))
+
+	// Nested mounts are only flagged with the warn policy
+	s = score(spec, Options{NestedMounts: "warn"})
+	assert.Equal(t, scorecard.GradeCritical, s.Grade)
+	assert.Equal(t, []string{
+		"The volume config is mounted inside the mount of the volume data",
+		"The volume config is mounted inside the mount of the volume config",
+		"The volumes data and config are mounted at the same path /etc/app",
+		"The volume cache is not mounted by any container",
+	}, summaries(s))
+
+	// A nested mount alone is fine by default
+	s = score(corev1.PodSpec{
+		Volumes: volumes[:2],
+		Containers: []corev1.Container{{Name: "app", VolumeMounts: []corev1.VolumeMount{
+			{Name: "data", MountPath: "/var/lib/app"},
+			{Name: "config", MountPath: "/var/lib/app/config"},
+		}}},
+	}, Options{})
+	assert.Equal(t, scorecard.GradeAllOK, s.Grade)
+
+	// Only unmounted volumes are a warning
+	s = score(corev1.PodSpec{Volumes: volumes[:1], Containers: []corev1.Container{{Name: "app"}}}, Options{})
+	assert.Equal(t, scorecard.GradeWarning, s.Grade)
+}
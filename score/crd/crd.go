@@ -0,0 +1,227 @@
+package crd
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/score/checks"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+type Options struct {
+	Namespace ks.DefaultNamespace
+}
+
+func Register(allChecks *checks.Checks, options Options) {
+	allChecks.RegisterCustomResourceDefinitionCheck(
+		"CustomResourceDefinition structural schema",
+		`Makes sure that all versions of the CustomResourceDefinition have a structural schema, in which every field has a type`,
+		crdStructuralSchema,
+	)
+	allChecks.RegisterCustomResourceDefinitionCheck(
+		"CustomResourceDefinition versions",
+		`Makes sure that exactly one version of the CustomResourceDefinition is the storage version, and that it is served`,
+		crdVersions,
+	)
+	allChecks.RegisterCustomResourceDefinitionContextCheck(
+		"CustomResourceDefinition conversion",
+		`Makes sure that versions with different schemas are converted by a webhook, and that the Service of the conversion webhook is in the input`,
+		func(ctx *checks.Context, crd ks.CustomResourceDefinition) (scorecard.TestScore, error) {
+			services := checks.Index(ctx, servicesKey{options.Namespace}, func(all ks.AllTypes) map[serviceRef]bool {
+				return servicesByName(all.Services(), options)
+			})
+			return crdConversion(services)(crd)
+		},
+	)
+	allChecks.RegisterCustomResourceDefinitionCheck(
+		"CustomResourceDefinition deprecation",
+		`Makes sure that deprecated versions of the CustomResourceDefinition set a deprecationWarning`,
+		crdDeprecation,
+	)
+}
+
+func crdStructuralSchema(crd ks.CustomResourceDefinition) (score scorecard.TestScore, err error) {
+	score.Grade = scorecard.GradeAllOK
+	for _, version := range crd.Spec().Versions {
+		if version.Schema == nil || version.Schema.OpenAPIV3Schema == nil {
+			score.Grade = scorecard.GradeCritical
+			score.AddComment(
+				version.Name,
+				"The version has no schema",
+				"Set schema.openAPIV3Schema, apiextensions.k8s.io/v1 requires a structural schema for all versions.",
+			)
+			continue
+		}
+		untyped := untypedFields(*version.Schema.OpenAPIV3Schema, version.Name)
+		slices.Sort(untyped)
+		for _, path := range untyped {
+			score.Grade = scorecard.GradeCritical
+			score.AddComment(
+				path,
+				"The field has no type",
+				"Every field of a structural schema has a type, unless it sets x-kubernetes-int-or-string or x-kubernetes-preserve-unknown-fields.",
+			)
+		}
+	}
+	return
+}
+
+// untypedFields returns the paths of the fields of the schema that do not have a type
+func untypedFields(schema ks.JSONSchemaProps, path string) []string {
+	var untyped []string
+	preserveUnknownFields := schema.XPreserveUnknownFields != nil && *schema.XPreserveUnknownFields
+	if schema.Type == "" && !schema.XIntOrString && !preserveUnknownFields {
+		untyped = append(untyped, path)
+	}
+	for name, property := range schema.Properties {
+		untyped = append(untyped, untypedFields(property, path+"."+name)...)
+	}
+	if schema.Items != nil {
+		untyped = append(untyped, untypedFields(*schema.Items, path+"[]")...)
+	}
+	return untyped
+}
+
+func crdVersions(crd ks.CustomResourceDefinition) (score scorecard.TestScore, err error) {
+	score.Grade = scorecard.GradeAllOK
+	var storage []string
+	served := false
+	for _, version := range crd.Spec().Versions {
+		if version.Served {
+			served = true
+		}
+		if !version.Storage {
+			continue
+		}
+		storage = append(storage, version.Name)
+		if !version.Served {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment(
+				version.Name,
+				"The storage version is not served",
+				"Objects are stored in a version that clients can not read or write. Serve the storage version, or migrate the storage to a served version.",
+			)
+		}
+	}
+
+	if !served {
+		score.Grade = scorecard.GradeCritical
+		score.AddComment("", "No version is served", "Set served to true on at least one version.")
+	}
+	if len(storage) != 1 {
+		score.Grade = scorecard.GradeCritical
+		score.AddComment(
+			"",
+			fmt.Sprintf("The CustomResourceDefinition has %d storage versions", len(storage)),
+			"Set storage to true on exactly one version.",
+		)
+	}
+	return
+}
+
+// servicesKey is the key of the index of the Services by namespace and name
+type servicesKey struct{ namespace ks.DefaultNamespace }
+
+// serviceRef is the namespace and name of a Service
+type serviceRef struct {
+	namespace string
+	name      string
+}
+
+func servicesByName(services []ks.Service, options Options) map[serviceRef]bool {
+	byName := make(map[serviceRef]bool)
+	for _, s := range services {
+		service := s.Service()
+		byName[serviceRef{options.Namespace.Of(service.Namespace), service.Name}] = true
+	}
+	return byName
+}
+
+func crdConversion(
+	services map[serviceRef]bool,
+) func(ks.CustomResourceDefinition) (scorecard.TestScore, error) {
+	return func(crd ks.CustomResourceDefinition) (score scorecard.TestScore, err error) {
+		score.Grade = scorecard.GradeAllOK
+		spec := crd.Spec()
+		conversion := spec.Conversion
+
+		if conversion == nil || conversion.Strategy == "" || conversion.Strategy == "None" {
+			if different := differentSchemas(spec.Versions); len(different) > 0 {
+				score.Grade = scorecard.GradeWarning
+				score.AddComment(
+					"",
+					"Versions with different schemas are not converted",
+					"Only the apiVersion of objects is changed between "+strings.Join(different, ", ")+
+						" without a conversion webhook, fields that are renamed or moved are lost. Set conversion.strategy to Webhook.",
+				)
+			}
+			return
+		}
+
+		if conversion.Strategy != "Webhook" {
+			return
+		}
+		if conversion.Webhook == nil || conversion.Webhook.ClientConfig == nil {
+			score.Grade = scorecard.GradeCritical
+			score.AddComment(
+				"conversion.webhook",
+				"The conversion webhook is not configured",
+				"Set conversion.webhook.clientConfig to the Service or URL of the conversion webhook.",
+			)
+			return
+		}
+		service := conversion.Webhook.ClientConfig.Service
+		if service == nil || services[serviceRef{service.Namespace, service.Name}] {
+			return
+		}
+		score.Grade = scorecard.GradeWarning
+		score.AddComment(
+			"conversion.webhook",
+			fmt.Sprintf("The Service %s/%s of the conversion webhook is not in the input", service.Namespace, service.Name),
+			"Objects of the CustomResourceDefinition can not be read or written in other versions than the storage version while the conversion webhook is unavailable. "+
+				"Deploy the conversion webhook together with the CustomResourceDefinition.",
+		)
+		return
+	}
+}
+
+// differentSchemas returns the names of the served versions if their schemas are not the same
+func differentSchemas(versions []ks.CustomResourceDefinitionVersion) []string {
+	var names []string
+	var first *ks.CustomResourceValidation
+	different := false
+	for _, version := range versions {
+		if !version.Served && !version.Storage {
+			continue
+		}
+		names = append(names, version.Name)
+		if len(names) == 1 {
+			first = version.Schema
+		} else if !reflect.DeepEqual(first, version.Schema) {
+			different = true
+		}
+	}
+	if !different {
+		return nil
+	}
+	return names
+}
+
+func crdDeprecation(crd ks.CustomResourceDefinition) (score scorecard.TestScore, err error) {
+	score.Grade = scorecard.GradeAllOK
+	for _, version := range crd.Spec().Versions {
+		if !version.Deprecated || version.DeprecationWarning != nil {
+			continue
+		}
+		score.Grade = scorecard.GradeWarning
+		score.AddComment(
+			version.Name,
+			"The deprecated version does not set a deprecationWarning",
+			"Clients get a generic warning for the version. Set deprecationWarning to tell them which version to migrate to.",
+		)
+	}
+	return
+}
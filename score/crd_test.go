@@ -0,0 +1,62 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func TestCustomResourceDefinition(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore([]ks.NamedReader{testFile("crd.yaml")}, nil, nil)
+	assert.NoError(t, err)
+
+	grades := make(map[string]map[string]scorecard.Grade)
+	paths := make(map[string][]string)
+	for _, o := range sc {
+		if o.TypeMeta.Kind != "CustomResourceDefinition" {
+			continue
+		}
+		grades[o.ObjectMeta.Name] = make(map[string]scorecard.Grade)
+		for _, c := range o.Checks {
+			if c.Check.TargetType != "CustomResourceDefinition" {
+				continue
+			}
+			grades[o.ObjectMeta.Name][c.Check.ID] = c.Grade
+			for _, comment := range c.Comments {
+				paths[o.ObjectMeta.Name+"/"+c.Check.ID] = append(paths[o.ObjectMeta.Name+"/"+c.Check.ID], comment.Path)
+			}
+		}
+	}
+
+	ok := scorecard.GradeAllOK
+	assert.Equal(t, map[string]map[string]scorecard.Grade{
+		"widgets.example.com": {
+			"customresourcedefinition-structural-schema": ok,
+			"customresourcedefinition-versions":          ok,
+			"customresourcedefinition-conversion":        ok,
+			"customresourcedefinition-deprecation":       ok,
+		},
+		"gadgets.example.com": {
+			"customresourcedefinition-structural-schema": scorecard.GradeCritical,
+			"customresourcedefinition-versions":          scorecard.GradeCritical,
+			"customresourcedefinition-conversion":        scorecard.GradeWarning,
+			"customresourcedefinition-deprecation":       scorecard.GradeWarning,
+		},
+		"gizmos.example.com": {
+			"customresourcedefinition-structural-schema": ok,
+			"customresourcedefinition-versions":          ok,
+			"customresourcedefinition-conversion":        scorecard.GradeWarning,
+			"customresourcedefinition-deprecation":       ok,
+		},
+	}, grades)
+	assert.Equal(
+		t,
+		[]string{"v2.spec", "v2.spec.items[]", "v1"},
+		paths["gadgets.example.com/customresourcedefinition-structural-schema"],
+	)
+	assert.Equal(t, []string{"v2", ""}, paths["gadgets.example.com/customresourcedefinition-versions"])
+}
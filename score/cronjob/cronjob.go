@@ -1,6 +1,9 @@
 package cronjob
 
 import (
+	"fmt"
+	"time"
+
 	ks "github.com/romnn/kube-score/domain"
 	"github.com/romnn/kube-score/score/checks"
 	"github.com/romnn/kube-score/scorecard"
@@ -17,6 +20,16 @@ func Register(allChecks *checks.Checks) {
 		`Makes sure CronJobs have a valid RestartPolicy`,
 		cronJobHasRestartPolicy,
 	)
+	allChecks.RegisterCronJobCheck(
+		"CronJob has timezone",
+		`Makes sure that CronJobs have a configured timeZone, so that the schedule does not depend on the timezone of the kube-controller-manager`,
+		cronJobHasTimeZone,
+	)
+	allChecks.RegisterCronJobCheck(
+		"CronJob deadline matches schedule",
+		`Makes sure that the startingDeadlineSeconds of CronJobs is not shorter than the interval of the schedule`,
+		cronJobDeadlineMatchesSchedule,
+	)
 }
 
 func cronJobHasDeadline(job ks.CronJob) (score scorecard.TestScore, err error) {
@@ -55,3 +68,54 @@ func cronJobHasRestartPolicy(job ks.CronJob) (score scorecard.TestScore, err err
 
 	return
 }
+
+func cronJobHasTimeZone(job ks.CronJob) (score scorecard.TestScore, err error) {
+	if tz := job.TimeZone(); tz == nil || *tz == "" {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment(
+			"",
+			"The CronJob should have timeZone configured",
+			"Without a timeZone, the schedule is interpreted in the local timezone of the kube-controller-manager",
+		)
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+func cronJobDeadlineMatchesSchedule(job ks.CronJob) (score scorecard.TestScore, err error) {
+	deadline := job.StartingDeadlineSeconds()
+	if deadline == nil {
+		score.Grade = scorecard.GradeAllOK
+		score.Skipped = true
+		score.AddComment("", "Skipped because the CronJob has no startingDeadlineSeconds configured", "")
+		return
+	}
+
+	sched, parseErr := parseSchedule(job.Schedule())
+	if parseErr != nil {
+		score.Grade = scorecard.GradeCritical
+		score.AddComment("", "The CronJob has an invalid schedule", parseErr.Error())
+		return
+	}
+
+	interval := sched.minInterval()
+	deadlineDuration := time.Duration(*deadline) * time.Second
+	if interval > 0 && deadlineDuration < interval {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment(
+			"",
+			"The startingDeadlineSeconds is shorter than the schedule interval",
+			fmt.Sprintf(
+				"The CronJob runs every %s at most, but is cancelled if it can not be started within %s. "+
+					"A short disruption of the kube-controller-manager can cause a full run of the CronJob to be skipped.",
+				interval, deadlineDuration,
+			),
+		)
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	return
+}
@@ -0,0 +1,179 @@
+package cronjob
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// schedule is a parsed standard five field cron expression
+type schedule struct {
+	minutes     map[int]bool
+	hours       map[int]bool
+	daysOfMonth map[int]bool
+	months      map[int]bool
+	daysOfWeek  map[int]bool
+
+	// If both day of month and day of week are restricted, a day matches if either matches
+	domRestricted bool
+	dowRestricted bool
+}
+
+var scheduleMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+func parseSchedule(expr string) (*schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := scheduleMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields, got %d", len(fields))
+	}
+
+	var s schedule
+	var err error
+	if s.minutes, err = parseField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("invalid minute: %w", err)
+	}
+	if s.hours, err = parseField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("invalid hour: %w", err)
+	}
+	if s.daysOfMonth, err = parseField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("invalid day of month: %w", err)
+	}
+	if s.months, err = parseField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, fmt.Errorf("invalid month: %w", err)
+	}
+	if s.daysOfWeek, err = parseField(fields[4], 0, 7, dayNames); err != nil {
+		return nil, fmt.Errorf("invalid day of week: %w", err)
+	}
+	// Both 0 and 7 are sunday
+	if s.daysOfWeek[7] {
+		s.daysOfWeek[0] = true
+	}
+	s.domRestricted = !isWildcard(fields[2])
+	s.dowRestricted = !isWildcard(fields[4])
+
+	return &s, nil
+}
+
+func isWildcard(field string) bool {
+	return field == "*" || field == "?"
+}
+
+func parseField(field string, low, high int, names map[string]int) (map[int]bool, error) {
+	values := make(map[int]bool)
+	for part := range strings.SplitSeq(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step <= 0 {
+				return nil, fmt.Errorf("invalid step %q", stepPart)
+			}
+		}
+
+		start, end := low, high
+		if !isWildcard(rangePart) {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = parseValue(from, names); err != nil {
+				return nil, err
+			}
+			end = start
+			if isRange {
+				if end, err = parseValue(to, names); err != nil {
+					return nil, err
+				}
+			} else if hasStep {
+				end = high
+			}
+		}
+		if start < low || end > high || start > end {
+			return nil, fmt.Errorf("%q is out of range %d-%d", part, low, high)
+		}
+
+		for v := start; v <= end; v += step {
+			values[v] = true
+		}
+	}
+	return values, nil
+}
+
+func parseValue(value string, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(value)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", value)
+	}
+	return v, nil
+}
+
+func (s *schedule) matchesDay(t time.Time) bool {
+	if !s.months[int(t.Month())] {
+		return false
+	}
+	dom := s.daysOfMonth[t.Day()]
+	dow := s.daysOfWeek[int(t.Weekday())]
+	if s.domRestricted && s.dowRestricted {
+		return dom || dow
+	}
+	return dom && dow
+}
+
+// minInterval returns the shortest time between two consecutive runs of the schedule.
+// Four years are simulated to cover leap years. Zero is returned if the schedule never runs
+// more than once in that period.
+func (s *schedule) minInterval() time.Duration {
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(4, 0, 0)
+
+	var previous time.Time
+	var shortest time.Duration
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		if !s.matchesDay(day) {
+			continue
+		}
+		for hour := range 24 {
+			if !s.hours[hour] {
+				continue
+			}
+			for minute := range 60 {
+				if !s.minutes[minute] {
+					continue
+				}
+				run := day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+				if !previous.IsZero() {
+					if interval := run.Sub(previous); shortest == 0 || interval < shortest {
+						shortest = interval
+					}
+				}
+				previous = run
+			}
+		}
+	}
+	return shortest
+}
@@ -0,0 +1,54 @@
+package cronjob
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleMinInterval(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		schedule string
+		expected time.Duration
+	}{
+		{"* * * * *", time.Minute},
+		{"*/15 * * * *", 15 * time.Minute},
+		{"0,10,45 * * * *", 10 * time.Minute},
+		{"5-10/5 * * * *", 5 * time.Minute},
+		{"@hourly", time.Hour},
+		{"@daily", 24 * time.Hour},
+		{"0 9 * * mon-fri", 24 * time.Hour},
+		{"0 0 * * SUN", 7 * 24 * time.Hour},
+		{"0 0 * * 7", 7 * 24 * time.Hour},
+		{"0 0 1 * *", 28 * 24 * time.Hour},
+		{"0 0 1 * 1", 24 * time.Hour},
+		{"@yearly", 365 * 24 * time.Hour},
+		{"0 0 30 2 ?", 0},
+	}
+
+	for _, tc := range cases {
+		sched, err := parseSchedule(tc.schedule)
+		assert.NoError(t, err, tc.schedule)
+		assert.Equal(t, tc.expected, sched.minInterval(), tc.schedule)
+	}
+}
+
+func TestScheduleInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, schedule := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * foo *",
+	} {
+		_, err := parseSchedule(schedule)
+		assert.Error(t, err, schedule)
+	}
+}
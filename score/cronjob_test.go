@@ -3,6 +3,10 @@ package score
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
 	"github.com/romnn/kube-score/scorecard"
 )
 
@@ -36,6 +40,83 @@ func TestCronJobNotHasDeadline(t *testing.T) {
 	}
 }
 
+func TestCronJobHasTimeZone(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("cronjob-batchv1-timezone-set.yaml")},
+		nil,
+		&config.RunConfiguration{
+			KubernetesVersion: config.Semver{Major: 1, Minor: 27},
+		},
+		"CronJob has timezone",
+		scorecard.GradeAllOK,
+	)
+}
+
+func TestCronJobNotHasTimeZone(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("cronjob-batchv1-timezone-not-set.yaml")},
+		nil,
+		&config.RunConfiguration{
+			KubernetesVersion: config.Semver{Major: 1, Minor: 27},
+		},
+		"CronJob has timezone",
+		scorecard.GradeWarning,
+	)
+}
+
+func TestCronJobTimeZoneSkippedBeforeV127(t *testing.T) {
+	t.Parallel()
+	assert.True(t, wasSkipped(
+		t,
+		[]ks.NamedReader{testFile("cronjob-batchv1-timezone-not-set.yaml")},
+		nil,
+		&config.RunConfiguration{
+			KubernetesVersion: config.Semver{Major: 1, Minor: 26},
+		},
+		"CronJob has timezone",
+	))
+}
+
+func TestCronJobDeadlineMatchesSchedule(t *testing.T) {
+	t.Parallel()
+
+	for _, v := range []string{"batchv1beta1", "batchv1"} {
+		t.Run(v, func(t *testing.T) {
+			testExpectedScore(
+				t,
+				"cronjob-"+v+"-deadline-set.yaml",
+				"CronJob deadline matches schedule",
+				scorecard.GradeAllOK,
+			)
+		})
+	}
+}
+
+func TestCronJobDeadlineShorterThanSchedule(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(
+		t,
+		"cronjob-batchv1-deadline-shorter-than-schedule.yaml",
+		"CronJob deadline matches schedule",
+		scorecard.GradeWarning,
+	)
+}
+
+func TestCronJobDeadlineMatchesScheduleSkipped(t *testing.T) {
+	t.Parallel()
+	assert.True(t, wasSkipped(
+		t,
+		[]ks.NamedReader{testFile("cronjob-batchv1-deadline-not-set.yaml")},
+		nil,
+		nil,
+		"CronJob deadline matches schedule",
+	))
+}
+
 func TestProbesPodCronMissingReady(t *testing.T) {
 	t.Parallel()
 
@@ -1,6 +1,8 @@
 package deployment
 
 import (
+	"fmt"
+
 	ks "github.com/romnn/kube-score/domain"
 	"github.com/romnn/kube-score/score/checks"
 	"github.com/romnn/kube-score/score/internal"
@@ -11,53 +13,94 @@ import (
 )
 
 type Options struct {
-	Namespace string
+	Namespace   ks.DefaultNamespace
+	MinReplicas int32
 }
 
-func Register(allChecks *checks.Checks, all ks.AllTypes, options Options) {
-	allChecks.RegisterDeploymentCheck(
+func Register(allChecks *checks.Checks, options Options) {
+	allChecks.RegisterDeploymentContextCheck(
 		"Deployment Strategy",
 		`Makes sure that all Deployments targeted by service use RollingUpdate strategy`,
-		deploymentRolloutStrategy(all.Services(), options),
+		func(ctx *checks.Context, deployment v1.Deployment) (scorecard.TestScore, error) {
+			return deploymentRolloutStrategy(serviceSelectorsIn(ctx, options, deployment.Namespace))(deployment)
+		},
 	)
-	allChecks.RegisterDeploymentCheck(
+	allChecks.RegisterDeploymentContextCheck(
 		"Deployment Replicas",
 		`Makes sure that Deployment has multiple replicas`,
-		deploymentReplicas(all.Services(), all.HorizontalPodAutoscalers(), options),
+		func(ctx *checks.Context, deployment v1.Deployment) (scorecard.TestScore, error) {
+			return deploymentReplicas(
+				serviceSelectorsIn(ctx, options, deployment.Namespace),
+				hpaTargetsIn(ctx, options, deployment.Namespace),
+				options,
+			)(deployment)
+		},
+	)
+	allChecks.RegisterDeploymentCheck(
+		"Deployment Paused",
+		`Makes sure that Deployments are not paused`,
+		deploymentPaused,
 	)
 }
 
-// deploymentRolloutStrategy checks if a Deployment has the update strategy on RollingUpdate if targeted by a service
-func deploymentRolloutStrategy(
-	svcs []ks.Service,
-	options Options,
-) func(deployment v1.Deployment) (scorecard.TestScore, error) {
-	svcsInNamespace := make(map[string][]map[string]string)
-	for _, s := range svcs {
-		svc := s.Service()
-		namespace := svc.Namespace
-		if namespace == "" {
-			namespace = options.Namespace
+// serviceSelectorsKey is the key of the index of the selectors of the Services by namespace
+type serviceSelectorsKey struct{ namespace ks.DefaultNamespace }
+
+// serviceSelectorsIn returns the selectors of the Services of the run in the namespace
+func serviceSelectorsIn(ctx *checks.Context, options Options, namespace string) []map[string]string {
+	return checks.Index(ctx, serviceSelectorsKey{options.Namespace}, func(all ks.AllTypes) map[string][]map[string]string {
+		selectors := make(map[string][]map[string]string)
+		for _, s := range all.Services() {
+			svc := s.Service()
+			ns := options.Namespace.Of(svc.Namespace)
+			selectors[ns] = append(selectors[ns], svc.Spec.Selector)
 		}
+		return selectors
+	})[options.Namespace.Of(namespace)]
+}
+
+// hpaTargetsKey is the key of the index of the targets of the HorizontalPodAutoscalers by namespace
+type hpaTargetsKey struct{ namespace ks.DefaultNamespace }
 
-		if _, ok := svcsInNamespace[namespace]; !ok {
-			svcsInNamespace[namespace] = []map[string]string{}
+// hpaTargetsIn returns the targets of the HorizontalPodAutoscalers of the run in the namespace
+func hpaTargetsIn(
+	ctx *checks.Context,
+	options Options,
+	namespace string,
+) []autoscalingv1.CrossVersionObjectReference {
+	return checks.Index(ctx, hpaTargetsKey{options.Namespace}, func(all ks.AllTypes) map[string][]autoscalingv1.CrossVersionObjectReference {
+		targets := make(map[string][]autoscalingv1.CrossVersionObjectReference)
+		for _, hpa := range all.HorizontalPodAutoscalers() {
+			ns := options.Namespace.Of(hpa.GetObjectMeta().Namespace)
+			targets[ns] = append(targets[ns], hpa.HpaTarget())
 		}
-		svcsInNamespace[namespace] = append(
-			svcsInNamespace[namespace],
-			svc.Spec.Selector,
+		return targets
+	})[options.Namespace.Of(namespace)]
+}
+
+// deploymentPaused checks that the Deployment is not paused, which is usually left over from a manual rollout
+func deploymentPaused(deployment v1.Deployment) (score scorecard.TestScore, err error) {
+	if deployment.Spec.Paused {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment(
+			"",
+			"The Deployment is paused",
+			"Changes to the pod template of a paused Deployment are not rolled out. Remove spec.paused, unless the Deployment is meant to be paused",
 		)
+		return
 	}
+	score.Grade = scorecard.GradeAllOK
+	return
+}
 
+// deploymentRolloutStrategy checks if a Deployment has the update strategy on RollingUpdate if targeted by a service
+func deploymentRolloutStrategy(
+	svcSelectors []map[string]string,
+) func(deployment v1.Deployment) (scorecard.TestScore, error) {
 	return func(deployment v1.Deployment) (score scorecard.TestScore, err error) {
 		referencedByService := false
 
-		deploymentNamespace := deployment.Namespace
-		if deploymentNamespace == "" {
-			deploymentNamespace = options.Namespace
-		}
-
-		for _, svcSelector := range svcsInNamespace[deploymentNamespace] {
+		for _, svcSelector := range svcSelectors {
 			if internal.LabelSelectorMatchesLabels(
 				svcSelector,
 				deployment.Spec.Template.Labels,
@@ -84,57 +127,17 @@ func deploymentRolloutStrategy(
 	}
 }
 
-// deploymentReplicas checks if a Deployment has >= 2 replicas if not (targeted by service || has HorizontalPodAutoscaler)
+// deploymentReplicas checks if a Deployment has >= options.MinReplicas replicas if not (targeted by service || has HorizontalPodAutoscaler)
 func deploymentReplicas(
-	svcs []ks.Service,
-	hpas []ks.HpaTargeter,
+	svcSelectors []map[string]string,
+	hpaTargets []autoscalingv1.CrossVersionObjectReference,
 	options Options,
 ) func(deployment v1.Deployment) (scorecard.TestScore, error) {
-	svcsInNamespace := make(map[string][]map[string]string)
-	for _, s := range svcs {
-		svc := s.Service()
-		namespace := svc.Namespace
-		if namespace == "" {
-			namespace = options.Namespace
-		}
-		if _, ok := svcsInNamespace[namespace]; !ok {
-			svcsInNamespace[namespace] = []map[string]string{}
-		}
-		svcsInNamespace[namespace] = append(
-			svcsInNamespace[namespace],
-			svc.Spec.Selector,
-		)
-	}
-
-	hpasInNamespace := make(map[string][]autoscalingv1.CrossVersionObjectReference)
-	for _, hpa := range hpas {
-		hpaTarget := hpa.HpaTarget()
-		hpaMeta := hpa.GetObjectMeta()
-
-		hpaNamespace := hpaMeta.Namespace
-		if hpaNamespace == "" {
-			hpaNamespace = options.Namespace
-		}
-
-		if _, ok := hpasInNamespace[hpaNamespace]; !ok {
-			hpasInNamespace[hpaNamespace] = []autoscalingv1.CrossVersionObjectReference{}
-		}
-		hpasInNamespace[hpaNamespace] = append(
-			hpasInNamespace[hpaNamespace],
-			hpaTarget,
-		)
-	}
-
 	return func(deployment v1.Deployment) (score scorecard.TestScore, err error) {
 		referencedByService := false
 		hasHPA := false
 
-		deploymentNamespace := deployment.Namespace
-		if deploymentNamespace == "" {
-			deploymentNamespace = options.Namespace
-		}
-
-		for _, svcSelector := range svcsInNamespace[deploymentNamespace] {
+		for _, svcSelector := range svcSelectors {
 			if internal.LabelSelectorMatchesLabels(
 				svcSelector,
 				deployment.Spec.Template.Labels,
@@ -144,7 +147,7 @@ func deploymentReplicas(
 			}
 		}
 
-		for _, hpaTarget := range hpasInNamespace[deploymentNamespace] {
+		for _, hpaTarget := range hpaTargets {
 			if deployment.APIVersion == hpaTarget.APIVersion &&
 				deployment.Kind == hpaTarget.Kind &&
 				deployment.Name == hpaTarget.Name {
@@ -169,11 +172,15 @@ func deploymentReplicas(
 				"",
 			)
 		default:
-			if ptr.Deref(deployment.Spec.Replicas, 1) >= 2 {
+			minReplicas, annotationErr := internal.MinReplicas(deployment.Annotations, options.MinReplicas)
+			if annotationErr != nil {
+				score.AddComment("", "Invalid annotation", annotationErr.Error())
+			}
+			if ptr.Deref(deployment.Spec.Replicas, 1) >= minReplicas {
 				score.Grade = scorecard.GradeAllOK
 			} else {
 				score.Grade = scorecard.GradeWarning
-				score.AddComment("", "Deployment few replicas", "Deployments targeted by Services are recommended to have at least 2 replicas to prevent unwanted downtime.")
+				score.AddComment("", "Deployment few replicas", fmt.Sprintf("Deployments targeted by Services are recommended to have at least %d replicas to prevent unwanted downtime.", minReplicas))
 			}
 		}
 
@@ -3,6 +3,7 @@ package score
 import (
 	"testing"
 
+	"github.com/romnn/kube-score/config"
 	ks "github.com/romnn/kube-score/domain"
 	"github.com/romnn/kube-score/scorecard"
 	"github.com/stretchr/testify/assert"
@@ -86,6 +87,32 @@ func TestServiceTargetsDeploymentReplicasNok(t *testing.T) {
 	)
 }
 
+func TestServiceTargetsDeploymentReplicasConfigured(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("service-target-deployment-replica-1.yaml")},
+		nil,
+		&config.RunConfiguration{
+			CheckParameters: config.CheckParameters{
+				"deployment-replicas": {"minReplicas": 1},
+			},
+		},
+		"Deployment Replicas",
+		scorecard.GradeAllOK,
+	)
+}
+
+func TestServiceTargetsDeploymentReplicasAnnotation(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(
+		t,
+		"service-target-deployment-replica-1-min-replicas-annotation.yaml",
+		"Deployment Replicas",
+		scorecard.GradeAllOK,
+	)
+}
+
 func TestHPATargetsDeployment(t *testing.T) {
 	t.Parallel()
 	assert.True(t, wasSkipped(t,
@@ -105,3 +132,13 @@ func TestHPATargetsDeployment(t *testing.T) {
 		"Skipped as the Deployment is controlled by a HorizontalPodAutoscaler",
 	)
 }
+
+func TestDeploymentPaused(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "deployment-paused.yaml", "Deployment Paused", scorecard.GradeWarning)
+}
+
+func TestDeploymentNotPaused(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "service-target-deployment.yaml", "Deployment Paused", scorecard.GradeAllOK)
+}
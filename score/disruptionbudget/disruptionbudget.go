@@ -2,6 +2,7 @@ package disruptionbudget
 
 import (
 	"fmt"
+	"os"
 
 	ks "github.com/romnn/kube-score/domain"
 	"github.com/romnn/kube-score/score/checks"
@@ -13,23 +14,24 @@ import (
 )
 
 type Options struct {
-	Namespace string
+	Namespace ks.DefaultNamespace
 }
 
-func Register(
-	allChecks *checks.Checks,
-	budgets ks.PodDisruptionBudgets,
-	options Options,
-) {
-	allChecks.RegisterStatefulSetCheck(
+func Register(allChecks *checks.Checks, options Options) {
+	// The PDBs of all namespaces are used, to find the PDBs that select the pods from another namespace
+	allChecks.RegisterStatefulSetContextCheck(
 		"StatefulSet has PodDisruptionBudget",
 		`Makes sure that all StatefulSets are targeted by a PDB`,
-		statefulSetHas(budgets.PodDisruptionBudgets(), options),
+		func(ctx *checks.Context, statefulset appsv1.StatefulSet) (scorecard.TestScore, error) {
+			return statefulSetHas(ctx.Objects().PodDisruptionBudgets(), options)(statefulset)
+		},
 	)
-	allChecks.RegisterDeploymentCheck(
+	allChecks.RegisterDeploymentContextCheck(
 		"Deployment has PodDisruptionBudget",
 		`Makes sure that all Deployments are targeted by a PDB`,
-		deploymentHas(budgets.PodDisruptionBudgets(), options),
+		func(ctx *checks.Context, deployment appsv1.Deployment) (scorecard.TestScore, error) {
+			return deploymentHas(ctx.Objects().PodDisruptionBudgets(), options)(deployment)
+		},
 	)
 	allChecks.RegisterPodDisruptionBudgetCheck(
 		"PodDisruptionBudget has policy",
@@ -47,9 +49,7 @@ func hasMatching(
 	verbose := false
 	var hasNamespaceMismatch []string
 
-	if namespace == "" {
-		namespace = options.Namespace
-	}
+	namespace = options.Namespace.Of(namespace)
 
 	for _, budget := range budgets {
 		selector, err := metav1.LabelSelectorAsSelector(
@@ -59,10 +59,7 @@ func hasMatching(
 			return false, "", fmt.Errorf("failed to create selector: %w", err)
 		}
 
-		budgetNamespace := budget.Namespace()
-		if budgetNamespace == "" {
-			budgetNamespace = options.Namespace
-		}
+		budgetNamespace := options.Namespace.Of(budget.Namespace())
 
 		// var requirements []k8slabels.Requirement
 		// for k, v := range labels {
@@ -75,14 +72,15 @@ func hasMatching(
 		// test := k8slabels.NewSelector().Add(requirements...)
 
 		if verbose {
-			fmt.Printf("selector = %+v\n", selector)
-			fmt.Printf("labels = %+v\n", k8slabels.Set(labels))
-			fmt.Printf(
+			fmt.Fprintf(os.Stderr, "selector = %+v\n", selector)
+			fmt.Fprintf(os.Stderr, "labels = %+v\n", k8slabels.Set(labels))
+			fmt.Fprintf(
+				os.Stderr,
 				"\t pdbNamespace = %q namespace=%q\n",
 				budgetNamespace,
 				namespace,
 			)
-			fmt.Printf("\t match = %t\n", selector.Matches(k8slabels.Set(labels)))
+			fmt.Fprintf(os.Stderr, "\t match = %t\n", selector.Matches(k8slabels.Set(labels)))
 		}
 		if !selector.Matches(k8slabels.Set(labels)) {
 			continue
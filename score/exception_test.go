@@ -0,0 +1,67 @@
+package score
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+type namedReader struct {
+	*strings.Reader
+	name string
+}
+
+func (n namedReader) Name() string {
+	return n.name
+}
+
+func TestScoreException(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore([]ks.NamedReader{testFile("score-exception.yaml")}, nil, nil)
+	assert.NoError(t, err)
+
+	grades := make(map[string]scorecard.Grade)
+	skipped := make(map[string]bool)
+	summaries := make(map[string][]string)
+	for _, o := range sc {
+		for _, c := range o.Checks {
+			if c.Check.ID != "container-image-tag" {
+				continue
+			}
+			name := o.ObjectMeta.Namespace + "/" + o.ObjectMeta.Name
+			grades[name] = c.Grade
+			skipped[name] = c.Skipped
+			for _, comment := range c.Comments {
+				summaries[name] = append(summaries[name], comment.Summary)
+			}
+		}
+	}
+	assert.Equal(t, map[string]bool{
+		"team/web":    true,
+		"team/api":    false,
+		"other/web":   false,
+		"team/legacy": false,
+	}, skipped)
+	assert.Equal(t, []string{"Skipped because of the ScoreException team/web-latest"}, summaries["team/web"])
+	assert.Equal(t, scorecard.GradeCritical, grades["team/legacy"])
+	assert.Contains(t, summaries["team/legacy"], "ScoreException has expired")
+}
+
+func TestInvalidScoreException(t *testing.T) {
+	t.Parallel()
+	for name, spec := range map[string]string{
+		"checks":   "reason: no checks",
+		"reason":   "checks: [container-image-tag]",
+		"expires":  "checks: [container-image-tag]\n  reason: invalid expiry\n  expires: tomorrow",
+		"selector": "checks: [container-image-tag]\n  reason: invalid selector\n  selector:\n    matchExpressions:\n      - key: app\n        operator: Equals",
+	} {
+		doc := "apiVersion: kube-score.com/v1alpha1\nkind: ScoreException\nmetadata:\n  name: invalid\nspec:\n  " + spec + "\n"
+		_, err := testScore([]ks.NamedReader{namedReader{Reader: strings.NewReader(doc), name: "exception.yaml"}}, nil, &config.RunConfiguration{Namespace: "team"})
+		assert.ErrorContains(t, err, "invalid ScoreException team/invalid", name)
+	}
+}
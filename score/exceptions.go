@@ -0,0 +1,58 @@
+package score
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+)
+
+// scoreExceptions returns the ScoreExceptions of the input. An invalid exception fails the run, so
+// that it does not silently fail to ignore a check, or ignore more checks than intended.
+func scoreExceptions(all ks.AllTypes, namespace ks.DefaultNamespace) ([]config.ScoreException, error) {
+	var exceptions []config.ScoreException
+	for _, e := range all.ScoreExceptions() {
+		if e.FileLocation().Skip {
+			continue
+		}
+		meta := e.GetObjectMeta()
+		exception, err := scoreException(e.Spec())
+		if err != nil {
+			return nil, fmt.Errorf("invalid ScoreException %s/%s: %w", namespace.Of(meta.Namespace), meta.Name, err)
+		}
+		exception.Namespace = namespace.Of(meta.Namespace)
+		exception.Name = meta.Name
+		exceptions = append(exceptions, exception)
+	}
+	return exceptions, nil
+}
+
+func scoreException(spec ks.ScoreExceptionSpec) (config.ScoreException, error) {
+	exception := config.ScoreException{Checks: spec.Checks, Reason: spec.Reason}
+	if len(spec.Checks) == 0 {
+		return exception, errors.New("spec.checks is empty")
+	}
+	if spec.Reason == "" {
+		return exception, errors.New("spec.reason is empty")
+	}
+	if spec.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(spec.Selector)
+		if err != nil {
+			return exception, fmt.Errorf("invalid spec.selector: %w", err)
+		}
+		exception.Selector = selector
+	}
+	if spec.Expires != "" {
+		expires, err := time.Parse(time.DateOnly, spec.Expires)
+		if err != nil {
+			return exception, fmt.Errorf("invalid spec.expires %q, expected YYYY-MM-DD", spec.Expires)
+		}
+		// The exception is applied until the end of the day
+		exception.Expires = expires.AddDate(0, 0, 1)
+	}
+	return exception, nil
+}
@@ -0,0 +1,368 @@
+package score
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+)
+
+// objectFilter decides which objects are scored. Objects that are not scored are still used by
+// the checks of other objects.
+type objectFilter struct {
+	namespace        ks.DefaultNamespace
+	exemptNamespaces []string
+	includeKinds     []string
+	excludeKinds     []string
+	skipHelmTests    bool
+	// selector is nil if all objects are selected
+	selector k8slabels.Selector
+	// owners are the objects of the input that can own other objects, see ownedInInput
+	owners map[ownerKey]bool
+}
+
+// ownerKey identifies an owner of an object. Owners are in the same namespace as the objects that
+// they own, and are identified by their name instead of their uid, as the uid is not in manifests.
+type ownerKey struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+func newObjectFilter(runConfig *config.RunConfiguration) (objectFilter, error) {
+	f := objectFilter{
+		namespace:        ks.DefaultNamespace(runConfig.Namespace),
+		exemptNamespaces: runConfig.ExemptNamespaces,
+		includeKinds:     runConfig.IncludeKinds,
+		excludeKinds:     runConfig.ExcludeKinds,
+		skipHelmTests:    runConfig.HelmHooks == config.HelmHooksSkipTests,
+	}
+	if runConfig.Selector != "" {
+		selector, err := k8slabels.Parse(runConfig.Selector)
+		if err != nil {
+			return objectFilter{}, fmt.Errorf("invalid selector: %w", err)
+		}
+		f.selector = selector
+	}
+	return f, nil
+}
+
+func (f objectFilter) empty() bool {
+	return len(f.exemptNamespaces) == 0 && len(f.includeKinds) == 0 && len(f.excludeKinds) == 0 &&
+		f.selector == nil && !f.skipHelmTests && len(f.owners) == 0
+}
+
+func (f objectFilter) scored(typeMeta metav1.TypeMeta, objectMeta metav1.ObjectMeta) bool {
+	if len(f.includeKinds) > 0 && !containsKind(f.includeKinds, typeMeta.Kind) {
+		return false
+	}
+	if containsKind(f.excludeKinds, typeMeta.Kind) {
+		return false
+	}
+	if f.selector != nil && !f.selector.Matches(k8slabels.Set(objectMeta.Labels)) {
+		return false
+	}
+	if f.skipHelmTests && config.IsHelmTestHook(objectMeta.Annotations) {
+		return false
+	}
+	if f.ownedInInput(objectMeta) {
+		return false
+	}
+
+	namespace := f.namespace.Of(objectMeta.Namespace)
+	for _, pattern := range f.exemptNamespaces {
+		if matched, _ := path.Match(pattern, namespace); matched {
+			return false
+		}
+	}
+	return true
+}
+
+// ownedInInput returns true if the controller of the object is also in the input, such as the
+// CronJob of a Job or the ReplicaSet of a Pod in a dump of a cluster. The object has the same pod
+// template as its controller, and is not scored, so that its findings are only reported once, on
+// the top-level owner.
+func (f objectFilter) ownedInInput(objectMeta metav1.ObjectMeta) bool {
+	owner := metav1.GetControllerOfNoCopy(&objectMeta)
+	if owner == nil {
+		return false
+	}
+	return f.owners[ownerKey{
+		kind:      owner.Kind,
+		namespace: f.namespace.Of(objectMeta.Namespace),
+		name:      owner.Name,
+	}]
+}
+
+// ownersOf returns the objects of the input that own other objects of the input
+func ownersOf(all ks.AllTypes, namespace ks.DefaultNamespace) map[ownerKey]bool {
+	objects := make(map[ownerKey]bool)
+	for _, metas := range [][]ks.BothMeta{all.Metas(), all.OtherObjects()} {
+		for _, m := range metas {
+			objects[ownerKey{
+				kind:      m.TypeMeta.Kind,
+				namespace: namespace.Of(m.ObjectMeta.Namespace),
+				name:      m.ObjectMeta.Name,
+			}] = true
+		}
+	}
+
+	owners := make(map[ownerKey]bool)
+	for _, metas := range [][]ks.BothMeta{all.Metas(), all.OtherObjects()} {
+		for _, m := range metas {
+			owner := metav1.GetControllerOfNoCopy(&m.ObjectMeta)
+			if owner == nil {
+				continue
+			}
+			key := ownerKey{kind: owner.Kind, namespace: namespace.Of(m.ObjectMeta.Namespace), name: owner.Name}
+			if objects[key] {
+				owners[key] = true
+			}
+		}
+	}
+	return owners
+}
+
+// containsKind returns true if the kind is in the list, kinds are compared case-insensitively
+func containsKind(kinds []string, kind string) bool {
+	for _, k := range kinds {
+		if strings.EqualFold(k, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// apply returns the objects that are scored
+func (f objectFilter) apply(all ks.AllTypes) ks.AllTypes {
+	f.owners = ownersOf(all, f.namespace)
+	if f.empty() {
+		return all
+	}
+	return &filteredObjects{
+		metas: filter(all.Metas(), func(m ks.BothMeta) bool {
+			return f.scored(m.TypeMeta, m.ObjectMeta)
+		}),
+		pods: filter(all.Pods(), func(p ks.Pod) bool {
+			return f.scored(p.Pod().TypeMeta, p.Pod().ObjectMeta)
+		}),
+		jobs: filter(all.Jobs(), func(j ks.Job) bool {
+			return f.scored(j.GetTypeMeta(), j.GetObjectMeta())
+		}),
+		podSpeccers: filter(all.PodSpeccers(), func(p ks.PodSpecer) bool {
+			return f.scored(p.GetTypeMeta(), p.GetObjectMeta())
+		}),
+		services: filter(all.Services(), func(s ks.Service) bool {
+			return f.scored(s.Service().TypeMeta, s.Service().ObjectMeta)
+		}),
+		statefulSets: filter(all.StatefulSets(), func(s ks.StatefulSet) bool {
+			return f.scored(s.StatefulSet().TypeMeta, s.StatefulSet().ObjectMeta)
+		}),
+		deployments: filter(all.Deployments(), func(d ks.Deployment) bool {
+			return f.scored(d.Deployment().TypeMeta, d.Deployment().ObjectMeta)
+		}),
+		networkPolicies: filter(all.NetworkPolicies(), func(n ks.NetworkPolicy) bool {
+			return f.scored(n.NetworkPolicy().TypeMeta, n.NetworkPolicy().ObjectMeta)
+		}),
+		ingresses: filter(all.Ingresses(), func(i ks.Ingress) bool {
+			return f.scored(i.GetTypeMeta(), i.GetObjectMeta())
+		}),
+		cronJobs: filter(all.CronJobs(), func(c ks.CronJob) bool {
+			return f.scored(c.GetTypeMeta(), c.GetObjectMeta())
+		}),
+		podDisruptionBudgets: filter(all.PodDisruptionBudgets(), func(p ks.PodDisruptionBudget) bool {
+			return f.scored(p.GetTypeMeta(), p.GetObjectMeta())
+		}),
+		hpas: filter(all.HorizontalPodAutoscalers(), func(h ks.HpaTargeter) bool {
+			return f.scored(h.GetTypeMeta(), h.GetObjectMeta())
+		}),
+		priorityClasses: filter(all.PriorityClasses(), func(p ks.PriorityClass) bool {
+			return f.scored(p.PriorityClass().TypeMeta, p.PriorityClass().ObjectMeta)
+		}),
+		storageClasses: filter(all.StorageClasses(), func(s ks.StorageClass) bool {
+			return f.scored(s.StorageClass().TypeMeta, s.StorageClass().ObjectMeta)
+		}),
+		webhooks: filter(all.WebhookConfigurations(), func(w ks.WebhookConfiguration) bool {
+			return f.scored(w.GetTypeMeta(), w.GetObjectMeta())
+		}),
+		crds: filter(all.CustomResourceDefinitions(), func(c ks.CustomResourceDefinition) bool {
+			return f.scored(c.GetTypeMeta(), c.GetObjectMeta())
+		}),
+		serviceMonitors: filter(all.ServiceMonitors(), func(m ks.ServiceMonitor) bool {
+			return f.scored(m.GetTypeMeta(), m.GetObjectMeta())
+		}),
+		podMonitors: filter(all.PodMonitors(), func(m ks.PodMonitor) bool {
+			return f.scored(m.GetTypeMeta(), m.GetObjectMeta())
+		}),
+		// ConfigMaps, Secrets, ServiceAccounts, Certificates, RuntimeClasses, ResourceQuotas,
+		// LimitRanges and other objects are not scored
+		configMaps:      all.ConfigMaps(),
+		secrets:         all.Secrets(),
+		serviceAccounts: all.ServiceAccounts(),
+		certificates:    all.Certificates(),
+		runtimeClasses:  all.RuntimeClasses(),
+		resourceQuotas:  all.ResourceQuotas(),
+		limitRanges:     all.LimitRanges(),
+		scoreExceptions: all.ScoreExceptions(),
+		documents:       all,
+		otherObjects:    all.OtherObjects(),
+	}
+}
+
+func filter[T any](items []T, keep func(T) bool) []T {
+	var kept []T
+	for _, item := range items {
+		if keep(item) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+type filteredObjects struct {
+	metas                []ks.BothMeta
+	pods                 []ks.Pod
+	jobs                 []ks.Job
+	podSpeccers          []ks.PodSpecer
+	services             []ks.Service
+	configMaps           []ks.ConfigMap
+	secrets              []ks.Secret
+	serviceAccounts      []ks.ServiceAccount
+	otherObjects         []ks.BothMeta
+	statefulSets         []ks.StatefulSet
+	deployments          []ks.Deployment
+	networkPolicies      []ks.NetworkPolicy
+	ingresses            []ks.Ingress
+	cronJobs             []ks.CronJob
+	podDisruptionBudgets []ks.PodDisruptionBudget
+	hpas                 []ks.HpaTargeter
+	priorityClasses      []ks.PriorityClass
+	storageClasses       []ks.StorageClass
+	webhooks             []ks.WebhookConfiguration
+	crds                 []ks.CustomResourceDefinition
+	serviceMonitors      []ks.ServiceMonitor
+	podMonitors          []ks.PodMonitor
+	certificates         []ks.Certificate
+	runtimeClasses       []ks.RuntimeClass
+	resourceQuotas       []ks.ResourceQuota
+	limitRanges          []ks.LimitRange
+	scoreExceptions      []ks.ScoreException
+	documents            ks.Documents
+}
+
+func (f *filteredObjects) Metas() []ks.BothMeta {
+	return f.metas
+}
+
+func (f *filteredObjects) Pods() []ks.Pod {
+	return f.pods
+}
+
+func (f *filteredObjects) Jobs() []ks.Job {
+	return f.jobs
+}
+
+func (f *filteredObjects) PodSpeccers() []ks.PodSpecer {
+	return f.podSpeccers
+}
+
+func (f *filteredObjects) Services() []ks.Service {
+	return f.services
+}
+
+func (f *filteredObjects) ConfigMaps() []ks.ConfigMap {
+	return f.configMaps
+}
+
+func (f *filteredObjects) Secrets() []ks.Secret {
+	return f.secrets
+}
+
+func (f *filteredObjects) ServiceAccounts() []ks.ServiceAccount {
+	return f.serviceAccounts
+}
+
+func (f *filteredObjects) OtherObjects() []ks.BothMeta {
+	return f.otherObjects
+}
+
+func (f *filteredObjects) StatefulSets() []ks.StatefulSet {
+	return f.statefulSets
+}
+
+func (f *filteredObjects) Deployments() []ks.Deployment {
+	return f.deployments
+}
+
+func (f *filteredObjects) NetworkPolicies() []ks.NetworkPolicy {
+	return f.networkPolicies
+}
+
+func (f *filteredObjects) Ingresses() []ks.Ingress {
+	return f.ingresses
+}
+
+func (f *filteredObjects) CronJobs() []ks.CronJob {
+	return f.cronJobs
+}
+
+func (f *filteredObjects) PodDisruptionBudgets() []ks.PodDisruptionBudget {
+	return f.podDisruptionBudgets
+}
+
+func (f *filteredObjects) HorizontalPodAutoscalers() []ks.HpaTargeter {
+	return f.hpas
+}
+
+func (f *filteredObjects) PriorityClasses() []ks.PriorityClass {
+	return f.priorityClasses
+}
+
+func (f *filteredObjects) StorageClasses() []ks.StorageClass {
+	return f.storageClasses
+}
+
+func (f *filteredObjects) WebhookConfigurations() []ks.WebhookConfiguration {
+	return f.webhooks
+}
+
+func (f *filteredObjects) CustomResourceDefinitions() []ks.CustomResourceDefinition {
+	return f.crds
+}
+
+func (f *filteredObjects) ServiceMonitors() []ks.ServiceMonitor {
+	return f.serviceMonitors
+}
+
+func (f *filteredObjects) PodMonitors() []ks.PodMonitor {
+	return f.podMonitors
+}
+
+func (f *filteredObjects) ScoreExceptions() []ks.ScoreException {
+	return f.scoreExceptions
+}
+
+func (f *filteredObjects) Document(typeMeta metav1.TypeMeta, objectMeta metav1.ObjectMeta) ([]byte, bool) {
+	return f.documents.Document(typeMeta, objectMeta)
+}
+
+func (f *filteredObjects) Certificates() []ks.Certificate {
+	return f.certificates
+}
+
+func (f *filteredObjects) RuntimeClasses() []ks.RuntimeClass {
+	return f.runtimeClasses
+}
+
+func (f *filteredObjects) ResourceQuotas() []ks.ResourceQuota {
+	return f.resourceQuotas
+}
+
+func (f *filteredObjects) LimitRanges() []ks.LimitRange {
+	return f.limitRanges
+}
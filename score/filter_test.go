@@ -0,0 +1,153 @@
+package score
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+)
+
+func TestExemptNamespaces(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore(
+		[]ks.NamedReader{testFile("exempt-namespaces.yaml")},
+		nil,
+		&config.RunConfiguration{
+			Namespace:        "default",
+			ExemptNamespaces: []string{"kube-system", "cattle-*"},
+		},
+	)
+	assert.NoError(t, err)
+
+	var scored []string
+	for _, o := range sc {
+		scored = append(scored, o.TypeMeta.Kind+"/"+o.ObjectMeta.Name)
+	}
+	assert.Equal(t, []string{"Deployment/app"}, scored)
+}
+
+func TestExemptDefaultNamespace(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore(
+		[]ks.NamedReader{testFile("exempt-namespaces.yaml")},
+		nil,
+		&config.RunConfiguration{
+			Namespace:        "default",
+			ExemptNamespaces: []string{"default"},
+		},
+	)
+	assert.NoError(t, err)
+	assert.Len(t, sc, 2)
+	for _, o := range sc {
+		assert.NotEqual(t, "app", o.ObjectMeta.Name)
+	}
+}
+
+func TestIncludeAndExcludeKinds(t *testing.T) {
+	t.Parallel()
+	scoredKinds := func(runConfig *config.RunConfiguration) []string {
+		sc, err := testScore([]ks.NamedReader{testFile("exempt-namespaces.yaml")}, nil, runConfig)
+		assert.NoError(t, err)
+		var kinds []string
+		for _, o := range sc {
+			kinds = append(kinds, o.TypeMeta.Kind)
+		}
+		sort.Strings(kinds)
+		return kinds
+	}
+
+	assert.Equal(t,
+		[]string{"DaemonSet", "Deployment"},
+		scoredKinds(&config.RunConfiguration{IncludeKinds: []string{"deployment", "DaemonSet"}}),
+	)
+	assert.Equal(t,
+		[]string{"Deployment", "Service"},
+		scoredKinds(&config.RunConfiguration{ExcludeKinds: []string{"DaemonSet"}}),
+	)
+	assert.Equal(t,
+		[]string{"Deployment"},
+		scoredKinds(&config.RunConfiguration{
+			IncludeKinds: []string{"Deployment", "DaemonSet"},
+			ExcludeKinds: []string{"DaemonSet"},
+		}),
+	)
+}
+
+func TestSelector(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore(
+		[]ks.NamedReader{testFile("selector.yaml")},
+		nil,
+		&config.RunConfiguration{Selector: "app.kubernetes.io/part-of in (payments)"},
+	)
+	assert.NoError(t, err)
+
+	var scored []string
+	for _, o := range sc {
+		scored = append(scored, o.TypeMeta.Kind+"/"+o.ObjectMeta.Name)
+	}
+	sort.Strings(scored)
+	assert.Equal(t, []string{"Deployment/payments", "Service/payments"}, scored)
+}
+
+func TestInvalidSelector(t *testing.T) {
+	t.Parallel()
+	_, err := testScore(
+		[]ks.NamedReader{testFile("selector.yaml")},
+		nil,
+		&config.RunConfiguration{Selector: "app in payments"},
+	)
+	assert.Error(t, err)
+}
+
+func TestHelmHooks(t *testing.T) {
+	t.Parallel()
+	networkPolicy := func(helmHooks config.HelmHooks) map[string]bool {
+		sc, err := testScore(
+			[]ks.NamedReader{testFile("helm-hooks.yaml")},
+			nil,
+			&config.RunConfiguration{HelmHooks: helmHooks},
+		)
+		assert.NoError(t, err)
+		skipped := make(map[string]bool)
+		for _, o := range sc {
+			for _, c := range o.Checks {
+				if c.Check.ID == "pod-networkpolicy" {
+					skipped[o.ObjectMeta.Name] = c.Skipped
+				}
+			}
+		}
+		return skipped
+	}
+
+	assert.Equal(t,
+		map[string]bool{"migrate": false, "test-connection": false, "backup": false},
+		networkPolicy(config.HelmHooksScore),
+	)
+	assert.Equal(t,
+		map[string]bool{"migrate": true, "test-connection": true, "backup": false},
+		networkPolicy(config.HelmHooksRelaxed),
+	)
+	// Test hooks are not scored
+	assert.Equal(t,
+		map[string]bool{"migrate": true, "backup": false},
+		networkPolicy(config.HelmHooksSkipTests),
+	)
+}
+
+func TestOwnedObjectsAreScoredOnTheirOwner(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore([]ks.NamedReader{testFile("owner-references.yaml")}, nil, nil)
+	assert.NoError(t, err)
+
+	var scored []string
+	for _, o := range sc {
+		scored = append(scored, o.TypeMeta.Kind+"/"+o.ObjectMeta.Name)
+	}
+	sort.Strings(scored)
+	// The ReplicaSet of the Pod is not in the input, so the Pod is scored
+	assert.Equal(t, []string{"CronJob/report", "Pod/api-6d4cf56db6-xyz12"}, scored)
+}
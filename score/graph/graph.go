@@ -0,0 +1,214 @@
+// Package graph builds the references between the objects of the input, such as the Services
+// that select the pods of a workload, so that checks that span multiple objects do not each
+// have to reimplement the matching.
+package graph
+
+import (
+	"cmp"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+
+	ks "github.com/romnn/kube-score/domain"
+)
+
+// Node is an object of the input. The namespace is always set, objects without a namespace
+// are in the default namespace of the graph.
+type Node struct {
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+func (n Node) String() string {
+	return n.Kind + "/" + n.Namespace + "/" + n.Name
+}
+
+// Relation is the kind of reference from one object to another
+type Relation string
+
+const (
+	// RelationSelects is a Service selecting the pods of a workload
+	RelationSelects Relation = "selects"
+	// RelationRoutes is an Ingress routing to a Service
+	RelationRoutes Relation = "routes"
+	// RelationScales is a HorizontalPodAutoscaler scaling a workload
+	RelationScales Relation = "scales"
+	// RelationProtects is a PodDisruptionBudget selecting the pods of a workload
+	RelationProtects Relation = "protects"
+	// RelationAppliesTo is a NetworkPolicy selecting the pods of a workload
+	RelationAppliesTo Relation = "applies-to"
+)
+
+type Edge struct {
+	From     Node
+	To       Node
+	Relation Relation
+}
+
+// Graph is the set of references between the objects of the input
+type Graph struct {
+	namespace ks.DefaultNamespace
+	nodes     []Node
+	edges     []Edge
+	incoming  map[Node][]Edge
+	outgoing  map[Node][]Edge
+}
+
+type workload struct {
+	node   Node
+	labels k8slabels.Set
+}
+
+// Build builds the graph of all objects, objects without a namespace are in the namespace
+func Build(all ks.AllTypes, namespace string) *Graph {
+	g := &Graph{
+		namespace: ks.DefaultNamespace(namespace),
+		incoming:  make(map[Node][]Edge),
+		outgoing:  make(map[Node][]Edge),
+	}
+
+	var workloads []workload
+	for _, p := range all.PodSpeccers() {
+		n := g.addNode(p.GetTypeMeta(), p.GetObjectMeta())
+		workloads = append(workloads, workload{n, p.GetPodTemplateSpec().Labels})
+	}
+	for _, p := range all.Pods() {
+		pod := p.Pod()
+		n := g.addNode(pod.TypeMeta, pod.ObjectMeta)
+		workloads = append(workloads, workload{n, pod.Labels})
+	}
+
+	// selectWorkloads adds an edge to all workloads in the namespace that match the selector
+	selectWorkloads := func(from Node, relation Relation, selector k8slabels.Selector) {
+		for _, w := range workloads {
+			if w.node.Namespace == from.Namespace && selector.Matches(w.labels) {
+				g.addEdge(from, w.node, relation)
+			}
+		}
+	}
+
+	services := make(map[Node]bool)
+	for _, s := range all.Services() {
+		svc := s.Service()
+		n := g.addNode(svc.TypeMeta, svc.ObjectMeta)
+		services[n] = true
+		// Services without a selector do not select any pods
+		if len(svc.Spec.Selector) > 0 {
+			selectWorkloads(n, RelationSelects, k8slabels.SelectorFromSet(svc.Spec.Selector))
+		}
+	}
+
+	for _, ingress := range all.Ingresses() {
+		n := g.addNode(ingress.GetTypeMeta(), ingress.GetObjectMeta())
+		for _, rule := range ingress.Rules() {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				if path.Backend.Service == nil {
+					continue
+				}
+				target := Node{Kind: "Service", Namespace: n.Namespace, Name: path.Backend.Service.Name}
+				if services[target] {
+					g.addEdge(n, target, RelationRoutes)
+				}
+			}
+		}
+	}
+
+	for _, hpa := range all.HorizontalPodAutoscalers() {
+		n := g.addNode(hpa.GetTypeMeta(), hpa.GetObjectMeta())
+		target := hpa.HpaTarget()
+		for _, w := range workloads {
+			if w.node.Namespace == n.Namespace && w.node.Kind == target.Kind && w.node.Name == target.Name {
+				g.addEdge(n, w.node, RelationScales)
+			}
+		}
+	}
+
+	for _, pdb := range all.PodDisruptionBudgets() {
+		n := g.addNode(pdb.GetTypeMeta(), pdb.GetObjectMeta())
+		// A nil selector does not select any pods
+		if selector, err := metav1.LabelSelectorAsSelector(pdb.PodDisruptionBudgetSelector()); err == nil {
+			selectWorkloads(n, RelationProtects, selector)
+		}
+	}
+
+	for _, np := range all.NetworkPolicies() {
+		netpol := np.NetworkPolicy()
+		n := g.addNode(netpol.TypeMeta, netpol.ObjectMeta)
+		// An empty podSelector selects all pods of the namespace
+		if selector, err := metav1.LabelSelectorAsSelector(&netpol.Spec.PodSelector); err == nil {
+			selectWorkloads(n, RelationAppliesTo, selector)
+		}
+	}
+
+	slices.SortFunc(g.nodes, compareNodes)
+	slices.SortFunc(g.edges, func(a, b Edge) int {
+		return cmp.Or(
+			compareNodes(a.From, b.From),
+			compareNodes(a.To, b.To),
+			cmp.Compare(a.Relation, b.Relation),
+		)
+	})
+	return g
+}
+
+func compareNodes(a, b Node) int {
+	return cmp.Or(
+		cmp.Compare(a.Namespace, b.Namespace),
+		cmp.Compare(a.Kind, b.Kind),
+		cmp.Compare(a.Name, b.Name),
+	)
+}
+
+// Node returns the node of an object
+func (g *Graph) Node(typeMeta metav1.TypeMeta, objectMeta metav1.ObjectMeta) Node {
+	namespace := g.namespace.Of(objectMeta.Namespace)
+	return Node{Kind: typeMeta.Kind, Namespace: namespace, Name: objectMeta.Name}
+}
+
+func (g *Graph) addNode(typeMeta metav1.TypeMeta, objectMeta metav1.ObjectMeta) Node {
+	n := g.Node(typeMeta, objectMeta)
+	g.nodes = append(g.nodes, n)
+	return n
+}
+
+func (g *Graph) addEdge(from, to Node, relation Relation) {
+	e := Edge{From: from, To: to, Relation: relation}
+	g.edges = append(g.edges, e)
+	g.outgoing[from] = append(g.outgoing[from], e)
+	g.incoming[to] = append(g.incoming[to], e)
+}
+
+// Nodes returns all objects of the graph, sorted by namespace, kind and name
+func (g *Graph) Nodes() []Node {
+	return g.nodes
+}
+
+// Edges returns all references of the graph, sorted by the objects they are from and to
+func (g *Graph) Edges() []Edge {
+	return g.edges
+}
+
+// From returns the references from the object to other objects
+func (g *Graph) From(n Node) []Edge {
+	return g.outgoing[n]
+}
+
+// To returns the references from other objects to the object, of any of the relations. All
+// references are returned if no relations are given.
+func (g *Graph) To(n Node, relations ...Relation) []Edge {
+	if len(relations) == 0 {
+		return g.incoming[n]
+	}
+	var edges []Edge
+	for _, e := range g.incoming[n] {
+		if slices.Contains(relations, e.Relation) {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
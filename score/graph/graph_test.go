@@ -0,0 +1,160 @@
+package graph
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/parser"
+)
+
+type namedReader struct {
+	io.Reader
+	name string
+}
+
+func (n namedReader) Name() string {
+	return n.name
+}
+
+const manifests = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: web:1.0.0
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: other
+spec:
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      containers:
+      - name: web
+        image: web:1.0.0
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  selector:
+    app: web
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: external
+spec:
+  type: ExternalName
+  externalName: example.com
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: web
+spec:
+  rules:
+  - http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: web
+            port:
+              number: 80
+      - path: /missing
+        pathType: Prefix
+        backend:
+          service:
+            name: missing
+            port:
+              number: 80
+---
+apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: web
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: web
+  minReplicas: 2
+  maxReplicas: 4
+---
+apiVersion: policy/v1
+kind: PodDisruptionBudget
+metadata:
+  name: web
+spec:
+  minAvailable: 1
+  selector:
+    matchLabels:
+      app: web
+---
+apiVersion: networking.k8s.io/v1
+kind: NetworkPolicy
+metadata:
+  name: default-deny
+  namespace: other
+spec:
+  podSelector: {}
+`
+
+func build(t *testing.T) *Graph {
+	p, err := parser.New(nil)
+	assert.NoError(t, err)
+	parsed, err := p.ParseFiles([]ks.NamedReader{
+		namedReader{Reader: strings.NewReader(manifests), name: "manifests.yaml"},
+	})
+	assert.NoError(t, err)
+	return Build(parsed, "default")
+}
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+	g := build(t)
+
+	web := Node{Kind: "Deployment", Namespace: "default", Name: "web"}
+	otherWeb := Node{Kind: "Deployment", Namespace: "other", Name: "web"}
+	service := Node{Kind: "Service", Namespace: "default", Name: "web"}
+
+	assert.Equal(t, []Edge{
+		{From: Node{"HorizontalPodAutoscaler", "default", "web"}, To: web, Relation: RelationScales},
+		{From: Node{"Ingress", "default", "web"}, To: service, Relation: RelationRoutes},
+		{From: Node{"PodDisruptionBudget", "default", "web"}, To: web, Relation: RelationProtects},
+		{From: service, To: web, Relation: RelationSelects},
+		{From: Node{"NetworkPolicy", "other", "default-deny"}, To: otherWeb, Relation: RelationAppliesTo},
+	}, g.Edges())
+
+	assert.Len(t, g.Nodes(), 8)
+	assert.Len(t, g.To(web), 3)
+	assert.Len(t, g.To(web, RelationSelects, RelationProtects), 2)
+	assert.Len(t, g.From(service), 1)
+	assert.Empty(t, g.From(Node{Kind: "Service", Namespace: "default", Name: "external"}))
+}
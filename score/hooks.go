@@ -0,0 +1,107 @@
+package score
+
+import (
+	"fmt"
+	"sort"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// Hooks are called by ScoreWithHooks while scoring. They can be used to observe the scoring,
+// to skip checks, or to mutate the results. All hooks are optional.
+type Hooks struct {
+	// BeforeObject is called once for every object, before its first check is run
+	BeforeObject func(o *scorecard.ScoredObject)
+
+	// AfterObject is called once for every object, after all checks have been run.
+	// Returning an error aborts the scoring.
+	AfterObject func(o *scorecard.ScoredObject) error
+
+	// BeforeCheck is called before a check is run on an object.
+	// If it returns false, the check is not run and not added to the object.
+	BeforeCheck func(o *scorecard.ScoredObject, check ks.Check) bool
+
+	// AfterCheck is called with the result of a check, before it is added to the object.
+	// The score can be mutated, e.g. to change the grade. Returning an error aborts the scoring.
+	AfterCheck func(o *scorecard.ScoredObject, check ks.Check, score *scorecard.TestScore) error
+
+	// Progress is called after an object has been scored by a group of checks, with the number of
+	// objects of the groups that have been scored and the total
+	Progress func(done, total int)
+}
+
+func (h *Hooks) beforeObject(o *scorecard.ScoredObject) {
+	if h != nil && h.BeforeObject != nil {
+		h.BeforeObject(o)
+	}
+}
+
+func (h *Hooks) progress(done, total int) {
+	if h != nil && h.Progress != nil {
+		h.Progress(done, total)
+	}
+}
+
+// afterObjects calls the AfterObject hook for all objects, in a stable order
+func (h *Hooks) afterObjects(scoreCard scorecard.Scorecard) error {
+	if h == nil || h.AfterObject == nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(scoreCard))
+	for k := range scoreCard {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if err := h.AfterObject(scoreCard[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runCheck runs fn surrounded by the check hooks. ok is false if the check has been skipped
+// by the BeforeCheck hook. fn is not run if the object would skip the check anyway, such as
+// optional checks that are not enabled, as some checks query registries or run scanners.
+// An error or a panic of fn is added to the object as an errored check, see scorecard.Errored.
+func (h *Hooks) runCheck(
+	o *scorecard.ScoredObject,
+	check ks.Check,
+	locationer ks.FileLocationer,
+	annotations []map[string]string,
+	fn func() (scorecard.TestScore, error),
+) (score scorecard.TestScore, ok bool, err error) {
+	if h != nil && h.BeforeCheck != nil && !h.BeforeCheck(o, check) {
+		return score, false, nil
+	}
+
+	if o.IsSkipped(check, locationer, annotations...) {
+		return scorecard.TestScore{Grade: scorecard.GradeAllOK}, true, nil
+	}
+
+	score, err = recoverCheck(fn)
+	if err != nil {
+		score = scorecard.Errored(err)
+	}
+
+	if h != nil && h.AfterCheck != nil {
+		if err := h.AfterCheck(o, check, &score); err != nil {
+			return score, false, err
+		}
+	}
+	return score, true, nil
+}
+
+// recoverCheck runs fn, and returns a panic of fn as an error, so that a broken check, such as a
+// custom check, only fails for the object instead of crashing the run
+func recoverCheck(fn func() (scorecard.TestScore, error)) (score scorecard.TestScore, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("the check panicked: %v", r)
+		}
+	}()
+	return fn()
+}
@@ -0,0 +1,178 @@
+package score
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/parser"
+	"github.com/romnn/kube-score/score/checks"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func testScoreWithHooks(t *testing.T, file string, hooks *Hooks) (scorecard.Scorecard, error) {
+	t.Helper()
+
+	p, err := parser.New(nil)
+	assert.NoError(t, err)
+	parsed, err := p.ParseFiles([]ks.NamedReader{testFile(file)})
+	assert.NoError(t, err)
+
+	runConfig := &config.RunConfiguration{
+		EnabledOptionalTests: map[string]struct{}{"container-ports-check": {}},
+	}
+	allChecks := RegisterAllChecks(&checks.Config{}, runConfig)
+
+	card, err := ScoreWithHooks(parsed, allChecks, runConfig, hooks)
+	if err != nil {
+		return nil, err
+	}
+	return *card, nil
+}
+
+func findCheck(sc scorecard.Scorecard, name string) (scorecard.TestScore, bool) {
+	for _, o := range sc {
+		for _, check := range o.Checks {
+			if check.Check.Name == name {
+				return check, true
+			}
+		}
+	}
+	return scorecard.TestScore{}, false
+}
+
+func TestHooksAfterCheckChangesGrade(t *testing.T) {
+	t.Parallel()
+
+	sc, err := testScoreWithHooks(t, "pod-container-ports-name-too-long.yaml", &Hooks{
+		AfterCheck: func(o *scorecard.ScoredObject, check ks.Check, score *scorecard.TestScore) error {
+			if check.ID == "container-ports-check" && score.Grade == scorecard.GradeCritical {
+				score.Grade = scorecard.GradeWarning
+			}
+			return nil
+		},
+	})
+	assert.NoError(t, err)
+
+	check, ok := findCheck(sc, "Container Ports Check")
+	assert.True(t, ok)
+	assert.Equal(t, scorecard.GradeWarning, check.Grade)
+}
+
+func TestHooksBeforeCheckSkipsCheck(t *testing.T) {
+	t.Parallel()
+
+	var seen []string
+	sc, err := testScoreWithHooks(t, "pod-container-ports-name-too-long.yaml", &Hooks{
+		BeforeCheck: func(o *scorecard.ScoredObject, check ks.Check) bool {
+			seen = append(seen, check.ID)
+			return check.ID != "container-ports-check"
+		},
+	})
+	assert.NoError(t, err)
+
+	_, ok := findCheck(sc, "Container Ports Check")
+	assert.False(t, ok)
+	assert.Contains(t, seen, "container-ports-check")
+}
+
+func TestHooksObjects(t *testing.T) {
+	t.Parallel()
+
+	var before, after int
+	sc, err := testScoreWithHooks(t, "pod-container-ports-name-too-long.yaml", &Hooks{
+		BeforeObject: func(o *scorecard.ScoredObject) {
+			assert.Empty(t, o.Checks)
+			before++
+		},
+		AfterObject: func(o *scorecard.ScoredObject) error {
+			assert.NotEmpty(t, o.Checks)
+			after++
+			return nil
+		},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, sc, 1)
+	assert.Equal(t, 1, before)
+	assert.Equal(t, 1, after)
+}
+
+func TestHooksProgress(t *testing.T) {
+	t.Parallel()
+
+	var done []int
+	total := -1
+	_, err := testScoreWithHooks(t, "pod-container-ports-name-too-long.yaml", &Hooks{
+		Progress: func(d, tot int) {
+			done = append(done, d)
+			total = tot
+		},
+	})
+	assert.NoError(t, err)
+	// The Pod is scored by the checks of all objects, of pods and of Pod objects
+	assert.Equal(t, 3, total)
+	assert.Equal(t, []int{1, 2, 3}, done)
+}
+
+func TestHooksErrorAbortsScoring(t *testing.T) {
+	t.Parallel()
+
+	_, err := testScoreWithHooks(t, "pod-container-ports-name-too-long.yaml", &Hooks{
+		AfterCheck: func(o *scorecard.ScoredObject, check ks.Check, score *scorecard.TestScore) error {
+			return errors.New("failed")
+		},
+	})
+	assert.Error(t, err)
+}
+
+func TestCheckErrorIsAddedToObject(t *testing.T) {
+	t.Parallel()
+
+	p, err := parser.New(nil)
+	assert.NoError(t, err)
+	parsed, err := p.ParseFiles([]ks.NamedReader{testFile("pod-container-ports-name-too-long.yaml")})
+	assert.NoError(t, err)
+
+	allChecks := RegisterAllChecks(&checks.Config{}, nil)
+	allChecks.RegisterPodCheck("custom/Failing", "", func(ks.PodSpecer) (scorecard.TestScore, error) {
+		return scorecard.TestScore{Grade: scorecard.GradeAllOK}, errors.New("broken")
+	})
+
+	// The other checks are still run
+	card, err := Score(parsed, allChecks, nil)
+	assert.NoError(t, err)
+	check, ok := findCheck(*card, "custom/Failing")
+	assert.True(t, ok)
+	assert.Equal(t, scorecard.GradeCritical, check.Grade)
+	assert.Equal(t, "broken", check.Error)
+	assert.Equal(t, "The check failed to run", check.Comments[0].Summary)
+	_, ok = findCheck(*card, "Container Image Tag")
+	assert.True(t, ok)
+}
+
+func TestCheckPanicIsAddedToObject(t *testing.T) {
+	t.Parallel()
+
+	p, err := parser.New(nil)
+	assert.NoError(t, err)
+	parsed, err := p.ParseFiles([]ks.NamedReader{testFile("pod-container-ports-name-too-long.yaml")})
+	assert.NoError(t, err)
+
+	allChecks := RegisterAllChecks(&checks.Config{}, nil)
+	allChecks.RegisterPodCheck("custom/Panicking", "", func(ps ks.PodSpecer) (scorecard.TestScore, error) {
+		var containers []string
+		return scorecard.TestScore{Grade: scorecard.GradeAllOK}, errors.New(containers[len(ps.GetPodTemplateSpec().Spec.Containers)])
+	})
+
+	card, err := Score(parsed, allChecks, nil)
+	assert.NoError(t, err)
+	check, ok := findCheck(*card, "custom/Panicking")
+	assert.True(t, ok)
+	assert.Equal(t, scorecard.GradeCritical, check.Grade)
+	assert.Contains(t, check.Error, "the check panicked: runtime error: index out of range")
+	_, ok = findCheck(*card, "Container Image Tag")
+	assert.True(t, ok)
+}
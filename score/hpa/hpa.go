@@ -2,23 +2,36 @@ package hpa
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/romnn/kube-score/domain"
 	"github.com/romnn/kube-score/score/checks"
+	"github.com/romnn/kube-score/score/internal"
 	"github.com/romnn/kube-score/scorecard"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/ptr"
 )
 
 type Options struct {
-	AllTargetableObjs []domain.BothMeta
-	Namespace         string
+	Namespace   domain.DefaultNamespace
+	MinReplicas int32
+	// ScalableKinds are the custom resources with a scale subresource, e.g. Rollout.argoproj.io
+	ScalableKinds []string
 }
 
 func Register(allChecks *checks.Checks, options Options) {
-	allChecks.RegisterHorizontalPodAutoscalerCheck(
+	allChecks.RegisterHorizontalPodAutoscalerContextCheck(
 		"HorizontalPodAutoscaler has target",
 		`Makes sure that the HPA targets a valid object`,
-		hpaHasTarget(options),
+		func(ctx *checks.Context, hpa domain.HpaTargeter) (scorecard.TestScore, error) {
+			return hpaHasTarget(targetsIn(ctx, options, hpa.GetObjectMeta().Namespace), options)(hpa)
+		},
+	)
+	allChecks.RegisterHorizontalPodAutoscalerCheck(
+		"HorizontalPodAutoscaler Target Scalable",
+		`Makes sure that the kind of the HPA target has a scale subresource`,
+		hpaTargetScalable(options),
 	)
 	allChecks.RegisterHorizontalPodAutoscalerCheck(
 		"HorizontalPodAutoscaler Replicas",
@@ -27,38 +40,35 @@ func Register(allChecks *checks.Checks, options Options) {
 	)
 }
 
+// targetsKey is the key of the index of the objects that HPAs can target by namespace
+type targetsKey struct{ namespace domain.DefaultNamespace }
+
+// targetsIn returns the objects of the run in the namespace that HPAs can target
+func targetsIn(ctx *checks.Context, options Options, namespace string) []domain.BothMeta {
+	return checks.Index(ctx, targetsKey{options.Namespace}, func(all domain.AllTypes) map[string][]domain.BothMeta {
+		targets := append(slices.Clip(all.Metas()), all.OtherObjects()...)
+		return checks.ByNamespace(targets, options.Namespace, func(t domain.BothMeta) metav1.ObjectMeta {
+			return t.ObjectMeta
+		})
+	})[options.Namespace.Of(namespace)]
+}
+
 func hpaHasTarget(
+	allTargetableObjs []domain.BothMeta,
 	options Options,
 ) func(hpa domain.HpaTargeter) (score scorecard.TestScore, err error) {
-	verbose := false
 	return func(hpa domain.HpaTargeter) (scorecard.TestScore, error) {
 		targetRef := hpa.HpaTarget()
-		var hasTarget bool
-		for _, t := range options.AllTargetableObjs {
+		targetGroupKind := groupKind(targetRef.APIVersion, targetRef.Kind)
 
-			hpaNamespace := hpa.GetObjectMeta().Namespace
-			if hpaNamespace == "" {
-				hpaNamespace = options.Namespace
-			}
+		hpaNamespace := options.Namespace.Of(hpa.GetObjectMeta().Namespace)
 
-			namespace := t.ObjectMeta.Namespace
-			if namespace == "" {
-				namespace = options.Namespace
-			}
+		var hasTarget bool
+		for _, t := range allTargetableObjs {
+			namespace := options.Namespace.Of(t.ObjectMeta.Namespace)
 
-			if verbose {
-				fmt.Printf("hpa=%s\n", targetRef.Name)
-				fmt.Printf(
-					"\t apiVersion: %s == %s\n",
-					targetRef.APIVersion,
-					t.TypeMeta.APIVersion,
-				)
-				fmt.Printf("\t kind: %s == %s\n", targetRef.Kind, t.TypeMeta.Kind)
-				fmt.Printf("\t name: %s == %s\n", targetRef.Name, t.ObjectMeta.Name)
-				fmt.Printf("\t namespace: %s == %s\n", hpaNamespace, namespace)
-			}
-			if t.TypeMeta.APIVersion == targetRef.APIVersion &&
-				t.TypeMeta.Kind == targetRef.Kind &&
+			// The version of the target does not matter, the object can be served in any version
+			if groupKind(t.TypeMeta.APIVersion, t.TypeMeta.Kind) == targetGroupKind &&
 				t.ObjectMeta.Name == targetRef.Name &&
 				namespace == hpaNamespace {
 				hasTarget = true
@@ -77,15 +87,71 @@ func hpaHasTarget(
 	}
 }
 
+// scalableKinds are the built-in kinds with a scale subresource
+var scalableKinds = []schema.GroupKind{
+	{Group: "apps", Kind: "Deployment"},
+	{Group: "apps", Kind: "StatefulSet"},
+	{Group: "apps", Kind: "ReplicaSet"},
+	{Group: "", Kind: "ReplicationController"},
+}
+
+// groupAliases are the deprecated API groups of kinds that have moved to another group
+var groupAliases = map[string]string{
+	"extensions": "apps",
+}
+
+// groupKind returns the group and kind of the apiVersion, deprecated groups are replaced
+// by the current group of the kind
+func groupKind(apiVersion, kind string) schema.GroupKind {
+	group := schema.FromAPIVersionAndKind(apiVersion, kind).Group
+	if alias, ok := groupAliases[group]; ok {
+		group = alias
+	}
+	return schema.GroupKind{Group: group, Kind: kind}
+}
+
+func hpaTargetScalable(
+	options Options,
+) func(hpa domain.HpaTargeter) (score scorecard.TestScore, err error) {
+	return func(hpa domain.HpaTargeter) (score scorecard.TestScore, err error) {
+		targetRef := hpa.HpaTarget()
+		target := groupKind(targetRef.APIVersion, targetRef.Kind)
+		if slices.Contains(scalableKinds, target) {
+			score.Grade = scorecard.GradeAllOK
+			return
+		}
+		for _, kind := range options.ScalableKinds {
+			if schema.ParseGroupKind(kind) == target {
+				score.Grade = scorecard.GradeAllOK
+				return
+			}
+		}
+		score.Grade = scorecard.GradeCritical
+		score.AddComment(
+			"",
+			"The HPA target can not be scaled",
+			fmt.Sprintf(
+				"%s has no scale subresource. Target a Deployment, StatefulSet or ReplicaSet, or add custom resources with a scale subresource to the scalableKinds parameter, e.g. %s",
+				target, "Rollout.argoproj.io",
+			),
+		)
+		return
+	}
+}
+
 func hpaHasMultipleReplicas(
 	options Options,
 ) func(hpa domain.HpaTargeter) (score scorecard.TestScore, err error) {
 	return func(hpa domain.HpaTargeter) (score scorecard.TestScore, err error) {
-		if ptr.Deref(hpa.MinReplicas(), 1) >= 2 {
+		minReplicas, annotationErr := internal.MinReplicas(hpa.GetObjectMeta().Annotations, options.MinReplicas)
+		if annotationErr != nil {
+			score.AddComment("", "Invalid annotation", annotationErr.Error())
+		}
+		if ptr.Deref(hpa.MinReplicas(), 1) >= minReplicas {
 			score.Grade = scorecard.GradeAllOK
 		} else {
 			score.Grade = scorecard.GradeWarning
-			score.AddComment("", "HPA few replicas", "HorizontalPodAutoscalers are recommended to have at least 2 replicas to prevent unwanted downtime.")
+			score.AddComment("", "HPA few replicas", fmt.Sprintf("HorizontalPodAutoscalers are recommended to have at least %d replicas to prevent unwanted downtime.", minReplicas))
 		}
 		return
 	}
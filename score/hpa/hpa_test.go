@@ -151,7 +151,7 @@ func TestHpaHasTarget(t *testing.T) {
 			expectedGrade: scorecard.GradeCritical,
 		},
 
-		// No match (version)
+		// Match (other version of the group)
 		{
 			hpa: v1.HorizontalPodAutoscaler{
 				ObjectMeta: metav1.ObjectMeta{Namespace: "foospace"},
@@ -172,12 +172,36 @@ func TestHpaHasTarget(t *testing.T) {
 					ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "foospace"},
 				},
 			},
+			expectedGrade: scorecard.GradeAllOK,
+		},
+
+		// No match (group)
+		{
+			hpa: v1.HorizontalPodAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "foospace"},
+				Spec: v1.HorizontalPodAutoscalerSpec{
+					ScaleTargetRef: v1.CrossVersionObjectReference{
+						Kind:       "Deployment",
+						Name:       "foo",
+						APIVersion: "example.com/v1",
+					},
+				},
+			},
+			allTargets: []domain.BothMeta{
+				{
+					TypeMeta: metav1.TypeMeta{
+						Kind:       "Deployment",
+						APIVersion: "apps/v1",
+					},
+					ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "foospace"},
+				},
+			},
 			expectedGrade: scorecard.GradeCritical,
 		},
 	}
 
 	for _, tc := range testcases {
-		fn := hpaHasTarget(Options{AllTargetableObjs: tc.allTargets})
+		fn := hpaHasTarget(tc.allTargets, Options{})
 		score, _ := fn(hpav1{tc.hpa})
 		assert.Equal(t, tc.expectedGrade, score.Grade)
 	}
@@ -3,6 +3,8 @@ package score
 import (
 	"testing"
 
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
 	"github.com/romnn/kube-score/scorecard"
 )
 
@@ -36,6 +38,67 @@ func TestHorizontalPodAutoscalerHasNoTarget(t *testing.T) {
 	)
 }
 
+func TestHorizontalPodAutoscalerTargetsOlderAPIVersion(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(
+		t,
+		"hpa-target-older-api-version.yaml",
+		"HorizontalPodAutoscaler has target",
+		scorecard.GradeAllOK,
+	)
+}
+
+func TestHorizontalPodAutoscalerTargetsExtensionsDeployment(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(
+		t,
+		"hpa-target-extensions-deployment.yaml",
+		"HorizontalPodAutoscaler has target",
+		scorecard.GradeAllOK,
+	)
+	testExpectedScore(
+		t,
+		"hpa-target-extensions-deployment.yaml",
+		"HorizontalPodAutoscaler Target Scalable",
+		scorecard.GradeAllOK,
+	)
+}
+
+func TestHorizontalPodAutoscalerTargetsCustomResource(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(
+		t,
+		"hpa-target-custom-resource.yaml",
+		"HorizontalPodAutoscaler has target",
+		scorecard.GradeAllOK,
+	)
+	comments := testExpectedScore(
+		t,
+		"hpa-target-custom-resource.yaml",
+		"HorizontalPodAutoscaler Target Scalable",
+		scorecard.GradeCritical,
+	)
+	if len(comments) != 1 || comments[0].Summary != "The HPA target can not be scaled" {
+		t.Errorf("unexpected comments %+v", comments)
+	}
+}
+
+func TestHorizontalPodAutoscalerTargetsScalableCustomResource(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("hpa-target-custom-resource.yaml")},
+		nil,
+		&config.RunConfiguration{
+			CheckParameters: config.CheckParameters{
+				"horizontalpodautoscaler-target-scalable": {"scalableKinds": []string{"Rollout.argoproj.io"}},
+			},
+		},
+		"HorizontalPodAutoscaler Target Scalable",
+		scorecard.GradeAllOK,
+	)
+}
+
 func TestHorizontalPodAutoscalerMinReplicasOk(t *testing.T) {
 	t.Parallel()
 	testExpectedScore(
@@ -46,6 +109,32 @@ func TestHorizontalPodAutoscalerMinReplicasOk(t *testing.T) {
 	)
 }
 
+func TestHorizontalPodAutoscalerMinReplicasConfigured(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("hpa-min-replicas-ok.yaml")},
+		nil,
+		&config.RunConfiguration{
+			CheckParameters: config.CheckParameters{
+				"horizontalpodautoscaler-replicas": {"minReplicas": 3},
+			},
+		},
+		"HorizontalPodAutoscaler Replicas",
+		scorecard.GradeWarning,
+	)
+}
+
+func TestHorizontalPodAutoscalerMinReplicasAnnotation(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(
+		t,
+		"hpa-min-replicas-annotation.yaml",
+		"HorizontalPodAutoscaler Replicas",
+		scorecard.GradeWarning,
+	)
+}
+
 func TestHorizontalPodAutoscalerMinReplicasNok(t *testing.T) {
 	t.Parallel()
 	testExpectedScore(
@@ -0,0 +1,161 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/score/checks"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+type Options struct {
+	Namespace           ks.DefaultNamespace
+	SkipInitContainers  bool
+	RegistryCredentials map[string]config.RegistryCredential
+	// HTTPClient is used to query the registries, defaults to a client with a timeout
+	HTTPClient *http.Client
+	// VulnerabilityScanner is the scanner binary (trivy or grype) invoked for images
+	// that are not covered by any of the VulnerabilityReports
+	VulnerabilityScanner string
+	VulnerabilityReports []string
+	// PublicRegistries are the registry patterns that images can be pulled from without imagePullSecrets
+	PublicRegistries []string
+}
+
+func Register(allChecks *checks.Checks, options Options) {
+	registry := NewRegistry(options.HTTPClient, options.RegistryCredentials)
+
+	allChecks.RegisterOptionalPodCheck(
+		"Container Image Exists",
+		`Makes sure that the image of all containers exists in the registry, by requesting the manifest of the tag or digest. Credentials for private registries are read from the --registry-auth-file`,
+		containerImageExists(registry, options),
+	)
+	allChecks.RegisterOptionalPodCheck(
+		"Container Image Vulnerabilities",
+		`Makes sure that the images of all containers have no critical vulnerabilities. The images are scanned with the --vulnerability-scanner (trivy or grype), or looked up in the JSON reports given with --vulnerability-report`,
+		containerImageVulnerabilities(
+			NewVulnerabilityScanner(options.VulnerabilityScanner, options.VulnerabilityReports),
+			options,
+		),
+	)
+	allChecks.RegisterOptionalPodContextCheck(
+		"Container Image Pull Secrets",
+		`Makes sure that pods with images from private registries, that are not in the publicRegistries parameter, have imagePullSecrets directly or through their ServiceAccount, and that the pull Secrets exist in the input`,
+		func(ctx *checks.Context, ps ks.PodSpecer) (scorecard.TestScore, error) {
+			serviceAccounts, secrets := pullSecretsIn(ctx, options, ps.GetObjectMeta().Namespace)
+			return containerImagePullSecrets(serviceAccounts, secrets, options)(ps)
+		},
+	)
+}
+
+func containerImageExists(
+	registry *Registry,
+	options Options,
+) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		pod := ps.GetPodTemplateSpec().Spec
+
+		var allContainers []corev1.Container
+		if !options.SkipInitContainers {
+			allContainers = append(allContainers, pod.InitContainers...)
+		}
+		allContainers = append(allContainers, pod.Containers...)
+
+		score.Grade = scorecard.GradeAllOK
+
+		for _, container := range allContainers {
+			exists, queryErr := registry.ManifestExists(context.Background(), container.Image)
+			if queryErr != nil {
+				if score.Grade > scorecard.GradeWarning {
+					score.Grade = scorecard.GradeWarning
+				}
+				score.AddComment(
+					container.Name,
+					"Could not verify that the image exists",
+					fmt.Sprintf("Failed to query the manifest of %s: %s", container.Image, queryErr),
+				)
+				continue
+			}
+			if !exists {
+				score.Grade = scorecard.GradeCritical
+				score.AddComment(
+					container.Name,
+					"The image does not exist",
+					fmt.Sprintf("The registry has no manifest for %s, check the image name and tag for typos", container.Image),
+				)
+			}
+		}
+
+		return
+	}
+}
+
+func containerImageVulnerabilities(
+	scanner VulnerabilityScanner,
+	options Options,
+) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		if options.VulnerabilityScanner == "" && len(options.VulnerabilityReports) == 0 {
+			score.Grade = scorecard.GradeAllOK
+			score.Skipped = true
+			score.AddComment("", "Skipped because no vulnerability scanner or report is configured", "")
+			return
+		}
+
+		pod := ps.GetPodTemplateSpec().Spec
+
+		var allContainers []corev1.Container
+		if !options.SkipInitContainers {
+			allContainers = append(allContainers, pod.InitContainers...)
+		}
+		allContainers = append(allContainers, pod.Containers...)
+
+		score.Grade = scorecard.GradeAllOK
+
+		for _, container := range allContainers {
+			vulns, scanErr := scanner.Scan(context.Background(), container.Image)
+			if errors.Is(scanErr, errNoReport) {
+				score.AddComment(
+					container.Name,
+					"No vulnerability report for the image",
+					fmt.Sprintf("None of the vulnerability reports covers %s", container.Image),
+				)
+				continue
+			}
+			if scanErr != nil {
+				if score.Grade > scorecard.GradeWarning {
+					score.Grade = scorecard.GradeWarning
+				}
+				score.AddComment(
+					container.Name,
+					"Could not scan the image for vulnerabilities",
+					fmt.Sprintf("Failed to scan %s: %s", container.Image, scanErr),
+				)
+				continue
+			}
+
+			for _, vuln := range vulns {
+				if !vuln.isCritical() {
+					continue
+				}
+				score.Grade = scorecard.GradeCritical
+				description := fmt.Sprintf(
+					"%s %s in %s is affected by a critical vulnerability",
+					vuln.Package, vuln.InstalledVersion, container.Image,
+				)
+				if vuln.FixedVersion != "" {
+					description += fmt.Sprintf(", it is fixed in %s", vuln.FixedVersion)
+				}
+				score.AddComment(container.Name, vuln.ID, description)
+			}
+		}
+
+		return
+	}
+}
@@ -0,0 +1,157 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/config"
+	"github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func TestParseReference(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		image    string
+		expected reference
+	}{
+		{"nginx", reference{"docker.io", "library/nginx", "latest"}},
+		{"nginx:1.25", reference{"docker.io", "library/nginx", "1.25"}},
+		{"bitnami/redis:7.2", reference{"docker.io", "bitnami/redis", "7.2"}},
+		{"docker.io/library/nginx:1.25", reference{"docker.io", "library/nginx", "1.25"}},
+		{"index.docker.io/nginx", reference{"docker.io", "library/nginx", "latest"}},
+		{"ghcr.io/org/app:v1.0.0", reference{"ghcr.io", "org/app", "v1.0.0"}},
+		{"localhost:5000/app", reference{"localhost:5000", "app", "latest"}},
+		{"localhost/app:dev", reference{"localhost", "app", "dev"}},
+		{"nginx@sha256:abc", reference{"docker.io", "library/nginx", "sha256:abc"}},
+		{"nginx:1.25@sha256:abc", reference{"docker.io", "library/nginx", "sha256:abc"}},
+	}
+
+	for _, tc := range cases {
+		ref, err := parseReference(tc.image)
+		assert.NoError(t, err, tc.image)
+		assert.Equal(t, tc.expected, ref, tc.image)
+	}
+}
+
+func TestContainerImageExists(t *testing.T) {
+	t.Parallel()
+
+	var manifestRequests int
+	var srv *httptest.Server
+	srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			user, pass, ok := r.BasicAuth()
+			if !ok || user != "user" || pass != "secret" ||
+				r.URL.Query().Get("scope") != "repository:org/app:pull" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": "t0ken"})
+		case strings.HasPrefix(r.URL.Path, "/v2/org/app/manifests/"):
+			if r.Header.Get("Authorization") != "Bearer t0ken" {
+				w.Header().Set(
+					"WWW-Authenticate",
+					`Bearer realm="`+srv.URL+`/token",service="test"`,
+				)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			manifestRequests++
+			if strings.HasSuffix(r.URL.Path, "/v1.0.0") {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	host := strings.TrimPrefix(srv.URL, "https://")
+	registry := NewRegistry(srv.Client(), map[string]config.RegistryCredential{
+		host: {Username: "user", Password: "secret"},
+	})
+	fn := containerImageExists(registry, Options{})
+
+	cases := []struct {
+		image    string
+		expected scorecard.Grade
+	}{
+		{host + "/org/app:v1.0.0", scorecard.GradeAllOK},
+		{host + "/org/app:v1.0.O", scorecard.GradeCritical},
+		{host + "/org/app:v1.0.0", scorecard.GradeAllOK},
+		{host + "/org/other:v1.0.0", scorecard.GradeWarning},
+	}
+
+	for _, tc := range cases {
+		score, err := fn(&podSpeccer{spec: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "app", Image: tc.image}},
+			},
+		}})
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, score.Grade, tc.image)
+	}
+
+	// Every unique image is only queried once
+	assert.Equal(t, 2, manifestRequests)
+}
+
+func TestRegistryDoesNotCacheErrors(t *testing.T) {
+	t.Parallel()
+
+	var manifestRequests int
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		manifestRequests++
+		if manifestRequests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	image := strings.TrimPrefix(srv.URL, "https://") + "/org/app:v1.0.0"
+	registry := NewRegistry(srv.Client(), nil)
+
+	_, err := registry.ManifestExists(context.Background(), image)
+	assert.Error(t, err)
+
+	for range 2 {
+		exists, err := registry.ManifestExists(context.Background(), image)
+		assert.NoError(t, err)
+		assert.True(t, exists)
+	}
+	assert.Equal(t, 2, manifestRequests)
+}
+
+type podSpeccer struct {
+	spec corev1.PodTemplateSpec
+}
+
+func (p *podSpeccer) GetTypeMeta() metav1.TypeMeta {
+	return metav1.TypeMeta{}
+}
+
+func (p *podSpeccer) GetObjectMeta() metav1.ObjectMeta {
+	return metav1.ObjectMeta{}
+}
+
+func (p *podSpeccer) GetPodTemplateSpec() corev1.PodTemplateSpec {
+	return p.spec
+}
+
+func (p *podSpeccer) FileLocation() domain.FileLocation {
+	return domain.FileLocation{}
+}
@@ -0,0 +1,108 @@
+package image
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+)
+
+// InventoryImage is a unique container image of the input
+type InventoryImage struct {
+	Image      string `json:"image"`
+	Registry   string `json:"registry"`
+	Repository string `json:"repository"`
+	Tag        string `json:"tag,omitempty"`
+	Digest     string `json:"digest,omitempty"`
+	// Workloads is the number of objects with a container of the image
+	Workloads int `json:"workloads"`
+}
+
+// InventoryRegistry is a registry that images of the input are pulled from
+type InventoryRegistry struct {
+	Registry string `json:"registry"`
+	Images   int    `json:"images"`
+}
+
+// Inventory lists the container images of the input
+type Inventory struct {
+	Images     []InventoryImage    `json:"images"`
+	Registries []InventoryRegistry `json:"registries"`
+}
+
+// NewInventory lists every unique image of the containers, init containers and ephemeral
+// containers of all workloads and pods, sorted by image. Images that can not be parsed are
+// listed with an empty registry.
+func NewInventory(all ks.AllTypes) Inventory {
+	workloads := make(map[string]map[string]bool)
+	add := func(typeMeta metav1.TypeMeta, objectMeta metav1.ObjectMeta, spec corev1.PodSpec) {
+		workload := typeMeta.Kind + "/" + objectMeta.Namespace + "/" + objectMeta.Name
+		for _, image := range podImages(spec) {
+			if workloads[image] == nil {
+				workloads[image] = make(map[string]bool)
+			}
+			workloads[image][workload] = true
+		}
+	}
+	for _, p := range all.PodSpeccers() {
+		add(p.GetTypeMeta(), p.GetObjectMeta(), p.GetPodTemplateSpec().Spec)
+	}
+	for _, p := range all.Pods() {
+		pod := p.Pod()
+		add(pod.TypeMeta, pod.ObjectMeta, pod.Spec)
+	}
+
+	inventory := Inventory{Images: []InventoryImage{}, Registries: []InventoryRegistry{}}
+	registries := make(map[string]int)
+	for image, objects := range workloads {
+		item := InventoryImage{Image: image, Workloads: len(objects)}
+		if ref, err := parseReference(image); err == nil {
+			item.Registry = ref.registry
+			item.Repository = ref.repository
+			item.Tag, item.Digest = tagAndDigest(image)
+			registries[ref.registry]++
+		}
+		inventory.Images = append(inventory.Images, item)
+	}
+	slices.SortFunc(inventory.Images, func(a, b InventoryImage) int { return cmp.Compare(a.Image, b.Image) })
+
+	for registry, images := range registries {
+		inventory.Registries = append(inventory.Registries, InventoryRegistry{Registry: registry, Images: images})
+	}
+	slices.SortFunc(inventory.Registries, func(a, b InventoryRegistry) int {
+		return cmp.Compare(a.Registry, b.Registry)
+	})
+	return inventory
+}
+
+func podImages(spec corev1.PodSpec) []string {
+	var images []string
+	for _, c := range spec.InitContainers {
+		images = append(images, c.Image)
+	}
+	for _, c := range spec.Containers {
+		images = append(images, c.Image)
+	}
+	for _, c := range spec.EphemeralContainers {
+		images = append(images, c.Image)
+	}
+	return slices.DeleteFunc(images, func(image string) bool { return image == "" })
+}
+
+// tagAndDigest returns the tag and digest of the image as written, the tag defaults to latest
+// when neither is set
+func tagAndDigest(image string) (tag, digest string) {
+	name, digest, _ := strings.Cut(image, "@")
+	// Tags can only be in the last component, a colon before that is a port
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		tag = name[i+1:]
+	}
+	if tag == "" && digest == "" {
+		tag = "latest"
+	}
+	return tag, digest
+}
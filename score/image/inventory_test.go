@@ -0,0 +1,76 @@
+package image
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/parser"
+)
+
+type namedReader struct {
+	io.Reader
+	name string
+}
+
+func (n namedReader) Name() string {
+	return n.name
+}
+
+func TestNewInventory(t *testing.T) {
+	t.Parallel()
+	manifests := `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      initContainers:
+        - name: migrate
+          image: ghcr.io/example/app:v1.2.3
+      containers:
+        - name: app
+          image: ghcr.io/example/app:v1.2.3
+        - name: proxy
+          image: nginx
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+spec:
+  containers:
+    - name: debug
+      image: registry.example.com:5000/tools/debug@sha256:abc
+    - name: proxy
+      image: nginx
+`
+	p, err := parser.New(nil)
+	assert.NoError(t, err)
+	all, err := p.ParseFiles([]domain.NamedReader{namedReader{strings.NewReader(manifests), "app.yaml"}})
+	assert.NoError(t, err)
+
+	assert.Equal(t, Inventory{
+		Images: []InventoryImage{
+			{Image: "ghcr.io/example/app:v1.2.3", Registry: "ghcr.io", Repository: "example/app", Tag: "v1.2.3", Workloads: 1},
+			{Image: "nginx", Registry: "docker.io", Repository: "library/nginx", Tag: "latest", Workloads: 2},
+			{
+				Image:      "registry.example.com:5000/tools/debug@sha256:abc",
+				Registry:   "registry.example.com:5000",
+				Repository: "tools/debug",
+				Digest:     "sha256:abc",
+				Workloads:  1,
+			},
+		},
+		Registries: []InventoryRegistry{
+			{Registry: "docker.io", Images: 1},
+			{Registry: "ghcr.io", Images: 1},
+			{Registry: "registry.example.com:5000", Images: 1},
+		},
+	}, NewInventory(all))
+}
@@ -0,0 +1,134 @@
+package image
+
+import (
+	"fmt"
+	"path"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/score/checks"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// serviceAccountsKey and secretsKey are the keys of the indexes of the ServiceAccounts and Secrets
+// by namespace
+type (
+	serviceAccountsKey struct{ namespace ks.DefaultNamespace }
+	secretsKey         struct{ namespace ks.DefaultNamespace }
+)
+
+// pullSecretsIn returns the ServiceAccounts and Secrets of the run in the namespace
+func pullSecretsIn(ctx *checks.Context, options Options, namespace string) ([]ks.ServiceAccount, []ks.Secret) {
+	serviceAccounts := checks.Index(ctx, serviceAccountsKey{options.Namespace}, func(all ks.AllTypes) map[string][]ks.ServiceAccount {
+		return checks.ByNamespace(all.ServiceAccounts(), options.Namespace, func(s ks.ServiceAccount) metav1.ObjectMeta {
+			return s.ServiceAccount().ObjectMeta
+		})
+	})
+	secrets := checks.Index(ctx, secretsKey{options.Namespace}, func(all ks.AllTypes) map[string][]ks.Secret {
+		return checks.ByNamespace(all.Secrets(), options.Namespace, func(s ks.Secret) metav1.ObjectMeta {
+			return s.Secret().ObjectMeta
+		})
+	})
+	namespace = options.Namespace.Of(namespace)
+	return serviceAccounts[namespace], secrets[namespace]
+}
+
+// containerImagePullSecrets makes sure that pods with images from private registries have
+// imagePullSecrets, directly or through their ServiceAccount, and that the Secrets exist
+func containerImagePullSecrets(
+	serviceAccounts []ks.ServiceAccount,
+	secrets []ks.Secret,
+	options Options,
+) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		pod := ps.GetPodTemplateSpec().Spec
+
+		namespace := options.Namespace.Of(ps.GetObjectMeta().Namespace)
+
+		pullSecrets := pod.ImagePullSecrets
+		serviceAccountName := pod.ServiceAccountName
+		if serviceAccountName == "" {
+			serviceAccountName = "default"
+		}
+		for _, sa := range serviceAccounts {
+			obj := sa.ServiceAccount()
+			if obj.Name == serviceAccountName {
+				pullSecrets = append(pullSecrets, obj.ImagePullSecrets...)
+			}
+		}
+
+		var allContainers []corev1.Container
+		if !options.SkipInitContainers {
+			allContainers = append(allContainers, pod.InitContainers...)
+		}
+		allContainers = append(allContainers, pod.Containers...)
+
+		score.Grade = scorecard.GradeAllOK
+
+		if len(pullSecrets) == 0 {
+			for _, container := range allContainers {
+				ref, err := parseReference(container.Image)
+				if err != nil || isPublicRegistry(ref.registry, options.PublicRegistries) {
+					continue
+				}
+				score.Grade = scorecard.GradeCritical
+				score.AddComment(
+					container.Name,
+					"The image is from a private registry without imagePullSecrets",
+					fmt.Sprintf(
+						"Set imagePullSecrets on the pod or its ServiceAccount %s to pull images from %s",
+						serviceAccountName, ref.registry,
+					),
+				)
+			}
+			return
+		}
+
+		for _, ref := range pullSecrets {
+			found := false
+			for _, s := range secrets {
+				obj := s.Secret()
+				if obj.Name != ref.Name {
+					continue
+				}
+				found = true
+				if obj.Type != corev1.SecretTypeDockerConfigJson && obj.Type != corev1.SecretTypeDockercfg {
+					score.Grade = scorecard.GradeWarning
+					score.AddComment(
+						"",
+						"The image pull Secret has the wrong type",
+						fmt.Sprintf(
+							"The Secret %s has the type %q, image pull Secrets need the type %s",
+							ref.Name, obj.Type, corev1.SecretTypeDockerConfigJson,
+						),
+					)
+				}
+			}
+			if !found {
+				score.Grade = scorecard.GradeWarning
+				score.AddComment(
+					"",
+					"The image pull Secret was not found",
+					fmt.Sprintf(
+						"No Secret %s was found in the namespace %s of the input, make sure that it is created",
+						ref.Name, namespace,
+					),
+				)
+			}
+		}
+
+		return
+	}
+}
+
+// isPublicRegistry returns true if the registry matches any of the patterns
+func isPublicRegistry(registry string, publicRegistries []string) bool {
+	return slices.ContainsFunc(publicRegistries, func(pattern string) bool {
+		matched, _ := path.Match(config.NormalizeRegistryHost(pattern), registry)
+		return matched
+	})
+}
@@ -0,0 +1,272 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/romnn/kube-score/config"
+)
+
+const dockerHub = "docker.io"
+
+var manifestMediaTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+	"application/vnd.docker.distribution.manifest.v1+prettyjws",
+}
+
+// reference is a parsed container image reference, e.g. "ghcr.io/org/app:v1.0.0"
+type reference struct {
+	registry   string
+	repository string
+	// tag or digest
+	reference string
+}
+
+func parseReference(image string) (reference, error) {
+	if image == "" {
+		return reference{}, errors.New("empty image reference")
+	}
+
+	var ref reference
+	name := image
+	if n, digest, ok := strings.Cut(image, "@"); ok {
+		name = n
+		ref.reference = digest
+	}
+
+	// The first component is a registry if it looks like a host
+	ref.registry = dockerHub
+	if first, rest, ok := strings.Cut(name, "/"); ok &&
+		(strings.ContainsAny(first, ".:") || first == "localhost") {
+		ref.registry = config.NormalizeRegistryHost(first)
+		name = rest
+	}
+
+	// Tags can only be in the last component, a colon before that is a port
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		if ref.reference == "" {
+			ref.reference = name[i+1:]
+		}
+		name = name[:i]
+	}
+	if ref.reference == "" {
+		ref.reference = "latest"
+	}
+
+	if name == "" {
+		return reference{}, fmt.Errorf("invalid image reference %q", image)
+	}
+	if ref.registry == dockerHub && !strings.Contains(name, "/") {
+		name = "library/" + name
+	}
+	ref.repository = name
+	return ref, nil
+}
+
+// apiHost returns the host serving the registry API
+func (r reference) apiHost() string {
+	if r.registry == dockerHub {
+		return "registry-1.docker.io"
+	}
+	return r.registry
+}
+
+// Registry checks if image manifests exist by querying the registry API.
+// Results are cached, so that every unique image is only queried once. Concurrent queries of the
+// same image share one request, and errors are not cached, so that the image is queried again.
+type Registry struct {
+	client      *http.Client
+	credentials map[string]config.RegistryCredential
+
+	group singleflight.Group
+	mu    sync.Mutex
+	cache map[string]bool
+}
+
+func NewRegistry(client *http.Client, credentials map[string]config.RegistryCredential) *Registry {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Registry{
+		client:      client,
+		credentials: credentials,
+		cache:       make(map[string]bool),
+	}
+}
+
+// ManifestExists returns true if the registry has a manifest for the image
+func (r *Registry) ManifestExists(ctx context.Context, image string) (bool, error) {
+	r.mu.Lock()
+	exists, ok := r.cache[image]
+	r.mu.Unlock()
+	if ok {
+		return exists, nil
+	}
+
+	res, err, _ := r.group.Do(image, func() (any, error) {
+		exists, err := r.manifestExists(ctx, image)
+		if err != nil {
+			return false, err
+		}
+		r.mu.Lock()
+		r.cache[image] = exists
+		r.mu.Unlock()
+		return exists, nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return res.(bool), nil
+}
+
+func (r *Registry) manifestExists(ctx context.Context, image string) (bool, error) {
+	ref, err := parseReference(image)
+	if err != nil {
+		return false, err
+	}
+
+	manifestURL := fmt.Sprintf(
+		"https://%s/v2/%s/manifests/%s",
+		ref.apiHost(), ref.repository, ref.reference,
+	)
+
+	resp, err := r.headManifest(ctx, manifestURL, "")
+	if err != nil {
+		return false, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		authorization, err := r.authorize(ctx, ref, resp.Header.Get("WWW-Authenticate"))
+		if err != nil {
+			return false, err
+		}
+		resp, err = r.headManifest(ctx, manifestURL, authorization)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unexpected response from %s: %s", ref.registry, resp.Status)
+	}
+}
+
+func (r *Registry) headManifest(
+	ctx context.Context,
+	manifestURL, authorization string,
+) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, manifestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", strings.Join(manifestMediaTypes, ", "))
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return resp, nil
+}
+
+// authorize returns the value of the Authorization header for the challenge of the registry
+func (r *Registry) authorize(ctx context.Context, ref reference, challenge string) (string, error) {
+	cred, hasCred := r.credentials[ref.registry]
+
+	scheme, params, _ := strings.Cut(challenge, " ")
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if !hasCred {
+			return "", fmt.Errorf("%s requires credentials", ref.registry)
+		}
+		req, err := http.NewRequest(http.MethodGet, "/", nil)
+		if err != nil {
+			return "", err
+		}
+		req.SetBasicAuth(cred.Username, cred.Password)
+		return req.Header.Get("Authorization"), nil
+
+	case "bearer":
+		attrs := parseChallengeParams(params)
+		realm, err := url.Parse(attrs["realm"])
+		if err != nil || attrs["realm"] == "" {
+			return "", fmt.Errorf("invalid authentication challenge from %s", ref.registry)
+		}
+		query := realm.Query()
+		if service := attrs["service"]; service != "" {
+			query.Set("service", service)
+		}
+		query.Set("scope", fmt.Sprintf("repository:%s:pull", ref.repository))
+		realm.RawQuery = query.Encode()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+		if err != nil {
+			return "", err
+		}
+		if hasCred {
+			req.SetBasicAuth(cred.Username, cred.Password)
+		}
+		resp, err := r.client.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("failed to get token for %s: %s", ref.registry, resp.Status)
+		}
+
+		var token struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+			return "", fmt.Errorf("failed to decode token for %s: %w", ref.registry, err)
+		}
+		if token.Token == "" {
+			token.Token = token.AccessToken
+		}
+		return "Bearer " + token.Token, nil
+
+	default:
+		return "", fmt.Errorf("unsupported authentication scheme %q from %s", scheme, ref.registry)
+	}
+}
+
+// parseChallengeParams parses the parameters of a WWW-Authenticate header,
+// e.g. `realm="https://auth.docker.io/token",service="registry.docker.io"`
+func parseChallengeParams(params string) map[string]string {
+	attrs := make(map[string]string)
+	for params != "" {
+		var key, value string
+		key, params, _ = strings.Cut(params, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if strings.HasPrefix(params, `"`) {
+			value, params, _ = strings.Cut(params[1:], `"`)
+			params = strings.TrimPrefix(params, ",")
+		} else {
+			value, params, _ = strings.Cut(params, ",")
+		}
+		attrs[key] = value
+	}
+	return attrs
+}
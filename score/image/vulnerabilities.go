@@ -0,0 +1,213 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+const (
+	ScannerTrivy = "trivy"
+	ScannerGrype = "grype"
+)
+
+// Vulnerability is a single vulnerability found in an image
+type Vulnerability struct {
+	ID               string
+	Severity         string
+	Package          string
+	InstalledVersion string
+	FixedVersion     string
+}
+
+func (v Vulnerability) isCritical() bool {
+	return strings.EqualFold(v.Severity, "critical")
+}
+
+// VulnerabilityScanner returns the vulnerabilities of an image
+type VulnerabilityScanner interface {
+	Scan(ctx context.Context, image string) ([]Vulnerability, error)
+}
+
+// NewVulnerabilityScanner returns a scanner that consumes the given JSON reports of Trivy or Grype.
+// If no report covers an image and scanner is set, the scanner binary is invoked instead.
+func NewVulnerabilityScanner(scanner string, reports []string) VulnerabilityScanner {
+	return &cachedScanner{
+		scanner: scanner,
+		reports: reports,
+		cache:   make(map[string]scanResult),
+	}
+}
+
+type scanResult struct {
+	vulnerabilities []Vulnerability
+	err             error
+}
+
+type cachedScanner struct {
+	scanner string
+	reports []string
+
+	loadReports sync.Once
+	reportsErr  error
+	fromReports map[string][]Vulnerability
+
+	mu    sync.Mutex
+	cache map[string]scanResult
+}
+
+func (s *cachedScanner) Scan(ctx context.Context, image string) ([]Vulnerability, error) {
+	s.loadReports.Do(func() {
+		s.fromReports, s.reportsErr = loadVulnerabilityReports(s.reports)
+	})
+	if s.reportsErr != nil {
+		return nil, s.reportsErr
+	}
+	if vulns, ok := s.fromReports[image]; ok {
+		return vulns, nil
+	}
+	if s.scanner == "" {
+		return nil, errNoReport
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if res, ok := s.cache[image]; ok {
+		return res.vulnerabilities, res.err
+	}
+	vulns, err := runScanner(ctx, s.scanner, image)
+	s.cache[image] = scanResult{vulnerabilities: vulns, err: err}
+	return vulns, err
+}
+
+var errNoReport = errors.New("no vulnerability report for the image")
+
+func runScanner(ctx context.Context, scanner, image string) ([]Vulnerability, error) {
+	var args []string
+	switch scanner {
+	case ScannerTrivy:
+		args = []string{"image", "--quiet", "--format", "json", image}
+	case ScannerGrype:
+		args = []string{image, "--quiet", "--output", "json"}
+	default:
+		return nil, fmt.Errorf("unknown vulnerability scanner %q", scanner)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, scanner, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s failed: %w: %s", scanner, err, strings.TrimSpace(stderr.String()))
+	}
+
+	_, vulns, err := parseVulnerabilityReport(stdout.Bytes())
+	return vulns, err
+}
+
+func loadVulnerabilityReports(paths []string) (map[string][]Vulnerability, error) {
+	reports := make(map[string][]Vulnerability)
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read vulnerability report: %w", err)
+		}
+		image, vulns, err := parseVulnerabilityReport(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse vulnerability report %s: %w", path, err)
+		}
+		reports[image] = append(reports[image], vulns...)
+	}
+	return reports, nil
+}
+
+type trivyReport struct {
+	ArtifactName string `json:"ArtifactName"`
+	Results      []struct {
+		Vulnerabilities []struct {
+			VulnerabilityID  string `json:"VulnerabilityID"`
+			PkgName          string `json:"PkgName"`
+			InstalledVersion string `json:"InstalledVersion"`
+			FixedVersion     string `json:"FixedVersion"`
+			Severity         string `json:"Severity"`
+		} `json:"Vulnerabilities"`
+	} `json:"Results"`
+}
+
+type grypeReport struct {
+	Source struct {
+		Target struct {
+			UserInput string `json:"userInput"`
+		} `json:"target"`
+	} `json:"source"`
+	Matches []struct {
+		Vulnerability struct {
+			ID       string `json:"id"`
+			Severity string `json:"severity"`
+			Fix      struct {
+				Versions []string `json:"versions"`
+			} `json:"fix"`
+		} `json:"vulnerability"`
+		Artifact struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"artifact"`
+	} `json:"matches"`
+}
+
+// parseVulnerabilityReport parses a JSON report of Trivy or Grype, and returns the scanned image
+func parseVulnerabilityReport(data []byte) (string, []Vulnerability, error) {
+	var format struct {
+		ArtifactName *string         `json:"ArtifactName"`
+		Matches      json.RawMessage `json:"matches"`
+	}
+	if err := json.Unmarshal(data, &format); err != nil {
+		return "", nil, err
+	}
+
+	var vulns []Vulnerability
+	switch {
+	case format.ArtifactName != nil:
+		var report trivyReport
+		if err := json.Unmarshal(data, &report); err != nil {
+			return "", nil, err
+		}
+		for _, res := range report.Results {
+			for _, v := range res.Vulnerabilities {
+				vulns = append(vulns, Vulnerability{
+					ID:               v.VulnerabilityID,
+					Severity:         v.Severity,
+					Package:          v.PkgName,
+					InstalledVersion: v.InstalledVersion,
+					FixedVersion:     v.FixedVersion,
+				})
+			}
+		}
+		return report.ArtifactName, vulns, nil
+
+	case format.Matches != nil:
+		var report grypeReport
+		if err := json.Unmarshal(data, &report); err != nil {
+			return "", nil, err
+		}
+		for _, m := range report.Matches {
+			vulns = append(vulns, Vulnerability{
+				ID:               m.Vulnerability.ID,
+				Severity:         m.Vulnerability.Severity,
+				Package:          m.Artifact.Name,
+				InstalledVersion: m.Artifact.Version,
+				FixedVersion:     strings.Join(m.Vulnerability.Fix.Versions, ", "),
+			})
+		}
+		return report.Source.Target.UserInput, vulns, nil
+
+	default:
+		return "", nil, errors.New("unknown report format, expected a JSON report of trivy or grype")
+	}
+}
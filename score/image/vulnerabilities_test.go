@@ -0,0 +1,103 @@
+package image
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/romnn/kube-score/scorecard"
+)
+
+const trivyReportJSON = `{
+	"ArtifactName": "nginx:1.25",
+	"Results": [{
+		"Target": "nginx:1.25 (debian 12.1)",
+		"Vulnerabilities": [
+			{"VulnerabilityID": "CVE-2023-0001", "PkgName": "libssl3", "InstalledVersion": "3.0.9", "FixedVersion": "3.0.11", "Severity": "CRITICAL"},
+			{"VulnerabilityID": "CVE-2023-0002", "PkgName": "zlib1g", "InstalledVersion": "1.2.13", "Severity": "LOW"}
+		]
+	}]
+}`
+
+const grypeReportJSON = `{
+	"source": {"type": "image", "target": {"userInput": "redis:7.2"}},
+	"matches": [
+		{"vulnerability": {"id": "CVE-2023-0003", "severity": "Medium", "fix": {"versions": []}}, "artifact": {"name": "libc6", "version": "2.36"}}
+	]
+}`
+
+func TestParseVulnerabilityReport(t *testing.T) {
+	t.Parallel()
+
+	image, vulns, err := parseVulnerabilityReport([]byte(trivyReportJSON))
+	assert.NoError(t, err)
+	assert.Equal(t, "nginx:1.25", image)
+	assert.Equal(t, []Vulnerability{
+		{ID: "CVE-2023-0001", Severity: "CRITICAL", Package: "libssl3", InstalledVersion: "3.0.9", FixedVersion: "3.0.11"},
+		{ID: "CVE-2023-0002", Severity: "LOW", Package: "zlib1g", InstalledVersion: "1.2.13"},
+	}, vulns)
+
+	image, vulns, err = parseVulnerabilityReport([]byte(grypeReportJSON))
+	assert.NoError(t, err)
+	assert.Equal(t, "redis:7.2", image)
+	assert.Equal(t, []Vulnerability{
+		{ID: "CVE-2023-0003", Severity: "Medium", Package: "libc6", InstalledVersion: "2.36"},
+	}, vulns)
+
+	_, _, err = parseVulnerabilityReport([]byte(`{}`))
+	assert.Error(t, err)
+}
+
+func TestContainerImageVulnerabilities(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	trivyPath := filepath.Join(dir, "trivy.json")
+	grypePath := filepath.Join(dir, "grype.json")
+	assert.NoError(t, os.WriteFile(trivyPath, []byte(trivyReportJSON), 0o600))
+	assert.NoError(t, os.WriteFile(grypePath, []byte(grypeReportJSON), 0o600))
+
+	options := Options{VulnerabilityReports: []string{trivyPath, grypePath}}
+	fn := containerImageVulnerabilities(
+		NewVulnerabilityScanner(options.VulnerabilityScanner, options.VulnerabilityReports),
+		options,
+	)
+
+	cases := []struct {
+		image            string
+		expected         scorecard.Grade
+		expectedComments []string
+	}{
+		{"nginx:1.25", scorecard.GradeCritical, []string{"CVE-2023-0001"}},
+		{"redis:7.2", scorecard.GradeAllOK, nil},
+		{"postgres:16", scorecard.GradeAllOK, []string{"No vulnerability report for the image"}},
+	}
+
+	for _, tc := range cases {
+		score, err := fn(&podSpeccer{spec: corev1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "app", Image: tc.image}},
+			},
+		}})
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, score.Grade, tc.image)
+
+		var summaries []string
+		for _, c := range score.Comments {
+			summaries = append(summaries, c.Summary)
+		}
+		assert.Equal(t, tc.expectedComments, summaries, tc.image)
+	}
+}
+
+func TestContainerImageVulnerabilitiesSkipped(t *testing.T) {
+	t.Parallel()
+
+	fn := containerImageVulnerabilities(NewVulnerabilityScanner("", nil), Options{})
+	score, err := fn(&podSpeccer{})
+	assert.NoError(t, err)
+	assert.True(t, score.Skipped)
+}
@@ -2,6 +2,10 @@ package ingress
 
 import (
 	"fmt"
+	"strings"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	ks "github.com/romnn/kube-score/domain"
 	"github.com/romnn/kube-score/score/checks"
@@ -9,17 +13,124 @@ import (
 )
 
 type Options struct {
-	Namespace string
+	Namespace ks.DefaultNamespace
 }
 
-func Register(allChecks *checks.Checks, services ks.Services, options Options) {
-	allChecks.RegisterIngressCheck(
+func Register(allChecks *checks.Checks, options Options) {
+	allChecks.RegisterIngressContextCheck(
 		"Ingress targets Service",
 		`Makes sure that the Ingress targets a Service`,
-		ingressTargetsService(services.Services(), options),
+		func(ctx *checks.Context, ingress ks.Ingress) (scorecard.TestScore, error) {
+			return ingressTargetsService(servicesIn(ctx, options, ingress.GetObjectMeta().Namespace), options)(ingress)
+		},
+	)
+	allChecks.RegisterIngressContextCheck(
+		"Ingress has no conflicts",
+		`Makes sure that no other Ingress of the same class routes the same host and path to a different backend, or uses a different TLS secret for the same host`,
+		func(ctx *checks.Context, ingress ks.Ingress) (scorecard.TestScore, error) {
+			ingresses := checks.Index(ctx, classesKey{}, func(all ks.AllTypes) map[string][]ks.Ingress {
+				byClass := make(map[string][]ks.Ingress)
+				for _, i := range all.Ingresses() {
+					byClass[i.IngressClassName()] = append(byClass[i.IngressClassName()], i)
+				}
+				return byClass
+			})
+			return ingressHasNoConflicts(ingresses[ingress.IngressClassName()], options)(ingress)
+		},
+	)
+	allChecks.RegisterIngressCheck(
+		"Ingress PathType",
+		`Makes sure that all Ingress paths set an explicit pathType of Prefix or Exact, and that regular expressions are not used with Prefix`,
+		ingressPathType,
+	)
+	allChecks.RegisterOptionalIngressContextCheck(
+		"Ingress TLS certificate",
+		`Makes sure that the TLS secrets of the Ingress are issued by cert-manager, by a Certificate in the input or by the ingress-shim annotations`,
+		func(ctx *checks.Context, ingress ks.Ingress) (scorecard.TestScore, error) {
+			certificates := checks.Index(ctx, certificatesKey{options.Namespace},
+				func(all ks.AllTypes) map[secretRef]bool {
+					return certificateSecrets(all.Certificates(), options)
+				},
+			)
+			return ingressTLSCertificate(certificates, options)(ingress)
+		},
 	)
 }
 
+// servicesKey is the key of the index of the Services by namespace
+type servicesKey struct{ namespace ks.DefaultNamespace }
+
+// servicesIn returns the Services of the run in the namespace
+func servicesIn(ctx *checks.Context, options Options, namespace string) []ks.Service {
+	return checks.Index(ctx, servicesKey{options.Namespace}, func(all ks.AllTypes) map[string][]ks.Service {
+		return checks.ByNamespace(all.Services(), options.Namespace, func(s ks.Service) metav1.ObjectMeta {
+			return s.Service().ObjectMeta
+		})
+	})[options.Namespace.Of(namespace)]
+}
+
+// classesKey is the key of the index of the Ingresses by class, as the hosts of an ingress
+// controller are not scoped to a namespace
+type classesKey struct{}
+
+// ingressShimAnnotations are the annotations with which the ingress-shim of cert-manager creates the
+// Certificates of the TLS secrets of an Ingress
+var ingressShimAnnotations = []string{
+	"cert-manager.io/issuer",
+	"cert-manager.io/cluster-issuer",
+	"kubernetes.io/tls-acme",
+}
+
+// certificatesKey is the key of the index of the Secrets of the Certificates
+type certificatesKey struct{ namespace ks.DefaultNamespace }
+
+// secretRef is the namespace and name of a Secret
+type secretRef struct {
+	namespace string
+	name      string
+}
+
+func certificateSecrets(certificates []ks.Certificate, options Options) map[secretRef]bool {
+	secrets := make(map[secretRef]bool)
+	for _, certificate := range certificates {
+		namespace := options.Namespace.Of(certificate.GetObjectMeta().Namespace)
+		secrets[secretRef{namespace, certificate.SecretName()}] = true
+	}
+	return secrets
+}
+
+// ingressTLSCertificate checks that every TLS secret of the Ingress has an issuer
+func ingressTLSCertificate(
+	certificates map[secretRef]bool,
+	options Options,
+) func(ks.Ingress) (scorecard.TestScore, error) {
+	return func(ingress ks.Ingress) (score scorecard.TestScore, err error) {
+		score.Grade = scorecard.GradeAllOK
+		annotations := ingress.GetObjectMeta().Annotations
+		for _, annotation := range ingressShimAnnotations {
+			if annotations[annotation] != "" {
+				return
+			}
+		}
+
+		namespace := ingressNamespace(ingress, options)
+		for _, tls := range ingress.TLS() {
+			// Without a secretName, the default certificate of the ingress controller is used
+			if tls.SecretName == "" || certificates[secretRef{namespace, tls.SecretName}] {
+				continue
+			}
+			score.Grade = scorecard.GradeWarning
+			score.AddComment(
+				tls.SecretName,
+				"The TLS secret is not issued by a Certificate",
+				"The certificate in the secret is not renewed automatically. Add a cert-manager Certificate with the secretName, "+
+					"or the cert-manager.io/issuer or cert-manager.io/cluster-issuer annotation to the Ingress.",
+			)
+		}
+		return
+	}
+}
+
 func ingressTargetsService(
 	allServices []ks.Service,
 	options Options,
@@ -47,14 +158,8 @@ func ingressTargetsServiceCommon(
 			for _, srv := range allServices {
 				service := srv.Service()
 
-				serviceNamespace := service.Namespace
-				if serviceNamespace == "" {
-					serviceNamespace = options.Namespace
-				}
-				ingressNamespace := ingress.GetObjectMeta().Namespace
-				if ingressNamespace == "" {
-					ingressNamespace = options.Namespace
-				}
+				serviceNamespace := options.Namespace.Of(service.Namespace)
+				ingressNamespace := options.Namespace.Of(ingress.GetObjectMeta().Namespace)
 
 				if serviceNamespace != ingressNamespace {
 					continue
@@ -114,3 +219,165 @@ func ingressTargetsServiceCommon(
 
 	return
 }
+
+// ingressHasNoConflicts checks that the hosts and paths of the Ingress are not claimed by
+// another Ingress of the same class. Ingress controllers are cluster wide, so Ingresses in
+// all namespaces are taken into account.
+func ingressHasNoConflicts(
+	allIngresses []ks.Ingress,
+	options Options,
+) func(ks.Ingress) (scorecard.TestScore, error) {
+	return func(ingress ks.Ingress) (score scorecard.TestScore, err error) {
+		score.Grade = scorecard.GradeAllOK
+
+		self := ingressRef(ingress, options)
+		for _, other := range allIngresses {
+			if ingressRef(other, options) == self ||
+				other.IngressClassName() != ingress.IngressClassName() {
+				continue
+			}
+
+			for _, rule := range ingress.Rules() {
+				if rule.HTTP == nil {
+					continue
+				}
+				for _, path := range rule.HTTP.Paths {
+					otherBackend, ok := findBackend(other, rule.Host, path.Path, options)
+					if !ok || otherBackend == backendRef(ingress, path.Backend, options) {
+						continue
+					}
+					score.Grade = scorecard.GradeCritical
+					score.AddComment(
+						rule.Host+path.Path,
+						"The host and path are routed to a different backend by another Ingress",
+						fmt.Sprintf(
+							"The Ingress %s routes the same host and path to %s. Which backend receives the traffic depends on the Ingress controller.",
+							ingressRef(other, options),
+							otherBackend,
+						),
+					)
+				}
+			}
+
+			for _, tls := range ingress.TLS() {
+				for _, host := range tls.Hosts {
+					for _, otherTLS := range other.TLS() {
+						for _, otherHost := range otherTLS.Hosts {
+							if host != otherHost || tls.SecretName == otherTLS.SecretName {
+								continue
+							}
+							if score.Grade > scorecard.GradeWarning {
+								score.Grade = scorecard.GradeWarning
+							}
+							score.AddComment(
+								host,
+								"The host uses a different TLS secret in another Ingress",
+								fmt.Sprintf(
+									"The Ingress %s serves the host with the TLS secret %q instead of %q. Only one of the certificates will be served.",
+									ingressRef(other, options),
+									otherTLS.SecretName,
+									tls.SecretName,
+								),
+							)
+						}
+					}
+				}
+			}
+		}
+
+		return
+	}
+}
+
+func ingressNamespace(ingress ks.Ingress, options Options) string {
+	return options.Namespace.Of(ingress.GetObjectMeta().Namespace)
+}
+
+func ingressRef(ingress ks.Ingress, options Options) string {
+	return ingressNamespace(ingress, options) + "/" + ingress.GetObjectMeta().Name
+}
+
+// backendRef returns a string identifying the backend, Services are local to the namespace of the Ingress
+func backendRef(ingress ks.Ingress, backend networkingv1.IngressBackend, options Options) string {
+	switch {
+	case backend.Service != nil && backend.Service.Port.Name != "":
+		return fmt.Sprintf(
+			"service %s/%s:%s",
+			ingressNamespace(ingress, options),
+			backend.Service.Name,
+			backend.Service.Port.Name,
+		)
+	case backend.Service != nil:
+		return fmt.Sprintf(
+			"service %s/%s:%d",
+			ingressNamespace(ingress, options),
+			backend.Service.Name,
+			backend.Service.Port.Number,
+		)
+	case backend.Resource != nil:
+		return fmt.Sprintf(
+			"resource %s/%s/%s",
+			ingressNamespace(ingress, options),
+			backend.Resource.Kind,
+			backend.Resource.Name,
+		)
+	default:
+		return ""
+	}
+}
+
+func findBackend(ingress ks.Ingress, host, path string, options Options) (string, bool) {
+	for _, rule := range ingress.Rules() {
+		if rule.HTTP == nil || rule.Host != host {
+			continue
+		}
+		for _, p := range rule.HTTP.Paths {
+			if p.Path == path {
+				return backendRef(ingress, p.Backend, options), true
+			}
+		}
+	}
+	return "", false
+}
+
+// ingressPathType checks that the matching semantics of all Ingress paths are portable
+// across Ingress controllers
+func ingressPathType(ingress ks.Ingress) (score scorecard.TestScore, err error) {
+	score.Grade = scorecard.GradeAllOK
+	for _, rule := range ingress.Rules() {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			switch {
+			case path.PathType == nil:
+				score.Grade = scorecard.GradeWarning
+				score.AddComment(
+					rule.Host+path.Path,
+					"The path does not set a pathType",
+					"Set pathType to Prefix or Exact. Without it the matching semantics depend on the Ingress controller.",
+				)
+			case *path.PathType == networkingv1.PathTypeImplementationSpecific:
+				score.Grade = scorecard.GradeWarning
+				score.AddComment(
+					rule.Host+path.Path,
+					"The path uses pathType ImplementationSpecific",
+					"The matching semantics of ImplementationSpecific depend on the Ingress controller. Set pathType to Prefix or Exact.",
+				)
+			case *path.PathType == networkingv1.PathTypePrefix && isRegexPath(path.Path):
+				score.Grade = scorecard.GradeWarning
+				score.AddComment(
+					rule.Host+path.Path,
+					"The path looks like a regular expression but uses pathType Prefix",
+					"Prefix paths are matched element wise by their literal value, regular expressions are not evaluated.",
+				)
+			}
+		}
+	}
+
+	return
+}
+
+func isRegexPath(path string) bool {
+	return strings.ContainsAny(path, "*()[]{}$^+?|\\")
+}
@@ -3,7 +3,10 @@ package score
 import (
 	"testing"
 
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
 	"github.com/romnn/kube-score/scorecard"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestIngressTargetsService(t *testing.T) {
@@ -95,3 +98,116 @@ func TestIngressNoPanicIssue388(t *testing.T) {
 		scorecard.GradeAllOK,
 	)
 }
+
+func TestIngressConflictingPaths(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(
+		t,
+		"ingress-conflicting-paths.yaml",
+		"Ingress has no conflicts",
+		scorecard.GradeCritical,
+	)
+	assert.Len(t, comments, 2)
+}
+
+func TestIngressConflictingTLS(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(
+		t,
+		"ingress-conflicting-tls.yaml",
+		"Ingress has no conflicts",
+		scorecard.GradeWarning,
+	)
+}
+
+func TestIngressNoConflictsDifferentClass(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(
+		t,
+		"ingress-different-class.yaml",
+		"Ingress has no conflicts",
+		scorecard.GradeAllOK,
+	)
+}
+
+func TestIngressPathType(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(
+		t,
+		"ingress-pathtype.yaml",
+		"Ingress PathType",
+		scorecard.GradeAllOK,
+	)
+}
+
+func TestIngressPathTypeInvalid(t *testing.T) {
+	t.Parallel()
+	summaries := getSummaries(
+		t,
+		[]ks.NamedReader{testFile("ingress-pathtype-invalid.yaml")},
+		nil,
+		&config.RunConfiguration{
+			KubernetesVersion: config.Semver{Major: 1, Minor: 18},
+		},
+		"Ingress PathType",
+	)
+	assert.Equal(t, []string{
+		"The path uses pathType ImplementationSpecific",
+		"The path looks like a regular expression but uses pathType Prefix",
+	}, summaries)
+}
+
+func TestIngressPathTypeNotSet(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(
+		t,
+		"ingress-networkingv1beta1-pathtype-not-set.yaml",
+		"Ingress PathType",
+		scorecard.GradeWarning,
+	)
+}
+
+func TestIngressPathTypeSkippedBeforeV118(t *testing.T) {
+	t.Parallel()
+	assert.True(t, wasSkipped(
+		t,
+		[]ks.NamedReader{testFile("ingress-networkingv1beta1-pathtype-not-set.yaml")},
+		nil,
+		&config.RunConfiguration{
+			KubernetesVersion: config.Semver{Major: 1, Minor: 17},
+		},
+		"Ingress PathType",
+	))
+}
+
+func TestIngressTLSCertificate(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore(
+		[]ks.NamedReader{testFile("ingress-tls-certificate.yaml")},
+		nil,
+		&config.RunConfiguration{
+			EnabledOptionalTests: map[string]struct{}{"ingress-tls-certificate": {}},
+		},
+	)
+	assert.NoError(t, err)
+
+	grades := make(map[string]scorecard.Grade)
+	paths := make(map[string][]string)
+	for _, o := range sc {
+		for _, c := range o.Checks {
+			if c.Check.ID != "ingress-tls-certificate" {
+				continue
+			}
+			grades[o.ObjectMeta.Name] = c.Grade
+			for _, comment := range c.Comments {
+				paths[o.ObjectMeta.Name] = append(paths[o.ObjectMeta.Name], comment.Path)
+			}
+		}
+	}
+	assert.Equal(t, map[string]scorecard.Grade{
+		"issued":       scorecard.GradeAllOK,
+		"ingress-shim": scorecard.GradeAllOK,
+		"manual":       scorecard.GradeWarning,
+	}, grades)
+	assert.Equal(t, []string{"manual-tls"}, paths["manual"])
+}
@@ -0,0 +1,23 @@
+package internal
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// MinReplicasAnnotation overrides the configured minimum replica count of the object
+const MinReplicasAnnotation = "kube-score/min-replicas"
+
+// MinReplicas returns the minimum replica count set with the MinReplicasAnnotation, or def
+// if the annotation is not set
+func MinReplicas(annotations map[string]string, def int32) (int32, error) {
+	value, ok := annotations[MinReplicasAnnotation]
+	if !ok {
+		return def, nil
+	}
+	minReplicas, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || minReplicas < 0 {
+		return def, fmt.Errorf("invalid %s annotation %q: must be a non-negative integer", MinReplicasAnnotation, value)
+	}
+	return int32(minReplicas), nil
+}
@@ -0,0 +1,99 @@
+package job
+
+import (
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/score/checks"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+type Options struct {
+	KubernetesVersion config.Semver
+}
+
+func Register(allChecks *checks.Checks, options Options) {
+	allChecks.RegisterJobCheck(
+		"Job parallelism",
+		`Makes sure that the completions, parallelism and completionMode of Jobs are a valid combination`,
+		jobParallelism(options),
+	)
+	allChecks.RegisterJobCheck(
+		"Job RestartPolicy",
+		`Makes sure Jobs have a valid RestartPolicy`,
+		jobHasRestartPolicy,
+	)
+}
+
+func jobParallelism(options Options) func(ks.Job) (scorecard.TestScore, error) {
+	return func(job ks.Job) (score scorecard.TestScore, err error) {
+		score.Grade = scorecard.GradeAllOK
+
+		completions := job.Completions()
+		parallelism := job.Parallelism()
+
+		if mode := job.CompletionMode(); mode != nil && *mode == batchv1.IndexedCompletion {
+			// Indexed Jobs are enabled by default since Kubernetes v1.22
+			if options.KubernetesVersion.LessThan(config.Semver{Major: 1, Minor: 22}) {
+				score.Grade = scorecard.GradeCritical
+				score.AddComment(
+					"",
+					"The Job uses completionMode Indexed, which is not supported by the Kubernetes version",
+					fmt.Sprintf(
+						"Indexed Jobs are supported since Kubernetes v1.22, but the target version is v%d.%d",
+						options.KubernetesVersion.Major, options.KubernetesVersion.Minor,
+					),
+				)
+			}
+			if completions == nil {
+				score.Grade = scorecard.GradeCritical
+				score.AddComment(
+					"",
+					"The Job uses completionMode Indexed without completions",
+					"Indexed Jobs require completions to be set, as it defines the range of completion indexes",
+				)
+			}
+		}
+
+		if completions != nil && parallelism != nil && *parallelism > *completions {
+			if score.Grade > scorecard.GradeWarning {
+				score.Grade = scorecard.GradeWarning
+			}
+			score.AddComment(
+				"",
+				"The Job has a higher parallelism than completions",
+				fmt.Sprintf(
+					"At most %d pods will be running at the same time, setting parallelism to %d has no effect",
+					*completions, *parallelism,
+				),
+			)
+		}
+
+		return
+	}
+}
+
+// Job restartPolicy must be "OnFailure" or "Never". It cannot be empty (unspecified)
+func jobHasRestartPolicy(job ks.Job) (score scorecard.TestScore, err error) {
+	podTmpl := job.GetPodTemplateSpec()
+	restartPolicy := podTmpl.Spec.RestartPolicy
+
+	if len(restartPolicy) > 0 {
+		if restartPolicy == "Never" || restartPolicy == "OnFailure" {
+			score.Grade = scorecard.GradeAllOK
+		} else {
+			score.Grade = scorecard.GradeCritical
+			score.AddComment("", "The Job must have a valid RestartPolicy configured",
+				"Valid Job RestartPolicy settings are Never or OnFailure")
+		}
+	} else {
+		score.Grade = scorecard.GradeCritical
+		score.AddComment("", "The Job is missing a valid RestartPolicy",
+			"Valid Job RestartPolicy settings are Never or OnFailure")
+	}
+
+	return
+}
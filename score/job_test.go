@@ -0,0 +1,81 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func TestJobParallelism(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "job-batchv1.yaml", "Job parallelism", scorecard.GradeAllOK)
+}
+
+func TestJobParallelismHigherThanCompletions(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(
+		t,
+		"job-batchv1-parallelism-higher-than-completions.yaml",
+		"Job parallelism",
+		scorecard.GradeWarning,
+	)
+}
+
+func TestJobIndexedCompletionMode(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("job-batchv1-indexed.yaml")},
+		nil,
+		&config.RunConfiguration{
+			KubernetesVersion: config.Semver{Major: 1, Minor: 22},
+		},
+		"Job parallelism",
+		scorecard.GradeAllOK,
+	)
+}
+
+func TestJobIndexedCompletionModeNotSupported(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("job-batchv1-indexed.yaml")},
+		nil,
+		&config.RunConfiguration{
+			KubernetesVersion: config.Semver{Major: 1, Minor: 21},
+		},
+		"Job parallelism",
+		scorecard.GradeCritical,
+	)
+}
+
+func TestJobIndexedCompletionModeWithoutCompletions(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("job-batchv1-indexed-without-completions.yaml")},
+		nil,
+		&config.RunConfiguration{
+			KubernetesVersion: config.Semver{Major: 1, Minor: 22},
+		},
+		"Job parallelism",
+		scorecard.GradeCritical,
+	)
+}
+
+func TestJobRestartPolicyValid(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "job-batchv1.yaml", "Job RestartPolicy", scorecard.GradeAllOK)
+}
+
+func TestJobRestartPolicyInvalid(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(
+		t,
+		"job-batchv1-restartpolicy-invalid.yaml",
+		"Job RestartPolicy",
+		scorecard.GradeCritical,
+	)
+}
@@ -8,12 +8,22 @@ import (
 	"github.com/romnn/kube-score/scorecard"
 )
 
-func Register(allChecks *checks.Checks) {
+type Options struct {
+	// AnnotationPrefixes are accepted in addition to kube-score for the severity annotations
+	AnnotationPrefixes []string
+}
+
+func Register(allChecks *checks.Checks, options Options) {
 	allChecks.RegisterMetaCheck(
 		"Label values",
 		"Validates label values",
 		validateLabelValues,
 	)
+	allChecks.RegisterMetaContextCheck(
+		"Severity annotation",
+		"Makes sure that the entries of the kube-score/severity annotation of the object and its pod template are valid",
+		severityAnnotation(options),
+	)
 }
 
 func validateLabelValues(meta domain.BothMeta) (score scorecard.TestScore, err error) {
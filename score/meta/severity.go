@@ -0,0 +1,63 @@
+package meta
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/score/checks"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// objectRef identifies an object with a pod template
+type objectRef struct {
+	kind, namespace, name string
+}
+
+// templateAnnotationsKey is the key of the index of the annotations of the pod templates
+type templateAnnotationsKey struct{}
+
+// templateAnnotationsIn returns the annotations of the pod templates by the object of the template,
+// the severity annotations of a template apply to the pod checks of the object
+func templateAnnotationsIn(ctx *checks.Context) map[objectRef]map[string]string {
+	return checks.Index(ctx, templateAnnotationsKey{}, func(all domain.AllTypes) map[objectRef]map[string]string {
+		annotations := make(map[objectRef]map[string]string)
+		for _, ps := range all.PodSpeccers() {
+			if ps.GetTypeMeta().Kind == "Pod" {
+				continue
+			}
+			meta := ps.GetObjectMeta()
+			ref := objectRef{ps.GetTypeMeta().Kind, meta.Namespace, meta.Name}
+			annotations[ref] = ps.GetPodTemplateSpec().Annotations
+		}
+		return annotations
+	})
+}
+
+// severityAnnotation reports the malformed entries of the severity annotations of the object and
+// of its pod template. It reports them once per object, instead of with every check that the
+// annotation would downgrade.
+func severityAnnotation(options Options) checks.ContextCheckFunc[domain.BothMeta] {
+	return func(ctx *checks.Context, meta domain.BothMeta) (score scorecard.TestScore, err error) {
+		score.Grade = scorecard.GradeAllOK
+		ref := objectRef{meta.TypeMeta.Kind, meta.ObjectMeta.Namespace, meta.ObjectMeta.Name}
+		for _, annotations := range []map[string]string{
+			meta.ObjectMeta.Annotations,
+			templateAnnotationsIn(ctx)[ref],
+		} {
+			invalid := scorecard.InvalidSeverityAnnotations(options.AnnotationPrefixes, annotations)
+			for _, key := range slices.Sorted(maps.Keys(invalid)) {
+				for _, err := range invalid[key] {
+					score.Grade = scorecard.GradeWarning
+					score.AddComment(
+						key,
+						fmt.Sprintf("Invalid %s annotation", key),
+						fmt.Sprintf("%s, the entry is ignored and the other entries of the annotation are applied", err),
+					)
+				}
+			}
+		}
+		return
+	}
+}
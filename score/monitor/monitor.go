@@ -0,0 +1,168 @@
+package monitor
+
+import (
+	"fmt"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/score/checks"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+type Options struct {
+	Namespace ks.DefaultNamespace
+}
+
+func Register(allChecks *checks.Checks, options Options) {
+	allChecks.RegisterServiceMonitorContextCheck(
+		"ServiceMonitor targets Service",
+		`Makes sure that the selector of the ServiceMonitor matches a Service in the input, and that the Services have the ports of its endpoints`,
+		func(ctx *checks.Context, monitor ks.ServiceMonitor) (scorecard.TestScore, error) {
+			services := checks.Index(ctx, servicesKey{options.Namespace}, func(all ks.AllTypes) []target {
+				return serviceTargets(all.Services(), options)
+			})
+			spec := monitor.Spec()
+			return monitorTargets(
+				services, "Service", monitor.GetObjectMeta(), spec.Selector, spec.NamespaceSelector, spec.Endpoints, options,
+			)
+		},
+	)
+	allChecks.RegisterPodMonitorContextCheck(
+		"PodMonitor targets Pod",
+		`Makes sure that the selector of the PodMonitor matches a Pod or a pod template in the input, and that the containers have the ports of its endpoints`,
+		func(ctx *checks.Context, monitor ks.PodMonitor) (scorecard.TestScore, error) {
+			pods := checks.Index(ctx, podsKey{options.Namespace}, func(all ks.AllTypes) []target {
+				return podTargets(all.Pods(), all.PodSpeccers(), options)
+			})
+			spec := monitor.Spec()
+			return monitorTargets(
+				pods, "pod", monitor.GetObjectMeta(), spec.Selector, spec.NamespaceSelector, spec.PodMetricsEndpoints, options,
+			)
+		},
+	)
+}
+
+// target is a Service or pod that can be scraped, with the names of its ports
+type target struct {
+	namespace string
+	labels    map[string]string
+	ports     []string
+}
+
+// servicesKey is the key of the index of the Services that can be targeted by ServiceMonitors
+type servicesKey struct{ namespace ks.DefaultNamespace }
+
+func serviceTargets(services []ks.Service, options Options) []target {
+	targets := make([]target, 0, len(services))
+	for _, s := range services {
+		service := s.Service()
+		var ports []string
+		for _, port := range service.Spec.Ports {
+			ports = append(ports, port.Name)
+		}
+		targets = append(targets, target{options.Namespace.Of(service.Namespace), service.Labels, ports})
+	}
+	return targets
+}
+
+// podsKey is the key of the index of the pods and pod templates that can be targeted by PodMonitors
+type podsKey struct{ namespace ks.DefaultNamespace }
+
+func podTargets(pods []ks.Pod, podSpeccers []ks.PodSpecer, options Options) []target {
+	targets := make([]target, 0, len(pods)+len(podSpeccers))
+	for _, p := range pods {
+		pod := p.Pod()
+		targets = append(targets, target{options.Namespace.Of(pod.Namespace), pod.Labels, containerPorts(pod.Spec.Containers)})
+	}
+	for _, p := range podSpeccers {
+		template := p.GetPodTemplateSpec()
+		targets = append(targets, target{
+			options.Namespace.Of(p.GetObjectMeta().Namespace),
+			template.Labels,
+			containerPorts(template.Spec.Containers),
+		})
+	}
+	return targets
+}
+
+// containerPorts returns the names of the ports of the containers
+func containerPorts(containers []corev1.Container) []string {
+	var ports []string
+	for _, container := range containers {
+		for _, port := range container.Ports {
+			ports = append(ports, port.Name)
+		}
+	}
+	return ports
+}
+
+// monitorTargets checks that the selector of a monitor matches a target, and that the ports of the
+// endpoints are ports of the matched targets
+func monitorTargets(
+	targets []target,
+	targetName string,
+	objectMeta metav1.ObjectMeta,
+	labelSelector metav1.LabelSelector,
+	namespaceSelector ks.MonitorNamespaceSelector,
+	endpoints []ks.MonitorEndpoint,
+	options Options,
+) (score scorecard.TestScore, err error) {
+	selector, err := metav1.LabelSelectorAsSelector(&labelSelector)
+	if err != nil {
+		return score, fmt.Errorf("failed to create selector: %w", err)
+	}
+
+	namespace := options.Namespace.Of(objectMeta.Namespace)
+	var matched []target
+	for _, t := range targets {
+		if !selectsNamespace(namespaceSelector, namespace, t.namespace) {
+			continue
+		}
+		if selector.Matches(labels.Set(t.labels)) {
+			matched = append(matched, t)
+		}
+	}
+
+	if len(matched) == 0 {
+		score.Grade = scorecard.GradeCritical
+		score.AddComment(
+			"selector",
+			fmt.Sprintf("The selector does not match any %s", targetName),
+			fmt.Sprintf("Nothing is scraped by the monitor. Change the selector to match the labels of the %s, or its namespaceSelector to the namespace of it.", targetName),
+		)
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	for _, endpoint := range endpoints {
+		if endpoint.Port == "" {
+			continue
+		}
+		if slices.ContainsFunc(matched, func(t target) bool { return slices.Contains(t.ports, endpoint.Port) }) {
+			continue
+		}
+		score.Grade = scorecard.GradeCritical
+		score.AddComment(
+			endpoint.Port,
+			fmt.Sprintf("No selected %s has a port named %s", targetName, endpoint.Port),
+			fmt.Sprintf("The endpoint is not scraped. Set port to the name of a port of the %s.", targetName),
+		)
+	}
+	return
+}
+
+// selectsNamespace returns true if the namespace selector of a monitor in the namespace selects the
+// target namespace
+func selectsNamespace(selector ks.MonitorNamespaceSelector, namespace, target string) bool {
+	if selector.Any {
+		return true
+	}
+	if len(selector.MatchNames) > 0 {
+		return slices.Contains(selector.MatchNames, target)
+	}
+	return namespace == target
+}
@@ -0,0 +1,40 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func TestMonitorTargets(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore([]ks.NamedReader{testFile("monitor.yaml")}, nil, nil)
+	assert.NoError(t, err)
+
+	grades := make(map[string]scorecard.Grade)
+	summaries := make(map[string][]string)
+	for _, o := range sc {
+		for _, c := range o.Checks {
+			if c.Check.ID != "servicemonitor-targets-service" && c.Check.ID != "podmonitor-targets-pod" {
+				continue
+			}
+			key := o.TypeMeta.Kind + "/" + o.ObjectMeta.Name
+			grades[key] = c.Grade
+			for _, comment := range c.Comments {
+				summaries[key] = append(summaries[key], comment.Summary)
+			}
+		}
+	}
+	assert.Equal(t, map[string]scorecard.Grade{
+		"ServiceMonitor/app":             scorecard.GradeAllOK,
+		"ServiceMonitor/wrong-namespace": scorecard.GradeCritical,
+		"ServiceMonitor/wrong-port":      scorecard.GradeCritical,
+		"PodMonitor/app":                 scorecard.GradeAllOK,
+		"PodMonitor/wrong-selector":      scorecard.GradeCritical,
+	}, grades)
+	assert.Equal(t, []string{"No selected Service has a port named metrics"}, summaries["ServiceMonitor/wrong-port"])
+	assert.Equal(t, []string{"The selector does not match any pod"}, summaries["PodMonitor/wrong-selector"])
+}
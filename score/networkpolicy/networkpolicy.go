@@ -2,39 +2,79 @@ package networkpolicy
 
 import (
 	"fmt"
+	"os"
 
+	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
+	"github.com/romnn/kube-score/config"
 	ks "github.com/romnn/kube-score/domain"
 	"github.com/romnn/kube-score/score/checks"
 	"github.com/romnn/kube-score/scorecard"
 )
 
 type Options struct {
-	Namespace string
+	Namespace   ks.DefaultNamespace
+	ServiceMesh config.ServiceMesh
 }
 
-func Register(
-	allChecks *checks.Checks,
-	netpols ks.NetworkPolicies,
-	pods ks.Pods,
-	podspecers ks.PodSpeccers,
-	options Options,
-) {
-	allChecks.RegisterPodCheck(
+func Register(allChecks *checks.Checks, options Options) {
+	allChecks.RegisterPodContextCheck(
 		"Pod NetworkPolicy",
 		`Makes sure that all Pods are targeted by a NetworkPolicy`,
-		podHasNetworkPolicy(netpols.NetworkPolicies(), options),
+		func(ctx *checks.Context, ps ks.PodSpecer) (scorecard.TestScore, error) {
+			netpols := networkPoliciesIn(ctx, options, ps.GetPodTemplateSpec().Namespace)
+			return podHasNetworkPolicy(netpols, options)(ps)
+		},
 	)
-	allChecks.RegisterNetworkPolicyCheck(
+	allChecks.RegisterNetworkPolicyContextCheck(
 		"NetworkPolicy targets Pod",
 		`Makes sure that all NetworkPolicies targets at least one Pod`,
-		networkPolicyTargetsPod(pods.Pods(), podspecers.PodSpeccers(), options),
+		func(ctx *checks.Context, netPol networkingv1.NetworkPolicy) (scorecard.TestScore, error) {
+			pods, podspecers := podsIn(ctx, options, netPol.Namespace)
+			return networkPolicyTargetsPod(pods, podspecers, options)(netPol)
+		},
+	)
+	allChecks.RegisterNetworkPolicyCheck(
+		"NetworkPolicy allows service mesh ports",
+		`Makes sure that NetworkPolicies restricting ingress ports allow the ports of the service mesh sidecar. Only evaluated if --service-mesh is set`,
+		networkPolicyAllowsServiceMeshPorts(options),
 	)
 }
 
+// The keys of the indexes of the context, by the default namespace that they are built with
+type (
+	networkPoliciesKey struct{ namespace ks.DefaultNamespace }
+	podsKey            struct{ namespace ks.DefaultNamespace }
+	podSpecersKey      struct{ namespace ks.DefaultNamespace }
+)
+
+// networkPoliciesIn returns the NetworkPolicies of the run in the namespace
+func networkPoliciesIn(ctx *checks.Context, options Options, namespace string) []ks.NetworkPolicy {
+	return checks.Index(ctx, networkPoliciesKey{options.Namespace}, func(all ks.AllTypes) map[string][]ks.NetworkPolicy {
+		return checks.ByNamespace(all.NetworkPolicies(), options.Namespace, func(n ks.NetworkPolicy) metav1.ObjectMeta {
+			return n.NetworkPolicy().ObjectMeta
+		})
+	})[options.Namespace.Of(namespace)]
+}
+
+// podsIn returns the Pods and the objects with a pod template of the run in the namespace
+func podsIn(ctx *checks.Context, options Options, namespace string) ([]ks.Pod, []ks.PodSpecer) {
+	pods := checks.Index(ctx, podsKey{options.Namespace}, func(all ks.AllTypes) map[string][]ks.Pod {
+		return checks.ByNamespace(all.Pods(), options.Namespace, func(p ks.Pod) metav1.ObjectMeta {
+			return p.Pod().ObjectMeta
+		})
+	})
+	podspecers := checks.Index(ctx, podSpecersKey{options.Namespace}, func(all ks.AllTypes) map[string][]ks.PodSpecer {
+		return checks.ByNamespace(all.PodSpeccers(), options.Namespace, ks.PodSpecer.GetObjectMeta)
+	})
+	namespace = options.Namespace.Of(namespace)
+	return pods[namespace], podspecers[namespace]
+}
+
 // podHasNetworkPolicy returns a function that tests that all pods have matching NetworkPolicies
 // podHasNetworkPolicy takes a list of all defined NetworkPolicies as input
 func podHasNetworkPolicy(
@@ -48,26 +88,20 @@ func podHasNetworkPolicy(
 
 		pod := ps.GetPodTemplateSpec()
 
-		podNamespace := pod.Namespace
-		if podNamespace == "" {
-			podNamespace = options.Namespace
-		}
+		podNamespace := options.Namespace.Of(pod.Namespace)
 
 		for _, n := range allNetpols {
 			netPol := n.NetworkPolicy()
 
-			netPolNamespace := netPol.Namespace
-			if netPolNamespace == "" {
-				netPolNamespace = options.Namespace
-			}
+			netPolNamespace := options.Namespace.Of(netPol.Namespace)
 
 			if verbose {
 				if netPol.Name == "signoz-schema-migrator-allow-k8s-api" {
-					fmt.Printf("netpol=%s\n", netPol.Name)
-					fmt.Printf("\t pod      =%+v\n", pod.Name)
-					fmt.Printf("\t selector =%+v\n", netPol.Spec.PodSelector)
-					fmt.Printf("\t labels   =%+v\n", pod.Labels)
-					fmt.Printf("\t ns       =%q == %q\n", podNamespace, netPolNamespace)
+					fmt.Fprintf(os.Stderr, "netpol=%s\n", netPol.Name)
+					fmt.Fprintf(os.Stderr, "\t pod      =%+v\n", pod.Name)
+					fmt.Fprintf(os.Stderr, "\t selector =%+v\n", netPol.Spec.PodSelector)
+					fmt.Fprintf(os.Stderr, "\t labels   =%+v\n", pod.Labels)
+					fmt.Fprintf(os.Stderr, "\t ns       =%q == %q\n", podNamespace, netPolNamespace)
 				}
 				// fmt.Printf(
 				// 	"NAMESPACES:\n\t%80s = %s selector=%v\n\t%80s = %s labels=%v\n",
@@ -166,10 +200,7 @@ func networkPolicyTargetsPod(
 	return func(netPol networkingv1.NetworkPolicy) (score scorecard.TestScore, err error) {
 		hasMatch := false
 
-		netPolNamespace := netPol.Namespace
-		if netPolNamespace == "" {
-			netPolNamespace = options.Namespace
-		}
+		netPolNamespace := options.Namespace.Of(netPol.Namespace)
 
 		// for _, j := range jobs {
 		// 	// fmt.Printf("=== job ===")
@@ -182,10 +213,7 @@ func networkPolicyTargetsPod(
 		for _, p := range pods {
 			pod := p.Pod()
 
-			podNamespace := pod.Namespace
-			if podNamespace == "" {
-				podNamespace = options.Namespace
-			}
+			podNamespace := options.Namespace.Of(pod.Namespace)
 
 			if verbose {
 				// fmt.Printf(
@@ -198,11 +226,11 @@ func networkPolicyTargetsPod(
 				// 	pod.Labels,
 				// )
 				if netPol.Name == "signoz-schema-migrator-allow-k8s-api" {
-					fmt.Printf("netpol=%s\n", netPol.Name)
-					fmt.Printf("\t pod      =%+v\n", pod.Name)
-					fmt.Printf("\t selector =%+v\n", netPol.Spec.PodSelector)
-					fmt.Printf("\t labels   =%+v\n", pod.Labels)
-					fmt.Printf("\t ns       =%q == %q\n", podNamespace, netPolNamespace)
+					fmt.Fprintf(os.Stderr, "netpol=%s\n", netPol.Name)
+					fmt.Fprintf(os.Stderr, "\t pod      =%+v\n", pod.Name)
+					fmt.Fprintf(os.Stderr, "\t selector =%+v\n", netPol.Spec.PodSelector)
+					fmt.Fprintf(os.Stderr, "\t labels   =%+v\n", pod.Labels)
+					fmt.Fprintf(os.Stderr, "\t ns       =%q == %q\n", podNamespace, netPolNamespace)
 				}
 			}
 			if podNamespace != netPolNamespace {
@@ -235,10 +263,7 @@ func networkPolicyTargetsPod(
 
 		if !hasMatch {
 			for _, pod := range podspecers {
-				podNamespace := pod.GetObjectMeta().Namespace
-				if podNamespace == "" {
-					podNamespace = options.Namespace
-				}
+				podNamespace := options.Namespace.Of(pod.GetObjectMeta().Namespace)
 
 				if podNamespace != netPolNamespace {
 					continue
@@ -246,11 +271,11 @@ func networkPolicyTargetsPod(
 
 				if verbose {
 					if netPol.Name == "signoz-schema-migrator-allow-k8s-api" {
-						fmt.Printf("netpol=%s\n", netPol.Name)
-						fmt.Printf("\t pod      =%+v\n", pod.GetObjectMeta().Name)
-						fmt.Printf("\t selector =%+v\n", netPol.Spec.PodSelector)
-						fmt.Printf("\t labels   =%+v\n", pod.GetPodTemplateSpec().Labels)
-						fmt.Printf("\t ns       =%q == %q\n", podNamespace, netPolNamespace)
+						fmt.Fprintf(os.Stderr, "netpol=%s\n", netPol.Name)
+						fmt.Fprintf(os.Stderr, "\t pod      =%+v\n", pod.GetObjectMeta().Name)
+						fmt.Fprintf(os.Stderr, "\t selector =%+v\n", netPol.Spec.PodSelector)
+						fmt.Fprintf(os.Stderr, "\t labels   =%+v\n", pod.GetPodTemplateSpec().Labels)
+						fmt.Fprintf(os.Stderr, "\t ns       =%q == %q\n", podNamespace, netPolNamespace)
 					}
 				}
 
@@ -291,3 +316,74 @@ func networkPolicyTargetsPod(
 		return
 	}
 }
+
+// networkPolicyAllowsServiceMeshPorts checks that NetworkPolicies which only allow ingress
+// traffic to specific ports also allow traffic to the ports of the service mesh sidecar
+func networkPolicyAllowsServiceMeshPorts(
+	options Options,
+) func(networkingv1.NetworkPolicy) (scorecard.TestScore, error) {
+	return func(netPol networkingv1.NetworkPolicy) (score scorecard.TestScore, err error) {
+		sidecarPorts := options.ServiceMesh.SidecarPorts()
+		if len(sidecarPorts) == 0 {
+			score.Grade = scorecard.GradeAllOK
+			score.Skipped = true
+			score.AddComment("", "Skipped because no service mesh is configured", "")
+			return
+		}
+
+		allowed := make(map[int32]bool)
+		for _, rule := range netPol.Spec.Ingress {
+			// A rule without ports allows traffic to all ports
+			if len(rule.Ports) == 0 {
+				score.Grade = scorecard.GradeAllOK
+				return
+			}
+			for _, port := range rule.Ports {
+				if port.Protocol != nil && *port.Protocol != corev1.ProtocolTCP {
+					continue
+				}
+				// A port without a number matches all ports of the protocol
+				if port.Port == nil {
+					score.Grade = scorecard.GradeAllOK
+					return
+				}
+				if port.Port.Type != intstr.Int {
+					continue
+				}
+				start, end := port.Port.IntVal, port.Port.IntVal
+				if port.EndPort != nil {
+					end = *port.EndPort
+				}
+				for _, sidecarPort := range sidecarPorts {
+					if sidecarPort >= start && sidecarPort <= end {
+						allowed[sidecarPort] = true
+					}
+				}
+			}
+		}
+
+		score.Grade = scorecard.GradeAllOK
+
+		// Policies without ingress rules deny all ingress traffic on purpose
+		if len(netPol.Spec.Ingress) == 0 {
+			return
+		}
+
+		for _, sidecarPort := range sidecarPorts {
+			if !allowed[sidecarPort] {
+				score.Grade = scorecard.GradeWarning
+				score.AddComment(
+					fmt.Sprintf("%d", sidecarPort),
+					"The NetworkPolicy does not allow ingress traffic to a service mesh sidecar port",
+					fmt.Sprintf(
+						"The %s sidecar expects port %d to be reachable. Add it to the ports of an ingress rule.",
+						options.ServiceMesh,
+						sidecarPort,
+					),
+				)
+			}
+		}
+
+		return
+	}
+}
@@ -3,7 +3,10 @@ package score
 import (
 	"testing"
 
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
 	"github.com/romnn/kube-score/scorecard"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestPodHasNoMatchingNetworkPolicy(t *testing.T) {
@@ -187,3 +190,39 @@ func TestNetworkPolicyEmptyPodSelector(t *testing.T) {
 		scorecard.GradeAllOK,
 	)
 }
+
+func TestNetworkPolicyAllowsServiceMeshPorts(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("networkpolicy-service-mesh-ports-allowed.yaml")},
+		nil,
+		&config.RunConfiguration{ServiceMesh: config.ServiceMeshIstio},
+		"NetworkPolicy allows service mesh ports",
+		scorecard.GradeAllOK,
+	)
+}
+
+func TestNetworkPolicyNotAllowsServiceMeshPorts(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("networkpolicy-service-mesh-ports-not-allowed.yaml")},
+		nil,
+		&config.RunConfiguration{ServiceMesh: config.ServiceMeshIstio},
+		"NetworkPolicy allows service mesh ports",
+		scorecard.GradeWarning,
+	)
+	assert.Len(t, comments, 3)
+}
+
+func TestNetworkPolicyServiceMeshPortsSkippedWithoutMesh(t *testing.T) {
+	t.Parallel()
+	assert.True(t, wasSkipped(
+		t,
+		[]ks.NamedReader{testFile("networkpolicy-service-mesh-ports-not-allowed.yaml")},
+		nil,
+		nil,
+		"NetworkPolicy allows service mesh ports",
+	))
+}
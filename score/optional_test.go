@@ -1,8 +1,11 @@
 package score
 
 import (
+	"slices"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/romnn/kube-score/config"
 	ks "github.com/romnn/kube-score/domain"
 	"github.com/romnn/kube-score/score/checks"
@@ -60,7 +63,7 @@ func TestOptionalRunCliFlagEnabledDefault(t *testing.T) {
 		&config.RunConfiguration{
 			EnabledOptionalTests: enabledOptionalTests,
 		},
-		"Container Memory Requests Equal Limits",
+		"Container Resource Requests Equal Limits",
 		scorecard.GradeCritical,
 	)
 }
@@ -77,9 +80,21 @@ func TestOptionalRunAnnotationEnabled(t *testing.T) {
 		},
 		nil,
 		&config.RunConfiguration{
-			EnabledOptionalTests: enabledOptionalTests,
+			EnabledOptionalTests:        enabledOptionalTests,
+			UseOptionalChecksAnnotation: true,
 		},
-		"Container Memory Requests Equal Limits",
+		"Container Resource Requests Equal Limits",
 		scorecard.GradeCritical,
 	)
 }
+
+func TestAllChecks(t *testing.T) {
+	t.Parallel()
+
+	// The metadata does not depend on which checks are ignored
+	registered := RegisterAllChecks(&checks.Config{
+		IgnoredTests: map[string]struct{}{"container-image-tag": {}},
+	}, nil)
+	assert.Equal(t, registered.All(), AllChecks())
+	assert.True(t, slices.ContainsFunc(AllChecks(), func(c ks.Check) bool { return c.Optional }))
+}
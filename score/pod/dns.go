@@ -0,0 +1,108 @@
+package pod
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// clusterDNSSuffixes are parts of hostnames that are only resolved by the cluster DNS
+var clusterDNSSuffixes = []string{".svc", ".cluster.local"}
+
+// podDNS checks that the dnsPolicy and dnsConfig of the pod can resolve the names that it uses
+func podDNS(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+	spec := ps.GetPodTemplateSpec().Spec
+	score.Grade = scorecard.GradeAllOK
+
+	warn := func(summary, description string) {
+		if score.Grade > scorecard.GradeWarning {
+			score.Grade = scorecard.GradeWarning
+		}
+		score.AddComment("", summary, description)
+	}
+
+	switch spec.DNSPolicy {
+	case corev1.DNSDefault:
+		if name, ok := clusterHostname(spec); ok {
+			warn(
+				"The pod does not use the cluster DNS",
+				fmt.Sprintf(
+					"The dnsPolicy Default uses the DNS of the node, which can not resolve %s. Use the dnsPolicy ClusterFirst",
+					name,
+				),
+			)
+		}
+	case corev1.DNSNone:
+		if spec.DNSConfig == nil || len(spec.DNSConfig.Nameservers) == 0 {
+			score.Grade = scorecard.GradeCritical
+			score.AddComment(
+				"",
+				"The pod has no nameservers",
+				"The dnsPolicy None requires nameservers to be set in dnsConfig",
+			)
+		}
+	case "", corev1.DNSClusterFirst:
+		if spec.HostNetwork {
+			warn(
+				"The pod uses the host network without the cluster DNS",
+				"Pods with hostNetwork fall back to the DNS of the node with the dnsPolicy ClusterFirst. Use the dnsPolicy ClusterFirstWithHostNet",
+			)
+		}
+	}
+
+	if spec.DNSPolicy == corev1.DNSDefault || spec.DNSPolicy == corev1.DNSNone {
+		return
+	}
+
+	var ndots *string
+	if spec.DNSConfig != nil {
+		for _, option := range spec.DNSConfig.Options {
+			if option.Name == "ndots" {
+				ndots = option.Value
+			}
+		}
+	}
+	if ndots == nil {
+		if score.Grade > scorecard.GradeAlmostOK {
+			score.Grade = scorecard.GradeAlmostOK
+		}
+		score.AddComment(
+			"",
+			"The pod uses the default ndots of 5",
+			"Names with less than 5 dots are first looked up in all search domains of the cluster, which multiplies the DNS queries of external names. "+
+				"Consider setting a lower ndots option in dnsConfig, e.g. 2, for services with many DNS lookups",
+		)
+		return
+	}
+	if n, err := strconv.Atoi(*ndots); err != nil || n < 0 || n > 15 {
+		warn(
+			"The pod has an invalid ndots option",
+			fmt.Sprintf("The ndots option has to be a number between 0 and 15, but is %q", *ndots),
+		)
+	}
+
+	return
+}
+
+// clusterHostname returns the first hostname of a Service in the environment variables or arguments of the containers
+func clusterHostname(spec corev1.PodSpec) (string, bool) {
+	for _, container := range append(spec.InitContainers, spec.Containers...) {
+		values := append(append([]string{}, container.Command...), container.Args...)
+		for _, env := range container.Env {
+			values = append(values, env.Value)
+		}
+		for _, value := range values {
+			for _, suffix := range clusterDNSSuffixes {
+				if strings.Contains(value, suffix) {
+					return value, true
+				}
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,30 @@
+package pod
+
+import (
+	"fmt"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// podEphemeralContainers reports the ephemeral containers of manifests. Ephemeral containers are
+// debug containers that are added to running pods with kubectl debug, so they are expected in pods
+// that are captured from a cluster, but should not be committed to the manifests. The other checks
+// ignore ephemeral containers.
+func podEphemeralContainers(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+	score.Grade = scorecard.GradeAllOK
+
+	if ps.FileLocation().Exported {
+		return score, nil
+	}
+
+	for _, c := range ps.GetPodTemplateSpec().Spec.EphemeralContainers {
+		score.Grade = scorecard.GradeInfo
+		score.AddComment(
+			c.Name,
+			fmt.Sprintf("The ephemeral container %s is part of the manifest", c.Name),
+			"Ephemeral containers are added to running pods for debugging, and can not be created with the pod. Remove it from the manifest",
+		)
+	}
+	return score, nil
+}
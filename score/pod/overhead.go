@@ -0,0 +1,317 @@
+package pod
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/score/checks"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// The keys of the indexes of the context
+type (
+	runtimeClassesKey struct{}
+	resourceQuotasKey struct{ namespace ks.DefaultNamespace }
+	limitRangesKey    struct{ namespace ks.DefaultNamespace }
+	replicasKey       struct{ namespace ks.DefaultNamespace }
+)
+
+// runtimeClassNamed returns the RuntimeClass of the run with the name
+func runtimeClassNamed(ctx *checks.Context, name string) (ks.RuntimeClass, bool) {
+	rc, ok := checks.Index(ctx, runtimeClassesKey{}, func(all ks.AllTypes) map[string]ks.RuntimeClass {
+		byName := make(map[string]ks.RuntimeClass)
+		for _, rc := range all.RuntimeClasses() {
+			byName[rc.RuntimeClass().Name] = rc
+		}
+		return byName
+	})[name]
+	return rc, ok
+}
+
+// resourceQuotasIn returns the ResourceQuotas of the run in the namespace
+func resourceQuotasIn(ctx *checks.Context, options Options, namespace string) []ks.ResourceQuota {
+	return checks.Index(ctx, resourceQuotasKey{options.Namespace}, func(all ks.AllTypes) map[string][]ks.ResourceQuota {
+		return checks.ByNamespace(all.ResourceQuotas(), options.Namespace, func(q ks.ResourceQuota) metav1.ObjectMeta {
+			return q.ResourceQuota().ObjectMeta
+		})
+	})[options.Namespace.Of(namespace)]
+}
+
+// limitRangesIn returns the LimitRanges of the run in the namespace
+func limitRangesIn(ctx *checks.Context, options Options, namespace string) []ks.LimitRange {
+	return checks.Index(ctx, limitRangesKey{options.Namespace}, func(all ks.AllTypes) map[string][]ks.LimitRange {
+		return checks.ByNamespace(all.LimitRanges(), options.Namespace, func(l ks.LimitRange) metav1.ObjectMeta {
+			return l.LimitRange().ObjectMeta
+		})
+	})[options.Namespace.Of(namespace)]
+}
+
+// workloadRef is the kind, namespace and name of a workload
+type workloadRef struct {
+	kind, namespace, name string
+}
+
+// replicasOf returns the replicas of the Deployment or StatefulSet of the pod template, and 1 for
+// the other kinds of pods
+func replicasOf(ctx *checks.Context, options Options, ps ks.PodSpecer) int32 {
+	replicas := checks.Index(ctx, replicasKey{options.Namespace}, func(all ks.AllTypes) map[workloadRef]int32 {
+		replicas := make(map[workloadRef]int32)
+		for _, d := range all.Deployments() {
+			deployment := d.Deployment()
+			ref := workloadRef{"Deployment", options.Namespace.Of(deployment.Namespace), deployment.Name}
+			replicas[ref] = ptr.Deref(deployment.Spec.Replicas, 1)
+		}
+		for _, s := range all.StatefulSets() {
+			statefulSet := s.StatefulSet()
+			ref := workloadRef{"StatefulSet", options.Namespace.Of(statefulSet.Namespace), statefulSet.Name}
+			replicas[ref] = ptr.Deref(statefulSet.Spec.Replicas, 1)
+		}
+		return replicas
+	})
+	meta := ps.GetObjectMeta()
+	if r, ok := replicas[workloadRef{ps.GetTypeMeta().Kind, options.Namespace.Of(meta.Namespace), meta.Name}]; ok {
+		return r
+	}
+	return 1
+}
+
+// quotaResources are the keys of the hard limits of ResourceQuotas that the overhead of a pod is
+// counted against, and if they limit the requests or the limits of the pod
+var quotaResources = []struct {
+	key      corev1.ResourceName
+	resource corev1.ResourceName
+	limits   bool
+}{
+	{corev1.ResourceCPU, corev1.ResourceCPU, false},
+	{corev1.ResourceMemory, corev1.ResourceMemory, false},
+	{corev1.ResourceRequestsCPU, corev1.ResourceCPU, false},
+	{corev1.ResourceRequestsMemory, corev1.ResourceMemory, false},
+	{corev1.ResourceLimitsCPU, corev1.ResourceCPU, true},
+	{corev1.ResourceLimitsMemory, corev1.ResourceMemory, true},
+}
+
+// podOverhead checks that the pods of a workload still fit into the ResourceQuotas of their
+// namespace when the overhead of their RuntimeClass is added to their requests and limits, like the
+// quota admission does. The requests and limits of the containers are defaulted by the LimitRanges
+// of the namespace. The check only applies to pods with a RuntimeClass with an overhead in the
+// input, and reports the effective overhead per pod. ResourceQuotas with scopes are not evaluated.
+func podOverhead(
+	runtimeClass ks.RuntimeClass,
+	quotas []ks.ResourceQuota,
+	limitRanges []ks.LimitRange,
+	replicas int32,
+) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		score.Grade = scorecard.GradeAllOK
+
+		rc := runtimeClass.RuntimeClass()
+		if rc.Overhead == nil || len(rc.Overhead.PodFixed) == 0 {
+			return score, nil
+		}
+		overhead := rc.Overhead.PodFixed
+		spec := ps.GetPodTemplateSpec().Spec
+
+		for _, q := range quotas {
+			quota := q.ResourceQuota()
+			if len(quota.Spec.Scopes) > 0 || quota.Spec.ScopeSelector != nil {
+				continue
+			}
+			for _, r := range quotaResources {
+				hard, ok := quota.Spec.Hard[r.key]
+				if !ok {
+					continue
+				}
+				extra, ok := overhead[r.resource]
+				if !ok {
+					continue
+				}
+				perPod, ok := podResource(spec, limitRanges, r.resource, r.limits)
+				if !ok {
+					// Pods without the limit are rejected by the quota, which is reported by container-resources
+					continue
+				}
+				perPod.Add(extra)
+				used := perPod.DeepCopy()
+				used.Mul(int64(replicas))
+				if used.Cmp(hard) <= 0 {
+					continue
+				}
+
+				score.Grade = scorecard.GradeCritical
+				if replicas == 1 {
+					score.AddComment(
+						"",
+						fmt.Sprintf("The pod does not fit into the ResourceQuota %s with the overhead of the runtime class %s", quota.Name, rc.Name),
+						fmt.Sprintf(
+							"The overhead of %s %s is added to the pod, which then uses %s of %s, more than the %s of the quota. "+
+								"Lower the %s of the containers or raise the quota",
+							extra.String(), r.resource, used.String(), r.key, hard.String(), kindOf(r.limits),
+						),
+					)
+					continue
+				}
+				score.AddComment(
+					"",
+					fmt.Sprintf("The %d replicas do not fit into the ResourceQuota %s with the overhead of the runtime class %s", replicas, quota.Name, rc.Name),
+					fmt.Sprintf(
+						"The overhead of %s %s is added to each of the %d pods, which then use %s of %s, more than the %s of the quota. "+
+							"Lower the %s of the containers or the replicas, or raise the quota",
+						extra.String(), r.resource, replicas, used.String(), r.key, hard.String(), kindOf(r.limits),
+					),
+				)
+			}
+		}
+
+		score.AddComment(
+			"",
+			fmt.Sprintf("The runtime class %s adds an overhead of %s per pod", rc.Name, formatResources(overhead)),
+			"The overhead is added to the requests and limits of the pod by the scheduler and the ResourceQuotas",
+		)
+		return score, nil
+	}
+}
+
+// podResource returns the requests or limits of a resource of a pod, like the scheduler counts them:
+// the larger of the sum of the containers and the sidecar containers, and the largest init
+// container together with the sidecar containers that are started before it. Sidecar containers are
+// the init containers with the restartPolicy Always. Limits are only counted if all containers set
+// them, after the defaults of the LimitRanges are applied.
+func podResource(
+	spec corev1.PodSpec,
+	limitRanges []ks.LimitRange,
+	name corev1.ResourceName,
+	limits bool,
+) (resource.Quantity, bool) {
+	of := func(c corev1.Container) (resource.Quantity, bool) {
+		requests, containerLimits := containerResources(c, limitRanges)
+		list := requests
+		if limits {
+			list = containerLimits
+		}
+		q, ok := list[name]
+		return q, ok
+	}
+
+	var sum resource.Quantity
+	for _, c := range spec.Containers {
+		q, ok := of(c)
+		if !ok && limits {
+			return resource.Quantity{}, false
+		}
+		sum.Add(q)
+	}
+
+	var sidecars, initContainers resource.Quantity
+	for _, c := range spec.InitContainers {
+		q, ok := of(c)
+		if !ok && limits {
+			return resource.Quantity{}, false
+		}
+		if c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways {
+			// Sidecar containers keep running, and are added to the init containers after them
+			sidecars.Add(q)
+			q = sidecars.DeepCopy()
+		} else {
+			q.Add(sidecars)
+		}
+		if q.Cmp(initContainers) > 0 {
+			initContainers = q
+		}
+	}
+
+	sum.Add(sidecars)
+	if initContainers.Cmp(sum) > 0 {
+		sum = initContainers
+	}
+	return sum, true
+}
+
+// containerResources returns the requests and limits of the container like the API server defaults
+// them: requests that are not set default to the limits, and the requests and limits that are still
+// not set to the defaults of the first LimitRange of the namespace that has them
+func containerResources(c corev1.Container, limitRanges []ks.LimitRange) (requests, limits corev1.ResourceList) {
+	requests = maps.Clone(c.Resources.Requests)
+	if requests == nil {
+		requests = corev1.ResourceList{}
+	}
+	limits = maps.Clone(c.Resources.Limits)
+	if limits == nil {
+		limits = corev1.ResourceList{}
+	}
+	for name, q := range limits {
+		if _, ok := requests[name]; !ok {
+			requests[name] = q
+		}
+	}
+
+	for _, l := range limitRanges {
+		for _, item := range l.LimitRange().Spec.Limits {
+			if item.Type != corev1.LimitTypeContainer {
+				continue
+			}
+			defaultLimits, defaultRequests := limitRangeDefaults(item)
+			for name, q := range defaultLimits {
+				if _, ok := limits[name]; !ok {
+					limits[name] = q
+				}
+			}
+			for name, q := range defaultRequests {
+				if _, ok := requests[name]; !ok {
+					requests[name] = q
+				}
+			}
+		}
+	}
+	return requests, limits
+}
+
+// limitRangeDefaults returns the default limits and requests of a LimitRange item of containers,
+// with the defaults of the API server: the default limit is the max, and the default request is
+// the default limit, or the min
+func limitRangeDefaults(item corev1.LimitRangeItem) (limits, requests corev1.ResourceList) {
+	limits = maps.Clone(item.Default)
+	if limits == nil {
+		limits = corev1.ResourceList{}
+	}
+	for name, q := range item.Max {
+		if _, ok := limits[name]; !ok {
+			limits[name] = q
+		}
+	}
+	requests = maps.Clone(item.DefaultRequest)
+	if requests == nil {
+		requests = corev1.ResourceList{}
+	}
+	for _, defaults := range []corev1.ResourceList{limits, item.Min} {
+		for name, q := range defaults {
+			if _, ok := requests[name]; !ok {
+				requests[name] = q
+			}
+		}
+	}
+	return limits, requests
+}
+
+func kindOf(limits bool) string {
+	if limits {
+		return "limits"
+	}
+	return "requests"
+}
+
+func formatResources(list corev1.ResourceList) string {
+	var parts []string
+	for name, q := range list {
+		parts = append(parts, fmt.Sprintf("%s %s", q.String(), name))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,157 @@
+package pod
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/score/checks"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+type Options struct {
+	Namespace ks.DefaultNamespace
+	// AllowedRuntimeClasses are the allowed values of runtimeClassName, all values are allowed if it is empty
+	AllowedRuntimeClasses []string
+	// RuntimeClassRequiredNamespaces are patterns of the namespaces in which pods must set a runtimeClassName
+	RuntimeClassRequiredNamespaces []string
+}
+
+func Register(allChecks *checks.Checks, options Options) {
+	allChecks.RegisterPodObjectCheck(
+		"Pod Has Controller",
+		`Makes sure that standalone Pods are managed by a controller, bare Pods are not rescheduled if their node fails`,
+		podHasController,
+	)
+	allChecks.RegisterPodObjectCheck(
+		"Pod RestartPolicy",
+		`Makes sure that the restartPolicy of Pods and their containers is valid, and matches the controller of the Pod`,
+		podRestartPolicy,
+	)
+	allChecks.RegisterOptionalPodCheck(
+		"Pod DNS",
+		`Makes sure that the dnsPolicy and dnsConfig of pods can resolve the names of Services, and that ndots is tuned`,
+		podDNS,
+	)
+	allChecks.RegisterOptionalPodCheck(
+		"Pod Node Placement",
+		`Makes sure that pods with tolerations also select nodes, and that no deprecated node labels are used`,
+		podNodePlacement,
+	)
+	allChecks.RegisterOptionalPodCheck(
+		"Pod RuntimeClass",
+		`Makes sure that pods only use allowed runtime classes, and set one in the namespaces that require it`,
+		podRuntimeClass(options),
+	)
+	allChecks.RegisterPodCheck(
+		"Pod Ephemeral Containers",
+		`Reports the ephemeral containers of manifests, which are debug containers of running pods. Pods that are captured from a cluster are not reported`,
+		podEphemeralContainers,
+	)
+	allChecks.RegisterPodContextCheck(
+		"Pod Overhead",
+		`Makes sure that the replicas of pods with a RuntimeClass with an overhead still fit into the ResourceQuotas of their namespace, with the defaults of its LimitRanges, and reports the overhead per pod`,
+		func(ctx *checks.Context, ps ks.PodSpecer) (scorecard.TestScore, error) {
+			runtimeClass := ps.GetPodTemplateSpec().Spec.RuntimeClassName
+			if runtimeClass == nil {
+				return scorecard.TestScore{Grade: scorecard.GradeAllOK}, nil
+			}
+			rc, ok := runtimeClassNamed(ctx, *runtimeClass)
+			if !ok {
+				return scorecard.TestScore{Grade: scorecard.GradeAllOK}, nil
+			}
+			namespace := ps.GetObjectMeta().Namespace
+			return podOverhead(
+				rc,
+				resourceQuotasIn(ctx, options, namespace),
+				limitRangesIn(ctx, options, namespace),
+				replicasOf(ctx, options, ps),
+			)(ps)
+		},
+	)
+}
+
+func podHasController(pod corev1.Pod) (score scorecard.TestScore, err error) {
+	if metav1.GetControllerOfNoCopy(&pod) != nil {
+		score.Grade = scorecard.GradeAllOK
+		return
+	}
+
+	score.Grade = scorecard.GradeWarning
+	score.AddComment(
+		"",
+		"The pod is not managed by a controller",
+		"Bare pods are not rescheduled if their node fails or is drained. "+
+			"Use a Deployment or StatefulSet for long running pods, and a Job for pods that run to completion.",
+	)
+	return
+}
+
+func podRestartPolicy(pod corev1.Pod) (score scorecard.TestScore, err error) {
+	score.Grade = scorecard.GradeAllOK
+
+	restartPolicy := pod.Spec.RestartPolicy
+	switch restartPolicy {
+	case "", corev1.RestartPolicyAlways, corev1.RestartPolicyOnFailure, corev1.RestartPolicyNever:
+	default:
+		score.Grade = scorecard.GradeCritical
+		score.AddComment(
+			"",
+			"The pod has an invalid restartPolicy",
+			fmt.Sprintf("The restartPolicy %q is not one of Always, OnFailure or Never", restartPolicy),
+		)
+	}
+
+	// An empty restartPolicy defaults to Always
+	if restartPolicy == "" {
+		restartPolicy = corev1.RestartPolicyAlways
+	}
+
+	if controller := metav1.GetControllerOfNoCopy(&pod); controller != nil {
+		switch controller.Kind {
+		case "ReplicaSet", "ReplicationController", "StatefulSet", "DaemonSet":
+			if restartPolicy != corev1.RestartPolicyAlways {
+				score.Grade = scorecard.GradeCritical
+				score.AddComment(
+					"",
+					"The restartPolicy does not match the controller of the pod",
+					fmt.Sprintf("Pods of a %s must have the restartPolicy Always, but has %s", controller.Kind, restartPolicy),
+				)
+			}
+		case "Job":
+			if restartPolicy == corev1.RestartPolicyAlways {
+				score.Grade = scorecard.GradeCritical
+				score.AddComment(
+					"",
+					"The restartPolicy does not match the controller of the pod",
+					"Pods of a Job must have the restartPolicy OnFailure or Never",
+				)
+			}
+		}
+	}
+
+	for _, container := range pod.Spec.Containers {
+		if container.RestartPolicy != nil {
+			score.Grade = scorecard.GradeCritical
+			score.AddComment(
+				container.Name,
+				"The container has a restartPolicy",
+				"A restartPolicy can only be set on init containers, to run them as sidecars",
+			)
+		}
+	}
+	for _, container := range pod.Spec.InitContainers {
+		if container.RestartPolicy != nil && *container.RestartPolicy != corev1.ContainerRestartPolicyAlways {
+			score.Grade = scorecard.GradeCritical
+			score.AddComment(
+				container.Name,
+				"The init container has an invalid restartPolicy",
+				fmt.Sprintf("The restartPolicy of init containers can only be Always, but is %s", *container.RestartPolicy),
+			)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,57 @@
+package pod
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// podRuntimeClass checks the runtimeClassName of the pod against the allowed runtime classes,
+// and requires one in the configured namespaces
+func podRuntimeClass(options Options) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		score.Grade = scorecard.GradeAllOK
+
+		namespace := options.Namespace.Of(ps.GetObjectMeta().Namespace)
+
+		runtimeClass := ps.GetPodTemplateSpec().Spec.RuntimeClassName
+		if runtimeClass == nil || *runtimeClass == "" {
+			if matchesAny(options.RuntimeClassRequiredNamespaces, namespace) {
+				score.Grade = scorecard.GradeCritical
+				score.AddComment(
+					"",
+					"The pod has no runtimeClassName",
+					fmt.Sprintf(
+						"Pods in the namespace %q must run with one of the runtime classes %s",
+						namespace, strings.Join(options.AllowedRuntimeClasses, ", "),
+					),
+				)
+			}
+			return
+		}
+
+		if len(options.AllowedRuntimeClasses) > 0 && !slices.Contains(options.AllowedRuntimeClasses, *runtimeClass) {
+			score.Grade = scorecard.GradeCritical
+			score.AddComment(
+				"",
+				fmt.Sprintf("The runtime class %s is not allowed", *runtimeClass),
+				fmt.Sprintf("Use one of the runtime classes %s", strings.Join(options.AllowedRuntimeClasses, ", ")),
+			)
+		}
+		return
+	}
+}
+
+// matchesAny returns true if the value matches any of the patterns of path.Match
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package pod
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// deprecatedNodeLabels maps deprecated well-known node labels to their replacements
+var deprecatedNodeLabels = map[string]string{
+	"beta.kubernetes.io/os":                    "kubernetes.io/os",
+	"beta.kubernetes.io/arch":                  "kubernetes.io/arch",
+	"beta.kubernetes.io/instance-type":         "node.kubernetes.io/instance-type",
+	"failure-domain.beta.kubernetes.io/zone":   "topology.kubernetes.io/zone",
+	"failure-domain.beta.kubernetes.io/region": "topology.kubernetes.io/region",
+}
+
+// nodeConditionTaintPrefix is the prefix of the taints of node conditions, which are tolerated by
+// most pods for a limited time, and do not select any nodes
+const nodeConditionTaintPrefix = "node.kubernetes.io/"
+
+// podNodePlacement checks that tolerations are paired with a nodeSelector or node affinity, and that
+// no deprecated node labels are selected
+func podNodePlacement(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+	spec := ps.GetPodTemplateSpec().Spec
+	score.Grade = scorecard.GradeAllOK
+
+	selectedLabels := nodeSelectorLabels(spec)
+
+	if len(selectedLabels) == 0 {
+		for _, toleration := range spec.Tolerations {
+			if strings.HasPrefix(toleration.Key, nodeConditionTaintPrefix) {
+				continue
+			}
+			key := toleration.Key
+			if key == "" {
+				key = "all taints"
+			}
+			score.Grade = scorecard.GradeWarning
+			score.AddComment(
+				"",
+				fmt.Sprintf("The pod tolerates %s without selecting nodes", key),
+				"Tolerations allow pods on tainted nodes, but do not make them run there. "+
+					"Add a nodeSelector or a required node affinity, so that the pod does not land on unintended nodes",
+			)
+		}
+	}
+
+	for _, label := range selectedLabels {
+		if replacement, ok := deprecatedNodeLabels[label]; ok {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment(
+				"",
+				fmt.Sprintf("The pod selects nodes by the deprecated label %s", label),
+				fmt.Sprintf("Use the label %s instead", replacement),
+			)
+		}
+	}
+
+	return
+}
+
+// nodeSelectorLabels returns the sorted node labels in the nodeSelector and the required node affinity
+func nodeSelectorLabels(spec corev1.PodSpec) []string {
+	labels := make(map[string]struct{})
+	for label := range spec.NodeSelector {
+		labels[label] = struct{}{}
+	}
+	if spec.Affinity != nil && spec.Affinity.NodeAffinity != nil {
+		if required := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution; required != nil {
+			for _, term := range required.NodeSelectorTerms {
+				for _, expr := range term.MatchExpressions {
+					labels[expr.Key] = struct{}{}
+				}
+			}
+		}
+	}
+
+	sorted := make([]string, 0, len(labels))
+	for label := range labels {
+		sorted = append(sorted, label)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
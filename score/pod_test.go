@@ -0,0 +1,345 @@
+package score
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func TestPodHasControllerBarePod(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-env-ok.yaml", "Pod Has Controller", scorecard.GradeWarning)
+}
+
+func TestPodHasControllerOwnedPod(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-owned-by-replicaset.yaml", "Pod Has Controller", scorecard.GradeAllOK)
+}
+
+func TestPodHasControllerNotRunOnTemplates(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore([]ks.NamedReader{testFile("deployment-host-antiaffinity-1-replica.yaml")}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, o := range sc {
+		for _, c := range o.Checks {
+			if c.Check.ID == "pod-has-controller" || c.Check.ID == "pod-restartpolicy" {
+				t.Errorf("unexpected check %s on %s", c.Check.ID, o.TypeMeta.Kind)
+			}
+		}
+	}
+}
+
+func TestPodRestartPolicyValid(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-owned-by-replicaset.yaml", "Pod RestartPolicy", scorecard.GradeAllOK)
+}
+
+func TestPodRestartPolicyInitContainerSidecar(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-init-container-sidecar.yaml", "Pod RestartPolicy", scorecard.GradeAllOK)
+}
+
+func TestPodRestartPolicyInvalid(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-restartpolicy-invalid.yaml", "Pod RestartPolicy", scorecard.GradeCritical)
+}
+
+func TestPodRestartPolicyReplicaSetNever(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(
+		t,
+		"pod-owned-by-replicaset-restartpolicy-never.yaml",
+		"Pod RestartPolicy",
+		scorecard.GradeCritical,
+	)
+	if len(comments) != 1 || comments[0].Summary != "The restartPolicy does not match the controller of the pod" {
+		t.Errorf("unexpected comments %+v", comments)
+	}
+}
+
+func TestPodRestartPolicyJobAlways(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-owned-by-job-restartpolicy-always.yaml", "Pod RestartPolicy", scorecard.GradeCritical)
+}
+
+func TestPodRestartPolicyContainer(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-container-restartpolicy.yaml", "Pod RestartPolicy", scorecard.GradeCritical)
+	if len(comments) != 1 || comments[0].Path != "foobar" {
+		t.Errorf("unexpected comments %+v", comments)
+	}
+}
+
+func testPodDNS(t *testing.T, file string, expected scorecard.Grade) []scorecard.TestScoreComment {
+	t.Helper()
+	return testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile(file)},
+		nil,
+		&config.RunConfiguration{
+			EnabledOptionalTests: map[string]struct{}{"pod-dns": {}},
+		},
+		"Pod DNS",
+		expected,
+	)
+}
+
+func TestPodDNSDefaultPolicyWithClusterService(t *testing.T) {
+	t.Parallel()
+	comments := testPodDNS(t, "pod-dns-default-cluster-service.yaml", scorecard.GradeWarning)
+	if len(comments) != 1 || comments[0].Summary != "The pod does not use the cluster DNS" {
+		t.Errorf("unexpected comments %+v", comments)
+	}
+}
+
+func TestPodDNSHostNetwork(t *testing.T) {
+	t.Parallel()
+	comments := testPodDNS(t, "pod-dns-hostnetwork.yaml", scorecard.GradeWarning)
+	if len(comments) != 1 || comments[0].Summary != "The pod uses the host network without the cluster DNS" {
+		t.Errorf("unexpected comments %+v", comments)
+	}
+}
+
+func TestPodDNSNoneWithoutNameservers(t *testing.T) {
+	t.Parallel()
+	testPodDNS(t, "pod-dns-none-without-nameservers.yaml", scorecard.GradeCritical)
+}
+
+func TestPodDNSDefaultNdots(t *testing.T) {
+	t.Parallel()
+	testPodDNS(t, "pod-env-ok.yaml", scorecard.GradeAlmostOK)
+}
+
+func TestPodDNSNdots(t *testing.T) {
+	t.Parallel()
+	testPodDNS(t, "pod-dns-ndots.yaml", scorecard.GradeAllOK)
+}
+
+func testPodNodePlacement(t *testing.T, file string, expected scorecard.Grade) []scorecard.TestScoreComment {
+	t.Helper()
+	return testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile(file)},
+		nil,
+		&config.RunConfiguration{
+			EnabledOptionalTests: map[string]struct{}{"pod-node-placement": {}},
+		},
+		"Pod Node Placement",
+		expected,
+	)
+}
+
+func TestPodNodePlacementTolerationsWithoutSelector(t *testing.T) {
+	t.Parallel()
+	comments := testPodNodePlacement(t, "pod-tolerations-without-selector.yaml", scorecard.GradeWarning)
+	if len(comments) != 1 || comments[0].Summary != "The pod tolerates dedicated without selecting nodes" {
+		t.Errorf("unexpected comments %+v", comments)
+	}
+}
+
+func TestPodNodePlacementTolerationsWithAffinity(t *testing.T) {
+	t.Parallel()
+	testPodNodePlacement(t, "pod-tolerations-with-affinity.yaml", scorecard.GradeAllOK)
+}
+
+func TestPodNodePlacementDeprecatedLabel(t *testing.T) {
+	t.Parallel()
+	comments := testPodNodePlacement(t, "pod-nodeselector-deprecated-label.yaml", scorecard.GradeWarning)
+	if len(comments) != 1 || comments[0].Description != "Use the label kubernetes.io/os instead" {
+		t.Errorf("unexpected comments %+v", comments)
+	}
+}
+
+func TestPodRuntimeClass(t *testing.T) {
+	t.Parallel()
+
+	sc, err := testScore(
+		[]ks.NamedReader{testFile("pod-runtimeclass.yaml")},
+		nil,
+		&config.RunConfiguration{
+			EnabledOptionalTests: map[string]struct{}{"pod-runtimeclass": {}},
+			CheckParameters: config.CheckParameters{
+				"pod-runtimeclass": {
+					"allowedRuntimeClasses": []string{"gvisor"},
+					"requiredNamespaces":    []string{"untrusted-*"},
+				},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]scorecard.Grade{
+		"pod-gvisor":  scorecard.GradeAllOK,
+		"pod-kata":    scorecard.GradeCritical,
+		"pod-default": scorecard.GradeCritical,
+	}
+	scored := 0
+	for _, o := range sc {
+		for _, c := range o.Checks {
+			if c.Check.ID != "pod-runtimeclass" {
+				continue
+			}
+			scored++
+			if c.Grade != expected[o.ObjectMeta.Name] {
+				t.Errorf("%s: expected %s, got %s", o.ObjectMeta.Name, expected[o.ObjectMeta.Name], c.Grade)
+			}
+		}
+	}
+	if scored != len(expected) {
+		t.Errorf("expected %d scored pods, got %d", len(expected), scored)
+	}
+}
+
+func TestPodRuntimeClassNotConfigured(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("pod-runtimeclass.yaml")},
+		nil,
+		&config.RunConfiguration{
+			EnabledOptionalTests: map[string]struct{}{"pod-runtimeclass": {}},
+		},
+		"Pod RuntimeClass",
+		scorecard.GradeAllOK,
+	)
+}
+
+func TestPodOverhead(t *testing.T) {
+	t.Parallel()
+
+	sc, err := testScore([]ks.NamedReader{testFile("pod-overhead.yaml")}, nil, &config.RunConfiguration{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	comments := make(map[string][]string)
+	for _, o := range sc {
+		for _, c := range o.Checks {
+			if c.Check.ID != "pod-overhead" {
+				continue
+			}
+			for _, comment := range c.Comments {
+				comments[o.ObjectMeta.Name] = append(comments[o.ObjectMeta.Name], c.Grade.String()+": "+comment.Summary)
+			}
+		}
+	}
+
+	overhead := "The runtime class gvisor adds an overhead of 120Mi memory, 250m cpu per pod"
+	expected := map[string][]string{
+		"too-large": {
+			"CRITICAL: The pod does not fit into the ResourceQuota compute with the overhead of the runtime class gvisor",
+			"CRITICAL: " + overhead,
+		},
+		"fits": {"OK: " + overhead},
+		// The quota is used by all replicas
+		"replicas": {
+			"CRITICAL: The 3 replicas do not fit into the ResourceQuota compute with the overhead of the runtime class gvisor",
+			"CRITICAL: " + overhead,
+		},
+		// The memory limit is set by the LimitRange
+		"defaulted": {
+			"CRITICAL: The pod does not fit into the ResourceQuota compute with the overhead of the runtime class gvisor",
+			"CRITICAL: " + overhead,
+		},
+		// The sidecar runs next to the container, and is added to its requests
+		"sidecar": {
+			"CRITICAL: The pod does not fit into the ResourceQuota compute with the overhead of the runtime class gvisor",
+			"CRITICAL: " + overhead,
+		},
+	}
+	if !reflect.DeepEqual(expected, comments) {
+		t.Errorf("expected %v, got %v", expected, comments)
+	}
+}
+
+func TestPodEphemeralContainers(t *testing.T) {
+	t.Parallel()
+
+	sc, err := testScore([]ks.NamedReader{testFile("pod-ephemeral-containers.yaml")}, nil, &config.RunConfiguration{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]scorecard.Grade{
+		"manifest": scorecard.GradeInfo,
+		"captured": scorecard.GradeAllOK,
+	}
+	scored := 0
+	for _, o := range sc {
+		for _, c := range o.Checks {
+			if c.Check.ID != "pod-ephemeral-containers" {
+				continue
+			}
+			scored++
+			if c.Grade != expected[o.ObjectMeta.Name] {
+				t.Errorf("%s: expected %s, got %s", o.ObjectMeta.Name, expected[o.ObjectMeta.Name], c.Grade)
+			}
+		}
+	}
+	if scored != len(expected) {
+		t.Errorf("expected %d scored pods, got %d", len(expected), scored)
+	}
+}
+
+func testContainerImagePullSecrets(t *testing.T, params config.CheckParameters, expected map[string]scorecard.Grade) {
+	t.Helper()
+
+	sc, err := testScore(
+		[]ks.NamedReader{testFile("pod-image-pull-secrets.yaml")},
+		nil,
+		&config.RunConfiguration{
+			EnabledOptionalTests: map[string]struct{}{"container-image-pull-secrets": {}},
+			CheckParameters:      params,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scored := 0
+	for _, o := range sc {
+		for _, c := range o.Checks {
+			if c.Check.ID != "container-image-pull-secrets" {
+				continue
+			}
+			scored++
+			if c.Grade != expected[o.ObjectMeta.Name] {
+				t.Errorf("%s: expected %s, got %s", o.ObjectMeta.Name, expected[o.ObjectMeta.Name], c.Grade)
+			}
+		}
+	}
+	if scored != len(expected) {
+		t.Errorf("expected %d scored pods, got %d", len(expected), scored)
+	}
+}
+
+func TestContainerImagePullSecrets(t *testing.T) {
+	t.Parallel()
+	testContainerImagePullSecrets(t, nil, map[string]scorecard.Grade{
+		"pod-public":                    scorecard.GradeAllOK,
+		"pod-private-without-secrets":   scorecard.GradeCritical,
+		"pod-private-serviceaccount":    scorecard.GradeAllOK,
+		"pod-private-secret-missing":    scorecard.GradeWarning,
+		"pod-private-secret-wrong-type": scorecard.GradeWarning,
+	})
+}
+
+func TestContainerImagePullSecretsPublicRegistries(t *testing.T) {
+	t.Parallel()
+	testContainerImagePullSecrets(t, config.CheckParameters{
+		"container-image-pull-secrets": {"publicRegistries": []string{"docker.io", "*.example.com"}},
+	}, map[string]scorecard.Grade{
+		"pod-public":                    scorecard.GradeAllOK,
+		"pod-private-without-secrets":   scorecard.GradeAllOK,
+		"pod-private-serviceaccount":    scorecard.GradeAllOK,
+		"pod-private-secret-missing":    scorecard.GradeWarning,
+		"pod-private-secret-wrong-type": scorecard.GradeWarning,
+	})
+}
@@ -0,0 +1,428 @@
+// Package podsecurity evaluates the pods against the Pod Security Standards, like the Pod Security
+// Admission controller evaluates them against the levels and versions of the namespaces. The
+// standards are implemented here instead of with k8s.io/pod-security-admission/policy, which
+// depends on k8s.io/component-base and the client libraries of the API server.
+package podsecurity
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/score/checks"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// The levels of the Pod Security Standards
+const (
+	LevelPrivileged = "privileged"
+	LevelBaseline   = "baseline"
+	LevelRestricted = "restricted"
+)
+
+const labelPrefix = "pod-security.kubernetes.io/"
+
+type Options struct {
+	Namespace ks.DefaultNamespace
+	// DefaultEnforce is the level that is enforced in the namespaces without an enforce label, as
+	// configured in the AdmissionConfiguration of the cluster
+	DefaultEnforce string
+	// KubernetesVersion is the version of the standards of the latest version, like the Pod
+	// Security Admission evaluates latest as the version of its API server
+	KubernetesVersion config.Semver
+}
+
+func Register(allChecks *checks.Checks, options Options) {
+	allChecks.RegisterOptionalPodContextCheck(
+		"Pod Security Admission",
+		"Makes sure that the pods satisfy the Pod Security Standards that the Pod Security Admission enforces, audits and warns about in their namespace",
+		func(ctx *checks.Context, ps ks.PodSpecer) (scorecard.TestScore, error) {
+			levels := checks.Index(ctx, namespaceLevelsKey{options.Namespace},
+				func(all ks.AllTypes) map[string]namespaceLevels {
+					return namespaceLevelsByName(all.OtherObjects(), options)
+				},
+			)
+			return podSecurityAdmission(levels, options)(ps)
+		},
+	)
+}
+
+// policy is the level and version of the Pod Security Standards of a mode
+type policy struct {
+	level   string
+	version config.Semver
+}
+
+func (p policy) String() string {
+	return fmt.Sprintf("%s Pod Security Standard of %s", p.level, p.version)
+}
+
+// namespaceLevels are the policies of the Pod Security Standards of a namespace by mode
+type namespaceLevels struct {
+	enforce, audit, warn policy
+}
+
+// namespaceLevelsKey is the key of the index of the levels of the namespaces
+type namespaceLevelsKey struct{ namespace ks.DefaultNamespace }
+
+func namespaceLevelsByName(others []ks.BothMeta, options Options) map[string]namespaceLevels {
+	levels := make(map[string]namespaceLevels)
+	for _, o := range others {
+		if o.TypeMeta.Kind != "Namespace" || o.TypeMeta.APIVersion != "v1" {
+			continue
+		}
+		levels[o.ObjectMeta.Name] = namespacePolicies(o.ObjectMeta.Labels, options)
+	}
+	return levels
+}
+
+func namespacePolicies(labels map[string]string, options Options) namespaceLevels {
+	return namespaceLevels{
+		enforce: policy{level(labels, "enforce", options.DefaultEnforce), version(labels, "enforce", options.KubernetesVersion)},
+		audit:   policy{level(labels, "audit", LevelPrivileged), version(labels, "audit", options.KubernetesVersion)},
+		warn:    policy{level(labels, "warn", LevelPrivileged), version(labels, "warn", options.KubernetesVersion)},
+	}
+}
+
+// level returns the level of the mode. Like with the Pod Security Admission, an invalid level is
+// evaluated as restricted.
+func level(labels map[string]string, mode, defaultLevel string) string {
+	value, ok := labels[labelPrefix+mode]
+	if !ok {
+		value = defaultLevel
+	}
+	switch value {
+	case "", LevelPrivileged:
+		return LevelPrivileged
+	case LevelBaseline:
+		return LevelBaseline
+	default:
+		return LevelRestricted
+	}
+}
+
+// version returns the version of the standards of the mode. Like with the Pod Security Admission,
+// latest, an invalid version and versions newer than the API server are evaluated as latest.
+func version(labels map[string]string, mode string, latest config.Semver) config.Semver {
+	value, ok := labels[labelPrefix+mode+"-version"]
+	if !ok || value == "latest" {
+		return latest
+	}
+	v, err := config.ParseSemver(value)
+	if err != nil || latest.LessThan(v) {
+		return latest
+	}
+	return v
+}
+
+func podSecurityAdmission(
+	levels map[string]namespaceLevels,
+	options Options,
+) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		namespace := options.Namespace.Of(ps.GetObjectMeta().Namespace)
+		nsLevels, ok := levels[namespace]
+		if !ok {
+			nsLevels = namespacePolicies(nil, options)
+		}
+
+		score.Grade = scorecard.GradeAllOK
+		enforce := nsLevels.enforce
+		if violations := Violations(ps.GetPodTemplateSpec(), enforce.level, enforce.version); len(violations) > 0 {
+			score.Grade = scorecard.GradeCritical
+			for _, v := range violations {
+				score.AddComment(v.Path, v.Summary, fmt.Sprintf(
+					"The namespace %s enforces the %s, the pods are rejected",
+					namespace, enforce,
+				))
+			}
+			return
+		}
+
+		// The pods are admitted, but are reported by the audit log and the warnings to the user
+		mode, p := "warns about", nsLevels.warn
+		if strictness(nsLevels.audit.level) > strictness(nsLevels.warn.level) {
+			mode, p = "audits", nsLevels.audit
+		}
+		if violations := Violations(ps.GetPodTemplateSpec(), p.level, p.version); len(violations) > 0 {
+			score.Grade = scorecard.GradeWarning
+			for _, v := range violations {
+				score.AddComment(v.Path, v.Summary, fmt.Sprintf(
+					"The namespace %s %s the %s, the pods will be rejected if it is enforced",
+					namespace, mode, p,
+				))
+			}
+		}
+		return
+	}
+}
+
+func strictness(level string) int {
+	return slices.Index([]string{LevelPrivileged, LevelBaseline, LevelRestricted}, level)
+}
+
+// Violation is a violation of a Pod Security Standard. Path is the name of the container, or empty
+// for the pod.
+type Violation struct {
+	Path    string
+	Summary string
+}
+
+// Violations returns the violations of the pod of the Pod Security Standard of the level, as
+// defined by the version of the standards
+func Violations(pod corev1.PodTemplateSpec, level string, version config.Semver) []Violation {
+	switch level {
+	case LevelBaseline:
+		return baseline(pod, version)
+	case LevelRestricted:
+		return append(baseline(pod, version), restricted(pod, version)...)
+	default:
+		return nil
+	}
+}
+
+// since returns true if the version of the standards includes the control of the version
+func since(version config.Semver, minor int) bool {
+	return !version.LessThan(config.Semver{Major: 1, Minor: minor})
+}
+
+// container is a container, init container or ephemeral container of a pod
+type container struct {
+	name            string
+	securityContext *corev1.SecurityContext
+	ports           []corev1.ContainerPort
+}
+
+// containers returns the init containers and containers of the pod. Ephemeral containers are
+// debug containers that are added to running pods, they are not part of the manifests that are
+// scored, see pod-ephemeral-containers.
+func containers(spec corev1.PodSpec) []container {
+	var all []container
+	for _, c := range spec.InitContainers {
+		all = append(all, container{c.Name, c.SecurityContext, c.Ports})
+	}
+	for _, c := range spec.Containers {
+		all = append(all, container{c.Name, c.SecurityContext, c.Ports})
+	}
+	return all
+}
+
+var baselineCapabilities = []corev1.Capability{
+	"AUDIT_WRITE", "CHOWN", "DAC_OVERRIDE", "FOWNER", "FSETID", "KILL", "MKNOD", "NET_BIND_SERVICE",
+	"SETFCAP", "SETGID", "SETPCAP", "SETUID", "SYS_CHROOT",
+}
+
+// baselineSELinuxTypes are the allowed SELinux types by the version that allows them
+var baselineSELinuxTypes = map[string]int{
+	"":                   0,
+	"container_t":        0,
+	"container_init_t":   0,
+	"container_kvm_t":    0,
+	"container_engine_t": 31,
+}
+
+// baselineSysctls are the allowed sysctls by the version that allows them
+var baselineSysctls = map[string]int{
+	"kernel.shm_rmid_forced":              0,
+	"net.ipv4.ip_local_port_range":        0,
+	"net.ipv4.tcp_syncookies":             0,
+	"net.ipv4.ping_group_range":           0,
+	"net.ipv4.ip_unprivileged_port_start": 22,
+	"net.ipv4.ip_local_reserved_ports":    27,
+	"net.ipv4.tcp_keepalive_time":         29,
+	"net.ipv4.tcp_fin_timeout":            29,
+	"net.ipv4.tcp_keepalive_intvl":        29,
+	"net.ipv4.tcp_keepalive_probes":       29,
+}
+
+// allowedIn returns true if the value is allowed by the version
+func allowedIn(allowed map[string]int, value string, version config.Semver) bool {
+	minor, ok := allowed[value]
+	return ok && since(version, minor)
+}
+
+const appArmorAnnotationPrefix = "container.apparmor.security.beta.kubernetes.io/"
+
+func baseline(pod corev1.PodTemplateSpec, version config.Semver) []Violation {
+	var violations []Violation
+	add := func(path, format string, args ...any) {
+		violations = append(violations, Violation{Path: path, Summary: fmt.Sprintf(format, args...)})
+	}
+
+	spec := pod.Spec
+	if spec.HostNetwork {
+		add("", "The pod uses the network namespace of the host")
+	}
+	if spec.HostPID {
+		add("", "The pod uses the process namespace of the host")
+	}
+	if spec.HostIPC {
+		add("", "The pod uses the IPC namespace of the host")
+	}
+	for _, v := range spec.Volumes {
+		if v.HostPath != nil {
+			add("", "The volume %s is a hostPath volume", v.Name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(pod.Annotations)) {
+		value := pod.Annotations[name]
+		if c, ok := strings.CutPrefix(name, appArmorAnnotationPrefix); ok &&
+			value != "runtime/default" && !strings.HasPrefix(value, "localhost/") {
+			add(c, "The AppArmor profile of the container is %s", value)
+		}
+	}
+
+	if sc := spec.SecurityContext; sc != nil {
+		if sc.WindowsOptions != nil && sc.WindowsOptions.HostProcess != nil && *sc.WindowsOptions.HostProcess {
+			add("", "The pod is a Windows HostProcess pod")
+		}
+		if sc.AppArmorProfile != nil && sc.AppArmorProfile.Type == corev1.AppArmorProfileTypeUnconfined {
+			add("", "The AppArmor profile of the pod is Unconfined")
+		}
+		if since(version, 19) && sc.SeccompProfile != nil && sc.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+			add("", "The seccomp profile of the pod is Unconfined")
+		}
+		if v := seLinuxViolation(sc.SELinuxOptions, version); v != "" {
+			add("", "The pod %s", v)
+		}
+		for _, sysctl := range sc.Sysctls {
+			if !allowedIn(baselineSysctls, sysctl.Name, version) {
+				add("", "The pod sets the sysctl %s", sysctl.Name)
+			}
+		}
+	}
+
+	for _, c := range containers(spec) {
+		for _, port := range c.ports {
+			if port.HostPort != 0 {
+				add(c.name, "The container uses the host port %d", port.HostPort)
+			}
+		}
+
+		sc := c.securityContext
+		if sc == nil {
+			continue
+		}
+		if sc.WindowsOptions != nil && sc.WindowsOptions.HostProcess != nil && *sc.WindowsOptions.HostProcess {
+			add(c.name, "The container is a Windows HostProcess container")
+		}
+		if sc.Privileged != nil && *sc.Privileged {
+			add(c.name, "The container is privileged")
+		}
+		if sc.Capabilities != nil {
+			for _, capability := range sc.Capabilities.Add {
+				if !slices.Contains(baselineCapabilities, capability) {
+					add(c.name, "The container adds the capability %s", capability)
+				}
+			}
+		}
+		if sc.AppArmorProfile != nil && sc.AppArmorProfile.Type == corev1.AppArmorProfileTypeUnconfined {
+			add(c.name, "The AppArmor profile of the container is Unconfined")
+		}
+		if since(version, 19) && sc.SeccompProfile != nil && sc.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+			add(c.name, "The seccomp profile of the container is Unconfined")
+		}
+		if v := seLinuxViolation(sc.SELinuxOptions, version); v != "" {
+			add(c.name, "The container %s", v)
+		}
+		if sc.ProcMount != nil && *sc.ProcMount != corev1.DefaultProcMount {
+			add(c.name, "The container uses the %s /proc mount", *sc.ProcMount)
+		}
+	}
+	return violations
+}
+
+func seLinuxViolation(options *corev1.SELinuxOptions, version config.Semver) string {
+	switch {
+	case options == nil:
+		return ""
+	case !allowedIn(baselineSELinuxTypes, options.Type, version):
+		return fmt.Sprintf("sets the SELinux type %s", options.Type)
+	case options.User != "":
+		return fmt.Sprintf("sets the SELinux user %s", options.User)
+	case options.Role != "":
+		return fmt.Sprintf("sets the SELinux role %s", options.Role)
+	default:
+		return ""
+	}
+}
+
+func restricted(pod corev1.PodTemplateSpec, version config.Semver) []Violation {
+	var violations []Violation
+	add := func(path, format string, args ...any) {
+		violations = append(violations, Violation{Path: path, Summary: fmt.Sprintf(format, args...)})
+	}
+
+	spec := pod.Spec
+	for _, v := range spec.Volumes {
+		if v.ConfigMap == nil && v.CSI == nil && v.DownwardAPI == nil && v.EmptyDir == nil &&
+			v.Ephemeral == nil && v.PersistentVolumeClaim == nil && v.Projected == nil && v.Secret == nil &&
+			v.HostPath == nil {
+			add("", "The volume %s is not a configMap, csi, downwardAPI, emptyDir, ephemeral, persistentVolumeClaim, projected or secret volume", v.Name)
+		}
+	}
+
+	podSC := spec.SecurityContext
+	if podSC == nil {
+		podSC = &corev1.PodSecurityContext{}
+	}
+	if podSC.RunAsNonRoot != nil && !*podSC.RunAsNonRoot {
+		add("", "The pod sets runAsNonRoot to false")
+	}
+	if since(version, 23) && podSC.RunAsUser != nil && *podSC.RunAsUser == 0 {
+		add("", "The pod runs as root")
+	}
+
+	// Privilege escalation, seccomp and capabilities are only restricted on Linux since v1.25
+	linux := !since(version, 25) || spec.OS == nil || spec.OS.Name != corev1.Windows
+
+	for _, c := range containers(spec) {
+		sc := c.securityContext
+		if sc == nil {
+			sc = &corev1.SecurityContext{}
+		}
+
+		switch {
+		case sc.RunAsNonRoot != nil && !*sc.RunAsNonRoot:
+			add(c.name, "The container sets runAsNonRoot to false")
+		case sc.RunAsNonRoot == nil && (podSC.RunAsNonRoot == nil || !*podSC.RunAsNonRoot):
+			add(c.name, "The container does not set runAsNonRoot to true")
+		}
+		if since(version, 23) && sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+			add(c.name, "The container runs as root")
+		}
+
+		if !linux {
+			continue
+		}
+		if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			add(c.name, "The container does not set allowPrivilegeEscalation to false")
+		}
+		profile := podSC.SeccompProfile
+		if sc.SeccompProfile != nil {
+			profile = sc.SeccompProfile
+		}
+		if since(version, 19) && profile == nil {
+			add(c.name, "The container does not set a seccomp profile")
+		}
+		if !since(version, 22) {
+			continue
+		}
+		if sc.Capabilities == nil || !slices.Contains(sc.Capabilities.Drop, "ALL") {
+			add(c.name, "The container does not drop all capabilities")
+		}
+		if sc.Capabilities != nil {
+			for _, capability := range sc.Capabilities.Add {
+				if capability != "NET_BIND_SERVICE" && slices.Contains(baselineCapabilities, capability) {
+					add(c.name, "The container adds the capability %s", capability)
+				}
+			}
+		}
+	}
+	return violations
+}
@@ -0,0 +1,127 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/score/podsecurity"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func podSecurityAdmissionScores(t *testing.T, runConfig *config.RunConfiguration) (map[string]scorecard.Grade, map[string][]string) {
+	t.Helper()
+	runConfig.EnabledOptionalTests = map[string]struct{}{"pod-security-admission": {}}
+	if runConfig.KubernetesVersion == (config.Semver{}) {
+		runConfig.KubernetesVersion = config.Semver{Major: 1, Minor: 30}
+	}
+	sc, err := testScore([]ks.NamedReader{testFile("pod-security-admission.yaml")}, nil, runConfig)
+	assert.NoError(t, err)
+
+	grades := make(map[string]scorecard.Grade)
+	summaries := make(map[string][]string)
+	for _, o := range sc {
+		for _, c := range o.Checks {
+			if c.Check.ID != "pod-security-admission" {
+				continue
+			}
+			grades[o.ObjectMeta.Name] = c.Grade
+			for _, comment := range c.Comments {
+				summaries[o.ObjectMeta.Name] = append(summaries[o.ObjectMeta.Name], comment.Summary)
+			}
+		}
+	}
+	return grades, summaries
+}
+
+func TestPodSecurityAdmission(t *testing.T) {
+	t.Parallel()
+	grades, summaries := podSecurityAdmissionScores(t, &config.RunConfiguration{})
+
+	assert.Equal(t, map[string]scorecard.Grade{
+		"compliant": scorecard.GradeAllOK,
+		"root":      scorecard.GradeCritical,
+		"plain":     scorecard.GradeWarning,
+		"host":      scorecard.GradeCritical,
+		"unlabeled": scorecard.GradeAllOK,
+		"pinned":    scorecard.GradeAllOK,
+	}, grades)
+	assert.Equal(t, []string{
+		"The pod runs as root",
+		"The container does not set runAsNonRoot to true",
+		"The container does not set allowPrivilegeEscalation to false",
+		"The container does not set a seccomp profile",
+		"The container does not drop all capabilities",
+	}, summaries["root"])
+	assert.Equal(t, []string{
+		"The pod uses the network namespace of the host",
+		"The volume docker is a hostPath volume",
+		"The AppArmor profile of the container is unconfined",
+		"The container uses the host port 80",
+		"The container is privileged",
+		"The container adds the capability SYS_ADMIN",
+	}, summaries["host"])
+}
+
+func TestPodSecurityAdmissionDefaultEnforce(t *testing.T) {
+	t.Parallel()
+	grades, _ := podSecurityAdmissionScores(t, &config.RunConfiguration{
+		CheckParameters: config.CheckParameters{
+			"pod-security-admission": {"defaultEnforce": "baseline"},
+		},
+	})
+	assert.Equal(t, scorecard.GradeCritical, grades["unlabeled"])
+	// The level of the namespace takes precedence over the default
+	assert.Equal(t, scorecard.GradeWarning, grades["plain"])
+}
+
+func TestPodSecurityAdmissionVersion(t *testing.T) {
+	t.Parallel()
+	pod := func(caps *corev1.Capabilities) corev1.PodTemplateSpec {
+		return corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsNonRoot:   ptr.To(true),
+				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+				Sysctls:        []corev1.Sysctl{{Name: "net.ipv4.tcp_keepalive_time", Value: "60"}},
+			},
+			Containers: []corev1.Container{{Name: "app", SecurityContext: &corev1.SecurityContext{
+				AllowPrivilegeEscalation: ptr.To(false),
+				Capabilities:             caps,
+			}}},
+		}}
+	}
+	summaries := func(violations []podsecurity.Violation) []string {
+		var res []string
+		for _, v := range violations {
+			res = append(res, v.Summary)
+		}
+		return res
+	}
+
+	// Dropping all capabilities is required since v1.22, the sysctl is allowed since v1.29
+	assert.Equal(t, []string{
+		"The pod sets the sysctl net.ipv4.tcp_keepalive_time",
+	}, summaries(podsecurity.Violations(pod(nil), podsecurity.LevelRestricted, config.Semver{Major: 1, Minor: 21})))
+	assert.Equal(t, []string{
+		"The pod sets the sysctl net.ipv4.tcp_keepalive_time",
+		"The container does not drop all capabilities",
+	}, summaries(podsecurity.Violations(pod(nil), podsecurity.LevelRestricted, config.Semver{Major: 1, Minor: 28})))
+	assert.Empty(t, podsecurity.Violations(
+		pod(&corev1.Capabilities{Drop: []corev1.Capability{"ALL"}}), podsecurity.LevelRestricted, config.Semver{Major: 1, Minor: 29},
+	))
+}
+
+func TestPodSecurityAdmissionBeforeV1_23(t *testing.T) {
+	t.Parallel()
+	grades, summaries := podSecurityAdmissionScores(t, &config.RunConfiguration{
+		KubernetesVersion: config.Semver{Major: 1, Minor: 22},
+	})
+	assert.Equal(t, scorecard.GradeAllOK, grades["root"])
+	assert.Equal(t, []string{
+		"Skipped because the check applies to Kubernetes v1.23 and newer, and the target version is v1.22",
+	}, summaries["root"])
+}
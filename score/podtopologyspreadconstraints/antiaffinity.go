@@ -0,0 +1,74 @@
+package podtopologyspreadconstraints
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// podAntiAffinityTopologySpreadDuplication is an advisory for pods that spread their replicas over
+// the nodes with both a podAntiAffinity and a topologySpreadConstraint. The scheduler has to satisfy
+// both, and a required podAntiAffinity together with a DoNotSchedule constraint commonly leaves pods
+// unschedulable when the workload is scaled to more replicas than there are nodes.
+func podAntiAffinityTopologySpreadDuplication(pod ks.PodSpecer) (score scorecard.TestScore, err error) {
+	template := pod.GetPodTemplateSpec()
+	labels := k8slabels.Set(template.Labels)
+	score.Grade = scorecard.GradeAllOK
+
+	spreads := false
+	for _, spread := range template.Spec.TopologySpreadConstraints {
+		if spread.TopologyKey == corev1.LabelHostname && selects(spread.LabelSelector, labels) {
+			spreads = true
+			break
+		}
+	}
+	if !spreads || template.Spec.Affinity == nil || template.Spec.Affinity.PodAntiAffinity == nil {
+		return score, nil
+	}
+
+	antiAffinity := template.Spec.Affinity.PodAntiAffinity
+	kind := ""
+	for _, term := range antiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+		if term.TopologyKey == corev1.LabelHostname && selects(term.LabelSelector, labels) {
+			kind = "required"
+			break
+		}
+	}
+	if kind == "" {
+		for _, term := range antiAffinity.PreferredDuringSchedulingIgnoredDuringExecution {
+			if term.PodAffinityTerm.TopologyKey == corev1.LabelHostname && selects(term.PodAffinityTerm.LabelSelector, labels) {
+				kind = "preferred"
+				break
+			}
+		}
+	}
+	if kind == "" {
+		return score, nil
+	}
+
+	score.Grade = scorecard.GradeInfo
+	score.AddComment(
+		"",
+		"The pods are spread over the nodes by both a podAntiAffinity and a topologySpreadConstraint",
+		fmt.Sprintf(
+			"The %s podAntiAffinity and the topologySpreadConstraint both use the topologyKey %s for the same pods. "+
+				"The scheduler has to satisfy both, which commonly leaves pods unschedulable when there are more replicas than nodes. "+
+				"Keep only the topologySpreadConstraint, with maxSkew and whenUnsatisfiable set to the strictness that is needed",
+			kind, corev1.LabelHostname,
+		),
+	)
+	return score, nil
+}
+
+func selects(selector *metav1.LabelSelector, labels k8slabels.Labels) bool {
+	if selector == nil {
+		return false
+	}
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	return err == nil && s.Matches(labels)
+}
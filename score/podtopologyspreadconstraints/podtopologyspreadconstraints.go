@@ -12,6 +12,11 @@ func Register(allChecks *checks.Checks) {
 		"Pod Topology Spread Constraints",
 		podTopologySpreadConstraints,
 	)
+	allChecks.RegisterPodCheck(
+		"Pod Anti Affinity and Topology Spread Duplication",
+		"Advises to consolidate a hostname podAntiAffinity and a hostname topologySpreadConstraint of the same pods into the topologySpreadConstraint",
+		podAntiAffinityTopologySpreadDuplication,
+	)
 }
 
 func podTopologySpreadConstraints(
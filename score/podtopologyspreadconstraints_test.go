@@ -3,6 +3,8 @@ package score
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/romnn/kube-score/scorecard"
 )
 
@@ -75,3 +77,25 @@ func TestPodTopologySpreadConstraintsInvalidDirective(t *testing.T) {
 		scorecard.GradeCritical,
 	)
 }
+
+func TestPodTopologySpreadConstraintsAntiAffinityDuplication(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(
+		t,
+		"pod-topology-spread-constraints-anti-affinity.yaml",
+		"Pod Anti Affinity and Topology Spread Duplication",
+		scorecard.GradeInfo,
+	)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The pods are spread over the nodes by both a podAntiAffinity and a topologySpreadConstraint", comments[0].Summary)
+}
+
+func TestPodTopologySpreadConstraintsWithoutAntiAffinity(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(
+		t,
+		"pod-topology-spread-constraints-one-constraint.yaml",
+		"Pod Anti Affinity and Topology Spread Duplication",
+		scorecard.GradeAllOK,
+	)
+}
@@ -0,0 +1,158 @@
+package priorityclass
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	schedulingv1 "k8s.io/api/scheduling/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/score/checks"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// highestUserDefinablePriority is the highest value of a PriorityClass that is not created by
+// Kubernetes, the system-cluster-critical and system-node-critical classes are above it
+const highestUserDefinablePriority = 1000000000
+
+type Options struct {
+	// BatchClassNames are patterns of the names of the PriorityClasses of batch workloads
+	BatchClassNames []string
+}
+
+func Register(allChecks *checks.Checks, options Options) {
+	allChecks.RegisterPriorityClassContextCheck(
+		"PriorityClass global default",
+		`Makes sure that at most one PriorityClass is the globalDefault`,
+		func(ctx *checks.Context, priorityClass schedulingv1.PriorityClass) (scorecard.TestScore, error) {
+			defaults := checks.Index(ctx, globalDefaultsKey{}, func(all ks.AllTypes) []string {
+				return globalDefaults(all.PriorityClasses())
+			})
+			return priorityClassGlobalDefault(defaults)(priorityClass)
+		},
+	)
+	allChecks.RegisterPriorityClassCheck(
+		"PriorityClass value",
+		`Makes sure that the value of the PriorityClass is not above the range of user defined classes, which is reserved for system-cluster-critical and system-node-critical`,
+		priorityClassValue,
+	)
+	allChecks.RegisterPriorityClassContextCheck(
+		"PriorityClass preemption policy",
+		`Makes sure that PriorityClasses of batch workloads set preemptionPolicy. A class is used by batch workloads if a Job or CronJob uses it, or if its name matches the batchClassNames parameter`,
+		func(ctx *checks.Context, priorityClass schedulingv1.PriorityClass) (scorecard.TestScore, error) {
+			batchClasses := checks.Index(ctx, batchClassesKey{}, func(all ks.AllTypes) map[string]bool {
+				return classesOfBatchWorkloads(all.PodSpeccers())
+			})
+			return priorityClassPreemptionPolicy(batchClasses, options)(priorityClass)
+		},
+	)
+}
+
+// globalDefaultsKey is the key of the index of the names of the globalDefault PriorityClasses
+type globalDefaultsKey struct{}
+
+// globalDefaults returns the names of the PriorityClasses that are the globalDefault
+func globalDefaults(priorityClasses []ks.PriorityClass) []string {
+	var names []string
+	for _, pc := range priorityClasses {
+		if pc.PriorityClass().GlobalDefault {
+			names = append(names, pc.PriorityClass().Name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+func priorityClassGlobalDefault(
+	defaults []string,
+) func(schedulingv1.PriorityClass) (scorecard.TestScore, error) {
+	return func(priorityClass schedulingv1.PriorityClass) (score scorecard.TestScore, err error) {
+		score.Grade = scorecard.GradeAllOK
+		if !priorityClass.GlobalDefault || len(defaults) < 2 {
+			return
+		}
+
+		score.Grade = scorecard.GradeCritical
+		score.AddComment(
+			"globalDefault",
+			"Multiple PriorityClasses are the globalDefault",
+			fmt.Sprintf(
+				"Only one PriorityClass can be the globalDefault, but %s are. Set globalDefault to true on only one of them.",
+				strings.Join(defaults, ", "),
+			),
+		)
+		return
+	}
+}
+
+func priorityClassValue(priorityClass schedulingv1.PriorityClass) (score scorecard.TestScore, err error) {
+	score.Grade = scorecard.GradeAllOK
+	if priorityClass.Value <= highestUserDefinablePriority {
+		return
+	}
+
+	score.Grade = scorecard.GradeCritical
+	score.AddComment(
+		"value",
+		"The value of the PriorityClass is too high",
+		fmt.Sprintf(
+			"Values above %d are reserved for the system-cluster-critical and system-node-critical PriorityClasses, and are rejected for other classes. "+
+				"Use one of the system classes, or a value of at most %d.",
+			highestUserDefinablePriority, highestUserDefinablePriority,
+		),
+	)
+	return
+}
+
+// batchClassesKey is the key of the index of the PriorityClasses that are used by batch workloads
+type batchClassesKey struct{}
+
+// classesOfBatchWorkloads returns the names of the PriorityClasses that are used by Jobs and CronJobs
+func classesOfBatchWorkloads(podSpeccers []ks.PodSpecer) map[string]bool {
+	classes := make(map[string]bool)
+	for _, p := range podSpeccers {
+		switch p.GetTypeMeta().Kind {
+		case "Job", "CronJob":
+			if name := p.GetPodTemplateSpec().Spec.PriorityClassName; name != "" {
+				classes[name] = true
+			}
+		}
+	}
+	return classes
+}
+
+func priorityClassPreemptionPolicy(
+	batchClasses map[string]bool,
+	options Options,
+) func(schedulingv1.PriorityClass) (scorecard.TestScore, error) {
+	return func(priorityClass schedulingv1.PriorityClass) (score scorecard.TestScore, err error) {
+		score.Grade = scorecard.GradeAllOK
+		if priorityClass.PreemptionPolicy != nil {
+			return
+		}
+		if !batchClasses[priorityClass.Name] && !matchesAny(options.BatchClassNames, priorityClass.Name) {
+			return
+		}
+
+		score.Grade = scorecard.GradeWarning
+		score.AddComment(
+			"preemptionPolicy",
+			"The PriorityClass of batch workloads does not set preemptionPolicy",
+			"Pods of the class preempt pods with a lower priority by default. "+
+				"Set preemptionPolicy to Never if batch workloads should wait for free resources instead of evicting running pods, or to PreemptLowerPriority to make it explicit.",
+		)
+		return
+	}
+}
+
+// matchesAny returns true if the value matches any of the patterns of path.Match
+func matchesAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, value); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func TestPriorityClass(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore([]ks.NamedReader{testFile("priorityclass.yaml")}, nil, nil)
+	assert.NoError(t, err)
+
+	grades := make(map[string]map[string]scorecard.Grade)
+	for _, o := range sc {
+		if o.TypeMeta.Kind != "PriorityClass" {
+			continue
+		}
+		grades[o.ObjectMeta.Name] = make(map[string]scorecard.Grade)
+		for _, c := range o.Checks {
+			grades[o.ObjectMeta.Name][c.Check.ID] = c.Grade
+		}
+	}
+
+	expected := func(globalDefault, value, preemptionPolicy scorecard.Grade) map[string]scorecard.Grade {
+		return map[string]scorecard.Grade{
+			"priorityclass-global-default":    globalDefault,
+			"priorityclass-value":             value,
+			"priorityclass-preemption-policy": preemptionPolicy,
+		}
+	}
+	ok := scorecard.GradeAllOK
+	for name, checks := range map[string]map[string]scorecard.Grade{
+		"default":       expected(scorecard.GradeCritical, ok, ok),
+		"also-default":  expected(scorecard.GradeCritical, ok, ok),
+		"too-high":      expected(ok, scorecard.GradeCritical, ok),
+		"nightly-batch": expected(ok, ok, scorecard.GradeWarning),
+		"reports":       expected(ok, ok, scorecard.GradeWarning),
+		"reports-never": expected(ok, ok, ok),
+	} {
+		for id, grade := range checks {
+			assert.Equal(t, grade, grades[name][id], "%s %s", name, id)
+		}
+	}
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
 	"github.com/romnn/kube-score/scorecard"
 )
 
@@ -185,3 +187,21 @@ func TestProbesMultipleContainersInit(t *testing.T) {
 	)
 	assert.Len(t, comments, 0)
 }
+
+func TestProbesLivenessExec(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("pod-probes-liveness-exec.yaml")},
+		nil,
+		&config.RunConfiguration{
+			EnabledOptionalTests: map[string]struct{}{"container-liveness-probe-exec": {}},
+		},
+		"Container Liveness Probe Exec",
+		scorecard.GradeWarning,
+	)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "The livenessProbe runs a shell pipeline", comments[0].Summary)
+	assert.Equal(t, "The livenessProbe depends on the host payments.default.svc", comments[1].Summary)
+	assert.Equal(t, "app", comments[1].Path)
+}
@@ -0,0 +1,157 @@
+package probes
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// shells are the commands of exec probes that run a script with -c
+var shells = []string{"sh", "bash", "ash", "dash", "zsh", "ksh"}
+
+// networkClients are the commands of exec probes that make requests to other hosts
+var networkClients = []string{"curl", "wget", "nc", "ncat"}
+
+// localHosts are the hosts that address the container itself
+var localHosts = []string{
+	"", "localhost", "0.0.0.0", "::1",
+	"$HOSTNAME", "${HOSTNAME}", "$(hostname)", "$POD_IP", "${POD_IP}",
+}
+
+// containerLivenessProbeExec flags exec liveness probes that run shell pipelines, which hide the
+// failures of all but the last command, or that request other hosts, so that an outage of another
+// service restarts all containers that depend on it.
+func containerLivenessProbeExec(options Options) func(ks.PodSpecer) (scorecard.TestScore, error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		score.Grade = scorecard.GradeAllOK
+
+		for _, container := range ps.GetPodTemplateSpec().Spec.Containers {
+			probe := container.LivenessProbe
+			if probe == nil || probe.Exec == nil || len(probe.Exec.Command) == 0 {
+				continue
+			}
+
+			commands := [][]string{probe.Exec.Command}
+			if script, ok := shellScript(probe.Exec.Command); ok {
+				if hasPipeline(script) {
+					score.Grade = scorecard.GradeWarning
+					score.AddCommentWithURL(
+						container.Name,
+						"The livenessProbe runs a shell pipeline",
+						"The exit code of a pipeline is the exit code of its last command, so the failures of the other commands do not fail the probe. "+
+							"Use an httpGet or tcpSocket probe, or a single command that checks the container.",
+						"https://github.com/romnn/kube-score/blob/master/README_PROBES.md",
+					)
+				}
+				commands = scriptCommands(script)
+			}
+
+			for _, command := range commands {
+				for _, host := range remoteHosts(command) {
+					score.Grade = scorecard.GradeWarning
+					score.AddCommentWithURL(
+						container.Name,
+						fmt.Sprintf("The livenessProbe depends on the host %s", host),
+						"When the other host is unavailable, the livenessProbe fails and all containers that depend on it are restarted, which turns an outage of one service into cascading restarts. "+
+							"Only check the container itself in the livenessProbe, with an httpGet or tcpSocket probe or a command against localhost.",
+						"https://github.com/romnn/kube-score/blob/master/README_PROBES.md",
+					)
+				}
+			}
+		}
+
+		return score, nil
+	}
+}
+
+// shellScript returns the script of a command that runs a shell with -c
+func shellScript(command []string) (string, bool) {
+	if len(command) < 3 || !slices.Contains(shells, path.Base(command[0])) {
+		return "", false
+	}
+	flag := command[1]
+	if !strings.HasPrefix(flag, "-") || strings.HasPrefix(flag, "--") || !strings.Contains(flag, "c") {
+		return "", false
+	}
+	return command[2], true
+}
+
+// hasPipeline returns true if the script pipes the output of a command into another command
+func hasPipeline(script string) bool {
+	return strings.Contains(strings.ReplaceAll(script, "||", ""), "|")
+}
+
+// scriptCommands splits a script into the fields of its commands
+func scriptCommands(script string) [][]string {
+	script = strings.NewReplacer("&&", "\n", "||", "\n", "|", "\n", ";", "\n").Replace(script)
+	var commands [][]string
+	for line := range strings.SplitSeq(script, "\n") {
+		fields := strings.Fields(strings.NewReplacer(`"`, "", "'", "").Replace(line))
+		if len(fields) > 0 {
+			commands = append(commands, fields)
+		}
+	}
+	return commands
+}
+
+// remoteHosts returns the hosts other than the container itself that a network client command
+// makes requests to
+func remoteHosts(command []string) []string {
+	if len(command) < 2 || !slices.Contains(networkClients, path.Base(command[0])) {
+		return nil
+	}
+
+	var targets []string
+	for _, arg := range command[1:] {
+		if strings.Contains(arg, "://") {
+			targets = append(targets, arg)
+		}
+	}
+	if len(targets) == 0 {
+		// Without a URL, the first argument that is not a flag is the host
+		for _, arg := range command[1:] {
+			if !strings.HasPrefix(arg, "-") {
+				targets = append(targets, arg)
+				break
+			}
+		}
+	}
+
+	var hosts []string
+	for _, target := range targets {
+		if host := targetHost(target); !isLocalHost(host) && !slices.Contains(hosts, host) {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+// targetHost returns the host of a URL or of a host:port target
+func targetHost(target string) string {
+	if _, rest, ok := strings.Cut(target, "://"); ok {
+		target = rest
+	}
+	if i := strings.IndexAny(target, "/?#"); i >= 0 {
+		target = target[:i]
+	}
+	if i := strings.LastIndex(target, "@"); i >= 0 {
+		target = target[i+1:]
+	}
+	if strings.HasPrefix(target, "[") {
+		if host, _, ok := strings.Cut(target[1:], "]"); ok {
+			return host
+		}
+	}
+	if host, port, ok := strings.Cut(target, ":"); ok && !strings.Contains(port, ":") {
+		return host
+	}
+	return target
+}
+
+func isLocalHost(host string) bool {
+	return slices.Contains(localHosts, strings.ToLower(host)) || strings.HasPrefix(host, "127.")
+}
@@ -6,26 +6,46 @@ import (
 	"github.com/romnn/kube-score/score/internal"
 	"github.com/romnn/kube-score/scorecard"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type Options struct {
 	SkipInitContainers bool
-	Namespace          string
+	Namespace          ks.DefaultNamespace
 }
 
-func Register(allChecks *checks.Checks, services ks.Services, options Options) {
-	allChecks.RegisterPodCheck(
+func Register(allChecks *checks.Checks, options Options) {
+	allChecks.RegisterPodContextCheck(
 		"Pod Probes",
 		`Makes sure that all Pods have safe probe configurations`,
-		containerProbes(services.Services(), options),
+		func(ctx *checks.Context, ps ks.PodSpecer) (scorecard.TestScore, error) {
+			return containerProbes(servicesIn(ctx, options, ps.GetObjectMeta().Namespace), options)(ps)
+		},
 	)
+	allChecks.RegisterOptionalPodCheck(
+		"Container Liveness Probe Exec",
+		`Makes sure that exec livenessProbes do not run shell pipelines or make requests to other hosts, such as with curl or wget`,
+		containerLivenessProbeExec(options),
+	)
+}
+
+// servicesKey is the key of the index of the Services by namespace
+type servicesKey struct{ namespace ks.DefaultNamespace }
+
+// servicesIn returns the Services of the run in the namespace
+func servicesIn(ctx *checks.Context, options Options, namespace string) []ks.Service {
+	return checks.Index(ctx, servicesKey{options.Namespace}, func(all ks.AllTypes) map[string][]ks.Service {
+		return checks.ByNamespace(all.Services(), options.Namespace, func(s ks.Service) metav1.ObjectMeta {
+			return s.Service().ObjectMeta
+		})
+	})[options.Namespace.Of(namespace)]
 }
 
 // containerProbes returns a function that checks if all probes are defined correctly in the Pod.
 // Only one probe of each type is required on the entire pod.
 // ReadinessProbes are not required if the pod is not targeted by a Service.
 //
-// containerProbes takes a slice of all defined Services as input.
+// containerProbes takes a slice of the Services in the namespace of the pod as input.
 func containerProbes(
 	allServices []ks.Service,
 	options Options,
@@ -157,14 +177,8 @@ func containerProbes(
 }
 
 func podIsTargetedByService(pod corev1.PodTemplateSpec, service corev1.Service, options Options) bool {
-	podNamespace := pod.Namespace
-	if podNamespace == "" {
-		podNamespace = options.Namespace
-	}
-	serviceNamespace := service.Namespace
-	if serviceNamespace == "" {
-		serviceNamespace = options.Namespace
-	}
+	podNamespace := options.Namespace.Of(pod.Namespace)
+	serviceNamespace := options.Namespace.Of(service.Namespace)
 
 	if podNamespace != serviceNamespace {
 		return false
@@ -127,3 +127,27 @@ func TestPodIsTargetedByService(t *testing.T) {
 		assert.False(t, res)
 	})
 }
+
+func TestRemoteHosts(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, remoteHosts([]string{"curl", "-f", "http://localhost:8080/health"}))
+	assert.Empty(t, remoteHosts([]string{"curl", "127.0.0.1:8080"}))
+	assert.Empty(t, remoteHosts([]string{"wget", "http://[::1]:8080/"}))
+	assert.Empty(t, remoteHosts([]string{"cat", "http://example.com/"}))
+	assert.Equal(t, []string{"example.com"}, remoteHosts([]string{"curl", "-H", "Accept: text/plain", "https://user@example.com:443/health"}))
+	assert.Equal(t, []string{"db"}, remoteHosts([]string{"/usr/bin/nc", "-z", "db", "5432"}))
+}
+
+func TestShellScript(t *testing.T) {
+	t.Parallel()
+
+	script, ok := shellScript([]string{"/bin/bash", "-ec", "pgrep app || exit 1"})
+	assert.True(t, ok)
+	assert.False(t, hasPipeline(script))
+	assert.Equal(t, [][]string{{"pgrep", "app"}, {"exit", "1"}}, scriptCommands(script))
+
+	_, ok = shellScript([]string{"/app/healthcheck", "-c", "config.yaml"})
+	assert.False(t, ok)
+	assert.True(t, hasPipeline("ps aux | grep app"))
+}
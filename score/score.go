@@ -2,79 +2,166 @@ package score
 
 import (
 	"errors"
+	"slices"
+	"sync"
 
 	"github.com/romnn/kube-score/config"
 	ks "github.com/romnn/kube-score/domain"
 	"github.com/romnn/kube-score/score/apps"
 	"github.com/romnn/kube-score/score/checks"
 	"github.com/romnn/kube-score/score/container"
+	"github.com/romnn/kube-score/score/crd"
 	"github.com/romnn/kube-score/score/cronjob"
 	"github.com/romnn/kube-score/score/deployment"
 	"github.com/romnn/kube-score/score/disruptionbudget"
 	"github.com/romnn/kube-score/score/hpa"
+	"github.com/romnn/kube-score/score/image"
 	"github.com/romnn/kube-score/score/ingress"
+	"github.com/romnn/kube-score/score/job"
 	"github.com/romnn/kube-score/score/meta"
+	"github.com/romnn/kube-score/score/monitor"
 	"github.com/romnn/kube-score/score/networkpolicy"
+	"github.com/romnn/kube-score/score/pod"
+	"github.com/romnn/kube-score/score/podsecurity"
 	"github.com/romnn/kube-score/score/podtopologyspreadconstraints"
+	"github.com/romnn/kube-score/score/priorityclass"
 	"github.com/romnn/kube-score/score/probes"
 	"github.com/romnn/kube-score/score/security"
 	"github.com/romnn/kube-score/score/service"
 	"github.com/romnn/kube-score/score/stable"
+	"github.com/romnn/kube-score/score/storageclass"
+	"github.com/romnn/kube-score/score/webhook"
 	"github.com/romnn/kube-score/scorecard"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// builtinChecks are the built-in checks registered with the default configuration. The
+// registration is only run once, and only the metadata of the checks is kept.
+var builtinChecks = sync.OnceValue(func() []ks.Check {
+	return RegisterAllChecks(nil, nil).All()
+})
+
+// AllChecks returns the metadata of all built-in checks, including the optional checks. The checks
+// are registered once per process with the default configuration to get their metadata, the
+// registered checks of a run are returned by RegisterAllChecks.
+func AllChecks() []ks.Check {
+	return slices.Clone(builtinChecks())
+}
+
 func RegisterAllChecks(
-	allObjects ks.AllTypes,
 	checksConfig *checks.Config,
 	runConfig *config.RunConfiguration,
 ) *checks.Checks {
-	allChecks := checks.New(checksConfig)
-
-	deployment.Register(allChecks, allObjects, deployment.Options{Namespace: runConfig.Namespace})
-	ingress.Register(allChecks, allObjects, ingress.Options{Namespace: runConfig.Namespace})
-	cronjob.Register(allChecks)
-	container.Register(allChecks, container.Options{
-		SkipInitContainers:                    runConfig.SkipInitContainers,
-		IgnoreContainerCpuLimitRequirement:    runConfig.IgnoreContainerCpuLimitRequirement,
-		IgnoreContainerMemoryLimitRequirement: runConfig.IgnoreContainerMemoryLimitRequirement,
-	})
-	disruptionbudget.Register(allChecks, allObjects, disruptionbudget.Options{
-		Namespace: runConfig.Namespace,
-	})
-	networkpolicy.Register(
-		allChecks,
-		allObjects,
-		allObjects,
-		allObjects,
-		networkpolicy.Options{
-			Namespace: runConfig.Namespace,
-		},
-	)
-	probes.Register(allChecks, allObjects, probes.Options{
-		SkipInitContainers: runConfig.SkipInitContainers,
-		Namespace:          runConfig.Namespace,
-	})
-	security.Register(allChecks, security.Options{
-		SkipInitContainers: runConfig.SkipInitContainers,
-	})
-	service.Register(allChecks, allObjects, allObjects, service.Options{Namespace: runConfig.Namespace})
-	stable.Register(runConfig.KubernetesVersion, allChecks)
-	apps.Register(
-		allChecks,
-		allObjects.HorizontalPodAutoscalers(),
-		allObjects.Services(),
-		apps.Options{
-			Namespace: runConfig.Namespace,
-		},
-	)
-	meta.Register(allChecks)
-	hpa.Register(allChecks, hpa.Options{
-		AllTargetableObjs: allObjects.Metas(),
-		Namespace:         runConfig.Namespace,
+	if runConfig == nil {
+		runConfig = &config.RunConfiguration{}
+	}
+
+	cnf := checks.Config{}
+	if checksConfig != nil {
+		cnf = *checksConfig
+	}
+	cnf.KubernetesVersion = runConfig.KubernetesVersion
+	allChecks := checks.New(&cnf)
+	params := runConfig.CheckParameters
+	namespace := ks.DefaultNamespace(runConfig.Namespace)
+
+	allChecks.RegisterBuiltin(func() {
+		deployment.Register(allChecks, deployment.Options{
+			Namespace:   namespace,
+			MinReplicas: int32(params.Int("deployment-replicas", "minReplicas")),
+		})
+		ingress.Register(allChecks, ingress.Options{
+			Namespace: namespace,
+		})
+		job.Register(allChecks, job.Options{
+			KubernetesVersion: runConfig.KubernetesVersion,
+		})
+		cronjob.Register(allChecks)
+		container.Register(allChecks, container.Options{
+			Namespace:                             namespace,
+			SkipInitContainers:                    runConfig.SkipInitContainers,
+			IgnoreContainerCpuLimitRequirement:    runConfig.IgnoreContainerCpuLimitRequirement,
+			IgnoreContainerMemoryLimitRequirement: runConfig.IgnoreContainerMemoryLimitRequirement,
+			MaxPortNameLength:                     params.Int("container-ports-check", "maxPortNameLength"),
+			ImageTagPolicy:                        params.String("container-image-tag", "tagPolicy"),
+			FloatingImageTags:                     params.Strings("container-image-tag", "floatingTags"),
+			NestedMounts:                          params.String("container-volume-mounts", "nestedMounts"),
+		})
+		image.Register(allChecks, image.Options{
+			Namespace:            namespace,
+			SkipInitContainers:   runConfig.SkipInitContainers,
+			PublicRegistries:     params.Strings("container-image-pull-secrets", "publicRegistries"),
+			RegistryCredentials:  runConfig.RegistryCredentials,
+			VulnerabilityScanner: runConfig.VulnerabilityScanner,
+			VulnerabilityReports: runConfig.VulnerabilityReports,
+		})
+		disruptionbudget.Register(allChecks, disruptionbudget.Options{
+			Namespace: namespace,
+		})
+		priorityclass.Register(allChecks, priorityclass.Options{
+			BatchClassNames: params.Strings("priorityclass-preemption-policy", "batchClassNames"),
+		})
+		crd.Register(allChecks, crd.Options{
+			Namespace: namespace,
+		})
+		monitor.Register(allChecks, monitor.Options{
+			Namespace: namespace,
+		})
+		webhook.Register(allChecks, webhook.Options{
+			MaxTimeoutSeconds: int32(params.Int("webhook-timeout", "maxTimeoutSeconds")),
+		})
+		storageclass.Register(allChecks, storageclass.Options{
+			ReclaimProfile:       params.String("storageclass-reclaim-policy", "profile"),
+			TopologyProvisioners: params.Strings("storageclass-volume-binding-mode", "topologyProvisioners"),
+		})
+		networkpolicy.Register(allChecks, networkpolicy.Options{
+			Namespace:   namespace,
+			ServiceMesh: runConfig.ServiceMesh,
+		})
+		probes.Register(allChecks, probes.Options{
+			SkipInitContainers: runConfig.SkipInitContainers,
+			Namespace:          namespace,
+		})
+		security.Register(allChecks, security.Options{
+			SkipInitContainers:        runConfig.SkipInitContainers,
+			MinUserID:                 int64(params.Int("container-security-context-user-group-id", "minUserId")),
+			MinGroupID:                int64(params.Int("container-security-context-user-group-id", "minGroupId")),
+			MaxTokenExpirationSeconds: int64(params.Int("pod-service-account-token", "maxExpirationSeconds")),
+			Namespace:                 namespace,
+			SafeSysctls:               params.Strings("pod-sysctls", "safeSysctls"),
+		})
+		service.Register(allChecks, service.Options{
+			Namespace:           namespace,
+			ServiceMesh:         runConfig.ServiceMesh,
+			IPFamilyProfile:     params.String("service-dual-stack", "profile"),
+			KubernetesVersion:   runConfig.KubernetesVersion,
+			TopologyMinReplicas: int32(params.Int("service-traffic-distribution", "minReplicas")),
+		})
+		stable.Register(runConfig.KubernetesVersion, allChecks)
+		apps.Register(allChecks, apps.Options{
+			Namespace:               namespace,
+			DeploymentTopologyKeys:  params.Strings("deployment-has-host-podantiaffinity", "topologyKeys"),
+			StatefulSetTopologyKeys: params.Strings("statefulset-has-host-podantiaffinity", "topologyKeys"),
+		})
+		meta.Register(allChecks, meta.Options{AnnotationPrefixes: runConfig.AnnotationPrefixes})
+		hpa.Register(allChecks, hpa.Options{
+			Namespace:     namespace,
+			MinReplicas:   int32(params.Int("horizontalpodautoscaler-replicas", "minReplicas")),
+			ScalableKinds: params.Strings("horizontalpodautoscaler-target-scalable", "scalableKinds"),
+		})
+		podtopologyspreadconstraints.Register(allChecks)
+		podsecurity.Register(allChecks, podsecurity.Options{
+			Namespace:         namespace,
+			DefaultEnforce:    params.String("pod-security-admission", "defaultEnforce"),
+			KubernetesVersion: runConfig.KubernetesVersion,
+		})
+		pod.Register(allChecks, pod.Options{
+			Namespace:                      namespace,
+			AllowedRuntimeClasses:          params.Strings("pod-runtimeclass", "allowedRuntimeClasses"),
+			RuntimeClassRequiredNamespaces: params.Strings("pod-runtimeclass", "requiredNamespaces"),
+		})
 	})
-	podtopologyspreadconstraints.Register(allChecks)
 
 	return allChecks
 }
@@ -83,6 +170,7 @@ type podSpeccer struct {
 	typeMeta   metav1.TypeMeta
 	objectMeta metav1.ObjectMeta
 	spec       corev1.PodTemplateSpec
+	location   ks.FileLocation
 }
 
 func (p *podSpeccer) GetTypeMeta() metav1.TypeMeta {
@@ -98,7 +186,54 @@ func (p *podSpeccer) GetPodTemplateSpec() corev1.PodTemplateSpec {
 }
 
 func (p *podSpeccer) FileLocation() ks.FileLocation {
-	return ks.FileLocation{}
+	return p.location
+}
+
+// runPodCheck runs the check on the pod template, without the containers that the check is ignored
+// for. The check is skipped if it is ignored for all containers.
+func runPodCheck(
+	ctx *checks.Context,
+	o *scorecard.ScoredObject,
+	test checks.GenCheck[ks.PodSpecer],
+	ps ks.PodSpecer,
+	annotations []map[string]string,
+) (scorecard.TestScore, error) {
+	ignored := o.IgnoredContainers(test.Check, annotations...)
+	if len(ignored) == 0 {
+		return test.Run(ctx, ps)
+	}
+
+	spec := ps.GetPodTemplateSpec()
+	var removed []string
+	isIgnored := func(name string) bool {
+		if !slices.Contains(ignored, name) {
+			return false
+		}
+		removed = append(removed, name)
+		return true
+	}
+	spec.Spec.InitContainers = slices.DeleteFunc(slices.Clone(spec.Spec.InitContainers), func(c corev1.Container) bool {
+		return isIgnored(c.Name)
+	})
+	spec.Spec.Containers = slices.DeleteFunc(slices.Clone(spec.Spec.Containers), func(c corev1.Container) bool {
+		return isIgnored(c.Name)
+	})
+	spec.Spec.EphemeralContainers = slices.DeleteFunc(slices.Clone(spec.Spec.EphemeralContainers), func(c corev1.EphemeralContainer) bool {
+		return isIgnored(c.Name)
+	})
+	if len(removed) == 0 {
+		return test.Run(ctx, ps)
+	}
+	if len(spec.Spec.InitContainers)+len(spec.Spec.Containers) == 0 {
+		slices.Sort(removed)
+		return scorecard.ContainersIgnored(test.Check, slices.Compact(removed)), nil
+	}
+	return test.Run(ctx, &podSpeccer{
+		typeMeta:   ps.GetTypeMeta(),
+		objectMeta: ps.GetObjectMeta(),
+		spec:       spec,
+		location:   ps.FileLocation(),
+	})
 }
 
 // Score runs a pre-configured list of tests against the files defined in the configuration, and returns a scorecard.
@@ -107,6 +242,16 @@ func Score(
 	allObjects ks.AllTypes,
 	allChecks *checks.Checks,
 	runConfig *config.RunConfiguration,
+) (*scorecard.Scorecard, error) {
+	return ScoreWithHooks(allObjects, allChecks, runConfig, nil)
+}
+
+// ScoreWithHooks is like Score, but calls the hooks for every scored object and check
+func ScoreWithHooks(
+	allObjects ks.AllTypes,
+	allChecks *checks.Checks,
+	runConfig *config.RunConfiguration,
+	hooks *Hooks,
 ) (*scorecard.Scorecard, error) {
 	if runConfig == nil {
 		runConfig = &config.RunConfiguration{}
@@ -116,74 +261,206 @@ func Score(
 		return nil, errors.New("no checks registered")
 	}
 
+	// The checks are read once, checks that are registered while scoring are not used by the run
+	var (
+		ingressChecks        = allChecks.Ingresses()
+		metaChecks           = allChecks.Metas()
+		podChecks            = allChecks.Pods()
+		podObjectChecks      = allChecks.PodObjects()
+		serviceChecks        = allChecks.Services()
+		statefulSetChecks    = allChecks.StatefulSets()
+		deploymentChecks     = allChecks.Deployments()
+		networkPolicyChecks  = allChecks.NetworkPolicies()
+		jobChecks            = allChecks.Jobs()
+		cronJobChecks        = allChecks.CronJobs()
+		hpaChecks            = allChecks.HorizontalPodAutoscalers()
+		pdbChecks            = allChecks.PodDisruptionBudgets()
+		priorityClassChecks  = allChecks.PriorityClasses()
+		storageClassChecks   = allChecks.StorageClasses()
+		webhookChecks        = allChecks.WebhookConfigurations()
+		crdChecks            = allChecks.CustomResourceDefinitions()
+		serviceMonitorChecks = allChecks.ServiceMonitors()
+		podMonitorChecks     = allChecks.PodMonitors()
+	)
+
+	// Objects that are filtered out are still used by the checks, through the context of the run
+	ctx := checks.NewContext(allObjects)
+	filter, err := newObjectFilter(runConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	exceptions, err := scoreExceptions(allObjects, ks.DefaultNamespace(runConfig.Namespace))
+	if err != nil {
+		return nil, err
+	}
+	if len(exceptions) > 0 {
+		withExceptions := *runConfig
+		withExceptions.Exceptions = append(slices.Clip(runConfig.Exceptions), exceptions...)
+		runConfig = &withExceptions
+	}
+	allObjects = filter.apply(allObjects)
+
 	scoreCard := scorecard.New()
 
+	// Every object is scored by the checks of its groups, e.g. the checks of all objects and the
+	// checks of Deployments
+	total := len(allObjects.Ingresses()) +
+		len(allObjects.Metas()) +
+		2*len(allObjects.Pods()) +
+		len(allObjects.PodSpeccers()) +
+		len(allObjects.Services()) +
+		len(allObjects.StatefulSets()) +
+		len(allObjects.Deployments()) +
+		len(allObjects.NetworkPolicies()) +
+		len(allObjects.Jobs()) +
+		len(allObjects.CronJobs()) +
+		len(allObjects.HorizontalPodAutoscalers()) +
+		len(allObjects.PodDisruptionBudgets()) +
+		len(allObjects.PriorityClasses()) +
+		len(allObjects.StorageClasses()) +
+		len(allObjects.WebhookConfigurations()) +
+		len(allObjects.CustomResourceDefinitions()) +
+		len(allObjects.ServiceMonitors()) +
+		len(allObjects.PodMonitors())
+	done := 0
+	step := func() {
+		done++
+		hooks.progress(done, total)
+	}
+
 	newObject := func(typeMeta metav1.TypeMeta, objectMeta metav1.ObjectMeta) *scorecard.ScoredObject {
-		return scoreCard.NewObject(typeMeta, objectMeta, runConfig)
+		objects := len(scoreCard)
+		o := scoreCard.NewObject(typeMeta, objectMeta, runConfig)
+		// Objects are scored by multiple groups of checks, only call the hook the first time
+		if len(scoreCard) > objects {
+			hooks.beforeObject(o)
+		}
+		return o
 	}
 
 	for _, ingress := range allObjects.Ingresses() {
 		o := newObject(ingress.GetTypeMeta(), ingress.GetObjectMeta())
-		for _, test := range allChecks.Ingresses() {
-			fn, err := test.Fn(ingress)
+		annotations := []map[string]string{ingress.GetObjectMeta().Annotations}
+		for _, test := range ingressChecks {
+			fn, ok, err := hooks.runCheck(o, test.Check, ingress, annotations, func() (scorecard.TestScore, error) {
+				return test.Run(ctx, ingress)
+			})
 			if err != nil {
 				return nil, err
 			}
-			o.Add(fn, test.Check, ingress, ingress.GetObjectMeta().Annotations)
+			if !ok {
+				continue
+			}
+			o.Add(fn, test.Check, ingress, annotations...)
 		}
+		step()
 	}
 
 	for _, meta := range allObjects.Metas() {
 		o := newObject(meta.TypeMeta, meta.ObjectMeta)
-		for _, test := range allChecks.Metas() {
-			fn, err := test.Fn(meta)
+		annotations := []map[string]string{meta.ObjectMeta.Annotations}
+		for _, test := range metaChecks {
+			fn, ok, err := hooks.runCheck(o, test.Check, meta, annotations, func() (scorecard.TestScore, error) {
+				return test.Run(ctx, meta)
+			})
 			if err != nil {
 				return nil, err
 			}
-			o.Add(fn, test.Check, meta, meta.ObjectMeta.Annotations)
+			if !ok {
+				continue
+			}
+			o.Add(fn, test.Check, meta, annotations...)
 		}
+		step()
 	}
 
 	for _, pod := range allObjects.Pods() {
 		o := newObject(pod.Pod().TypeMeta, pod.Pod().ObjectMeta)
-		for _, test := range allChecks.Pods() {
-			podTemplateSpec := corev1.PodTemplateSpec{
+		annotations := []map[string]string{pod.Pod().Annotations}
+		podspecer := &podSpeccer{
+			typeMeta:   pod.Pod().TypeMeta,
+			objectMeta: pod.Pod().ObjectMeta,
+			spec: corev1.PodTemplateSpec{
 				ObjectMeta: pod.Pod().ObjectMeta,
 				Spec:       pod.Pod().Spec,
+			},
+			location: pod.FileLocation(),
+		}
+		for _, test := range podChecks {
+			score, ok, err := hooks.runCheck(o, test.Check, pod, annotations, func() (scorecard.TestScore, error) {
+				return runPodCheck(ctx, o, test, podspecer, annotations)
+			})
+			if err != nil {
+				return nil, err
 			}
+			if !ok {
+				continue
+			}
+			o.Add(score, test.Check, pod, annotations...)
+		}
+		step()
+	}
 
-			score, _ := test.Fn(&podSpeccer{
-				typeMeta:   pod.Pod().TypeMeta,
-				objectMeta: pod.Pod().ObjectMeta,
-				spec:       podTemplateSpec,
+	for _, pod := range allObjects.Pods() {
+		o := newObject(pod.Pod().TypeMeta, pod.Pod().ObjectMeta)
+		annotations := []map[string]string{pod.Pod().Annotations}
+		for _, test := range podObjectChecks {
+			fn, ok, err := hooks.runCheck(o, test.Check, pod, annotations, func() (scorecard.TestScore, error) {
+				return test.Run(ctx, pod.Pod())
 			})
-			o.Add(score, test.Check, pod, pod.Pod().Annotations)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			o.Add(fn, test.Check, pod, annotations...)
 		}
+		step()
 	}
 
 	for _, podspecer := range allObjects.PodSpeccers() {
 		if podspecer.GetTypeMeta().Kind == "Job" && runConfig.SkipJobs {
+			step()
 			continue
 		}
 		o := newObject(podspecer.GetTypeMeta(), podspecer.GetObjectMeta())
-		for _, test := range allChecks.Pods() {
-			score, _ := test.Fn(podspecer)
-			o.Add(score, test.Check, podspecer,
-				podspecer.GetObjectMeta().Annotations,
-				podspecer.GetPodTemplateSpec().Annotations,
-			)
+		annotations := []map[string]string{
+			podspecer.GetObjectMeta().Annotations,
+			podspecer.GetPodTemplateSpec().Annotations,
 		}
+		for _, test := range podChecks {
+			score, ok, err := hooks.runCheck(o, test.Check, podspecer, annotations, func() (scorecard.TestScore, error) {
+				return runPodCheck(ctx, o, test, podspecer, annotations)
+			})
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			o.Add(score, test.Check, podspecer, annotations...)
+		}
+		step()
 	}
 
 	for _, service := range allObjects.Services() {
 		o := newObject(service.Service().TypeMeta, service.Service().ObjectMeta)
-		for _, test := range allChecks.Services() {
-			fn, err := test.Fn(service.Service())
+		annotations := []map[string]string{service.Service().Annotations}
+		for _, test := range serviceChecks {
+			fn, ok, err := hooks.runCheck(o, test.Check, service, annotations, func() (scorecard.TestScore, error) {
+				return test.Run(ctx, service.Service())
+			})
 			if err != nil {
 				return nil, err
 			}
-			o.Add(fn, test.Check, service, service.Service().Annotations)
+			if !ok {
+				continue
+			}
+			o.Add(fn, test.Check, service, annotations...)
 		}
+		step()
 	}
 
 	for _, statefulset := range allObjects.StatefulSets() {
@@ -191,18 +468,20 @@ func Score(
 			statefulset.StatefulSet().TypeMeta,
 			statefulset.StatefulSet().ObjectMeta,
 		)
-		for _, test := range allChecks.StatefulSets() {
-			fn, err := test.Fn(statefulset.StatefulSet())
+		annotations := []map[string]string{statefulset.StatefulSet().Annotations}
+		for _, test := range statefulSetChecks {
+			fn, ok, err := hooks.runCheck(o, test.Check, statefulset, annotations, func() (scorecard.TestScore, error) {
+				return test.Run(ctx, statefulset.StatefulSet())
+			})
 			if err != nil {
 				return nil, err
 			}
-			o.Add(
-				fn,
-				test.Check,
-				statefulset,
-				statefulset.StatefulSet().Annotations,
-			)
+			if !ok {
+				continue
+			}
+			o.Add(fn, test.Check, statefulset, annotations...)
 		}
+		step()
 	}
 
 	for _, deployment := range allObjects.Deployments() {
@@ -210,18 +489,20 @@ func Score(
 			deployment.Deployment().TypeMeta,
 			deployment.Deployment().ObjectMeta,
 		)
-		for _, test := range allChecks.Deployments() {
-			res, err := test.Fn(deployment.Deployment())
+		annotations := []map[string]string{deployment.Deployment().Annotations}
+		for _, test := range deploymentChecks {
+			res, ok, err := hooks.runCheck(o, test.Check, deployment, annotations, func() (scorecard.TestScore, error) {
+				return test.Run(ctx, deployment.Deployment())
+			})
 			if err != nil {
 				return nil, err
 			}
-			o.Add(
-				res,
-				test.Check,
-				deployment,
-				deployment.Deployment().Annotations,
-			)
+			if !ok {
+				continue
+			}
+			o.Add(res, test.Check, deployment, annotations...)
 		}
+		step()
 	}
 
 	for _, netpol := range allObjects.NetworkPolicies() {
@@ -229,49 +510,212 @@ func Score(
 			netpol.NetworkPolicy().TypeMeta,
 			netpol.NetworkPolicy().ObjectMeta,
 		)
-		for _, test := range allChecks.NetworkPolicies() {
-			fn, err := test.Fn(netpol.NetworkPolicy())
+		annotations := []map[string]string{netpol.NetworkPolicy().Annotations}
+		for _, test := range networkPolicyChecks {
+			fn, ok, err := hooks.runCheck(o, test.Check, netpol, annotations, func() (scorecard.TestScore, error) {
+				return test.Run(ctx, netpol.NetworkPolicy())
+			})
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			o.Add(fn, test.Check, netpol, annotations...)
+		}
+		step()
+	}
+
+	for _, j := range allObjects.Jobs() {
+		if runConfig.SkipJobs {
+			step()
+			continue
+		}
+		o := newObject(j.GetTypeMeta(), j.GetObjectMeta())
+		annotations := []map[string]string{j.GetObjectMeta().Annotations}
+		for _, test := range jobChecks {
+			fn, ok, err := hooks.runCheck(o, test.Check, j, annotations, func() (scorecard.TestScore, error) {
+				return test.Run(ctx, j)
+			})
 			if err != nil {
 				return nil, err
 			}
-			o.Add(fn, test.Check, netpol, netpol.NetworkPolicy().Annotations)
+			if !ok {
+				continue
+			}
+			o.Add(fn, test.Check, j, annotations...)
 		}
+		step()
 	}
 
 	for _, cjob := range allObjects.CronJobs() {
 		if runConfig.SkipJobs {
+			step()
 			continue
 		}
 		o := newObject(cjob.GetTypeMeta(), cjob.GetObjectMeta())
-		for _, test := range allChecks.CronJobs() {
-			fn, err := test.Fn(cjob)
+		annotations := []map[string]string{cjob.GetObjectMeta().Annotations}
+		for _, test := range cronJobChecks {
+			fn, ok, err := hooks.runCheck(o, test.Check, cjob, annotations, func() (scorecard.TestScore, error) {
+				return test.Run(ctx, cjob)
+			})
 			if err != nil {
 				return nil, err
 			}
-			o.Add(fn, test.Check, cjob, cjob.GetObjectMeta().Annotations)
+			if !ok {
+				continue
+			}
+			o.Add(fn, test.Check, cjob, annotations...)
 		}
+		step()
 	}
 
 	for _, hpa := range allObjects.HorizontalPodAutoscalers() {
 		o := newObject(hpa.GetTypeMeta(), hpa.GetObjectMeta())
-		for _, test := range allChecks.HorizontalPodAutoscalers() {
-			fn, err := test.Fn(hpa)
+		annotations := []map[string]string{hpa.GetObjectMeta().Annotations}
+		for _, test := range hpaChecks {
+			fn, ok, err := hooks.runCheck(o, test.Check, hpa, annotations, func() (scorecard.TestScore, error) {
+				return test.Run(ctx, hpa)
+			})
 			if err != nil {
 				return nil, err
 			}
-			o.Add(fn, test.Check, hpa, hpa.GetObjectMeta().Annotations)
+			if !ok {
+				continue
+			}
+			o.Add(fn, test.Check, hpa, annotations...)
 		}
+		step()
 	}
 
 	for _, pdb := range allObjects.PodDisruptionBudgets() {
 		o := newObject(pdb.GetTypeMeta(), pdb.GetObjectMeta())
-		for _, test := range allChecks.PodDisruptionBudgets() {
-			fn, err := test.Fn(pdb)
+		annotations := []map[string]string{pdb.GetObjectMeta().Annotations}
+		for _, test := range pdbChecks {
+			fn, ok, err := hooks.runCheck(o, test.Check, pdb, annotations, func() (scorecard.TestScore, error) {
+				return test.Run(ctx, pdb)
+			})
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			o.Add(fn, test.Check, pdb, annotations...)
+		}
+		step()
+	}
+
+	for _, pc := range allObjects.PriorityClasses() {
+		o := newObject(pc.PriorityClass().TypeMeta, pc.PriorityClass().ObjectMeta)
+		annotations := []map[string]string{pc.PriorityClass().Annotations}
+		for _, test := range priorityClassChecks {
+			fn, ok, err := hooks.runCheck(o, test.Check, pc, annotations, func() (scorecard.TestScore, error) {
+				return test.Run(ctx, pc.PriorityClass())
+			})
 			if err != nil {
 				return nil, err
 			}
-			o.Add(fn, test.Check, pdb, pdb.GetObjectMeta().Annotations)
+			if !ok {
+				continue
+			}
+			o.Add(fn, test.Check, pc, annotations...)
 		}
+		step()
+	}
+
+	for _, sc := range allObjects.StorageClasses() {
+		o := newObject(sc.StorageClass().TypeMeta, sc.StorageClass().ObjectMeta)
+		annotations := []map[string]string{sc.StorageClass().Annotations}
+		for _, test := range storageClassChecks {
+			fn, ok, err := hooks.runCheck(o, test.Check, sc, annotations, func() (scorecard.TestScore, error) {
+				return test.Run(ctx, sc.StorageClass())
+			})
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			o.Add(fn, test.Check, sc, annotations...)
+		}
+		step()
+	}
+
+	for _, wh := range allObjects.WebhookConfigurations() {
+		o := newObject(wh.GetTypeMeta(), wh.GetObjectMeta())
+		annotations := []map[string]string{wh.GetObjectMeta().Annotations}
+		for _, test := range webhookChecks {
+			fn, ok, err := hooks.runCheck(o, test.Check, wh, annotations, func() (scorecard.TestScore, error) {
+				return test.Run(ctx, wh)
+			})
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			o.Add(fn, test.Check, wh, annotations...)
+		}
+		step()
+	}
+
+	for _, crd := range allObjects.CustomResourceDefinitions() {
+		o := newObject(crd.GetTypeMeta(), crd.GetObjectMeta())
+		annotations := []map[string]string{crd.GetObjectMeta().Annotations}
+		for _, test := range crdChecks {
+			fn, ok, err := hooks.runCheck(o, test.Check, crd, annotations, func() (scorecard.TestScore, error) {
+				return test.Run(ctx, crd)
+			})
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			o.Add(fn, test.Check, crd, annotations...)
+		}
+		step()
+	}
+
+	for _, monitor := range allObjects.ServiceMonitors() {
+		o := newObject(monitor.GetTypeMeta(), monitor.GetObjectMeta())
+		annotations := []map[string]string{monitor.GetObjectMeta().Annotations}
+		for _, test := range serviceMonitorChecks {
+			fn, ok, err := hooks.runCheck(o, test.Check, monitor, annotations, func() (scorecard.TestScore, error) {
+				return test.Run(ctx, monitor)
+			})
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			o.Add(fn, test.Check, monitor, annotations...)
+		}
+		step()
+	}
+
+	for _, monitor := range allObjects.PodMonitors() {
+		o := newObject(monitor.GetTypeMeta(), monitor.GetObjectMeta())
+		annotations := []map[string]string{monitor.GetObjectMeta().Annotations}
+		for _, test := range podMonitorChecks {
+			fn, ok, err := hooks.runCheck(o, test.Check, monitor, annotations, func() (scorecard.TestScore, error) {
+				return test.Run(ctx, monitor)
+			})
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			o.Add(fn, test.Check, monitor, annotations...)
+		}
+		step()
+	}
+
+	if err := hooks.afterObjects(scoreCard); err != nil {
+		return nil, err
 	}
 
 	return &scoreCard, nil
@@ -22,7 +22,7 @@ func testFile(name string) *os.File {
 }
 
 // testExpectedScoreWithConfig runs all tests, but makes sure that the test for "testcase" was executed, and that
-// the grade is set to expectedScore. The function returns the comments of "testcase".
+// the grade is set to expectedScore. The function returns the comments of "testcase", without their fingerprints.
 func testExpectedScoreWithConfig(
 	t *testing.T,
 	files []ks.NamedReader,
@@ -38,7 +38,7 @@ func testExpectedScoreWithConfig(
 		for _, s := range objectScore.Checks {
 			if s.Check.Name == testcase {
 				assert.Equal(t, expectedScore, s.Grade)
-				return s.Comments
+				return withoutFingerprints(s.Comments)
 			}
 		}
 	}
@@ -47,6 +47,13 @@ func testExpectedScoreWithConfig(
 	return nil
 }
 
+func withoutFingerprints(comments []scorecard.TestScoreComment) []scorecard.TestScoreComment {
+	for i := range comments {
+		comments[i].Fingerprint = ""
+	}
+	return comments
+}
+
 func fileWasSkipped(
 	t *testing.T,
 	files []ks.NamedReader,
@@ -143,7 +150,7 @@ func testScore(
 		runConfig = &config.RunConfiguration{}
 	}
 
-	allChecks := RegisterAllChecks(parsed, checksConfig, runConfig)
+	allChecks := RegisterAllChecks(checksConfig, runConfig)
 
 	card, err := Score(parsed, allChecks, runConfig)
 	if err != nil {
@@ -453,6 +460,37 @@ func TestPodContainerTagFixed(t *testing.T) {
 	)
 }
 
+func TestPodContainerTagFloating(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("pod-image-tag-floating.yaml")},
+		nil,
+		&config.RunConfiguration{
+			CheckParameters: config.CheckParameters{
+				"container-image-tag": {"tagPolicy": "floating"},
+			},
+		},
+		"Container Image Tag",
+		scorecard.GradeWarning,
+	)
+	var paths []string
+	for _, c := range comments {
+		paths = append(paths, c.Path)
+	}
+	assert.Equal(t, []string{"channel", "major", "minor"}, paths)
+}
+
+func TestPodContainerTagFloatingDefaultPolicy(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(
+		t,
+		"pod-image-tag-floating.yaml",
+		"Container Image Tag",
+		scorecard.GradeAllOK,
+	)
+}
+
 func TestPodContainerPullPolicyUndefined(t *testing.T) {
 	t.Parallel()
 	testExpectedScore(
@@ -566,6 +604,150 @@ func TestAnnotationIgnoreDisabled(t *testing.T) {
 	assert.True(t, tested)
 }
 
+func TestAnnotationIgnoreCustomPrefix(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		file            string
+		prefixes        []string
+		expectedSkipped bool
+	}{
+		{"ignore-annotation-service-custom-prefix.yaml", nil, false},
+		{"ignore-annotation-service-custom-prefix.yaml", []string{"score.example.com"}, true},
+		{"ignore-annotation-service-custom-prefix.yaml", []string{"score.example.com/"}, true},
+		{"ignore-annotation-service-custom-prefix-per-check.yaml", nil, false},
+		{"ignore-annotation-service-custom-prefix-per-check.yaml", []string{"score.example.com"}, true},
+		// kube-score is always accepted
+		{"ignore-annotation-service.yaml", []string{"score.example.com"}, true},
+	} {
+		s, err := testScore(
+			[]ks.NamedReader{testFile(tc.file)},
+			nil,
+			&config.RunConfiguration{
+				UseIgnoreChecksAnnotation: true,
+				AnnotationPrefixes:        tc.prefixes,
+			},
+		)
+		assert.Nil(t, err)
+
+		tested := false
+		for _, o := range s {
+			for _, c := range o.Checks {
+				if c.Check.ID == "service-type" {
+					assert.Equal(t, tc.expectedSkipped, c.Skipped, "file=%s prefixes=%v", tc.file, tc.prefixes)
+					tested = true
+				}
+			}
+		}
+		assert.True(t, tested)
+	}
+}
+
+func TestSeverityAnnotation(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(
+		t,
+		"pod-severity-annotation-warning.yaml",
+		"Container Resources",
+		scorecard.GradeWarning,
+	)
+	assert.Contains(t, comments, scorecard.TestScoreComment{
+		Summary:     "Downgraded from CRITICAL to WARNING",
+		Description: "The grade has been overridden by the kube-score/severity annotation",
+	})
+
+	// Other checks are not affected
+	testExpectedScore(
+		t,
+		"pod-severity-annotation-warning.yaml",
+		"Container Security Context ReadOnlyRootFilesystem",
+		scorecard.GradeCritical,
+	)
+}
+
+func TestSeverityAnnotationNotExpired(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(
+		t,
+		"pod-severity-annotation-not-expired.yaml",
+		"Container Resources",
+		scorecard.GradeAllOK,
+	)
+}
+
+func TestSeverityAnnotationExpired(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(
+		t,
+		"pod-severity-annotation-expired.yaml",
+		"Container Resources",
+		scorecard.GradeCritical,
+	)
+	assert.Contains(t, comments, scorecard.TestScoreComment{
+		Summary:     "Severity override has expired",
+		Description: "The kube-score/severity annotation downgraded this check until 2000-01-01",
+	})
+}
+
+func TestSeverityAnnotationInvalid(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore(
+		[]ks.NamedReader{testFile("pod-severity-annotation-invalid.yaml")},
+		nil,
+		&config.RunConfiguration{KubernetesVersion: config.Semver{Major: 1, Minor: 18}},
+	)
+	assert.NoError(t, err)
+
+	scores := make(map[string]map[string]scorecard.TestScore)
+	for _, o := range sc {
+		scores[o.ObjectMeta.Name] = make(map[string]scorecard.TestScore)
+		for _, ts := range o.Checks {
+			scores[o.ObjectMeta.Name][ts.Check.ID] = ts
+			// The malformed entries are only reported by the severity-annotation check
+			if ts.Check.ID != "severity-annotation" {
+				for _, c := range ts.Comments {
+					assert.NotContains(t, c.Summary, "Invalid", ts.Check.ID)
+				}
+			}
+		}
+	}
+
+	// The valid entries of the annotation are applied
+	pod := scores["pod-test-1"]
+	assert.Equal(t, scorecard.GradeCritical, pod["container-resources"].Grade)
+	assert.Equal(t, scorecard.GradeInfo, pod["container-image-pull-policy"].Grade)
+	assert.Equal(t, scorecard.GradeWarning, pod["severity-annotation"].Grade)
+	assert.Equal(t, []scorecard.TestScoreComment{{
+		Path:        "kube-score/severity",
+		Summary:     "Invalid kube-score/severity annotation",
+		Description: `invalid grade "low" of container-resources, expected warning, info or ok, the entry is ignored and the other entries of the annotation are applied`,
+	}}, withoutFingerprints(pod["severity-annotation"].Comments))
+
+	// The annotations of the pod template are reported with the object of the template
+	deployment := scores["deployment-test-1"]
+	assert.Equal(t, scorecard.GradeCritical, deployment["container-resources"].Grade)
+	assert.Equal(t, scorecard.GradeWarning, deployment["severity-annotation"].Grade)
+	assert.Len(t, deployment["severity-annotation"].Comments, 1)
+}
+
+func TestAllChecksHaveMetadata(t *testing.T) {
+	t.Parallel()
+	allChecks := RegisterAllChecks(nil, nil)
+	assert.NoError(t, allChecks.Err())
+	for _, c := range allChecks.All() {
+		assert.NotEmpty(t, c.Category, c.ID)
+		assert.NotEmpty(t, c.Remediation, c.ID)
+		// The built-in checks do not have a namespace
+		assert.Empty(t, c.Namespace, c.ID)
+	}
+}
+
+func TestDefaultGrade(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, scorecard.GradeCritical, checks.DefaultGrade("container-security-context-privileged"))
+	assert.Equal(t, scorecard.GradeWarning, checks.DefaultGrade("service-type"))
+	assert.Equal(t, scorecard.GradeCritical, checks.DefaultGrade("custom-check"))
+}
+
 func TestList(t *testing.T) {
 	t.Parallel()
 	s, err := testScore([]ks.NamedReader{testFile("list.yaml")}, nil, nil)
@@ -763,6 +945,28 @@ func TestPodContainerPortsDuplicateNames(t *testing.T) {
 	)
 }
 
+func TestPodContainerPortsSharedPort(t *testing.T) {
+	t.Parallel()
+
+	comments := testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("pod-container-ports-shared-port.yaml")},
+		nil,
+		&config.RunConfiguration{
+			EnabledOptionalTests: map[string]struct{}{"container-ports-check": {}},
+		},
+		"Container Ports Check",
+		scorecard.GradeCritical,
+	)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "app", comments[0].Path)
+	assert.Equal(
+		t,
+		"Container port 8080/TCP is also declared by the container proxy. The containers of a pod share the network, only one of them can listen on the port",
+		comments[0].Description,
+	)
+}
+
 func TestPodContainerPortsNameLength(t *testing.T) {
 	t.Parallel()
 
@@ -854,6 +1058,37 @@ func TestPodEnvDuplicated(t *testing.T) {
 	assert.Empty(t, diff)
 }
 
+func TestPodEnvFromOK(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-env-envfrom-ok.yaml", "Environment Variable Key Duplication", scorecard.GradeAllOK)
+}
+
+func TestPodEnvFromDuplicated(t *testing.T) {
+	t.Parallel()
+
+	actual := testExpectedScore(
+		t,
+		"pod-env-envfrom-duplicated.yaml",
+		"Environment Variable Key Duplication",
+		scorecard.GradeWarning,
+	)
+
+	expected := []scorecard.TestScoreComment{
+		{
+			Path:        "foobar",
+			Summary:     "Environment Variable Key Duplication",
+			Description: "Container environment variable key 'PORT' of Secret 'app-secret' overrides the key of ConfigMap 'app-config'",
+		},
+		{
+			Path:        "foobar",
+			Summary:     "Environment Variable Key Duplication",
+			Description: "Container environment variable key 'LOG_LEVEL' overrides the key of ConfigMap 'app-config'",
+		},
+	}
+	diff := cmp.Diff(expected, actual)
+	assert.Empty(t, diff)
+}
+
 func TestMultipleIgnoreAnnotations(t *testing.T) {
 	t.Parallel()
 	s, err := testScore(
@@ -888,3 +1123,61 @@ func TestMultipleIgnoreAnnotations(t *testing.T) {
 	assert.True(t, tested)
 	assert.True(t, skipped)
 }
+
+func TestReplicaSetAndReplicationControllerAreScored(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore([]ks.NamedReader{testFile("replicaset.yaml")}, nil, nil)
+	assert.NoError(t, err)
+
+	grades := make(map[string]scorecard.Grade)
+	for _, o := range sc {
+		for _, c := range o.Checks {
+			if c.Check.ID == "container-resources" || c.Check.ID == "replicaset-managed-by-deployment" {
+				grades[o.TypeMeta.Kind+"/"+c.Check.ID] = c.Grade
+			}
+		}
+	}
+	assert.Equal(t, map[string]scorecard.Grade{
+		"ReplicaSet/container-resources":                         scorecard.GradeCritical,
+		"ReplicaSet/replicaset-managed-by-deployment":            scorecard.GradeWarning,
+		"ReplicationController/container-resources":              scorecard.GradeCritical,
+		"ReplicationController/replicaset-managed-by-deployment": scorecard.GradeWarning,
+	}, grades)
+}
+
+func TestIgnoreContainerGrade(t *testing.T) {
+	t.Parallel()
+
+	// The ignored container is removed before the check is run, the grade of the check only depends
+	// on the other containers
+	comments := testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("deployment-ignore-container.yaml")},
+		nil,
+		&config.RunConfiguration{UseIgnoreChecksAnnotation: true},
+		"Container Resources",
+		scorecard.GradeAllOK,
+	)
+	assert.Empty(t, comments)
+
+	// The check is skipped if it is ignored for all containers
+	s, err := testScore(
+		[]ks.NamedReader{testFile("deployment-ignore-all-containers.yaml")},
+		nil,
+		&config.RunConfiguration{UseIgnoreChecksAnnotation: true},
+	)
+	assert.NoError(t, err)
+	tested := false
+	for _, o := range s {
+		for _, c := range o.Checks {
+			if c.Check.ID == "container-resources" {
+				assert.True(t, c.Skipped)
+				assert.Equal(t, []scorecard.TestScoreComment{{
+					Summary: "Skipped because container-resources is ignored for the containers app, istio-proxy",
+				}}, c.Comments)
+				tested = true
+			}
+		}
+	}
+	assert.True(t, tested)
+}
@@ -0,0 +1,151 @@
+// Package scoretest runs the checks on test files the same way as the tests of the built-in
+// checks, so that custom checks that are registered with the library API can be tested with
+// golden files in the testdata directory.
+package scoretest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/parser"
+	"github.com/romnn/kube-score/score"
+	"github.com/romnn/kube-score/score/checks"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// Options configure how the test files are scored
+type Options struct {
+	// ChecksConfig is the configuration of the checks, defaults to no ignored checks
+	ChecksConfig *checks.Config
+	// RunConfig is the configuration of the run, defaults to Kubernetes v1.18
+	RunConfig *config.RunConfiguration
+	// Register registers the custom checks in addition to the built-in checks
+	Register func(allChecks *checks.Checks, allObjects ks.AllTypes, runConfig *config.RunConfiguration)
+}
+
+// Files opens the files in the testdata directory of the package under test. The files are
+// closed when the test finishes.
+func Files(t testing.TB, names ...string) []ks.NamedReader {
+	t.Helper()
+	var files []ks.NamedReader
+	for _, name := range names {
+		fp, err := os.Open(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatalf("failed to open test file: %v", err)
+		}
+		t.Cleanup(func() { _ = fp.Close() })
+		files = append(files, fp)
+	}
+	return files
+}
+
+// Score parses the files, and runs the built-in checks and the checks of opts.Register on them
+func Score(t testing.TB, files []ks.NamedReader, opts Options) scorecard.Scorecard {
+	t.Helper()
+
+	checksConfig := opts.ChecksConfig
+	if checksConfig == nil {
+		checksConfig = &checks.Config{}
+	}
+	runConfig := opts.RunConfig
+	if runConfig == nil {
+		runConfig = &config.RunConfiguration{
+			KubernetesVersion: config.Semver{Major: 1, Minor: 18},
+		}
+	}
+
+	p, err := parser.New(nil)
+	if err != nil {
+		t.Fatalf("failed to initialize parser: %v", err)
+	}
+	parsed, err := p.ParseFiles(files)
+	if err != nil {
+		t.Fatalf("failed to parse files: %v", err)
+	}
+
+	allChecks := score.RegisterAllChecks(checksConfig, runConfig)
+	if opts.Register != nil {
+		opts.Register(allChecks, parsed, runConfig)
+	}
+	if err := allChecks.Err(); err != nil {
+		t.Fatalf("failed to register checks: %v", err)
+	}
+
+	card, err := score.Score(parsed, allChecks, runConfig)
+	if err != nil {
+		t.Fatalf("failed to score: %v", err)
+	}
+	return *card
+}
+
+// ExpectedScore makes sure that the check with the name testcase was run, and that its grade is
+// expectedScore. It returns the comments of the check. Only the first object with a result of
+// the check is used, the files should have a single object that the check runs on.
+func ExpectedScore(
+	t testing.TB,
+	files []ks.NamedReader,
+	opts Options,
+	testcase string,
+	expectedScore scorecard.Grade,
+) []scorecard.TestScoreComment {
+	t.Helper()
+	s, ok := find(Score(t, files, opts), testcase)
+	if !ok {
+		t.Errorf("%s was not tested", testcase)
+		return nil
+	}
+	if s.Grade != expectedScore {
+		t.Errorf("%s: expected grade %s, got %s", testcase, expectedScore, s.Grade)
+	}
+	return s.Comments
+}
+
+// WasSkipped returns true if the check with the name testcase was skipped
+func WasSkipped(t testing.TB, files []ks.NamedReader, opts Options, testcase string) bool {
+	t.Helper()
+	s, ok := find(Score(t, files, opts), testcase)
+	if !ok {
+		t.Errorf("%s was not tested", testcase)
+	}
+	return s.Skipped
+}
+
+// Summaries returns the summaries of the comments of the check with the name testcase
+func Summaries(t testing.TB, files []ks.NamedReader, opts Options, testcase string) []string {
+	t.Helper()
+	s, ok := find(Score(t, files, opts), testcase)
+	if !ok {
+		t.Errorf("%s was not tested", testcase)
+	}
+	var summaries []string
+	for _, c := range s.Comments {
+		summaries = append(summaries, c.Summary)
+	}
+	return summaries
+}
+
+// FileWasSkipped returns true if the objects of the file with the name filename were skipped,
+// e.g. with the kube-score/skip annotation
+func FileWasSkipped(t testing.TB, files []ks.NamedReader, opts Options, filename string) bool {
+	t.Helper()
+	for _, o := range Score(t, files, opts) {
+		if o.FileLocation.Name == filename {
+			return o.FileLocation.Skip
+		}
+	}
+	return false
+}
+
+func find(sc scorecard.Scorecard, testcase string) (scorecard.TestScore, bool) {
+	for _, o := range sc {
+		for _, s := range o.Checks {
+			if s.Check.Name == testcase {
+				return s, true
+			}
+		}
+	}
+	return scorecard.TestScore{}, false
+}
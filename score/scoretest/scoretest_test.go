@@ -0,0 +1,65 @@
+package scoretest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/score/checks"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// registerTeamLabel registers a custom check, the same way as users of the library do
+func registerTeamLabel(allChecks *checks.Checks, _ ks.AllTypes, _ *config.RunConfiguration) {
+	allChecks.RegisterDeploymentCheck(
+		"custom/Deployment has team label",
+		"Makes sure that Deployments have a team label",
+		func(d appsv1.Deployment) (score scorecard.TestScore, err error) {
+			if d.Labels["team"] == "" {
+				score.Grade = scorecard.GradeCritical
+				score.AddComment("", "The Deployment has no team label", "Set the team label to the owning team")
+				return
+			}
+			score.Grade = scorecard.GradeAllOK
+			return
+		},
+	)
+}
+
+func TestExpectedScore(t *testing.T) {
+	t.Parallel()
+	opts := Options{Register: registerTeamLabel}
+
+	comments := ExpectedScore(t, Files(t, "deployment-no-team.yaml"), opts, "custom/Deployment has team label", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The Deployment has no team label", comments[0].Summary)
+
+	ExpectedScore(t, Files(t, "deployment-team.yaml"), opts, "custom/Deployment has team label", scorecard.GradeAllOK)
+
+	// The built-in checks run too
+	ExpectedScore(t, Files(t, "deployment-team.yaml"), opts, "Container Image Tag", scorecard.GradeAllOK)
+}
+
+func TestSummaries(t *testing.T) {
+	t.Parallel()
+	opts := Options{Register: registerTeamLabel}
+	assert.Equal(
+		t,
+		[]string{"The Deployment has no team label"},
+		Summaries(t, Files(t, "deployment-no-team.yaml"), opts, "custom/Deployment has team label"),
+	)
+}
+
+func TestWasSkipped(t *testing.T) {
+	t.Parallel()
+	opts := Options{
+		Register:  registerTeamLabel,
+		RunConfig: &config.RunConfiguration{UseIgnoreChecksAnnotation: true},
+	}
+	assert.True(t, WasSkipped(t, Files(t, "deployment-skipped.yaml"), opts, "custom/Deployment has team label"))
+	assert.False(t, WasSkipped(t, Files(t, "deployment-no-team.yaml"), opts, "custom/Deployment has team label"))
+	assert.False(t, FileWasSkipped(t, Files(t, "deployment-no-team.yaml"), opts, "testdata/deployment-no-team.yaml"))
+}
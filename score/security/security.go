@@ -1,14 +1,25 @@
 package security
 
 import (
+	"fmt"
+	"slices"
+
 	ks "github.com/romnn/kube-score/domain"
 	"github.com/romnn/kube-score/score/checks"
 	"github.com/romnn/kube-score/scorecard"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type Options struct {
 	SkipInitContainers bool
+	MinUserID          int64
+	MinGroupID         int64
+	// MaxTokenExpirationSeconds is the longest expirationSeconds of projected service account tokens
+	MaxTokenExpirationSeconds int64
+	Namespace                 ks.DefaultNamespace
+	// SafeSysctls are the sysctls that pods are allowed to set
+	SafeSysctls []string
 }
 
 func Register(allChecks *checks.Checks, options Options) {
@@ -33,6 +44,33 @@ func Register(allChecks *checks.Checks, options Options) {
 		`Makes sure that all pods have at a seccomp policy configured.`,
 		podSeccompProfile(options),
 	)
+	allChecks.RegisterOptionalPodCheck(
+		"Container Secrets In Environment",
+		`Makes sure that Secrets are not exposed to containers as environment variables`,
+		containerSecretsInEnvironment(options),
+	)
+	allChecks.RegisterPodContextCheck(
+		"Pod Service Account Token",
+		`Makes sure that mounted service account tokens are projected tokens with a bounded lifetime and an explicit audience, that are not world-readable`,
+		func(ctx *checks.Context, ps ks.PodSpecer) (scorecard.TestScore, error) {
+			return podServiceAccountToken(secretsIn(ctx, options, ps.GetObjectMeta().Namespace), options)(ps)
+		},
+	)
+	allChecks.RegisterOptionalPodCheck(
+		"Pod FSGroup",
+		`Makes sure that pods that mount PersistentVolumeClaims set an fsGroup, and the fsGroupChangePolicy OnRootMismatch`,
+		podFSGroup,
+	)
+	allChecks.RegisterPodCheck(
+		"Pod Share Process Namespace",
+		`Makes sure that the containers of pods do not share a process namespace`,
+		podShareProcessNamespace,
+	)
+	allChecks.RegisterPodCheck(
+		"Pod Sysctls",
+		`Makes sure that pods only set safe sysctls`,
+		podSysctls(options),
+	)
 }
 
 // containerSecurityContextReadOnlyRootFilesystem checks for pods using writeable root filesystems
@@ -121,7 +159,7 @@ func containerSecurityContextPrivileged(
 	}
 }
 
-// containerSecurityContextUserGroupID checks that the user and group are valid ( > 10000) in the security context
+// containerSecurityContextUserGroupID checks that the user and group are valid (>= options.MinUserID and options.MinGroupID) in the security context
 func containerSecurityContextUserGroupID(
 	options Options,
 ) func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
@@ -162,21 +200,27 @@ func containerSecurityContextUserGroupID(
 					sec.RunAsUser = podSecurityContext.RunAsUser
 				}
 			}
-			if sec.RunAsUser == nil || *sec.RunAsUser < 10000 {
+			if sec.RunAsUser == nil || *sec.RunAsUser < options.MinUserID {
 				hasLowUserID = true
 				score.AddComment(
 					container.Name,
 					"The container is running with a low user ID",
-					"A userid above 10 000 is recommended to avoid conflicts with the host. Set securityContext.runAsUser to a value > 10000",
+					fmt.Sprintf(
+						"A userid above %[1]d is recommended to avoid conflicts with the host. Set securityContext.runAsUser to a value > %[1]d",
+						options.MinUserID,
+					),
 				)
 			}
 
-			if sec.RunAsGroup == nil || *sec.RunAsGroup < 10000 {
+			if sec.RunAsGroup == nil || *sec.RunAsGroup < options.MinGroupID {
 				hasLowGroupID = true
 				score.AddComment(
 					container.Name,
 					"The container running with a low group ID",
-					"A groupid above 10 000 is recommended to avoid conflicts with the host. Set securityContext.runAsGroup to a value > 10000",
+					fmt.Sprintf(
+						"A groupid above %[1]d is recommended to avoid conflicts with the host. Set securityContext.runAsGroup to a value > %[1]d",
+						options.MinGroupID,
+					),
 				)
 			}
 		}
@@ -217,3 +261,256 @@ func podSeccompProfile(
 		return
 	}
 }
+
+// containerSecretsInEnvironment checks that no Secrets are used as environment variables, as they can leak
+// through /proc, crash dumps and child processes
+func containerSecretsInEnvironment(
+	options Options,
+) func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		var allContainers []corev1.Container
+		if !options.SkipInitContainers {
+			allContainers = append(
+				allContainers,
+				ps.GetPodTemplateSpec().Spec.InitContainers...)
+		}
+		allContainers = append(
+			allContainers,
+			ps.GetPodTemplateSpec().Spec.Containers...)
+
+		score.Grade = scorecard.GradeAllOK
+
+		const description = "Environment variables can leak through /proc, crash dumps and child processes. " +
+			"Mount the Secret as a volume with a restrictive defaultMode, such as 0400, instead"
+
+		for _, container := range allContainers {
+			for _, env := range container.Env {
+				if env.ValueFrom == nil || env.ValueFrom.SecretKeyRef == nil {
+					continue
+				}
+				score.Grade = scorecard.GradeWarning
+				score.AddComment(
+					container.Name,
+					fmt.Sprintf(
+						"The environment variable %s is set from the Secret %s",
+						env.Name, env.ValueFrom.SecretKeyRef.Name,
+					),
+					description,
+				)
+			}
+			for _, envFrom := range container.EnvFrom {
+				if envFrom.SecretRef == nil {
+					continue
+				}
+				score.Grade = scorecard.GradeWarning
+				score.AddComment(
+					container.Name,
+					fmt.Sprintf(
+						"All keys of the Secret %s are set as environment variables",
+						envFrom.SecretRef.Name,
+					),
+					description,
+				)
+			}
+		}
+
+		return
+	}
+}
+
+// podServiceAccountToken checks the service account tokens that are mounted as volumes
+// secretsKey is the key of the index of the Secrets by namespace
+type secretsKey struct{ namespace ks.DefaultNamespace }
+
+// secretsIn returns the Secrets of the run in the namespace
+func secretsIn(ctx *checks.Context, options Options, namespace string) []ks.Secret {
+	return checks.Index(ctx, secretsKey{options.Namespace}, func(all ks.AllTypes) map[string][]ks.Secret {
+		return checks.ByNamespace(all.Secrets(), options.Namespace, func(s ks.Secret) metav1.ObjectMeta {
+			return s.Secret().ObjectMeta
+		})
+	})[options.Namespace.Of(namespace)]
+}
+
+func podServiceAccountToken(
+	secrets []ks.Secret,
+	options Options,
+) func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		// Secrets with long-lived tokens, that are never rotated
+		tokenSecrets := make(map[string]struct{})
+		for _, s := range secrets {
+			secret := s.Secret()
+			if secret.Type == corev1.SecretTypeServiceAccountToken {
+				tokenSecrets[secret.Name] = struct{}{}
+			}
+		}
+
+		score.Grade = scorecard.GradeAllOK
+		warn := func(volume, summary, description string) {
+			if score.Grade > scorecard.GradeWarning {
+				score.Grade = scorecard.GradeWarning
+			}
+			score.AddComment(volume, summary, description)
+		}
+
+		for _, volume := range ps.GetPodTemplateSpec().Spec.Volumes {
+			if volume.Secret != nil {
+				if _, ok := tokenSecrets[volume.Secret.SecretName]; ok {
+					score.Grade = scorecard.GradeCritical
+					score.AddComment(
+						volume.Name,
+						"The volume mounts a long-lived service account token",
+						fmt.Sprintf(
+							"The Secret %s is of type %s, its token never expires. Use a projected serviceAccountToken volume instead",
+							volume.Secret.SecretName, corev1.SecretTypeServiceAccountToken,
+						),
+					)
+				}
+				continue
+			}
+
+			if volume.Projected == nil {
+				continue
+			}
+			for _, source := range volume.Projected.Sources {
+				token := source.ServiceAccountToken
+				if token == nil {
+					continue
+				}
+				if token.Audience == "" {
+					warn(
+						volume.Name,
+						"The projected service account token has no audience",
+						"Without an audience, the token is accepted by the API server and can be replayed against it. Set the audience to the identifier of the recipient of the token",
+					)
+				}
+				if token.ExpirationSeconds != nil && *token.ExpirationSeconds > options.MaxTokenExpirationSeconds {
+					warn(
+						volume.Name,
+						"The projected service account token is long-lived",
+						fmt.Sprintf(
+							"The token expires after %d seconds, use an expirationSeconds of at most %d",
+							*token.ExpirationSeconds, options.MaxTokenExpirationSeconds,
+						),
+					)
+				}
+			}
+
+			if mode := volume.Projected.DefaultMode; mode != nil && *mode&0o004 != 0 && hasServiceAccountToken(volume.Projected) {
+				warn(
+					volume.Name,
+					"The projected service account token is world-readable",
+					fmt.Sprintf("The defaultMode is %#o, use a mode that is not readable by others, such as 0400", *mode),
+				)
+			}
+		}
+
+		return
+	}
+}
+
+func hasServiceAccountToken(projected *corev1.ProjectedVolumeSource) bool {
+	for _, source := range projected.Sources {
+		if source.ServiceAccountToken != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// podFSGroup checks that pods that mount persistent volumes set the fsGroup, without recursively
+// changing the ownership of the volume every time that it is mounted
+func podFSGroup(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+	spec := ps.GetPodTemplateSpec().Spec
+
+	if !mountsPersistentVolumeClaim(ps.GetTypeMeta().Kind, spec) {
+		score.Grade = scorecard.GradeAllOK
+		score.Skipped = true
+		score.AddComment("", "Skipped because the pod does not mount a PersistentVolumeClaim", "")
+		return
+	}
+
+	score.Grade = scorecard.GradeAllOK
+	sc := spec.SecurityContext
+	if sc == nil || sc.FSGroup == nil {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment(
+			"",
+			"The pod mounts a PersistentVolumeClaim without an fsGroup",
+			"Set securityContext.fsGroup, so that the containers can access the volume without running as the owner of the files",
+		)
+		return
+	}
+	if sc.FSGroupChangePolicy == nil || *sc.FSGroupChangePolicy != corev1.FSGroupChangeOnRootMismatch {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment(
+			"",
+			"The pod changes the ownership of its volumes every time they are mounted",
+			"Set securityContext.fsGroupChangePolicy to OnRootMismatch, to avoid slow recursive changes of ownership on large volumes",
+		)
+	}
+
+	return
+}
+
+func mountsPersistentVolumeClaim(kind string, spec corev1.PodSpec) bool {
+	volumes := make(map[string]struct{})
+	for _, volume := range spec.Volumes {
+		if volume.PersistentVolumeClaim != nil || volume.Ephemeral != nil {
+			return true
+		}
+		volumes[volume.Name] = struct{}{}
+	}
+
+	// The volumeClaimTemplates of StatefulSets are mounted without a volume in the pod template
+	if kind == "StatefulSet" {
+		for _, container := range append(spec.InitContainers, spec.Containers...) {
+			for _, mount := range container.VolumeMounts {
+				if _, ok := volumes[mount.Name]; !ok {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// podShareProcessNamespace checks that the containers of the pod can not see and signal each others processes
+func podShareProcessNamespace(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+	share := ps.GetPodTemplateSpec().Spec.ShareProcessNamespace
+	if share != nil && *share {
+		score.Grade = scorecard.GradeWarning
+		score.AddComment(
+			"",
+			"The pod shares the process namespace between its containers",
+			"Containers can inspect and signal the processes of the other containers, and read their filesystems through /proc. Remove shareProcessNamespace, unless it is required by a sidecar",
+		)
+		return
+	}
+	score.Grade = scorecard.GradeAllOK
+	return
+}
+
+// podSysctls checks that the pod does not set sysctls that affect the other pods of the node
+func podSysctls(options Options) func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+	return func(ps ks.PodSpecer) (score scorecard.TestScore, err error) {
+		score.Grade = scorecard.GradeAllOK
+
+		sc := ps.GetPodTemplateSpec().Spec.SecurityContext
+		if sc == nil {
+			return
+		}
+		for _, sysctl := range sc.Sysctls {
+			if slices.Contains(options.SafeSysctls, sysctl.Name) {
+				continue
+			}
+			score.Grade = scorecard.GradeCritical
+			score.AddComment(
+				"",
+				fmt.Sprintf("The pod sets the unsafe sysctl %s", sysctl.Name),
+				"Unsafe sysctls are not isolated between pods, and can affect the other pods of the node or the node itself",
+			)
+		}
+		return
+	}
+}
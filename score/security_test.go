@@ -109,7 +109,7 @@ func TestContainerSecurityContextUserGroupIDLowGroup(t *testing.T) {
 	assert.Contains(t, comments, scorecard.TestScoreComment{
 		Path:        "foobar",
 		Summary:     "The container running with a low group ID",
-		Description: "A groupid above 10 000 is recommended to avoid conflicts with the host. Set securityContext.runAsGroup to a value > 10000",
+		Description: "A groupid above 10000 is recommended to avoid conflicts with the host. Set securityContext.runAsGroup to a value > 10000",
 	})
 }
 
@@ -130,10 +130,26 @@ func TestContainerSecurityContextUserGroupIDLowUser(t *testing.T) {
 	assert.Contains(t, comments, scorecard.TestScoreComment{
 		Path:        "foobar",
 		Summary:     "The container is running with a low user ID",
-		Description: "A userid above 10 000 is recommended to avoid conflicts with the host. Set securityContext.runAsUser to a value > 10000",
+		Description: "A userid above 10000 is recommended to avoid conflicts with the host. Set securityContext.runAsUser to a value > 10000",
 	})
 }
 
+func TestContainerSecurityContextUserGroupIDLowUserConfigured(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("pod-security-context-low-user-id.yaml")},
+		nil,
+		&config.RunConfiguration{
+			CheckParameters: config.CheckParameters{
+				"container-security-context-user-group-id": {"minUserId": 0},
+			},
+		},
+		"Container Security Context User Group ID",
+		scorecard.GradeAllOK,
+	)
+}
+
 func TestContainerSecurityContextUserGroupIDNoSecurityContext(t *testing.T) {
 	t.Parallel()
 	optionalChecks := make(map[string]struct{})
@@ -252,3 +268,164 @@ func TestContainerSecurityContextReadOnlyRootFilesystemNoSecurityContext(t *test
 		Description: "Set securityContext to run the container in a more secure context.",
 	})
 }
+
+func TestContainerSecretsInEnvironment(t *testing.T) {
+	t.Parallel()
+
+	comments := testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("pod-secret-env.yaml")},
+		nil,
+		&config.RunConfiguration{
+			EnabledOptionalTests: map[string]struct{}{"container-secrets-in-environment": {}},
+		},
+		"Container Secrets In Environment",
+		scorecard.GradeWarning,
+	)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "The environment variable PASSWORD is set from the Secret app-secret", comments[0].Summary)
+	assert.Equal(t, "All keys of the Secret db-secret are set as environment variables", comments[1].Summary)
+}
+
+func TestContainerSecretsInEnvironmentVolume(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("pod-secret-volume.yaml")},
+		nil,
+		&config.RunConfiguration{
+			EnabledOptionalTests: map[string]struct{}{"container-secrets-in-environment": {}},
+		},
+		"Container Secrets In Environment",
+		scorecard.GradeAllOK,
+	)
+}
+
+func TestContainerSecretsInEnvironmentNotRunByDefault(t *testing.T) {
+	t.Parallel()
+	skipped := wasSkipped(
+		t,
+		[]ks.NamedReader{testFile("pod-secret-env.yaml")},
+		nil,
+		nil,
+		"Container Secrets In Environment",
+	)
+	assert.True(t, skipped)
+}
+
+func TestPodServiceAccountTokenProjected(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-service-account-token-projected.yaml", "Pod Service Account Token", scorecard.GradeAllOK)
+}
+
+func TestPodServiceAccountTokenInsecure(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(
+		t,
+		"pod-service-account-token-insecure.yaml",
+		"Pod Service Account Token",
+		scorecard.GradeWarning,
+	)
+	assert.Len(t, comments, 3)
+	assert.Equal(t, "The projected service account token has no audience", comments[0].Summary)
+	assert.Equal(t, "The projected service account token is long-lived", comments[1].Summary)
+	assert.Equal(t, "The projected service account token is world-readable", comments[2].Summary)
+	assert.Equal(t, "The defaultMode is 0644, use a mode that is not readable by others, such as 0400", comments[2].Description)
+}
+
+func TestPodServiceAccountTokenMaxExpiration(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("pod-service-account-token-projected.yaml")},
+		nil,
+		&config.RunConfiguration{
+			CheckParameters: config.CheckParameters{
+				"pod-service-account-token": {"maxExpirationSeconds": 600},
+			},
+		},
+		"Pod Service Account Token",
+		scorecard.GradeWarning,
+	)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The token expires after 3600 seconds, use an expirationSeconds of at most 600", comments[0].Description)
+}
+
+func TestPodServiceAccountTokenSecret(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-service-account-token-secret.yaml", "Pod Service Account Token", scorecard.GradeCritical)
+}
+
+func testPodFSGroup(t *testing.T, file string, expected scorecard.Grade) []scorecard.TestScoreComment {
+	t.Helper()
+	return testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile(file)},
+		nil,
+		&config.RunConfiguration{
+			EnabledOptionalTests: map[string]struct{}{"pod-fsgroup": {}},
+		},
+		"Pod FSGroup",
+		expected,
+	)
+}
+
+func TestPodFSGroupMissing(t *testing.T) {
+	t.Parallel()
+	comments := testPodFSGroup(t, "pod-fsgroup-pvc-missing.yaml", scorecard.GradeWarning)
+	assert.Equal(t, "The pod mounts a PersistentVolumeClaim without an fsGroup", comments[0].Summary)
+}
+
+func TestPodFSGroupNoChangePolicy(t *testing.T) {
+	t.Parallel()
+	comments := testPodFSGroup(t, "statefulset-fsgroup-no-change-policy.yaml", scorecard.GradeWarning)
+	assert.Equal(t, "The pod changes the ownership of its volumes every time they are mounted", comments[0].Summary)
+}
+
+func TestPodFSGroupOK(t *testing.T) {
+	t.Parallel()
+	testPodFSGroup(t, "statefulset-fsgroup-ok.yaml", scorecard.GradeAllOK)
+}
+
+func TestPodFSGroupWithoutVolumes(t *testing.T) {
+	t.Parallel()
+	skipped := wasSkipped(
+		t,
+		[]ks.NamedReader{testFile("pod-secret-volume.yaml")},
+		nil,
+		&config.RunConfiguration{
+			EnabledOptionalTests: map[string]struct{}{"pod-fsgroup": {}},
+		},
+		"Pod FSGroup",
+	)
+	assert.True(t, skipped)
+}
+
+func TestPodShareProcessNamespace(t *testing.T) {
+	t.Parallel()
+	testExpectedScore(t, "pod-share-process-namespace.yaml", "Pod Share Process Namespace", scorecard.GradeWarning)
+	testExpectedScore(t, "pod-secret-volume.yaml", "Pod Share Process Namespace", scorecard.GradeAllOK)
+}
+
+func TestPodSysctlsUnsafe(t *testing.T) {
+	t.Parallel()
+	comments := testExpectedScore(t, "pod-sysctls.yaml", "Pod Sysctls", scorecard.GradeCritical)
+	assert.Len(t, comments, 1)
+	assert.Equal(t, "The pod sets the unsafe sysctl kernel.msgmax", comments[0].Summary)
+}
+
+func TestPodSysctlsAllowed(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("pod-sysctls.yaml")},
+		nil,
+		&config.RunConfiguration{
+			CheckParameters: config.CheckParameters{
+				"pod-sysctls": {"safeSysctls": []string{"net.ipv4.tcp_syncookies", "kernel.msgmax"}},
+			},
+		},
+		"Pod Sysctls",
+		scorecard.GradeAllOK,
+	)
+}
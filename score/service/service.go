@@ -1,8 +1,12 @@
 package service
 
 import (
+	"fmt"
+	"strings"
+
 	corev1 "k8s.io/api/core/v1"
 
+	"github.com/romnn/kube-score/config"
 	ks "github.com/romnn/kube-score/domain"
 	"github.com/romnn/kube-score/score/checks"
 	"github.com/romnn/kube-score/score/internal"
@@ -10,36 +14,97 @@ import (
 )
 
 type Options struct {
-	Namespace string
+	Namespace   ks.DefaultNamespace
+	ServiceMesh config.ServiceMesh
+	// IPFamilyProfile is dual-stack if the Services should be dual-stack, or single-stack if the
+	// cluster only has one IP family
+	IPFamilyProfile string
+	// KubernetesVersion decides how topology aware routing is configured
+	KubernetesVersion config.Semver
+	// TopologyMinReplicas is the number of replicas that a Service targets from which topology aware
+	// routing is recommended
+	TopologyMinReplicas int32
 }
 
-func Register(allChecks *checks.Checks, pods ks.Pods, podspeccers ks.PodSpeccers, options Options) {
-	allChecks.RegisterServiceCheck(
+func Register(allChecks *checks.Checks, options Options) {
+	allChecks.RegisterServiceContextCheck(
 		"Service Targets Pod",
 		`Makes sure that all Services targets a Pod`,
-		serviceTargetsPod(pods.Pods(), podspeccers.PodSpeccers(), options),
+		func(ctx *checks.Context, service corev1.Service) (scorecard.TestScore, error) {
+			podsInNamespace := checks.Index(ctx, podLabelsKey{options.Namespace},
+				func(all ks.AllTypes) map[string][]map[string]string {
+					return podLabelsByNamespace(all.Pods(), all.PodSpeccers(), options)
+				},
+			)
+			return serviceTargetsPod(podsInNamespace, options)(service)
+		},
+	)
+	allChecks.RegisterServiceContextCheck(
+		"Service has endpoints",
+		`Makes sure that Services without a selector have Endpoints or EndpointSlices in the input, as their endpoints are not managed by Kubernetes`,
+		func(ctx *checks.Context, service corev1.Service) (scorecard.TestScore, error) {
+			endpoints := checks.Index(ctx, endpointsKey{options.Namespace},
+				func(all ks.AllTypes) map[endpointsFor]bool {
+					return endpointsByService(all.OtherObjects(), options)
+				},
+			)
+			return serviceHasEndpoints(endpoints, options)(service)
+		},
 	)
 	allChecks.RegisterServiceCheck(
 		"Service Type",
 		`Makes sure that the Service type is not NodePort`,
 		serviceType(options),
 	)
+	allChecks.RegisterOptionalServiceCheck(
+		"Service Port Name",
+		`Makes sure that all Service port names are prefixed with their protocol (e.g. http-, grpc-, tcp-) or set appProtocol. Uses the protocols of the service mesh if --service-mesh is set, which also enables the check`,
+		servicePortName(options),
+	)
+	allChecks.RegisterOptionalServiceCheck(
+		"Service dual-stack",
+		`Makes sure that the ipFamilies and ipFamilyPolicy of the Service are valid, and match the IP families of the cluster set by the profile parameter`,
+		serviceDualStack(options),
+	)
+	allChecks.RegisterOptionalServiceCheck(
+		"Service session affinity timeout",
+		`Makes sure that Services with sessionAffinity ClientIP configure the timeout of the affinity`,
+		serviceSessionAffinityTimeout,
+	)
+	allChecks.RegisterOptionalServiceContextCheck(
+		"Service traffic distribution",
+		`Makes sure that Services that target many replicas prefer endpoints in the same zone with trafficDistribution or topology aware hints, if supported by the Kubernetes version`,
+		func(ctx *checks.Context, service corev1.Service) (scorecard.TestScore, error) {
+			replicasInNamespace := checks.Index(ctx, replicasKey{options.Namespace},
+				func(all ks.AllTypes) map[string][]labelsWithReplicas {
+					return replicasByNamespace(all.Deployments(), all.StatefulSets(), options)
+				},
+			)
+			return serviceTrafficDistribution(replicasInNamespace, options)(service)
+		},
+	)
 }
 
-// serviceTargetsPod checks if a Service targets a pod and issues a critical warning if no matching pod
-// could be found
-func serviceTargetsPod(
+// wellKnownProtocols are the protocol prefixes used by service meshes and ingress
+// controllers to detect the protocol of a port
+var wellKnownProtocols = []string{
+	"http", "http2", "https", "h2c", "grpc", "grpc-web", "ws", "wss",
+	"tcp", "tls", "udp", "mongo", "mysql", "redis",
+}
+
+// podLabelsKey is the key of the index of the labels of the pods by namespace
+type podLabelsKey struct{ namespace ks.DefaultNamespace }
+
+// podLabelsByNamespace returns the labels of the Pods and the pod templates by namespace
+func podLabelsByNamespace(
 	pods []ks.Pod,
 	podspecers []ks.PodSpecer,
 	options Options,
-) func(corev1.Service) (scorecard.TestScore, error) {
+) map[string][]map[string]string {
 	podsInNamespace := make(map[string][]map[string]string)
 	for _, p := range pods {
 		pod := p.Pod()
-		namespace := pod.Namespace
-		if namespace == "" {
-			namespace = options.Namespace
-		}
+		namespace := options.Namespace.Of(pod.Namespace)
 		if _, ok := podsInNamespace[namespace]; !ok {
 			podsInNamespace[namespace] = []map[string]string{}
 		}
@@ -49,10 +114,7 @@ func serviceTargetsPod(
 		)
 	}
 	for _, podSpec := range podspecers {
-		podNamespace := podSpec.GetObjectMeta().Namespace
-		if podNamespace == "" {
-			podNamespace = options.Namespace
-		}
+		podNamespace := options.Namespace.Of(podSpec.GetObjectMeta().Namespace)
 
 		if _, ok := podsInNamespace[podNamespace]; !ok {
 			podsInNamespace[podNamespace] = []map[string]string{}
@@ -62,7 +124,68 @@ func serviceTargetsPod(
 			podSpec.GetPodTemplateSpec().Labels,
 		)
 	}
+	return podsInNamespace
+}
+
+// endpointsKey is the key of the index of the Services with manually defined endpoints
+type endpointsKey struct{ namespace ks.DefaultNamespace }
+
+// endpointsFor is the namespace and name of a Service with endpoints
+type endpointsFor struct {
+	namespace string
+	name      string
+}
 
+// endpointsByService returns the Services that have an Endpoints object with the same name, or an
+// EndpointSlice with the kubernetes.io/service-name label
+func endpointsByService(others []ks.BothMeta, options Options) map[endpointsFor]bool {
+	endpoints := make(map[endpointsFor]bool)
+	for _, o := range others {
+		namespace := options.Namespace.Of(o.ObjectMeta.Namespace)
+		switch {
+		case o.TypeMeta.Kind == "Endpoints" && o.TypeMeta.APIVersion == "v1":
+			endpoints[endpointsFor{namespace, o.ObjectMeta.Name}] = true
+		case o.TypeMeta.Kind == "EndpointSlice" && strings.HasPrefix(o.TypeMeta.APIVersion, "discovery.k8s.io/"):
+			if name := o.ObjectMeta.Labels["kubernetes.io/service-name"]; name != "" {
+				endpoints[endpointsFor{namespace, name}] = true
+			}
+		}
+	}
+	return endpoints
+}
+
+// serviceHasEndpoints checks that a Service without a selector has manually defined endpoints
+func serviceHasEndpoints(
+	endpoints map[endpointsFor]bool,
+	options Options,
+) func(corev1.Service) (scorecard.TestScore, error) {
+	return func(service corev1.Service) (scorecard.TestScore, error) {
+		var score scorecard.TestScore
+		score.Grade = scorecard.GradeAllOK
+		if service.Spec.Type == corev1.ServiceTypeExternalName || len(service.Spec.Selector) > 0 {
+			return score, nil
+		}
+		if endpoints[endpointsFor{options.Namespace.Of(service.Namespace), service.Name}] {
+			return score, nil
+		}
+
+		score.Grade = scorecard.GradeWarning
+		score.AddComment(
+			"",
+			"The service has neither a selector nor endpoints",
+			"Services without a selector do not route to any pods, unless Endpoints or EndpointSlices are created for them. "+
+				"Add a selector, or define the Endpoints or an EndpointSlice with the label kubernetes.io/service-name together with the Service.",
+		)
+		return score, nil
+	}
+}
+
+// serviceTargetsPod checks if a Service targets a pod and issues a critical warning if no matching pod
+// could be found
+func serviceTargetsPod(
+	podsInNamespace map[string][]map[string]string,
+	options Options,
+) func(corev1.Service) (scorecard.TestScore, error) {
 	return func(service corev1.Service) (scorecard.TestScore, error) {
 		// Services of type ExternalName does not have a selector
 		var score scorecard.TestScore
@@ -73,10 +196,7 @@ func serviceTargetsPod(
 
 		hasMatch := false
 
-		serviceNamespace := service.Namespace
-		if serviceNamespace == "" {
-			serviceNamespace = options.Namespace
-		}
+		serviceNamespace := options.Namespace.Of(service.Namespace)
 
 		for _, podLabels := range podsInNamespace[serviceNamespace] {
 			if internal.LabelSelectorMatchesLabels(service.Spec.Selector, podLabels) {
@@ -113,3 +233,235 @@ func serviceType(options Options) func(service corev1.Service) (scorecard.TestSc
 		return score, nil
 	}
 }
+
+// hasProtocolPrefix reports if the port name is a protocol name, or is prefixed with one
+// followed by a dash
+func hasProtocolPrefix(name string, protocols []string) bool {
+	name = strings.ToLower(name)
+	for _, protocol := range protocols {
+		if name == protocol || strings.HasPrefix(name, protocol+"-") {
+			return true
+		}
+	}
+	return false
+}
+
+// servicePortName checks that all Service ports follow the protocol naming convention, or the
+// convention of the service mesh if one is configured
+func servicePortName(options Options) func(service corev1.Service) (scorecard.TestScore, error) {
+	prefixes := wellKnownProtocols
+	reason := "Service meshes and some ingress controllers rely on it for protocol detection."
+	if options.ServiceMesh != config.ServiceMeshNone {
+		prefixes = options.ServiceMesh.ProtocolPrefixes()
+		reason = string(options.ServiceMesh) + " may otherwise treat the traffic as plain TCP."
+	}
+
+	return func(service corev1.Service) (score scorecard.TestScore, err error) {
+		score.Grade = scorecard.GradeAllOK
+		if service.Spec.Type == corev1.ServiceTypeExternalName {
+			return
+		}
+
+		for _, port := range service.Spec.Ports {
+			if port.AppProtocol != nil && *port.AppProtocol != "" {
+				continue
+			}
+			if hasProtocolPrefix(port.Name, prefixes) {
+				continue
+			}
+
+			path := port.Name
+			if path == "" {
+				path = fmt.Sprintf("%d", port.Port)
+			}
+			score.Grade = scorecard.GradeWarning
+			score.AddComment(
+				path,
+				"The service port name does not follow the protocol naming convention",
+				"Name the port <protocol>[-<suffix>] (e.g. http-web, grpc-api or tcp-db), or set appProtocol. "+reason,
+			)
+		}
+		return
+	}
+}
+
+// serviceDualStack checks that the IP family configuration of the Service is valid, and that it
+// matches the dual-stack profile of the cluster
+func serviceDualStack(options Options) func(corev1.Service) (scorecard.TestScore, error) {
+	return func(service corev1.Service) (scorecard.TestScore, error) {
+		var score scorecard.TestScore
+		score.Grade = scorecard.GradeAllOK
+		if service.Spec.Type == corev1.ServiceTypeExternalName {
+			return score, nil
+		}
+
+		policy := corev1.IPFamilyPolicySingleStack
+		if service.Spec.IPFamilyPolicy != nil {
+			policy = *service.Spec.IPFamilyPolicy
+		}
+		families := service.Spec.IPFamilies
+
+		switch {
+		case len(families) > 2 || (len(families) == 2 && families[0] == families[1]):
+			score.Grade = scorecard.GradeCritical
+			score.AddComment(
+				"",
+				"The service has invalid ipFamilies",
+				"A service can have at most one IPv4 and one IPv6 family.",
+			)
+			return score, nil
+		case len(families) == 2 && policy == corev1.IPFamilyPolicySingleStack:
+			score.Grade = scorecard.GradeCritical
+			score.AddComment(
+				"",
+				"The service has two ipFamilies but is SingleStack",
+				"Set ipFamilyPolicy to PreferDualStack or RequireDualStack, the API server rejects services with two ipFamilies that are SingleStack.",
+			)
+			return score, nil
+		}
+
+		// Headless services without a selector get the IP families of the cluster
+		if service.Spec.ClusterIP == corev1.ClusterIPNone && len(service.Spec.Selector) == 0 {
+			return score, nil
+		}
+
+		switch options.IPFamilyProfile {
+		case "dual-stack":
+			if policy == corev1.IPFamilyPolicySingleStack {
+				score.Grade = scorecard.GradeWarning
+				score.AddComment(
+					"",
+					"The service is SingleStack",
+					"The service only gets an address of one IP family in a dual-stack cluster. "+
+						"Set ipFamilyPolicy to PreferDualStack, or RequireDualStack if the service must be reachable over both IPv4 and IPv6.",
+				)
+			}
+		case "single-stack":
+			if policy == corev1.IPFamilyPolicyRequireDualStack {
+				score.Grade = scorecard.GradeWarning
+				score.AddComment(
+					"",
+					"The service requires dual-stack",
+					"The service can not be created in a cluster that only has one IP family. Set ipFamilyPolicy to PreferDualStack or SingleStack.",
+				)
+			}
+		}
+		return score, nil
+	}
+}
+
+// serviceSessionAffinityTimeout checks that the timeout of the ClientIP session affinity is set
+func serviceSessionAffinityTimeout(service corev1.Service) (score scorecard.TestScore, err error) {
+	score.Grade = scorecard.GradeAllOK
+	if service.Spec.SessionAffinity != corev1.ServiceAffinityClientIP {
+		return
+	}
+
+	affinity := service.Spec.SessionAffinityConfig
+	if affinity != nil && affinity.ClientIP != nil && affinity.ClientIP.TimeoutSeconds != nil {
+		return
+	}
+	score.Grade = scorecard.GradeWarning
+	score.AddComment(
+		"",
+		"The service has sessionAffinity ClientIP without a timeout",
+		"Clients stick to the same pod for 3 hours by default, which can unbalance the load after the pods are scaled or restarted. "+
+			"Set spec.sessionAffinityConfig.clientIP.timeoutSeconds to how long a session should last.",
+	)
+	return
+}
+
+// replicasKey is the key of the index of the replicas of the pod templates by namespace
+type replicasKey struct{ namespace ks.DefaultNamespace }
+
+// labelsWithReplicas are the labels of a pod template and the number of replicas of it
+type labelsWithReplicas struct {
+	labels   map[string]string
+	replicas int32
+}
+
+// replicasByNamespace returns the labels and replicas of the Deployments and StatefulSets by
+// namespace
+func replicasByNamespace(
+	deployments []ks.Deployment,
+	statefulSets []ks.StatefulSet,
+	options Options,
+) map[string][]labelsWithReplicas {
+	replicasInNamespace := make(map[string][]labelsWithReplicas)
+	add := func(namespace string, labels map[string]string, replicas *int32) {
+		r := int32(1)
+		if replicas != nil {
+			r = *replicas
+		}
+		namespace = options.Namespace.Of(namespace)
+		replicasInNamespace[namespace] = append(
+			replicasInNamespace[namespace],
+			labelsWithReplicas{labels, r},
+		)
+	}
+	for _, d := range deployments {
+		deployment := d.Deployment()
+		add(deployment.Namespace, deployment.Spec.Template.Labels, deployment.Spec.Replicas)
+	}
+	for _, s := range statefulSets {
+		statefulSet := s.StatefulSet()
+		add(statefulSet.Namespace, statefulSet.Spec.Template.Labels, statefulSet.Spec.Replicas)
+	}
+	return replicasInNamespace
+}
+
+// serviceTrafficDistribution checks that Services with many endpoints configure topology aware
+// routing, with trafficDistribution since Kubernetes v1.31 and topology aware hints before
+func serviceTrafficDistribution(
+	replicasInNamespace map[string][]labelsWithReplicas,
+	options Options,
+) func(corev1.Service) (scorecard.TestScore, error) {
+	return func(service corev1.Service) (score scorecard.TestScore, err error) {
+		score.Grade = scorecard.GradeAllOK
+		if service.Spec.Type == corev1.ServiceTypeExternalName || len(service.Spec.Selector) == 0 {
+			return
+		}
+		if hasTopologyAwareRouting(service) {
+			return
+		}
+
+		var replicas int32
+		for _, r := range replicasInNamespace[options.Namespace.Of(service.Namespace)] {
+			if internal.LabelSelectorMatchesLabels(service.Spec.Selector, r.labels) {
+				replicas += r.replicas
+			}
+		}
+		if replicas < options.TopologyMinReplicas {
+			return
+		}
+
+		var recommendation string
+		switch {
+		case !options.KubernetesVersion.LessThan(config.Semver{Major: 1, Minor: 31}):
+			recommendation = "Set spec.trafficDistribution to PreferClose"
+		case !options.KubernetesVersion.LessThan(config.Semver{Major: 1, Minor: 27}):
+			recommendation = "Set the annotation service.kubernetes.io/topology-mode to Auto"
+		default:
+			recommendation = "Set the annotation service.kubernetes.io/topology-aware-hints to auto"
+		}
+		score.Grade = scorecard.GradeWarning
+		score.AddComment(
+			"",
+			fmt.Sprintf("The service targets %d replicas without topology aware routing", replicas),
+			recommendation+" to keep the traffic in the zone of the client when possible, which reduces the latency and the cost of cross-zone traffic.",
+		)
+		return
+	}
+}
+
+// hasTopologyAwareRouting reports if the Service sets trafficDistribution or enables topology aware
+// hints with an annotation
+func hasTopologyAwareRouting(service corev1.Service) bool {
+	if service.Spec.TrafficDistribution != nil && *service.Spec.TrafficDistribution != "" {
+		return true
+	}
+	if mode, ok := service.Annotations["service.kubernetes.io/topology-mode"]; ok && mode != "" && mode != "Disabled" {
+		return true
+	}
+	return strings.EqualFold(service.Annotations["service.kubernetes.io/topology-aware-hints"], "auto")
+}
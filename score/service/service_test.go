@@ -0,0 +1,59 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/romnn/kube-score/config"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func TestServicePortName(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		ports    []corev1.ServicePort
+		options  Options
+		expected scorecard.Grade
+	}{
+		{
+			ports:    []corev1.ServicePort{{Name: "http"}, {Name: "grpc-api"}, {Name: "tcp-db"}},
+			expected: scorecard.GradeAllOK,
+		},
+		{
+			ports:    []corev1.ServicePort{{Name: "web", AppProtocol: ptr.To("http")}},
+			expected: scorecard.GradeAllOK,
+		},
+		{
+			ports:    []corev1.ServicePort{{Name: "web"}},
+			expected: scorecard.GradeWarning,
+		},
+		{
+			ports:    []corev1.ServicePort{{Name: "httpd"}},
+			expected: scorecard.GradeWarning,
+		},
+		{
+			ports:    []corev1.ServicePort{{Port: 80}},
+			expected: scorecard.GradeWarning,
+		},
+		{
+			ports:    []corev1.ServicePort{{Name: "mongo-db"}},
+			expected: scorecard.GradeAllOK,
+		},
+		{
+			ports:    []corev1.ServicePort{{Name: "mongo-db"}},
+			options:  Options{ServiceMesh: config.ServiceMeshLinkerd},
+			expected: scorecard.GradeWarning,
+		},
+	}
+
+	for i, tc := range cases {
+		s, err := servicePortName(tc.options)(corev1.Service{
+			Spec: corev1.ServiceSpec{Ports: tc.ports},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, s.Grade, "Case: %d", i)
+	}
+}
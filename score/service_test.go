@@ -3,7 +3,11 @@ package score
 import (
 	"testing"
 
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/parser"
 	"github.com/romnn/kube-score/scorecard"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestServiceTargetsPodDeployment(t *testing.T) {
@@ -145,3 +149,245 @@ func TestServiceTypeDefault(t *testing.T) {
 		scorecard.GradeAllOK,
 	)
 }
+
+func TestServicePortNameServiceMeshDeclared(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("service-port-protocol-declared.yaml")},
+		nil,
+		&config.RunConfiguration{
+			ServiceMesh:          config.ServiceMeshIstio,
+			EnabledOptionalTests: map[string]struct{}{"service-port-name": {}},
+		},
+		"Service Port Name",
+		scorecard.GradeAllOK,
+	)
+}
+
+func TestServicePortNameServiceMeshNotDeclared(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("service-port-protocol-not-declared.yaml")},
+		nil,
+		&config.RunConfiguration{
+			ServiceMesh:          config.ServiceMeshLinkerd,
+			EnabledOptionalTests: map[string]struct{}{"service-port-name": {}},
+		},
+		"Service Port Name",
+		scorecard.GradeWarning,
+	)
+}
+
+func TestServicePortNameExternalName(t *testing.T) {
+	t.Parallel()
+	testExpectedScoreWithConfig(
+		t,
+		[]ks.NamedReader{testFile("service-port-protocol-external-name.yaml")},
+		nil,
+		&config.RunConfiguration{
+			ServiceMesh:          config.ServiceMeshIstio,
+			EnabledOptionalTests: map[string]struct{}{"service-port-name": {}},
+		},
+		"Service Port Name",
+		scorecard.GradeAllOK,
+	)
+}
+
+func TestServicePortNameOptional(t *testing.T) {
+	t.Parallel()
+	assert.True(t, wasSkipped(
+		t,
+		[]ks.NamedReader{testFile("service-port-name-invalid.yaml")},
+		nil,
+		nil,
+		"Service Port Name",
+	))
+}
+
+func TestServicePortNameInvalid(t *testing.T) {
+	t.Parallel()
+	summaries := getSummaries(
+		t,
+		[]ks.NamedReader{testFile("service-port-name-invalid.yaml")},
+		nil,
+		&config.RunConfiguration{
+			EnabledOptionalTests: map[string]struct{}{"service-port-name": {}},
+		},
+		"Service Port Name",
+	)
+	assert.Equal(
+		t,
+		[]string{"The service port name does not follow the protocol naming convention"},
+		summaries,
+	)
+}
+
+func TestServiceTargetsPodChecksReused(t *testing.T) {
+	t.Parallel()
+
+	// The checks are registered once and score inputs with and without the target of the Service
+	allChecks := RegisterAllChecks(nil, nil)
+	for file, expected := range map[string]scorecard.Grade{
+		"service-target-deployment.yaml": scorecard.GradeAllOK,
+		"service-not-target-pod.yaml":    scorecard.GradeCritical,
+	} {
+		p, err := parser.New(nil)
+		assert.NoError(t, err)
+		parsed, err := p.ParseFiles([]ks.NamedReader{testFile(file)})
+		assert.NoError(t, err)
+		card, err := Score(parsed, allChecks, nil)
+		assert.NoError(t, err)
+
+		scored := 0
+		for _, o := range *card {
+			for _, c := range o.Checks {
+				if c.Check.ID == "service-targets-pod" {
+					assert.Equal(t, expected, c.Grade, file)
+					scored++
+				}
+			}
+		}
+		assert.Equal(t, 1, scored, file)
+	}
+}
+
+func TestServiceHasEndpoints(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore([]ks.NamedReader{testFile("service-endpoints.yaml")}, nil, nil)
+	assert.NoError(t, err)
+
+	grades := make(map[string]scorecard.Grade)
+	for _, o := range sc {
+		for _, c := range o.Checks {
+			if c.Check.ID == "service-has-endpoints" {
+				grades[o.ObjectMeta.Name] = c.Grade
+			}
+		}
+	}
+	assert.Equal(t, map[string]scorecard.Grade{
+		"with-selector": scorecard.GradeAllOK,
+		"database":      scorecard.GradeAllOK,
+		"cache":         scorecard.GradeAllOK,
+		"no-endpoints":  scorecard.GradeWarning,
+		"external":      scorecard.GradeAllOK,
+	}, grades)
+}
+
+func TestServiceDualStack(t *testing.T) {
+	t.Parallel()
+	for profile, expected := range map[string]map[string]scorecard.Grade{
+		"dual-stack": {
+			"default-policy":            scorecard.GradeWarning,
+			"prefer-dual-stack":         scorecard.GradeAllOK,
+			"require-dual-stack":        scorecard.GradeAllOK,
+			"single-stack-two-families": scorecard.GradeCritical,
+			"headless-without-selector": scorecard.GradeAllOK,
+			"external":                  scorecard.GradeAllOK,
+		},
+		"single-stack": {
+			"default-policy":            scorecard.GradeAllOK,
+			"prefer-dual-stack":         scorecard.GradeAllOK,
+			"require-dual-stack":        scorecard.GradeWarning,
+			"single-stack-two-families": scorecard.GradeCritical,
+			"headless-without-selector": scorecard.GradeAllOK,
+			"external":                  scorecard.GradeAllOK,
+		},
+	} {
+		sc, err := testScore(
+			[]ks.NamedReader{testFile("service-dual-stack.yaml")},
+			nil,
+			&config.RunConfiguration{
+				EnabledOptionalTests: map[string]struct{}{"service-dual-stack": {}},
+				CheckParameters: config.CheckParameters{
+					"service-dual-stack": {"profile": profile},
+				},
+			},
+		)
+		assert.NoError(t, err)
+
+		grades := make(map[string]scorecard.Grade)
+		for _, o := range sc {
+			for _, c := range o.Checks {
+				if c.Check.ID == "service-dual-stack" {
+					grades[o.ObjectMeta.Name] = c.Grade
+				}
+			}
+		}
+		assert.Equal(t, expected, grades, profile)
+	}
+}
+
+func TestServiceSessionAffinityTimeout(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore(
+		[]ks.NamedReader{testFile("service-session-affinity.yaml")},
+		nil,
+		&config.RunConfiguration{
+			EnabledOptionalTests: map[string]struct{}{"service-session-affinity-timeout": {}},
+		},
+	)
+	assert.NoError(t, err)
+
+	grades := make(map[string]scorecard.Grade)
+	for _, o := range sc {
+		for _, c := range o.Checks {
+			if c.Check.ID == "service-session-affinity-timeout" {
+				grades[o.ObjectMeta.Name] = c.Grade
+			}
+		}
+	}
+	assert.Equal(t, map[string]scorecard.Grade{
+		"no-affinity":     scorecard.GradeAllOK,
+		"without-timeout": scorecard.GradeWarning,
+		"with-timeout":    scorecard.GradeAllOK,
+	}, grades)
+}
+
+func TestServiceTrafficDistribution(t *testing.T) {
+	t.Parallel()
+	for version, expected := range map[config.Semver]string{
+		{Major: 1, Minor: 22}: "",
+		{Major: 1, Minor: 26}: "service.kubernetes.io/topology-aware-hints",
+		{Major: 1, Minor: 29}: "service.kubernetes.io/topology-mode",
+		{Major: 1, Minor: 32}: "spec.trafficDistribution",
+	} {
+		sc, err := testScore(
+			[]ks.NamedReader{testFile("service-traffic-distribution.yaml")},
+			nil,
+			&config.RunConfiguration{
+				KubernetesVersion:    version,
+				EnabledOptionalTests: map[string]struct{}{"service-traffic-distribution": {}},
+			},
+		)
+		assert.NoError(t, err)
+
+		grades := make(map[string]scorecard.Grade)
+		for _, o := range sc {
+			for _, c := range o.Checks {
+				if c.Check.ID != "service-traffic-distribution" {
+					continue
+				}
+				grades[o.ObjectMeta.Name] = c.Grade
+				if o.ObjectMeta.Name == "large" && expected != "" {
+					assert.Len(t, c.Comments, 1)
+					assert.Contains(t, c.Comments[0].Description, expected)
+				}
+				if expected == "" {
+					assert.True(t, c.Skipped)
+				}
+			}
+		}
+		large := scorecard.GradeWarning
+		if expected == "" {
+			large = scorecard.GradeAllOK
+		}
+		assert.Equal(t, map[string]scorecard.Grade{
+			"large":               large,
+			"large-prefer-close":  scorecard.GradeAllOK,
+			"large-topology-mode": scorecard.GradeAllOK,
+			"small":               scorecard.GradeAllOK,
+		}, grades, version)
+	}
+}
@@ -0,0 +1,41 @@
+package stable
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// metaReplicaSetManaged recommends to replace ReplicationControllers and ReplicaSets that are not
+// created by a Deployment with Deployments, which can roll out changes to the pod template
+func metaReplicaSetManaged(meta domain.BothMeta) (score scorecard.TestScore, err error) {
+	score.Grade = scorecard.GradeAllOK
+
+	switch meta.TypeMeta.Kind {
+	case "ReplicationController":
+		score.Grade = scorecard.GradeWarning
+		score.AddComment(
+			"",
+			"ReplicationControllers are superseded by Deployments",
+			"It's recommended to use a Deployment with the same pod template instead, which manages ReplicaSets and can roll out changes",
+		)
+	case "ReplicaSet":
+		owner := metav1.GetControllerOfNoCopy(&meta.ObjectMeta)
+		if owner != nil && owner.Kind == "Deployment" {
+			return
+		}
+		score.Grade = scorecard.GradeWarning
+		score.AddComment(
+			"",
+			"The ReplicaSet is not managed by a Deployment",
+			fmt.Sprintf(
+				"It's recommended to use a Deployment with the same pod template instead of the ReplicaSet %s, which can roll out changes",
+				meta.ObjectMeta.Name,
+			),
+		)
+	}
+	return
+}
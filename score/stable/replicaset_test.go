@@ -0,0 +1,31 @@
+package stable
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func TestReplicaSetManaged(t *testing.T) {
+	t.Parallel()
+	isController := true
+	grade := func(kind string, owners ...v1.OwnerReference) scorecard.Grade {
+		score, err := metaReplicaSetManaged(ks.BothMeta{
+			TypeMeta:   v1.TypeMeta{Kind: kind},
+			ObjectMeta: v1.ObjectMeta{Name: "api", OwnerReferences: owners},
+		})
+		assert.NoError(t, err)
+		return score.Grade
+	}
+
+	assert.Equal(t, scorecard.GradeAllOK, grade("Deployment"))
+	assert.Equal(t, scorecard.GradeWarning, grade("ReplicationController"))
+	assert.Equal(t, scorecard.GradeWarning, grade("ReplicaSet"))
+	assert.Equal(t, scorecard.GradeAllOK, grade("ReplicaSet",
+		v1.OwnerReference{Kind: "Deployment", Name: "api", Controller: &isController},
+	))
+}
@@ -15,9 +15,16 @@ func Register(kubernetesVersion config.Semver, allChecks *checks.Checks) {
 		`Checks if the object is using a deprecated apiVersion`,
 		metaStableAvailable(kubernetesVersion),
 	)
+	allChecks.RegisterMetaCheck(
+		"ReplicaSet managed by Deployment",
+		`Makes sure that ReplicaSets are managed by a Deployment, and that ReplicationControllers are replaced by Deployments`,
+		metaReplicaSetManaged,
+	)
 }
 
 // ScoreMetaStableAvailable checks if the supplied TypeMeta is an unstable object type, that has a stable(r) replacement
+// that is available in kubernetesVersion. The version is compared per apiVersion, which is why this
+// check is not gated by the versions of the built-in checks, see checks.KubernetesVersions.
 func metaStableAvailable(
 	kubernetesVersion config.Semver,
 ) func(meta domain.BothMeta) (scorecard.TestScore, error) {
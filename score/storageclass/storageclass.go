@@ -0,0 +1,99 @@
+package storageclass
+
+import (
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+
+	"github.com/romnn/kube-score/score/checks"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+type Options struct {
+	// ReclaimProfile is data-critical to also flag the Delete reclaimPolicy, or default to only flag
+	// StorageClasses that do not set it
+	ReclaimProfile string
+	// TopologyProvisioners are the provisioners of volumes that can only be used in some zones or
+	// nodes
+	TopologyProvisioners []string
+}
+
+func Register(allChecks *checks.Checks, options Options) {
+	allChecks.RegisterStorageClassCheck(
+		"StorageClass reclaim policy",
+		`Makes sure that the StorageClass sets reclaimPolicy explicitly. With the profile parameter set to data-critical, the Delete reclaimPolicy is flagged too`,
+		storageClassReclaimPolicy(options),
+	)
+	allChecks.RegisterStorageClassCheck(
+		"StorageClass volume expansion",
+		`Makes sure that the StorageClass allows volume expansion`,
+		storageClassVolumeExpansion,
+	)
+	allChecks.RegisterStorageClassCheck(
+		"StorageClass volume binding mode",
+		`Makes sure that StorageClasses of topology-constrained provisioners, or with allowedTopologies, use the WaitForFirstConsumer volumeBindingMode`,
+		storageClassVolumeBindingMode(options),
+	)
+}
+
+func storageClassReclaimPolicy(options Options) func(storagev1.StorageClass) (scorecard.TestScore, error) {
+	return func(storageClass storagev1.StorageClass) (score scorecard.TestScore, err error) {
+		score.Grade = scorecard.GradeAllOK
+		if storageClass.ReclaimPolicy == nil {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment(
+				"reclaimPolicy",
+				"The StorageClass does not set reclaimPolicy",
+				"The volumes of the StorageClass are deleted together with their PersistentVolumeClaim by default. "+
+					"Set reclaimPolicy to Retain or Delete to make the lifecycle of the data explicit.",
+			)
+			return
+		}
+		if options.ReclaimProfile == "data-critical" && *storageClass.ReclaimPolicy == corev1.PersistentVolumeReclaimDelete {
+			score.Grade = scorecard.GradeWarning
+			score.AddComment(
+				"reclaimPolicy",
+				"The StorageClass deletes volumes of critical data",
+				"The volume and its data are deleted when the PersistentVolumeClaim is deleted, e.g. by accident. Set reclaimPolicy to Retain for critical data.",
+			)
+		}
+		return
+	}
+}
+
+func storageClassVolumeExpansion(storageClass storagev1.StorageClass) (score scorecard.TestScore, err error) {
+	score.Grade = scorecard.GradeAllOK
+	if storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion {
+		return
+	}
+
+	score.Grade = scorecard.GradeWarning
+	score.AddComment(
+		"allowVolumeExpansion",
+		"The StorageClass does not allow volume expansion",
+		"PersistentVolumeClaims of the StorageClass can not be resized when they are full. Set allowVolumeExpansion to true if the provisioner supports it.",
+	)
+	return
+}
+
+func storageClassVolumeBindingMode(options Options) func(storagev1.StorageClass) (scorecard.TestScore, error) {
+	return func(storageClass storagev1.StorageClass) (score scorecard.TestScore, err error) {
+		score.Grade = scorecard.GradeAllOK
+		if storageClass.VolumeBindingMode != nil && *storageClass.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer {
+			return
+		}
+		if len(storageClass.AllowedTopologies) == 0 && !slices.Contains(options.TopologyProvisioners, storageClass.Provisioner) {
+			return
+		}
+
+		score.Grade = scorecard.GradeWarning
+		score.AddComment(
+			"volumeBindingMode",
+			"The volumes of the StorageClass are bound before the pod is scheduled",
+			"Volumes of "+storageClass.Provisioner+" can only be used in some zones or nodes. With the Immediate volumeBindingMode, the volume can be provisioned where the pod can not be scheduled. "+
+				"Set volumeBindingMode to WaitForFirstConsumer.",
+		)
+		return
+	}
+}
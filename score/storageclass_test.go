@@ -0,0 +1,63 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func TestStorageClass(t *testing.T) {
+	t.Parallel()
+	ok := scorecard.GradeAllOK
+	warning := scorecard.GradeWarning
+	expected := func(reclaimPolicy, volumeExpansion, volumeBindingMode scorecard.Grade) map[string]scorecard.Grade {
+		return map[string]scorecard.Grade{
+			"storageclass-reclaim-policy":      reclaimPolicy,
+			"storageclass-volume-expansion":    volumeExpansion,
+			"storageclass-volume-binding-mode": volumeBindingMode,
+		}
+	}
+
+	for profile, classes := range map[string]map[string]map[string]scorecard.Grade{
+		"default": {
+			"defaults":               expected(warning, warning, warning),
+			"delete":                 expected(ok, ok, ok),
+			"retain":                 expected(ok, ok, ok),
+			"nfs":                    expected(ok, ok, ok),
+			"nfs-allowed-topologies": expected(ok, ok, warning),
+		},
+		"data-critical": {
+			"defaults":               expected(warning, warning, warning),
+			"delete":                 expected(warning, ok, ok),
+			"retain":                 expected(ok, ok, ok),
+			"nfs":                    expected(ok, ok, ok),
+			"nfs-allowed-topologies": expected(ok, ok, warning),
+		},
+	} {
+		sc, err := testScore(
+			[]ks.NamedReader{testFile("storageclass.yaml")},
+			nil,
+			&config.RunConfiguration{
+				CheckParameters: config.CheckParameters{
+					"storageclass-reclaim-policy": {"profile": profile},
+				},
+			},
+		)
+		assert.NoError(t, err)
+
+		grades := make(map[string]map[string]scorecard.Grade)
+		for _, o := range sc {
+			grades[o.ObjectMeta.Name] = make(map[string]scorecard.Grade)
+			for _, c := range o.Checks {
+				if c.Check.TargetType == "StorageClass" {
+					grades[o.ObjectMeta.Name][c.Check.ID] = c.Grade
+				}
+			}
+		}
+		assert.Equal(t, classes, grades, profile)
+	}
+}
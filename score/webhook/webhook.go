@@ -0,0 +1,139 @@
+package webhook
+
+import (
+	"fmt"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/score/checks"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+// defaultTimeoutSeconds is the timeout of webhooks that do not set timeoutSeconds
+const defaultTimeoutSeconds = 10
+
+// kubeSystemLabels are the labels that Kubernetes sets on the kube-system namespace
+var kubeSystemLabels = labels.Set{corev1.LabelMetadataName: metav1.NamespaceSystem}
+
+type Options struct {
+	MaxTimeoutSeconds int32
+}
+
+func Register(allChecks *checks.Checks, options Options) {
+	allChecks.RegisterWebhookConfigurationCheck(
+		"Webhook failure policy",
+		`Makes sure that all webhooks set failurePolicy explicitly`,
+		webhookFailurePolicy,
+	)
+	allChecks.RegisterWebhookConfigurationCheck(
+		"Webhook timeout",
+		`Makes sure that the timeout of all webhooks is at most the maxTimeoutSeconds parameter`,
+		webhookTimeout(options),
+	)
+	allChecks.RegisterWebhookConfigurationCheck(
+		"Webhook namespace selector",
+		`Makes sure that the namespaceSelector of all webhooks excludes the kube-system namespace`,
+		webhookNamespaceSelector,
+	)
+	allChecks.RegisterWebhookConfigurationCheck(
+		"Webhook side effects",
+		`Makes sure that all webhooks declare sideEffects as None or NoneOnDryRun`,
+		webhookSideEffects,
+	)
+}
+
+func webhookFailurePolicy(configuration ks.WebhookConfiguration) (score scorecard.TestScore, err error) {
+	score.Grade = scorecard.GradeAllOK
+	for _, webhook := range configuration.Webhooks() {
+		if webhook.FailurePolicy != nil {
+			continue
+		}
+		score.Grade = scorecard.GradeWarning
+		score.AddComment(
+			webhook.Name,
+			"The webhook does not set failurePolicy",
+			"Requests are rejected when the webhook is unavailable by default. "+
+				"Set failurePolicy to Fail if the webhook enforces a policy, or to Ignore if the cluster should keep working without it.",
+		)
+	}
+	return
+}
+
+func webhookTimeout(options Options) func(ks.WebhookConfiguration) (scorecard.TestScore, error) {
+	return func(configuration ks.WebhookConfiguration) (score scorecard.TestScore, err error) {
+		score.Grade = scorecard.GradeAllOK
+		for _, webhook := range configuration.Webhooks() {
+			timeout := int32(defaultTimeoutSeconds)
+			if webhook.TimeoutSeconds != nil {
+				timeout = *webhook.TimeoutSeconds
+			}
+			if timeout <= options.MaxTimeoutSeconds {
+				continue
+			}
+			score.Grade = scorecard.GradeWarning
+			score.AddComment(
+				webhook.Name,
+				fmt.Sprintf("The timeout of the webhook is %d seconds", timeout),
+				fmt.Sprintf(
+					"Every request that the webhook intercepts can be delayed by the timeout when the webhook is slow or unavailable. Set timeoutSeconds to at most %d.",
+					options.MaxTimeoutSeconds,
+				),
+			)
+		}
+		return
+	}
+}
+
+func webhookNamespaceSelector(configuration ks.WebhookConfiguration) (score scorecard.TestScore, err error) {
+	score.Grade = scorecard.GradeAllOK
+	for _, webhook := range configuration.Webhooks() {
+		selector, selectorErr := metav1.LabelSelectorAsSelector(webhook.NamespaceSelector)
+		if selectorErr != nil {
+			return score, fmt.Errorf("failed to create selector: %w", selectorErr)
+		}
+		// A nil namespaceSelector matches all namespaces
+		if webhook.NamespaceSelector != nil && !selector.Matches(kubeSystemLabels) {
+			continue
+		}
+
+		// The cluster can not recover by itself if a failing webhook blocks the requests of the
+		// components in kube-system
+		grade := scorecard.GradeWarning
+		if webhook.FailurePolicy == nil || *webhook.FailurePolicy == admissionregistrationv1.Fail {
+			grade = scorecard.GradeCritical
+		}
+		if grade < score.Grade {
+			score.Grade = grade
+		}
+		score.AddComment(
+			webhook.Name,
+			"The webhook intercepts requests in the kube-system namespace",
+			"If the webhook is unavailable, requests of the system components can be delayed or rejected. "+
+				"Exclude kube-system with a namespaceSelector, e.g. a NotIn expression on the kubernetes.io/metadata.name label.",
+		)
+	}
+	return
+}
+
+func webhookSideEffects(configuration ks.WebhookConfiguration) (score scorecard.TestScore, err error) {
+	score.Grade = scorecard.GradeAllOK
+	for _, webhook := range configuration.Webhooks() {
+		if webhook.SideEffects != nil &&
+			(*webhook.SideEffects == admissionregistrationv1.SideEffectClassNone ||
+				*webhook.SideEffects == admissionregistrationv1.SideEffectClassNoneOnDryRun) {
+			continue
+		}
+		score.Grade = scorecard.GradeCritical
+		score.AddComment(
+			webhook.Name,
+			"The webhook does not declare valid sideEffects",
+			"admissionregistration.k8s.io/v1 requires sideEffects to be None or NoneOnDryRun. "+
+				"Use NoneOnDryRun if the webhook has side effects, and skip them for dry-run requests.",
+		)
+	}
+	return
+}
@@ -0,0 +1,55 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	ks "github.com/romnn/kube-score/domain"
+	"github.com/romnn/kube-score/scorecard"
+)
+
+func TestWebhookConfiguration(t *testing.T) {
+	t.Parallel()
+	sc, err := testScore([]ks.NamedReader{testFile("webhook-configuration.yaml")}, nil, nil)
+	assert.NoError(t, err)
+
+	grades := make(map[string]map[string]scorecard.Grade)
+	paths := make(map[string][]string)
+	for _, o := range sc {
+		grades[o.ObjectMeta.Name] = make(map[string]scorecard.Grade)
+		for _, c := range o.Checks {
+			if c.Check.TargetType != "WebhookConfiguration" {
+				continue
+			}
+			grades[o.ObjectMeta.Name][c.Check.ID] = c.Grade
+			for _, comment := range c.Comments {
+				paths[o.ObjectMeta.Name+"/"+c.Check.ID] = append(paths[o.ObjectMeta.Name+"/"+c.Check.ID], comment.Path)
+			}
+		}
+	}
+
+	ok := scorecard.GradeAllOK
+	assert.Equal(t, map[string]map[string]scorecard.Grade{
+		"good": {
+			"webhook-failure-policy":     ok,
+			"webhook-timeout":            ok,
+			"webhook-namespace-selector": ok,
+			"webhook-side-effects":       ok,
+		},
+		"bad": {
+			"webhook-failure-policy":     scorecard.GradeWarning,
+			"webhook-timeout":            scorecard.GradeWarning,
+			"webhook-namespace-selector": scorecard.GradeCritical,
+			"webhook-side-effects":       scorecard.GradeCritical,
+		},
+		"ignored": {
+			"webhook-failure-policy":     ok,
+			"webhook-timeout":            ok,
+			"webhook-namespace-selector": scorecard.GradeWarning,
+			"webhook-side-effects":       scorecard.GradeCritical,
+		},
+	}, grades)
+	assert.Equal(t, []string{"everywhere.example.com"}, paths["ignored/webhook-namespace-selector"])
+	assert.Equal(t, []string{"everywhere.example.com"}, paths["ignored/webhook-side-effects"])
+}
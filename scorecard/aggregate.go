@@ -0,0 +1,41 @@
+package scorecard
+
+import (
+	"slices"
+	"strings"
+)
+
+// aggregateComments merges the comments that only differ in their path, such as the same finding in
+// many containers of a pod, into a single comment that lists all paths. The merged comment is at the
+// position of the first one.
+func aggregateComments(comments []TestScoreComment) []TestScoreComment {
+	type key struct {
+		summary, description, documentationURL string
+	}
+
+	var aggregated []TestScoreComment
+	var paths, fingerprints [][]string
+	first := make(map[key]int)
+	for _, c := range comments {
+		k := key{c.Summary, c.Description, c.DocumentationURL}
+		if i, ok := first[k]; ok && c.Path != "" {
+			paths[i] = append(paths[i], c.Path)
+			fingerprints[i] = append(fingerprints[i], c.Fingerprint)
+			continue
+		}
+		if c.Path != "" {
+			first[k] = len(aggregated)
+		}
+		aggregated = append(aggregated, c)
+		paths = append(paths, []string{c.Path})
+		fingerprints = append(fingerprints, []string{c.Fingerprint})
+	}
+
+	for i := range aggregated {
+		aggregated[i].Path = strings.Join(paths[i], ", ")
+		aggregated[i].Fingerprint = strings.Join(slices.DeleteFunc(fingerprints[i], func(f string) bool {
+			return f == ""
+		}), ", ")
+	}
+	return aggregated
+}
@@ -0,0 +1,57 @@
+package scorecard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+)
+
+type location struct{}
+
+func (location) FileLocation() ks.FileLocation {
+	return ks.FileLocation{Name: "pod.yaml", Line: 1}
+}
+
+func TestAggregateComments(t *testing.T) {
+	t.Parallel()
+	comments := []TestScoreComment{
+		{Path: "app", Summary: "CPU limit is not set", Description: "Resource limits are recommended"},
+		{Path: "app", Summary: "Memory limit is not set", Description: "Resource limits are recommended"},
+		{Path: "sidecar-1", Summary: "CPU limit is not set", Description: "Resource limits are recommended"},
+		{Summary: "No path"},
+		{Path: "sidecar-2", Summary: "CPU limit is not set", Description: "Resource limits are recommended"},
+		{Summary: "No path"},
+	}
+
+	assert.Equal(t, []TestScoreComment{
+		{Path: "app, sidecar-1, sidecar-2", Summary: "CPU limit is not set", Description: "Resource limits are recommended"},
+		{Path: "app", Summary: "Memory limit is not set", Description: "Resource limits are recommended"},
+		{Summary: "No path"},
+		{Summary: "No path"},
+	}, aggregateComments(comments))
+}
+
+func TestAddAggregatesComments(t *testing.T) {
+	t.Parallel()
+	ts := TestScore{Grade: GradeCritical}
+	ts.AddComment("a", "summary", "description")
+	ts.AddComment("b", "summary", "description")
+
+	for _, aggregate := range []bool{true, false} {
+		s := New()
+		o := s.NewObject(metav1.TypeMeta{Kind: "Pod"}, metav1.ObjectMeta{Name: "foo"}, &config.RunConfiguration{
+			AggregateComments: aggregate,
+		})
+		o.Add(ts, ks.Check{ID: "container-resources"}, location{})
+		if aggregate {
+			assert.Len(t, o.Checks[0].Comments, 1)
+			assert.Equal(t, "a, b", o.Checks[0].Comments[0].Path)
+		} else {
+			assert.Len(t, o.Checks[0].Comments, 2)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package scorecard
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// AnnotationCheckIDs returns the check IDs that a kube-score annotation refers to: the IDs in the
+// ignore and enable annotations, the IDs of the severity annotation, and the name of the other
+// annotations, that are the per-check annotations if the name is a check ID. Annotations that are
+// not using any of the prefixes do not refer to checks.
+func AnnotationCheckIDs(prefixes []string, key, value string) []string {
+	name, ok := annotationName(annotationPrefixes(prefixes), key)
+	if !ok {
+		return nil
+	}
+	if _, ok := ignoredContainer(name); ok {
+		name = ignoredChecksAnnotation
+	}
+
+	var ids []string
+	switch name {
+	case ignoredChecksAnnotation, optionalChecksAnnotation:
+		for id := range strings.SplitSeq(value, ",") {
+			id = strings.TrimSpace(id)
+			if id != "" && id != "*" {
+				ids = append(ids, id)
+			}
+		}
+	case severityAnnotation:
+		// Malformed values are reported by LintAnnotation
+		overrides, _ := parseSeverityOverrides(value)
+		for id := range overrides {
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+	case ignoreReasonAnnotation:
+	default:
+		ids = append(ids, name)
+	}
+	return ids
+}
+
+// addDeprecationNotes adds a comment to the score for every alias of the check that the
+// annotations of the object refer to, so that the old IDs of renamed checks are replaced
+func (so *ScoredObject) addDeprecationNotes(ts *TestScore, annotations []map[string]string) {
+	if len(ts.Check.Aliases) == 0 {
+		return
+	}
+
+	var used []string
+	for _, a := range annotations {
+		for key, value := range a {
+			for _, id := range AnnotationCheckIDs(so.annotationPrefixes, key, value) {
+				if slices.Contains(ts.Check.Aliases, id) && !slices.Contains(used, id) {
+					used = append(used, id)
+				}
+			}
+		}
+	}
+	slices.Sort(used)
+
+	for _, id := range used {
+		ts.AddComment(
+			"",
+			fmt.Sprintf("The check ID %s is deprecated, use %s", id, ts.Check.ID),
+			"The check has been renamed. The old ID in the annotations of the object still works, but will be removed in a future release.",
+		)
+	}
+}
@@ -0,0 +1,61 @@
+package scorecard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+)
+
+func TestAnnotationCheckIDs(t *testing.T) {
+	t.Parallel()
+
+	prefixes := []string{"score.example.com"}
+	assert.Equal(t, []string{"a", "b"}, AnnotationCheckIDs(prefixes, "kube-score/ignore", "a, *, b,"))
+	assert.Equal(t, []string{"a"}, AnnotationCheckIDs(prefixes, "score.example.com/enable", "a"))
+	assert.Equal(t, []string{"a", "b"}, AnnotationCheckIDs(prefixes, "kube-score/severity", "b=ok,a=warning"))
+	assert.Equal(t, []string{"a"}, AnnotationCheckIDs(prefixes, "kube-score/a", "disabled"))
+	assert.Empty(t, AnnotationCheckIDs(prefixes, "kube-score/ignore-reason", "a"))
+	assert.Empty(t, AnnotationCheckIDs(prefixes, "example.com/ignore", "a"))
+}
+
+func TestAliases(t *testing.T) {
+	t.Parallel()
+
+	check := ks.Check{ID: "container-resources", Aliases: []string{"container-resource-limits"}}
+	score := func(annotations map[string]string) TestScore {
+		card := New()
+		o := card.NewObject(metav1.TypeMeta{Kind: "Pod"}, metav1.ObjectMeta{Name: "pod"}, &config.RunConfiguration{
+			UseIgnoreChecksAnnotation:   true,
+			UseOptionalChecksAnnotation: true,
+		})
+		o.Add(TestScore{Grade: GradeCritical}, check, location{}, annotations)
+		return o.Checks[0]
+	}
+
+	deprecated := TestScoreComment{
+		Summary:     "The check ID container-resource-limits is deprecated, use container-resources",
+		Description: "The check has been renamed. The old ID in the annotations of the object still works, but will be removed in a future release.",
+	}
+
+	// The old ID ignores the check, with a deprecation note
+	ts := score(map[string]string{"kube-score/ignore": "container-resource-limits"})
+	assert.True(t, ts.Skipped)
+	assert.Equal(t, []TestScoreComment{{Summary: "Skipped because container-resources is ignored"}, deprecated}, ts.Comments)
+
+	ts = score(map[string]string{"kube-score/container-resource-limits": "disabled"})
+	assert.True(t, ts.Skipped)
+
+	// The old ID downgrades the check
+	ts = score(map[string]string{"kube-score/severity": "container-resource-limits=warning"})
+	assert.Equal(t, GradeWarning, ts.Grade)
+	assert.Equal(t, deprecated, ts.Comments[len(ts.Comments)-1])
+
+	// The current ID does not add a note
+	ts = score(map[string]string{"kube-score/ignore": "container-resources"})
+	assert.True(t, ts.Skipped)
+	assert.Len(t, ts.Comments, 1)
+}
@@ -0,0 +1,58 @@
+package scorecard
+
+import (
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+)
+
+// Builder builds a Scorecard from multiple goroutines. A Scorecard and its ScoredObjects are not
+// safe for concurrent writes, the Builder serializes the creation of objects and the scores that
+// are added to them. ScoredObject.IsSkipped only reads the configuration of the object, and can be
+// called without the Builder. Reading the Scorecard is only safe once all goroutines are done.
+type Builder struct {
+	mu   sync.Mutex
+	card Scorecard
+	cnf  *config.RunConfiguration
+}
+
+// NewBuilder returns a Builder of a new Scorecard
+func NewBuilder(cnf *config.RunConfiguration) *Builder {
+	return &Builder{card: New(), cnf: cnf}
+}
+
+// NewObject returns the object of the Scorecard with the type and name, and creates it if it does
+// not exist yet. created is true if the object was created by this call.
+func (b *Builder) NewObject(
+	typeMeta metav1.TypeMeta,
+	objectMeta metav1.ObjectMeta,
+) (o *ScoredObject, created bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	objects := len(b.card)
+	o = b.card.NewObject(typeMeta, objectMeta, b.cnf)
+	return o, len(b.card) > objects
+}
+
+// Add adds the score of the check to the object, see ScoredObject.Add
+func (b *Builder) Add(
+	o *ScoredObject,
+	ts TestScore,
+	check ks.Check,
+	locationer ks.FileLocationer,
+	annotations ...map[string]string,
+) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	o.Add(ts, check, locationer, annotations...)
+}
+
+// Scorecard returns the Scorecard that is built
+func (b *Builder) Scorecard() Scorecard {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.card
+}
@@ -0,0 +1,48 @@
+package scorecard
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+)
+
+func TestBuilderConcurrent(t *testing.T) {
+	t.Parallel()
+
+	b := NewBuilder(&config.RunConfiguration{})
+	typeMeta := metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	created := 0
+	for i := range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Ten objects are scored by ten checks each
+			o, isNew := b.NewObject(typeMeta, metav1.ObjectMeta{Name: fmt.Sprintf("app-%d", i%10)})
+			if isNew {
+				mu.Lock()
+				created++
+				mu.Unlock()
+			}
+			check := ks.Check{ID: fmt.Sprintf("check-%d", i/10)}
+			assert.False(t, o.IsSkipped(check, location{}))
+			b.Add(o, TestScore{Grade: GradeAllOK}, check, location{})
+		}()
+	}
+	wg.Wait()
+
+	card := b.Scorecard()
+	assert.Equal(t, 10, created)
+	assert.Len(t, card, 10)
+	for _, o := range card {
+		assert.Len(t, o.Checks, 10)
+	}
+}
@@ -0,0 +1,55 @@
+package scorecard
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	ks "github.com/romnn/kube-score/domain"
+)
+
+// ignoredContainer returns the name of the container if the annotation name ignores checks for a
+// single container
+func ignoredContainer(name string) (string, bool) {
+	return strings.CutPrefix(name, ignoredContainerChecksAnnotation)
+}
+
+// IgnoredContainers returns the sorted names of the containers that the check is ignored for with
+// the ignore.container.<name> annotations. The containers are removed from the pod template before
+// the check is run, so that the grade of the check only depends on the other containers.
+func (so *ScoredObject) IgnoredContainers(check ks.Check, annotations ...map[string]string) []string {
+	if !so.useIgnoreChecksAnnotation {
+		return nil
+	}
+
+	var containers []string
+	for _, a := range annotations {
+		for key, value := range a {
+			name, ok := annotationName(so.annotationPrefixes, key)
+			if !ok {
+				continue
+			}
+			container, ok := ignoredContainer(name)
+			if ok && container != "" && so.matches(check, value) && !slices.Contains(containers, container) {
+				containers = append(containers, container)
+			}
+		}
+	}
+	slices.Sort(containers)
+	return containers
+}
+
+// ContainersIgnored returns the score of a check that is ignored for all containers of the pod
+func ContainersIgnored(check ks.Check, containers []string) TestScore {
+	noun := "container"
+	if len(containers) > 1 {
+		noun = "containers"
+	}
+	return TestScore{
+		Skipped: true,
+		Comments: []TestScoreComment{{Summary: fmt.Sprintf(
+			"Skipped because %s is ignored for the %s %s",
+			check.ID, noun, strings.Join(containers, ", "),
+		)}},
+	}
+}
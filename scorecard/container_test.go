@@ -0,0 +1,53 @@
+package scorecard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+)
+
+func TestIgnoredContainers(t *testing.T) {
+	t.Parallel()
+
+	check := ks.Check{ID: "container-resources"}
+	ignored := func(annotations map[string]string) []string {
+		o := New().NewObject(metav1.TypeMeta{Kind: "Deployment"}, metav1.ObjectMeta{Name: "app"}, &config.RunConfiguration{
+			UseIgnoreChecksAnnotation: true,
+		})
+		return o.IgnoredContainers(check, nil, annotations)
+	}
+
+	assert.Equal(t, []string{"istio-proxy"}, ignored(map[string]string{"kube-score/ignore.container.istio-proxy": "container-resources"}))
+	assert.Equal(t, []string{"app", "istio-proxy"}, ignored(map[string]string{
+		"kube-score/ignore.container.istio-proxy": "*",
+		"kube-score/ignore.container.app":         "container-resources",
+	}))
+
+	// Other checks are not ignored for the container
+	assert.Empty(t, ignored(map[string]string{"kube-score/ignore.container.istio-proxy": "service-type"}))
+}
+
+func TestContainersIgnored(t *testing.T) {
+	t.Parallel()
+
+	ts := ContainersIgnored(ks.Check{ID: "container-resources"}, []string{"istio-proxy"})
+	assert.True(t, ts.Skipped)
+	assert.Equal(t, []TestScoreComment{{Summary: "Skipped because container-resources is ignored for the container istio-proxy"}}, ts.Comments)
+}
+
+func TestLintAnnotationContainer(t *testing.T) {
+	t.Parallel()
+
+	known := map[string]bool{"container-resources": true}
+	assert.Empty(t, LintAnnotation(nil, "kube-score/ignore.container.istio-proxy", "container-resources", known))
+	assert.Equal(t, []string{`kube-score/ignore.container.istio-proxy: unknown check "container-resource"`},
+		LintAnnotation(nil, "kube-score/ignore.container.istio-proxy", "container-resource", known))
+	assert.Equal(t, []string{"kube-score/ignore.container.: missing the name of the container"},
+		LintAnnotation(nil, "kube-score/ignore.container.", "container-resources", known))
+	assert.Equal(t, []string{"container-resources"},
+		AnnotationCheckIDs(nil, "kube-score/ignore.container.istio-proxy", "container-resources"))
+}
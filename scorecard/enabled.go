@@ -2,8 +2,10 @@ package scorecard
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/romnn/kube-score/config"
 	ks "github.com/romnn/kube-score/domain"
 )
 
@@ -11,54 +13,41 @@ func (so *ScoredObject) isEnabled(
 	check ks.Check,
 	annotations, childAnnotations map[string]string,
 ) bool {
-	isIn := func(annotations map[string]string, csv string, key string) bool {
-		// see if the check is explicitly allowed or denied
-		if checkAnnotation, ok := annotations[fmt.Sprintf("kube-score/%s", check.ID)]; ok {
-			switch strings.TrimSpace(strings.ToLower(checkAnnotation)) {
-			case "disable", "disabled":
-				fmt.Printf("disabling check %s\n", check.ID)
-				return true
-			case "enable", "enabled":
-				fmt.Printf("enabling check %s\n", check.ID)
-				return false
-			}
-		}
-
-		// search comma separated list of checks
-		for v := range strings.SplitSeq(csv, ",") {
-			v = strings.TrimSpace(v)
-			if v == key {
-				return true
-			}
-			if v == "*" {
-				// "*" wildcard matches all checks
-				return true
-			}
-			if vals, ok := impliedIgnoreAnnotations[v]; ok {
-				for i := range vals {
-					if vals[i] == key {
+	isIn := func(annotations map[string]string, csv string) bool {
+		// see if the check is explicitly allowed or denied, by its ID or one of its aliases
+		for _, id := range append([]string{check.ID}, check.Aliases...) {
+			for _, prefix := range so.annotationPrefixes {
+				if checkAnnotation, ok := annotations[fmt.Sprintf("%s/%s", prefix, id)]; ok {
+					switch strings.TrimSpace(strings.ToLower(checkAnnotation)) {
+					case "disable", "disabled":
 						return true
+					case "enable", "enabled":
+						return false
 					}
 				}
 			}
 		}
+		return so.matches(check, csv)
+	}
+
+	if _, ok := helmHookIgnoredChecks[check.ID]; ok && so.helmHooks.Relaxed() && config.IsHelmHook(annotations) {
 		return false
 	}
 
 	if childAnnotations != nil && so.useIgnoreChecksAnnotation &&
-		isIn(childAnnotations, childAnnotations[ignoredChecksAnnotation], check.ID) {
+		isIn(childAnnotations, so.annotation(childAnnotations, ignoredChecksAnnotation)) {
 		return false
 	}
 	if childAnnotations != nil && so.useOptionalChecksAnnotation &&
-		isIn(childAnnotations, childAnnotations[optionalChecksAnnotation], check.ID) {
+		isIn(childAnnotations, so.annotation(childAnnotations, optionalChecksAnnotation)) {
 		return true
 	}
 	if so.useIgnoreChecksAnnotation &&
-		isIn(annotations, annotations[ignoredChecksAnnotation], check.ID) {
+		isIn(annotations, so.annotation(annotations, ignoredChecksAnnotation)) {
 		return false
 	}
 	if so.useOptionalChecksAnnotation &&
-		isIn(annotations, annotations[optionalChecksAnnotation], check.ID) {
+		isIn(annotations, so.annotation(annotations, optionalChecksAnnotation)) {
 		return true
 	}
 
@@ -75,3 +64,52 @@ func (so *ScoredObject) isEnabled(
 	// Enabled by default
 	return true
 }
+
+// matches returns true if the comma separated list of check IDs, wildcards and groups includes the check
+func (so *ScoredObject) matches(check ks.Check, csv string) bool {
+	for v := range strings.SplitSeq(csv, ",") {
+		v = strings.TrimSpace(v)
+		if check.HasID(v) {
+			return true
+		}
+		if v == "*" {
+			// "*" wildcard matches all checks
+			return true
+		}
+		if vals, ok := impliedIgnoreAnnotations[v]; ok {
+			if slices.ContainsFunc(vals, check.HasID) {
+				return true
+			}
+		}
+		// Unknown groups are reported by LintAnnotation
+		if vals, ok, _ := so.checkGroups.Group(v); ok {
+			if slices.ContainsFunc(vals, check.HasID) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// annotationPrefixes returns the default prefix, followed by the additional prefixes without duplicates
+func annotationPrefixes(additional []string) []string {
+	prefixes := []string{defaultAnnotationPrefix}
+	for _, prefix := range additional {
+		prefix = strings.TrimSuffix(strings.TrimSpace(prefix), "/")
+		if prefix != "" && !slices.Contains(prefixes, prefix) {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return prefixes
+}
+
+// annotation returns the comma separated values of the annotation with the name under all accepted prefixes
+func (so *ScoredObject) annotation(annotations map[string]string, name string) string {
+	var values []string
+	for _, prefix := range so.annotationPrefixes {
+		if value, ok := annotations[prefix+"/"+name]; ok {
+			values = append(values, value)
+		}
+	}
+	return strings.Join(values, ",")
+}
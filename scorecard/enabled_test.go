@@ -0,0 +1,40 @@
+package scorecard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+)
+
+func TestGroups(t *testing.T) {
+	t.Parallel()
+
+	score := func(check ks.Check, annotations map[string]string) TestScore {
+		card := New()
+		o := card.NewObject(metav1.TypeMeta{Kind: "Pod"}, metav1.ObjectMeta{Name: "pod"}, &config.RunConfiguration{
+			UseIgnoreChecksAnnotation:   true,
+			UseOptionalChecksAnnotation: true,
+			CheckGroups: config.CheckGroups{
+				"resources": {"container-resources", "container-ephemeral-storage-request-and-limit"},
+			},
+		})
+		o.Add(TestScore{Grade: GradeCritical}, check, location{}, annotations)
+		return o.Checks[0]
+	}
+
+	ignore := map[string]string{"kube-score/ignore": "group:resources"}
+	assert.True(t, score(ks.Check{ID: "container-resources"}, ignore).Skipped)
+	assert.False(t, score(ks.Check{ID: "service-type"}, ignore).Skipped)
+
+	// Unknown groups do not match any check
+	assert.False(t, score(ks.Check{ID: "container-resources"}, map[string]string{"kube-score/ignore": "group:other"}).Skipped)
+
+	// Optional checks are enabled by a group
+	optional := ks.Check{ID: "container-resources", Optional: true}
+	assert.True(t, score(optional, nil).Skipped)
+	assert.False(t, score(optional, map[string]string{"kube-score/enable": "group:resources"}).Skipped)
+}
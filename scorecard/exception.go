@@ -0,0 +1,46 @@
+package scorecard
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// except skips the check if a ScoreException in the namespace of the object matches the object and
+// the check. Exceptions that have expired are not applied, but are reported on the check.
+func (so *ScoredObject) except(ts *TestScore, now time.Time) {
+	if len(so.exceptions) == 0 || ts.Grade >= GradeAllOK {
+		return
+	}
+
+	namespace := so.namespace.Of(so.ObjectMeta.Namespace)
+	for _, e := range so.exceptions {
+		if e.Namespace != namespace || !slices.Contains(e.Checks, ts.Check.ID) {
+			continue
+		}
+		if e.Selector != nil && !e.Selector.Matches(labels.Set(so.ObjectMeta.Labels)) {
+			continue
+		}
+
+		if !e.Expires.IsZero() && !now.Before(e.Expires) {
+			ts.AddComment(
+				"",
+				"ScoreException has expired",
+				fmt.Sprintf(
+					"The ScoreException %s/%s ignored this check until %s: %s",
+					e.Namespace, e.Name, e.Expires.AddDate(0, 0, -1).Format(time.DateOnly), e.Reason,
+				),
+			)
+			continue
+		}
+
+		ts.Skipped = true
+		ts.Comments = []TestScoreComment{{
+			Summary:     fmt.Sprintf("Skipped because of the ScoreException %s/%s", e.Namespace, e.Name),
+			Description: e.Reason,
+		}}
+		return
+	}
+}
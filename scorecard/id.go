@@ -0,0 +1,42 @@
+package scorecard
+
+import (
+	"os"
+	"path/filepath"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ID returns the stable identifier of the object, to key findings across runs, e.g. for
+// baselines and suppressions. The identifier has the format
+//
+//	<group>/<version>/<kind>/<namespace>/<name>@<file>
+//
+// where the group of the core API is "core", the namespace is empty for objects without a
+// namespace, and the file is relative to the working directory if it is in it.
+func (so *ScoredObject) ID() string {
+	gvk := schema.FromAPIVersionAndKind(so.TypeMeta.APIVersion, so.TypeMeta.Kind)
+	group := gvk.Group
+	if group == "" {
+		group = "core"
+	}
+	return group + "/" + gvk.Version + "/" + gvk.Kind + "/" + so.ObjectMeta.Namespace + "/" +
+		so.ObjectMeta.Name + "@" + relativeFileName(so.FileLocation.Name)
+}
+
+// relativeFileName returns the name relative to the working directory, so that the identifier
+// does not depend on where the repository is checked out
+func relativeFileName(name string) string {
+	if !filepath.IsAbs(name) {
+		return filepath.ToSlash(name)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return filepath.ToSlash(name)
+	}
+	rel, err := filepath.Rel(wd, name)
+	if err != nil || !filepath.IsLocal(rel) {
+		return filepath.ToSlash(name)
+	}
+	return filepath.ToSlash(rel)
+}
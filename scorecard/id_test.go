@@ -0,0 +1,50 @@
+package scorecard
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+)
+
+func TestScoredObjectID(t *testing.T) {
+	t.Parallel()
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+
+	for _, tc := range []struct {
+		object   ScoredObject
+		expected string
+	}{
+		{
+			ScoredObject{
+				TypeMeta:     metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+				ObjectMeta:   metav1.ObjectMeta{Namespace: "prod", Name: "app"},
+				FileLocation: ks.FileLocation{Name: filepath.Join(wd, "deploy", "app.yaml"), Line: 12},
+			},
+			"apps/v1/Deployment/prod/app@deploy/app.yaml",
+		},
+		{
+			ScoredObject{
+				TypeMeta:     metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+				ObjectMeta:   metav1.ObjectMeta{Name: "app"},
+				FileLocation: ks.FileLocation{Name: "app/templates/service.yaml", Line: 1},
+			},
+			"core/v1/Service//app@app/templates/service.yaml",
+		},
+		{
+			ScoredObject{
+				TypeMeta:     metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+				ObjectMeta:   metav1.ObjectMeta{Namespace: "default", Name: "app"},
+				FileLocation: ks.FileLocation{Name: "/elsewhere/pod.yaml"},
+			},
+			"core/v1/Pod/default/app@/elsewhere/pod.yaml",
+		},
+	} {
+		assert.Equal(t, tc.expected, tc.object.ID())
+	}
+}
@@ -0,0 +1,140 @@
+package scorecard
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/romnn/kube-score/config"
+)
+
+// LintAnnotation reports the problems of a single kube-score annotation of an object, such as
+// unknown check IDs in the ignore, enable and severity annotations. Annotations that are not
+// using any of the prefixes are ignored. knownChecks is the set of all check IDs, and of the
+// references to the known groups of checks, e.g. "group:resources".
+func LintAnnotation(prefixes []string, key, value string, knownChecks map[string]bool) []string {
+	name, ok := annotationName(annotationPrefixes(prefixes), key)
+	if !ok {
+		return nil
+	}
+
+	unknownCheck := func(id string) string {
+		if group, ok := strings.CutPrefix(id, config.GroupPrefix); ok {
+			return fmt.Sprintf("%s: unknown group %q", key, group)
+		}
+		return fmt.Sprintf("%s: unknown check %q", key, id)
+	}
+
+	// The ignores of a single container are linted like the ignores of the object
+	if container, ok := ignoredContainer(name); ok {
+		if container == "" {
+			return []string{fmt.Sprintf("%s: missing the name of the container", key)}
+		}
+		name = ignoredChecksAnnotation
+	}
+
+	var problems []string
+	switch name {
+	case ignoredChecksAnnotation, optionalChecksAnnotation:
+		for id := range strings.SplitSeq(value, ",") {
+			id = strings.TrimSpace(id)
+			if id == "" || id == "*" || knownChecks[id] {
+				continue
+			}
+			problems = append(problems, unknownCheck(id))
+		}
+	case severityAnnotation:
+		overrides, errs := parseSeverityOverrides(value)
+		for _, err := range errs {
+			problems = append(problems, fmt.Sprintf("%s: %s", key, err))
+		}
+		for _, id := range slices.Sorted(maps.Keys(overrides)) {
+			if !knownChecks[id] {
+				problems = append(problems, unknownCheck(id))
+			}
+		}
+	default:
+		if !knownChecks[name] {
+			return nil
+		}
+		switch strings.TrimSpace(strings.ToLower(value)) {
+		case "disable", "disabled", "enable", "enabled":
+		default:
+			problems = append(problems, fmt.Sprintf("%s: invalid value %q, expected enabled or disabled", key, value))
+		}
+	}
+	return problems
+}
+
+// LintAnnotationHygiene reports the problems of a single kube-score annotation of an object that
+// make the suppressions of the object hard to audit: severity overrides that have expired at now,
+// and ignores of checks without an ignore-reason annotation. annotations are all annotations of the
+// object. Unlike LintAnnotation, these problems do not change how the object is scored.
+func LintAnnotationHygiene(
+	prefixes []string,
+	key, value string,
+	annotations map[string]string,
+	knownChecks map[string]bool,
+	now time.Time,
+) []string {
+	prefixes = annotationPrefixes(prefixes)
+	name, ok := annotationName(prefixes, key)
+	if !ok {
+		return nil
+	}
+
+	if _, ok := ignoredContainer(name); ok {
+		name = ignoredChecksAnnotation
+	}
+
+	var problems []string
+	switch {
+	case name == ignoredChecksAnnotation && strings.TrimSpace(value) != "",
+		knownChecks[name] && isDisabled(value):
+		if !hasAnnotation(prefixes, annotations, ignoreReasonAnnotation) {
+			problems = append(problems, fmt.Sprintf("%s: checks are ignored without a %s/%s annotation",
+				key, defaultAnnotationPrefix, ignoreReasonAnnotation))
+		}
+	case name == severityAnnotation:
+		// Malformed entries are reported by LintAnnotation
+		overrides, _ := parseSeverityOverrides(value)
+		for id, override := range overrides {
+			if !override.expires.IsZero() && !now.Before(override.expires) {
+				problems = append(problems, fmt.Sprintf("%s: override of %s expired on %s",
+					key, id, override.expires.AddDate(0, 0, -1).Format(time.DateOnly)))
+			}
+		}
+		sort.Strings(problems)
+	}
+	return problems
+}
+
+func isDisabled(value string) bool {
+	switch strings.TrimSpace(strings.ToLower(value)) {
+	case "disable", "disabled":
+		return true
+	default:
+		return false
+	}
+}
+
+func hasAnnotation(prefixes []string, annotations map[string]string, name string) bool {
+	for _, prefix := range prefixes {
+		if strings.TrimSpace(annotations[prefix+"/"+name]) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func annotationName(prefixes []string, key string) (string, bool) {
+	for _, prefix := range prefixes {
+		if name, ok := strings.CutPrefix(key, prefix+"/"); ok {
+			return name, true
+		}
+	}
+	return "", false
+}
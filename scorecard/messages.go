@@ -0,0 +1,35 @@
+package scorecard
+
+import "github.com/romnn/kube-score/config"
+
+// applyMessages replaces the comments of the check with the configured message templates.
+// The templates are validated when the configuration is loaded, comments of templates that
+// fail are kept unchanged.
+func (so *ScoredObject) applyMessages(ts *TestScore) {
+	t, ok := so.messages[ts.Check.ID]
+	if !ok {
+		return
+	}
+
+	comments := make([]TestScoreComment, len(ts.Comments))
+	for i, c := range ts.Comments {
+		comments[i] = c
+		summary, description, documentationURL, err := t.Execute(config.MessageData{
+			Check:            ts.Check.ID,
+			Kind:             so.TypeMeta.Kind,
+			Namespace:        so.ObjectMeta.Namespace,
+			Name:             so.ObjectMeta.Name,
+			Container:        c.Path,
+			Summary:          c.Summary,
+			Description:      c.Description,
+			DocumentationURL: c.DocumentationURL,
+		})
+		if err != nil {
+			continue
+		}
+		comments[i].Summary = summary
+		comments[i].Description = description
+		comments[i].DocumentationURL = documentationURL
+	}
+	ts.Comments = comments
+}
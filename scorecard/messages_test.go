@@ -0,0 +1,38 @@
+package scorecard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+)
+
+func TestAddAppliesMessages(t *testing.T) {
+	t.Parallel()
+	ts := TestScore{Grade: GradeCritical}
+	ts.AddCommentWithURL("app", "CPU limit is not set", "Resource limits are recommended", "https://kube-score.com")
+
+	s := New()
+	o := s.NewObject(metav1.TypeMeta{Kind: "Pod"}, metav1.ObjectMeta{Name: "foo", Namespace: "bar"}, &config.RunConfiguration{
+		Messages: config.MessageTemplates{
+			"container-resources": {
+				Description:      "See the runbook for {{ .Kind }} {{ .Namespace }}/{{ .Name }} ({{ .Container }})",
+				DocumentationURL: "https://wiki.example.com/{{ .Check }}",
+			},
+		},
+	})
+	o.Add(ts, ks.Check{ID: "container-resources"}, location{})
+	o.Add(ts, ks.Check{ID: "container-image-tag"}, location{})
+
+	assert.Equal(t, []TestScoreComment{{
+		Path:             "app",
+		Summary:          "CPU limit is not set",
+		Description:      "See the runbook for Pod bar/foo (app)",
+		DocumentationURL: "https://wiki.example.com/container-resources",
+		Fingerprint:      o.Fingerprint("container-resources", TestScoreComment{Path: "app", Summary: "CPU limit is not set"}),
+	}}, o.Checks[0].Comments)
+	assert.Equal(t, ts.Comments, o.Checks[1].Comments)
+}
@@ -0,0 +1,78 @@
+package scorecard
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Policy decides if a scorecard fails, e.g. to set the exit code. A check fails if its grade is
+// below or equal to the threshold of its category, or to the default threshold if its category
+// has none. A threshold of 0 never fails.
+type Policy struct {
+	Threshold          Grade
+	CategoryThresholds map[string]Grade
+}
+
+// NewPolicy returns a policy with the default threshold and the thresholds of the categories,
+// which are parsed with ParseThreshold. categories are the known categories of the checks.
+func NewPolicy(threshold Grade, categoryThresholds map[string]string, categories []string) (Policy, error) {
+	p := Policy{
+		Threshold:          threshold,
+		CategoryThresholds: make(map[string]Grade, len(categoryThresholds)),
+	}
+
+	names := make([]string, 0, len(categoryThresholds))
+	for category := range categoryThresholds {
+		names = append(names, category)
+	}
+	sort.Strings(names)
+
+	for _, category := range names {
+		if !slices.Contains(categories, category) {
+			return Policy{}, fmt.Errorf("unknown category %q, expected one of %s", category, strings.Join(categories, ", "))
+		}
+		grade, err := ParseThreshold(categoryThresholds[category])
+		if err != nil {
+			return Policy{}, fmt.Errorf("invalid threshold of %s: %w", category, err)
+		}
+		p.CategoryThresholds[category] = grade
+	}
+	return p, nil
+}
+
+// ParseThreshold parses a grade, such as "critical" or "warning", or "never" for a threshold
+// that never fails
+func ParseThreshold(s string) (Grade, error) {
+	if strings.EqualFold(s, "never") {
+		return 0, nil
+	}
+	grade, err := parseGrade(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid threshold %q, expected critical, warning, info, almost-ok, ok or never", s)
+	}
+	return grade, nil
+}
+
+// Fails returns true if the check fails the policy. Skipped checks never fail.
+func (p Policy) Fails(ts TestScore) bool {
+	if ts.Skipped {
+		return false
+	}
+	threshold, ok := p.CategoryThresholds[ts.Check.Category]
+	if !ok {
+		threshold = p.Threshold
+	}
+	return ts.Grade <= threshold
+}
+
+// Evaluate returns true if any check of the scorecard fails the policy
+func (p Policy) Evaluate(s Scorecard) bool {
+	for _, o := range s {
+		if slices.ContainsFunc(o.Checks, p.Fails) {
+			return true
+		}
+	}
+	return false
+}
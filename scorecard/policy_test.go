@@ -0,0 +1,47 @@
+package scorecard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+)
+
+func TestPolicy(t *testing.T) {
+	t.Parallel()
+	categories := []string{"security", "reliability"}
+
+	s := New()
+	o := s.NewObject(metav1.TypeMeta{Kind: "Deployment"}, metav1.ObjectMeta{Name: "api"}, nil)
+	o.Checks = []TestScore{
+		{Check: ks.Check{ID: "deployment-replicas", Category: "reliability"}, Grade: GradeCritical},
+		{Check: ks.Check{ID: "container-image-tag", Category: "best-practice"}, Grade: GradeWarning},
+		{Check: ks.Check{ID: "container-security-context-privileged", Category: "security"}, Grade: GradeCritical, Skipped: true},
+	}
+
+	p, err := NewPolicy(GradeCritical, nil, categories)
+	assert.NoError(t, err)
+	assert.True(t, p.Evaluate(s))
+
+	// Reliability criticals are tolerated, the best practice warning does not reach the default threshold
+	p, err = NewPolicy(GradeCritical, map[string]string{"reliability": "never"}, categories)
+	assert.NoError(t, err)
+	assert.False(t, p.Evaluate(s))
+
+	p, err = NewPolicy(GradeWarning, map[string]string{"reliability": "never"}, categories)
+	assert.NoError(t, err)
+	assert.True(t, p.Evaluate(s))
+
+	// Skipped checks never fail
+	p, err = NewPolicy(0, map[string]string{"security": "ok"}, categories)
+	assert.NoError(t, err)
+	assert.False(t, p.Evaluate(s))
+
+	_, err = NewPolicy(GradeCritical, map[string]string{"performance": "critical"}, categories)
+	assert.EqualError(t, err, `unknown category "performance", expected one of security, reliability`)
+	_, err = NewPolicy(GradeCritical, map[string]string{"security": "fatal"}, categories)
+	assert.EqualError(t, err, `invalid threshold of security: invalid threshold "fatal", `+
+		`expected critical, warning, info, almost-ok, ok or never`)
+}
@@ -0,0 +1,250 @@
+package scorecard
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Filter returns a view of the scorecard with the objects that match
+func (s Scorecard) Filter(match func(*ScoredObject) bool) Scorecard {
+	filtered := make(Scorecard)
+	for key, o := range s {
+		if match(o) {
+			filtered[key] = o
+		}
+	}
+	return filtered
+}
+
+// FilterChecks returns a view of the scorecard with the checks that match. Objects without
+// any matching checks are left out.
+func (s Scorecard) FilterChecks(match func(*ScoredObject, TestScore) bool) Scorecard {
+	filtered := make(Scorecard)
+	for key, o := range s {
+		var checks []TestScore
+		for _, ts := range o.Checks {
+			if match(o, ts) {
+				checks = append(checks, ts)
+			}
+		}
+		if len(checks) == 0 {
+			continue
+		}
+		view := *o
+		view.Checks = checks
+		filtered[key] = &view
+	}
+	return filtered
+}
+
+// WorstGrade returns the lowest grade of the checks of the object that were not skipped.
+// ok is false if all checks were skipped.
+func (so *ScoredObject) WorstGrade() (grade Grade, ok bool) {
+	for _, ts := range so.Checks {
+		if ts.Skipped {
+			continue
+		}
+		if !ok || ts.Grade < grade {
+			grade, ok = ts.Grade, true
+		}
+	}
+	return grade, ok
+}
+
+// WorstGrade returns the lowest grade of the checks of all objects that were not skipped.
+// ok is false if all checks were skipped.
+func (s Scorecard) WorstGrade() (grade Grade, ok bool) {
+	for _, o := range s {
+		if g, found := o.WorstGrade(); found && (!ok || g < grade) {
+			grade, ok = g, true
+		}
+	}
+	return grade, ok
+}
+
+// WorstGradeByNamespace returns the lowest grade of the checks that were not skipped, per namespace
+func (s Scorecard) WorstGradeByNamespace() map[string]Grade {
+	return s.worstGradeBy(func(o *ScoredObject, _ TestScore) string { return o.namespace.Of(o.ObjectMeta.Namespace) })
+}
+
+// WorstGradeByKind returns the lowest grade of the checks that were not skipped, per kind of object
+func (s Scorecard) WorstGradeByKind() map[string]Grade {
+	return s.worstGradeBy(func(o *ScoredObject, _ TestScore) string { return o.TypeMeta.Kind })
+}
+
+// WorstGradeByCheck returns the lowest grade of the checks that were not skipped, per check ID
+func (s Scorecard) WorstGradeByCheck() map[string]Grade {
+	return s.worstGradeBy(func(_ *ScoredObject, ts TestScore) string { return ts.Check.ID })
+}
+
+func (s Scorecard) worstGradeBy(key func(*ScoredObject, TestScore) string) map[string]Grade {
+	worst := make(map[string]Grade)
+	for _, o := range s {
+		for _, ts := range o.Checks {
+			if ts.Skipped {
+				continue
+			}
+			k := key(o, ts)
+			if g, ok := worst[k]; !ok || ts.Grade < g {
+				worst[k] = ts.Grade
+			}
+		}
+	}
+	return worst
+}
+
+// Query matches checks of objects, see ParseQuery
+type Query struct {
+	raw string
+	// any of the alternatives has to match, and all conditions of an alternative
+	alternatives [][]condition
+}
+
+type condition struct {
+	field string
+	op    string
+	value string
+	grade Grade
+}
+
+var queryFields = map[string]bool{
+	"namespace": true,
+	"kind":      true,
+	"name":      true,
+	"check":     true,
+	"grade":     true,
+}
+
+// ParseQuery parses a query like "namespace=prod&&grade<=warning". A query is a list of
+// conditions joined with "&&", alternatives can be joined with "||".
+//
+// The fields namespace, kind, name and check are compared with "=" or "!=" to a value,
+// that may contain the wildcards of path.Match. The grade is compared with "=", "!=",
+// "<", "<=", ">" or ">=" to one of critical, warning, info, almost-ok or ok.
+func ParseQuery(raw string) (Query, error) {
+	q := Query{raw: raw}
+	for alternative := range strings.SplitSeq(raw, "||") {
+		var conditions []condition
+		for rawCondition := range strings.SplitSeq(alternative, "&&") {
+			c, err := parseCondition(strings.TrimSpace(rawCondition))
+			if err != nil {
+				return Query{}, fmt.Errorf("invalid query %q: %w", raw, err)
+			}
+			conditions = append(conditions, c)
+		}
+		q.alternatives = append(q.alternatives, conditions)
+	}
+	return q, nil
+}
+
+func parseCondition(raw string) (condition, error) {
+	// The longer operators are tried first, so that "<=" is not parsed as "<"
+	for _, op := range []string{"!=", "<=", ">=", "=", "<", ">"} {
+		field, value, ok := strings.Cut(raw, op)
+		if !ok {
+			continue
+		}
+		c := condition{
+			field: strings.TrimSpace(field),
+			op:    op,
+			value: strings.TrimSpace(value),
+		}
+		if !queryFields[c.field] {
+			return condition{}, fmt.Errorf("unknown field %q", c.field)
+		}
+		if c.field != "grade" {
+			if op != "=" && op != "!=" {
+				return condition{}, fmt.Errorf("%s can only be compared with = or !=", c.field)
+			}
+			if _, err := path.Match(c.value, ""); err != nil {
+				return condition{}, fmt.Errorf("invalid pattern %q: %w", c.value, err)
+			}
+			return c, nil
+		}
+		grade, err := parseGrade(c.value)
+		if err != nil {
+			return condition{}, err
+		}
+		c.grade = grade
+		return c, nil
+	}
+	return condition{}, fmt.Errorf("invalid condition %q, expected field, operator and value", raw)
+}
+
+func parseGrade(s string) (Grade, error) {
+	switch strings.ToLower(s) {
+	case "critical":
+		return GradeCritical, nil
+	case "warning":
+		return GradeWarning, nil
+	case "info":
+		return GradeInfo, nil
+	case "almost-ok":
+		return GradeAlmostOK, nil
+	case "ok":
+		return GradeAllOK, nil
+	default:
+		return 0, fmt.Errorf("invalid grade %q, expected critical, warning, info, almost-ok or ok", s)
+	}
+}
+
+func (q Query) String() string {
+	return q.raw
+}
+
+// Matches returns true if the check of the object matches the query. Skipped checks never match.
+func (q Query) Matches(so *ScoredObject, ts TestScore) bool {
+	if ts.Skipped {
+		return false
+	}
+	for _, conditions := range q.alternatives {
+		matches := true
+		for _, c := range conditions {
+			if !c.matches(so, ts) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+func (c condition) matches(so *ScoredObject, ts TestScore) bool {
+	var value string
+	switch c.field {
+	case "grade":
+		switch c.op {
+		case "=":
+			return ts.Grade == c.grade
+		case "!=":
+			return ts.Grade != c.grade
+		case "<":
+			return ts.Grade < c.grade
+		case "<=":
+			return ts.Grade <= c.grade
+		case ">":
+			return ts.Grade > c.grade
+		default:
+			return ts.Grade >= c.grade
+		}
+	case "namespace":
+		value = so.namespace.Of(so.ObjectMeta.Namespace)
+	case "kind":
+		value = so.TypeMeta.Kind
+	case "name":
+		value = so.ObjectMeta.Name
+	case "check":
+		value = ts.Check.ID
+	}
+	matched, _ := path.Match(c.value, value)
+	return matched == (c.op == "=")
+}
+
+// AnyMatches returns true if any check of any object matches the query
+func (s Scorecard) AnyMatches(q Query) bool {
+	return len(s.FilterChecks(q.Matches)) > 0
+}
@@ -0,0 +1,150 @@
+package scorecard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ks "github.com/romnn/kube-score/domain"
+)
+
+func newQueryTestScorecard() Scorecard {
+	s := New()
+	add := func(kind, namespace, name string, checks ...TestScore) {
+		o := s.NewObject(metav1.TypeMeta{Kind: kind}, metav1.ObjectMeta{Namespace: namespace, Name: name}, nil)
+		o.Checks = checks
+	}
+	score := func(id string, grade Grade) TestScore {
+		return TestScore{Check: ks.Check{ID: id}, Grade: grade}
+	}
+
+	add("Deployment", "prod", "api",
+		score("container-resources", GradeWarning),
+		score("container-image-tag", GradeAllOK),
+	)
+	add("Service", "prod", "api",
+		score("service-type", GradeAllOK),
+		TestScore{Check: ks.Check{ID: "service-port-name"}, Grade: GradeCritical, Skipped: true},
+	)
+	add("Deployment", "dev", "api",
+		score("container-resources", GradeCritical),
+	)
+	return s
+}
+
+func TestWorstGrade(t *testing.T) {
+	t.Parallel()
+	s := newQueryTestScorecard()
+
+	assert.Equal(t, map[string]Grade{"prod": GradeWarning, "dev": GradeCritical}, s.WorstGradeByNamespace())
+	assert.Equal(t, map[string]Grade{"Deployment": GradeCritical, "Service": GradeAllOK}, s.WorstGradeByKind())
+	assert.Equal(t, map[string]Grade{
+		"container-resources": GradeCritical,
+		"container-image-tag": GradeAllOK,
+		"service-type":        GradeAllOK,
+	}, s.WorstGradeByCheck())
+
+	grade, ok := s.WorstGrade()
+	assert.True(t, ok)
+	assert.Equal(t, GradeCritical, grade)
+
+	_, ok = Scorecard{}.WorstGrade()
+	assert.False(t, ok)
+
+	grade, ok = (&ScoredObject{Checks: []TestScore{{Grade: GradeCritical, Skipped: true}}}).WorstGrade()
+	assert.False(t, ok)
+	assert.Zero(t, grade)
+}
+
+func TestQueryDefaultNamespace(t *testing.T) {
+	t.Parallel()
+	s := New()
+	o := s.NewObject(metav1.TypeMeta{Kind: "Deployment"}, metav1.ObjectMeta{Name: "api"}, nil)
+	o.namespace = "default"
+	o.Checks = []TestScore{{Check: ks.Check{ID: "container-resources"}, Grade: GradeWarning}}
+
+	assert.Equal(t, map[string]Grade{"default": GradeWarning}, s.WorstGradeByNamespace())
+	q, err := ParseQuery("namespace=default")
+	assert.NoError(t, err)
+	assert.True(t, s.AnyMatches(q))
+}
+
+func TestFilter(t *testing.T) {
+	t.Parallel()
+	s := newQueryTestScorecard()
+
+	prod := s.Filter(func(o *ScoredObject) bool { return o.ObjectMeta.Namespace == "prod" })
+	assert.Len(t, prod, 2)
+	assert.False(t, prod.AnyBelowOrEqualToGrade(GradeCritical))
+	assert.True(t, prod.AnyBelowOrEqualToGrade(GradeWarning))
+
+	resources := s.FilterChecks(func(_ *ScoredObject, ts TestScore) bool { return ts.Check.ID == "container-resources" })
+	assert.Len(t, resources, 2)
+	for _, o := range resources {
+		assert.Len(t, o.Checks, 1)
+	}
+	// The original scorecard is not modified
+	assert.Len(t, s.Filter(func(o *ScoredObject) bool { return o.TypeMeta.Kind == "Deployment" && o.ObjectMeta.Namespace == "prod" }), 1)
+	for _, o := range s {
+		if o.TypeMeta.Kind == "Deployment" && o.ObjectMeta.Namespace == "prod" {
+			assert.Len(t, o.Checks, 2)
+		}
+	}
+}
+
+func TestQuery(t *testing.T) {
+	t.Parallel()
+	s := newQueryTestScorecard()
+
+	tests := []struct {
+		query   string
+		matches bool
+	}{
+		{"namespace=prod&&grade<=warning", true},
+		{"namespace=prod&&grade<=critical", false},
+		{"namespace=prod && check=service-port-name", false}, // skipped
+		{"namespace!=prod&&grade=critical", true},
+		{"kind=Service&&grade<ok", false},
+		{"check=container-*&&grade<warning", true},
+		{"name=web||kind=Service&&grade>=ok", true},
+		{"name=web||namespace=staging", false},
+	}
+	for _, tc := range tests {
+		q, err := ParseQuery(tc.query)
+		assert.NoError(t, err, tc.query)
+		assert.Equal(t, tc.matches, s.AnyMatches(q), tc.query)
+	}
+}
+
+func TestParseQueryInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, raw := range []string{
+		"",
+		"namespace",
+		"owner=team",
+		"grade<=bad",
+		"kind<Service",
+		"name=[",
+		"namespace=prod&&",
+	} {
+		_, err := ParseQuery(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestGradeInfo(t *testing.T) {
+	t.Parallel()
+	s := New()
+	o := s.NewObject(metav1.TypeMeta{Kind: "Pod"}, metav1.ObjectMeta{Name: "foo"}, nil)
+	o.Checks = []TestScore{{Check: ks.Check{ID: "pod-qos"}, Grade: GradeInfo}}
+
+	assert.Equal(t, "INFO", GradeInfo.String())
+	// Info never fails the run, not even with --exit-one-on-warning
+	assert.False(t, s.AnyBelowOrEqualToGrade(GradeWarning))
+
+	q, err := ParseQuery("grade=info")
+	assert.NoError(t, err)
+	assert.True(t, s.AnyMatches(q))
+}
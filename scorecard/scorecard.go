@@ -2,6 +2,7 @@ package scorecard
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/romnn/kube-score/config"
 	ks "github.com/romnn/kube-score/domain"
@@ -10,10 +11,32 @@ import (
 )
 
 const (
-	ignoredChecksAnnotation  = "kube-score/ignore"
-	optionalChecksAnnotation = "kube-score/enable"
+	// defaultAnnotationPrefix is always accepted, in addition to the configured AnnotationPrefixes
+	defaultAnnotationPrefix = "kube-score"
+
+	ignoredChecksAnnotation  = "ignore"
+	optionalChecksAnnotation = "enable"
+	// ignoredContainerChecksAnnotation is the prefix of the name of the annotations that ignore checks
+	// for a single container, e.g. kube-score/ignore.container.istio-proxy
+	ignoredContainerChecksAnnotation = "ignore.container."
+
+	// ignoreReasonAnnotation explains why checks of the object are ignored. It does not change the
+	// score, but ignores without a reason are reported by LintAnnotationHygiene.
+	ignoreReasonAnnotation = "ignore-reason"
 )
 
+// helmHookIgnoredChecks are the checks that expect an object to be targeted by other objects, which
+// one-shot Helm hooks are not. They are not run on hooks with the relaxed helm hooks policy.
+var helmHookIgnoredChecks = map[string]struct{}{
+	"pod-networkpolicy":                   {},
+	"pod-probes":                          {},
+	"pod-has-controller":                  {},
+	"deployment-has-poddisruptionbudget":  {},
+	"statefulset-has-poddisruptionbudget": {},
+	"deployment-orphan":                   {},
+	"statefulset-orphan":                  {},
+}
+
 // if this, then that
 var impliedIgnoreAnnotations = map[string][]string{
 	"container-resources": {"container-ephemeral-storage-request-and-limit"},
@@ -43,6 +66,14 @@ func (s Scorecard) NewObject(
 		useIgnoreChecksAnnotation:   cnf.UseIgnoreChecksAnnotation,
 		useOptionalChecksAnnotation: cnf.UseOptionalChecksAnnotation,
 		enabledOptionalTests:        cnf.EnabledOptionalTests,
+		annotationPrefixes:          annotationPrefixes(cnf.AnnotationPrefixes),
+		aggregateComments:           cnf.AggregateComments,
+		messages:                    cnf.Messages,
+		suppressions:                cnf.Suppressions,
+		checkGroups:                 cnf.CheckGroups,
+		helmHooks:                   cnf.HelmHooks,
+		exceptions:                  cnf.Exceptions,
+		namespace:                   ks.DefaultNamespace(cnf.Namespace),
 	}
 
 	// If this object already exists, return the previous version
@@ -72,6 +103,14 @@ type ScoredObject struct {
 	useIgnoreChecksAnnotation   bool
 	useOptionalChecksAnnotation bool
 	enabledOptionalTests        map[string]struct{}
+	annotationPrefixes          []string
+	aggregateComments           bool
+	messages                    config.MessageTemplates
+	suppressions                map[string]struct{}
+	checkGroups                 config.CheckGroups
+	helmHooks                   config.HelmHooks
+	exceptions                  []config.ScoreException
+	namespace                   ks.DefaultNamespace
 }
 
 func (so *ScoredObject) AnyBelowOrEqualToGrade(threshold Grade) bool {
@@ -106,24 +145,7 @@ func (so *ScoredObject) Add(
 	ts.Check = check
 	so.FileLocation = locationer.FileLocation()
 
-	skip := false
-	skipAll := so.FileLocation.Skip
-
-	// var annotations []map[string]string
-	// for _, a := range annotationProviders {
-	// 	annotations = append(annotations, a.Annotations())
-	// }
-
-	if !skipAll && annotations != nil {
-		skipAll = skipAll || parser.IsSkipped([]error{}, annotations...)
-		if len(annotations) == 1 && !so.isEnabled(check, annotations[0], nil) {
-			skip = true
-		}
-		if len(annotations) == 2 &&
-			!so.isEnabled(check, annotations[0], annotations[1]) {
-			skip = true
-		}
-	}
+	skip, skipAll := so.skipped(check, so.FileLocation, annotations)
 
 	// This test is ignored (via annotations), don't save the score
 	if skipAll {
@@ -135,16 +157,72 @@ func (so *ScoredObject) Add(
 	} else if skip {
 		ts.Skipped = true
 		ts.Comments = []TestScoreComment{{Summary: fmt.Sprintf("Skipped because %s is ignored", check.ID)}}
+	} else if !ts.Skipped && ts.Error == "" {
+		// Checks that failed to run can neither be suppressed nor downgraded
+		so.addFingerprints(&ts)
+		so.except(&ts, time.Now())
+		if !ts.Skipped {
+			so.suppress(&ts)
+		}
+		if !ts.Skipped {
+			so.overrideSeverity(&ts, annotations, time.Now())
+			so.applyMessages(&ts)
+		}
+	}
+
+	if so.aggregateComments {
+		ts.Comments = aggregateComments(ts.Comments)
 	}
+	so.addDeprecationNotes(&ts, annotations)
 
 	so.Checks = append(so.Checks, ts)
 }
 
+// IsSkipped returns true if Add would skip the check, because the object is skipped, or the check is
+// ignored or not enabled. The check does not have to be run in that case.
+func (so *ScoredObject) IsSkipped(
+	check ks.Check,
+	locationer ks.FileLocationer,
+	annotations ...map[string]string,
+) bool {
+	skip, skipAll := so.skipped(check, locationer.FileLocation(), annotations)
+	return skip || skipAll
+}
+
+func (so *ScoredObject) skipped(
+	check ks.Check,
+	location ks.FileLocation,
+	annotations []map[string]string,
+) (skip, skipAll bool) {
+	skipAll = location.Skip
+	if !skipAll && annotations != nil {
+		skipAll = parser.IsSkipped([]error{}, annotations...)
+		if len(annotations) == 1 && !so.isEnabled(check, annotations[0], nil) {
+			skip = true
+		}
+		if len(annotations) == 2 &&
+			!so.isEnabled(check, annotations[0], annotations[1]) {
+			skip = true
+		}
+	}
+	return skip, skipAll
+}
+
 type TestScore struct {
 	Check    ks.Check
 	Grade    Grade
 	Skipped  bool
 	Comments []TestScoreComment
+	// Error is the error of a check that failed to run on the object, see Errored
+	Error string
+}
+
+// Errored returns the score of a check that failed to run on an object. The check is critical, so
+// that the failure is not silently ignored.
+func Errored(err error) TestScore {
+	ts := TestScore{Grade: GradeCritical, Error: err.Error()}
+	ts.AddComment("", "The check failed to run", err.Error())
+	return ts
 }
 
 type Grade int
@@ -152,6 +230,8 @@ type Grade int
 const (
 	GradeCritical Grade = 1
 	GradeWarning  Grade = 5
+	// GradeInfo is an advisory finding, that is reported but never fails the run
+	GradeInfo     Grade = 6
 	GradeAlmostOK Grade = 7
 	GradeAllOK    Grade = 10
 )
@@ -162,6 +242,8 @@ func (g Grade) String() string {
 		return "CRITICAL"
 	case GradeWarning:
 		return "WARNING"
+	case GradeInfo:
+		return "INFO"
 	case GradeAlmostOK, GradeAllOK:
 		return "OK"
 	default:
@@ -174,6 +256,8 @@ type TestScoreComment struct {
 	Summary          string
 	Description      string
 	DocumentationURL string
+	// Fingerprint identifies the finding across runs, see ScoredObject.Fingerprint
+	Fingerprint string
 }
 
 func (ts *TestScore) AddComment(path, summary, description string) {
@@ -0,0 +1,121 @@
+package scorecard
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// severityAnnotation downgrades the grade of checks of the object, in the format
+// "check-id=grade[@expiry], ...", e.g. "container-resources=warning@2025-12-31".
+// The expiry is a date, the override is applied until the end of that day (UTC).
+const severityAnnotation = "severity"
+
+type severityOverride struct {
+	grade   Grade
+	expires time.Time
+}
+
+// parseSeverityOverrides returns the valid overrides of the value, and the errors of the entries
+// that are malformed
+func parseSeverityOverrides(value string) (map[string]severityOverride, []error) {
+	overrides := make(map[string]severityOverride)
+	var errs []error
+	for entry := range strings.SplitSeq(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		checkID, rawGrade, ok := strings.Cut(entry, "=")
+		if !ok {
+			errs = append(errs, fmt.Errorf("invalid entry %q, expected check-id=grade", entry))
+			continue
+		}
+		rawGrade, rawExpiry, hasExpiry := strings.Cut(rawGrade, "@")
+
+		var override severityOverride
+		switch strings.ToLower(strings.TrimSpace(rawGrade)) {
+		case "warning":
+			override.grade = GradeWarning
+		case "info":
+			override.grade = GradeInfo
+		case "ok":
+			override.grade = GradeAllOK
+		default:
+			errs = append(errs, fmt.Errorf("invalid grade %q of %s, expected warning, info or ok", rawGrade, checkID))
+			continue
+		}
+		if hasExpiry {
+			expires, err := time.Parse(time.DateOnly, strings.TrimSpace(rawExpiry))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid expiry %q of %s, expected YYYY-MM-DD", rawExpiry, checkID))
+				continue
+			}
+			override.expires = expires.AddDate(0, 0, 1)
+		}
+		overrides[strings.TrimSpace(checkID)] = override
+	}
+	return overrides, errs
+}
+
+// InvalidSeverityAnnotations returns the errors of the malformed entries of the severity
+// annotations of the object, with one of the prefixes, keyed by the annotation. The valid entries
+// of these annotations are still applied.
+func InvalidSeverityAnnotations(prefixes []string, annotations map[string]string) map[string][]error {
+	invalid := make(map[string][]error)
+	for _, prefix := range annotationPrefixes(prefixes) {
+		key := prefix + "/" + severityAnnotation
+		value, ok := annotations[key]
+		if !ok {
+			continue
+		}
+		if _, errs := parseSeverityOverrides(value); len(errs) > 0 {
+			invalid[key] = errs
+		}
+	}
+	return invalid
+}
+
+// overrideSeverity applies the severity annotations to the score. Annotations of later
+// maps take precedence, and the grade is only ever raised.
+func (so *ScoredObject) overrideSeverity(ts *TestScore, annotations []map[string]string, now time.Time) {
+	var override *severityOverride
+	for _, a := range annotations {
+		value := so.annotation(a, severityAnnotation)
+		if value == "" {
+			continue
+		}
+		// The malformed entries are reported once per object by the severity-annotation check
+		overrides, _ := parseSeverityOverrides(value)
+		// The ID takes precedence over the aliases of the check
+		for _, id := range append([]string{ts.Check.ID}, ts.Check.Aliases...) {
+			if o, ok := overrides[id]; ok {
+				override = &o
+				break
+			}
+		}
+	}
+
+	if override == nil || override.grade <= ts.Grade {
+		return
+	}
+	if !override.expires.IsZero() && !now.Before(override.expires) {
+		ts.AddComment(
+			"",
+			"Severity override has expired",
+			fmt.Sprintf(
+				"The %s/%s annotation downgraded this check until %s",
+				defaultAnnotationPrefix, severityAnnotation,
+				override.expires.AddDate(0, 0, -1).Format(time.DateOnly),
+			),
+		)
+		return
+	}
+
+	ts.AddComment(
+		"",
+		fmt.Sprintf("Downgraded from %s to %s", ts.Grade, override.grade),
+		fmt.Sprintf("The grade has been overridden by the %s/%s annotation", defaultAnnotationPrefix, severityAnnotation),
+	)
+	ts.Grade = override.grade
+}
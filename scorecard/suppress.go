@@ -0,0 +1,42 @@
+package scorecard
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Fingerprint returns the identifier of a finding of a check on the object, that is stable across
+// runs as long as the object, the check and the finding do not change. It is the start of the
+// SHA-256 of the ID of the object, the ID of the check, and the path and summary of the comment.
+func (so *ScoredObject) Fingerprint(checkID string, comment TestScoreComment) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{so.ID(), checkID, comment.Path, comment.Summary}, "\n")))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+func (so *ScoredObject) addFingerprints(ts *TestScore) {
+	for i := range ts.Comments {
+		ts.Comments[i].Fingerprint = so.Fingerprint(ts.Check.ID, ts.Comments[i])
+	}
+}
+
+// suppress removes the comments with suppressed fingerprints. The check is skipped if all of its
+// comments are suppressed, and is unchanged if only some of them are.
+func (so *ScoredObject) suppress(ts *TestScore) {
+	if len(so.suppressions) == 0 || len(ts.Comments) == 0 {
+		return
+	}
+
+	var comments []TestScoreComment
+	for _, c := range ts.Comments {
+		if _, ok := so.suppressions[c.Fingerprint]; !ok {
+			comments = append(comments, c)
+		}
+	}
+	if len(comments) == 0 {
+		ts.Skipped = true
+		ts.Comments = []TestScoreComment{{Summary: "Skipped because the findings are suppressed"}}
+		return
+	}
+	ts.Comments = comments
+}
@@ -0,0 +1,60 @@
+package scorecard
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/romnn/kube-score/config"
+	ks "github.com/romnn/kube-score/domain"
+)
+
+func TestSuppressFingerprints(t *testing.T) {
+	t.Parallel()
+
+	ts := TestScore{Grade: GradeCritical}
+	ts.AddComment("app", "CPU limit is not set", "")
+	ts.AddComment("app", "Memory limit is not set", "")
+
+	object := func(suppressions ...string) *ScoredObject {
+		s := New()
+		return s.NewObject(
+			metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			metav1.ObjectMeta{Name: "foo", Namespace: "bar"},
+			&config.RunConfiguration{Suppressions: listToSet(suppressions)},
+		)
+	}
+
+	o := object()
+	o.Add(ts, ks.Check{ID: "container-resources"}, location{})
+	cpu := o.Checks[0].Comments[0].Fingerprint
+	memory := o.Checks[0].Comments[1].Fingerprint
+	assert.Len(t, cpu, 16)
+	assert.NotEqual(t, cpu, memory)
+	assert.Equal(t, cpu, o.Fingerprint("container-resources", TestScoreComment{Path: "app", Summary: "CPU limit is not set"}))
+
+	// The fingerprint depends on the check
+	o.Add(ts, ks.Check{ID: "container-image-tag"}, location{})
+	assert.NotEqual(t, cpu, o.Checks[1].Comments[0].Fingerprint)
+
+	o = object(cpu)
+	o.Add(ts, ks.Check{ID: "container-resources"}, location{})
+	assert.False(t, o.Checks[0].Skipped)
+	assert.Equal(t, GradeCritical, o.Checks[0].Grade)
+	assert.Equal(t, []TestScoreComment{{Path: "app", Summary: "Memory limit is not set", Fingerprint: memory}}, o.Checks[0].Comments)
+
+	o = object(cpu, memory)
+	o.Add(ts, ks.Check{ID: "container-resources"}, location{})
+	assert.True(t, o.Checks[0].Skipped)
+	assert.Equal(t, []TestScoreComment{{Summary: "Skipped because the findings are suppressed"}}, o.Checks[0].Comments)
+	assert.False(t, o.AnyBelowOrEqualToGrade(GradeWarning))
+}
+
+func listToSet(items []string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, i := range items {
+		set[i] = struct{}{}
+	}
+	return set
+}